@@ -1,31 +1,57 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net/url"
+	"os"
 	"strings"
 	"time"
 
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
 	"go.opentelemetry.io/otel/exporters/prometheus"
 	"go.opentelemetry.io/otel/sdk/metric"
 	"go.opentelemetry.io/otel/sdk/resource"
+	sdkTrace "go.opentelemetry.io/otel/sdk/trace"
 	semconv "go.opentelemetry.io/otel/semconv/v1.18.0"
 
 	"github.com/kelseyhightower/envconfig"
 	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/util/validation"
 	kubeInformers "k8s.io/client-go/informers"
 	kubernetesClient "k8s.io/client-go/kubernetes"
 
+	"github.com/hellofresh/kangal/pkg/backends"
 	"github.com/hellofresh/kangal/pkg/controller"
 	"github.com/hellofresh/kangal/pkg/core/observability"
 	"github.com/hellofresh/kangal/pkg/kubernetes"
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
 	clientSet "github.com/hellofresh/kangal/pkg/kubernetes/generated/clientset/versioned"
 	informers "github.com/hellofresh/kangal/pkg/kubernetes/generated/informers/externalversions"
 )
 
+// testFileCredentialsPrefixes are the prefixes of rclone env vars configuring the object storage
+// backends used to fetch test files
+var testFileCredentialsPrefixes = []string{"RCLONE_S3_", "RCLONE_GCS_"}
+
 // reconcileDistribution defines the bucket boundaries for the histogram of reconcile latency metric
 // Bucket boundaries are 10ms, 100ms, 1s, 10s, 30s and 60s.
 var reconcileDistribution = []float64{10, 100, 1000, 10000, 30000, 60000}
 
+// loadTestDurationDistribution defines the bucket boundaries for the histogram of loadtest duration metric
+// Bucket boundaries are 1m, 5m, 10m, 30m, 1h, 2h, 4h, 12h and 24h.
+var loadTestDurationDistribution = []float64{60, 300, 600, 1800, 3600, 7200, 14400, 43200, 86400}
+
+// Trace exporters of the controller
+const (
+	traceExporterNone = "none"
+	traceExporterOTLP = "otlp"
+)
+
 type controllerCmdOptions struct {
 	kubeConfig           string
 	masterURL            string
@@ -34,6 +60,10 @@ type controllerCmdOptions struct {
 	podAnnotations       []string
 	nodeSelectors        []string
 	tolerations          []string
+	defaultTolerations   bool
+	imagePullSecrets     []string
+	jobBackoffLimit      int32
+	workers              int
 }
 
 // NewControllerCmd creates a new controller command
@@ -55,6 +85,7 @@ func NewControllerCmd() *cobra.Command {
 			if err != nil {
 				return err
 			}
+			cfg.TestFileCredentials = testFileCredentials(os.Environ())
 
 			logger, _, err := observability.NewLogger(cfg.Logger)
 			if err != nil {
@@ -66,10 +97,27 @@ func NewControllerCmd() *cobra.Command {
 				return fmt.Errorf("could not build prometheus exporter: %w", err)
 			}
 
+			tracerProvider, err := newTracerProvider(context.Background(), cfg)
+			if err != nil {
+				return fmt.Errorf("could not build tracer provider: %w", err)
+			}
+			if tracerProvider != nil {
+				otel.SetTracerProvider(tracerProvider)
+				defer func() {
+					if err := tracerProvider.Shutdown(context.Background()); err != nil {
+						logger.Warn("Could not flush traces", zap.Error(err))
+					}
+				}()
+			}
+
 			kubeCfg, err := kubernetes.BuildClientConfig(cfg.MasterURL, cfg.KubeConfig, cfg.KubeClientTimeout)
 			if err != nil {
 				return fmt.Errorf("error building kubeConfig: %w", err)
 			}
+			if cfg.DryRun {
+				logger.Warn("Dry run mode, changes to the cluster are logged instead of applied")
+				kubeCfg.Wrap(controller.DryRunTransport(logger, cfg.DryRunYAML))
+			}
 
 			kubeClient, err := kubernetesClient.NewForConfig(kubeCfg)
 			if err != nil {
@@ -83,13 +131,18 @@ func NewControllerCmd() *cobra.Command {
 
 			provider := metric.NewMeterProvider(
 				metric.WithReader(pe),
-				metric.WithResource(
-					resource.NewSchemaless(semconv.ServiceNameKey.String("kangal-controller"))),
+				metric.WithResource(newMetricsResource(cfg)),
 				metric.WithView(metric.NewView(
 					metric.Instrument{Name: "kangal_reconcile_latency"},
 					metric.Stream{Aggregation: metric.AggregationExplicitBucketHistogram{
 						Boundaries: reconcileDistribution,
 					}},
+				)),
+				metric.WithView(metric.NewView(
+					metric.Instrument{Name: "kangal_loadtest_duration"},
+					metric.Stream{Aggregation: metric.AggregationExplicitBucketHistogram{
+						Boundaries: loadTestDurationDistribution,
+					}},
 				)))
 			statsReporter, err := controller.NewMetricsReporter(provider.Meter("controller"))
 			if err != nil {
@@ -119,6 +172,10 @@ func NewControllerCmd() *cobra.Command {
 	flags.StringSliceVar(&opts.podAnnotations, "pod-annotation", []string{}, "annotation will be attached to the loadtest pods")
 	flags.StringSliceVar(&opts.nodeSelectors, "node-selector", []string{}, "nodeSelector rules will be attached to the loadtest pods")
 	flags.StringSliceVar(&opts.tolerations, "tolerations", []string{}, "toleration rules to be applied to the loadtest pods")
+	flags.Int32Var(&opts.jobBackoffLimit, "job-backoff-limit", -1, "number of retries of failed loadtest job pods, overrides JOB_BACKOFF_LIMIT when not negative")
+	flags.IntVar(&opts.workers, "workers", 0, "number of loadtests reconciled concurrently, overrides WORKERS when set")
+	flags.StringSliceVar(&opts.imagePullSecrets, "image-pull-secret", []string{}, "image pull secret of the loadtest pods, added to IMAGE_PULL_SECRETS")
+	flags.BoolVar(&opts.defaultTolerations, "default-tolerations", false, "tolerate the common load generator taint workload=loadtest (NoSchedule and NoExecute) in addition to --tolerations")
 
 	return cmd
 }
@@ -150,9 +207,99 @@ func populateCfgFromOpts(cfg controller.Config, opts *controllerCmdOptions) (con
 	if err != nil {
 		return controller.Config{}, fmt.Errorf("failed to convert node selectors: %w", err)
 	}
+	if opts.jobBackoffLimit >= 0 {
+		cfg.JobBackoffLimit = opts.jobBackoffLimit
+	}
+	if opts.workers != 0 {
+		cfg.Workers = opts.workers
+	}
+	cfg.ImagePullSecrets = append(cfg.ImagePullSecrets, opts.imagePullSecrets...)
+	if opts.defaultTolerations {
+		cfg.Tolerations = kubernetes.LoadGeneratorTolerations.Merge(cfg.Tolerations)
+	}
+	for _, tag := range cfg.NamespaceLabelTags {
+		if errs := validation.IsQualifiedName(tag); len(errs) > 0 {
+			return controller.Config{}, fmt.Errorf("invalid namespace label tag %q: %s", tag, strings.Join(errs, ", "))
+		}
+	}
+	if cfg.DebugEndpoint && cfg.DebugEndpointToken == "" {
+		return controller.Config{}, errors.New("DEBUG_ENDPOINT_TOKEN is required to enable the debug endpoint")
+	}
+	if cfg.ConfigEndpoint && cfg.DebugEndpointToken == "" {
+		return controller.Config{}, errors.New("DEBUG_ENDPOINT_TOKEN is required to enable the config endpoint")
+	}
+	if cfg.PauseEndpoint && cfg.DebugEndpointToken == "" {
+		return controller.Config{}, errors.New("DEBUG_ENDPOINT_TOKEN is required to enable the pause endpoint")
+	}
+	if cfg.IdleScaleDown && cfg.IdleCheckInterval <= 0 {
+		return controller.Config{}, errors.New("IDLE_CHECK_INTERVAL must be positive to enable idle scale down")
+	}
+	if cfg.ReportStorageLocation != "" {
+		if _, err := url.Parse(cfg.ReportStorageLocation); err != nil {
+			return controller.Config{}, fmt.Errorf("invalid report storage location: %w", err)
+		}
+	}
+	if cfg.DefaultRetentionClass != "" && !loadTestV1.RetentionClass(cfg.DefaultRetentionClass).IsValid() {
+		return controller.Config{}, fmt.Errorf("invalid default retention class %q, must be one of short, standard or long", cfg.DefaultRetentionClass)
+	}
+	cfg.SeccompProfile, err = backends.NewSeccompProfile(cfg.SeccompProfileType, cfg.SeccompLocalhostProfile)
+	if err != nil {
+		return controller.Config{}, fmt.Errorf("invalid seccomp profile: %w", err)
+	}
+	cfg.ResourceBudget, err = backends.ParseResourceBudget(cfg.MaxLoadTestPods, cfg.MaxLoadTestCPU, cfg.MaxLoadTestMemory)
+	if err != nil {
+		return controller.Config{}, fmt.Errorf("failed to parse resource budget: %w", err)
+	}
 	return cfg, nil
 }
 
+// newMetricsResource describes the controller process in the metrics and traces, including the node and zone
+// it runs on to tell controller replicas apart
+func newMetricsResource(cfg controller.Config) *resource.Resource {
+	attrs := []attribute.KeyValue{semconv.ServiceNameKey.String("kangal-controller")}
+	if cfg.NodeName != "" {
+		attrs = append(attrs, semconv.K8SNodeNameKey.String(cfg.NodeName))
+	}
+	if cfg.NodeZone != "" {
+		attrs = append(attrs, semconv.CloudAvailabilityZoneKey.String(cfg.NodeZone))
+	}
+	return resource.NewSchemaless(attrs...)
+}
+
+// newTracerProvider builds the provider of the reconcile traces for the configured exporter,
+// it returns nil when tracing is disabled
+func newTracerProvider(ctx context.Context, cfg controller.Config) (*sdkTrace.TracerProvider, error) {
+	switch cfg.TraceExporter {
+	case "", traceExporterNone:
+		return nil, nil
+	case traceExporterOTLP:
+		exporter, err := otlptracehttp.New(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("could not build OTLP trace exporter: %w", err)
+		}
+		return sdkTrace.NewTracerProvider(
+			sdkTrace.WithBatcher(exporter),
+			sdkTrace.WithResource(newMetricsResource(cfg)),
+		), nil
+	}
+	return nil, fmt.Errorf("unknown trace exporter %q", cfg.TraceExporter)
+}
+
+// testFileCredentials picks the rclone object storage env vars from the controller environment,
+// they are shared with the init containers fetching test files from s3:// and gs:// URLs
+func testFileCredentials(environ []string) map[string]string {
+	credentials := map[string]string{}
+	for _, kv := range environ {
+		key, value, _ := strings.Cut(kv, "=")
+		for _, prefix := range testFileCredentialsPrefixes {
+			if strings.HasPrefix(key, prefix) {
+				credentials[key] = value
+			}
+		}
+	}
+	return credentials
+}
+
 func convertKeyPairStringToMap(s []string) (map[string]string, error) {
 	m := make(map[string]string, len(s))
 	for _, a := range s {
@@ -1,9 +1,11 @@
 package cmd
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/hellofresh/kangal/pkg/controller"
 	"github.com/hellofresh/kangal/pkg/kubernetes"
@@ -18,6 +20,7 @@ func TestControllerPopulateCfgFromOpts(t *testing.T) {
 		podAnnotations       []string
 		nodeSelectors        []string
 		tolerations          []string
+		defaultTolerations   bool
 	}
 	tests := []struct {
 		name   string
@@ -101,6 +104,39 @@ func TestControllerPopulateCfgFromOpts(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: `test with default tolerations`,
+			fields: fields{
+				tolerations:        []string{"key1:value1:Equal:NoSchedule"},
+				defaultTolerations: true,
+			},
+			want: controller.Config{
+				NamespaceLabels:      map[string]string{},
+				NamespaceAnnotations: map[string]string{},
+				PodAnnotations:       map[string]string{},
+				NodeSelectors:        map[string]string{},
+				Tolerations: kubernetes.Tolerations{
+					{
+						Key:      "workload",
+						Value:    "loadtest",
+						Operator: "Equal",
+						Effect:   "NoSchedule",
+					},
+					{
+						Key:      "workload",
+						Value:    "loadtest",
+						Operator: "Equal",
+						Effect:   "NoExecute",
+					},
+					{
+						Key:      "key1",
+						Value:    "value1",
+						Operator: "Equal",
+						Effect:   "NoSchedule",
+					},
+				},
+			},
+		},
 		{
 			name: `test with some "`,
 			fields: fields{
@@ -126,9 +162,103 @@ func TestControllerPopulateCfgFromOpts(t *testing.T) {
 				podAnnotations:       tt.fields.podAnnotations,
 				nodeSelectors:        tt.fields.nodeSelectors,
 				tolerations:          tt.fields.tolerations,
+				defaultTolerations:   tt.fields.defaultTolerations,
 			}
 			got, _ := populateCfgFromOpts(controller.Config{}, opts)
 			assert.EqualValues(t, tt.want, got)
 		})
 	}
 }
+
+func TestPopulateCfgJobBackoffLimit(t *testing.T) {
+	cfg, err := populateCfgFromOpts(controller.Config{JobBackoffLimit: 2}, &controllerCmdOptions{jobBackoffLimit: -1})
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), cfg.JobBackoffLimit)
+
+	cfg, err = populateCfgFromOpts(controller.Config{JobBackoffLimit: 2}, &controllerCmdOptions{jobBackoffLimit: 4})
+	assert.NoError(t, err)
+	assert.Equal(t, int32(4), cfg.JobBackoffLimit)
+}
+
+func TestPopulateCfgWorkers(t *testing.T) {
+	cfg, err := populateCfgFromOpts(controller.Config{Workers: 1}, &controllerCmdOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, cfg.Workers)
+
+	cfg, err = populateCfgFromOpts(controller.Config{Workers: 1}, &controllerCmdOptions{workers: 8})
+	assert.NoError(t, err)
+	assert.Equal(t, 8, cfg.Workers)
+}
+
+func TestNewMetricsResource(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		cfg  controller.Config
+		want map[string]string
+	}{
+		{
+			name: "service name only",
+			cfg:  controller.Config{},
+			want: map[string]string{"service.name": "kangal-controller"},
+		},
+		{
+			name: "node and zone",
+			cfg:  controller.Config{NodeName: "node-1", NodeZone: "eu-west-1a"},
+			want: map[string]string{
+				"service.name":            "kangal-controller",
+				"k8s.node.name":           "node-1",
+				"cloud.availability_zone": "eu-west-1a",
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got := map[string]string{}
+			for _, attr := range newMetricsResource(tt.cfg).Attributes() {
+				got[string(attr.Key)] = attr.Value.AsString()
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNewTracerProvider(t *testing.T) {
+	for _, tt := range []struct {
+		exporter     string
+		wantProvider bool
+		wantErr      bool
+	}{
+		{exporter: ""},
+		{exporter: "none"},
+		{exporter: "otlp", wantProvider: true},
+		{exporter: "jaeger", wantErr: true},
+	} {
+		t.Run(tt.exporter, func(t *testing.T) {
+			provider, err := newTracerProvider(context.Background(), controller.Config{TraceExporter: tt.exporter})
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantProvider, provider != nil)
+			if provider != nil {
+				assert.NoError(t, provider.Shutdown(context.Background()))
+			}
+		})
+	}
+}
+
+func TestTestFileCredentials(t *testing.T) {
+	environ := []string{
+		"RCLONE_S3_ACCESS_KEY_ID=key",
+		"RCLONE_S3_SECRET_ACCESS_KEY=secret=with=equals",
+		"RCLONE_GCS_SERVICE_ACCOUNT_CREDENTIALS={}",
+		"RCLONE_CONFIG_REMOTECUSTOMDATA_TYPE=s3",
+		"HOME=/root",
+	}
+
+	assert.Equal(t, map[string]string{
+		"RCLONE_S3_ACCESS_KEY_ID":                "key",
+		"RCLONE_S3_SECRET_ACCESS_KEY":            "secret=with=equals",
+		"RCLONE_GCS_SERVICE_ACCOUNT_CREDENTIALS": "{}",
+	}, testFileCredentials(environ))
+}
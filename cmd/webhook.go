@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/kelseyhightower/envconfig"
+	"github.com/spf13/cobra"
+
+	"github.com/hellofresh/kangal/pkg/core/observability"
+	"github.com/hellofresh/kangal/pkg/webhook"
+)
+
+// NewWebhookCmd creates a new webhook command
+func NewWebhookCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "webhook",
+		Short:   "Run admission webhook validating load tests",
+		Aliases: []string{"w"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var cfg webhook.Config
+			if err := envconfig.Process("", &cfg); err != nil {
+				return fmt.Errorf("could not load config from env: %w", err)
+			}
+
+			logger, _, err := observability.NewLogger(cfg.Logger)
+			if err != nil {
+				return fmt.Errorf("could not build logger instance: %w", err)
+			}
+
+			return webhook.RunServer(cfg, webhook.Runner{
+				Logger: logger,
+			})
+		},
+	}
+
+	return cmd
+}
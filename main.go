@@ -5,10 +5,13 @@ import (
 
 	"github.com/hellofresh/kangal/cmd"
 	_ "github.com/hellofresh/kangal/pkg/backends/fake"
+	_ "github.com/hellofresh/kangal/pkg/backends/gatling"
 	_ "github.com/hellofresh/kangal/pkg/backends/ghz"
 	_ "github.com/hellofresh/kangal/pkg/backends/jmeter"
 	_ "github.com/hellofresh/kangal/pkg/backends/k6"
 	_ "github.com/hellofresh/kangal/pkg/backends/locust"
+	_ "github.com/hellofresh/kangal/pkg/backends/vegeta"
+	_ "github.com/hellofresh/kangal/pkg/backends/wrk2"
 )
 
 var version = "0.0.0-dev"
@@ -0,0 +1,21 @@
+package backends
+
+// MergePodAnnotations returns the annotations of loadtest pods merged from their sources. On conflicting keys
+// the default annotations of the backend override the controller-global annotations given with --pod-annotation,
+// which override the annotations of the loadtest, so a loadtest can't replace an annotation the controller is
+// configured with, e.g. the IAM role of the pods. The sources are not modified, nil is returned when all of them
+// are empty.
+func MergePodAnnotations(global, backend, loadTest map[string]string) map[string]string {
+	size := len(global) + len(backend) + len(loadTest)
+	if size == 0 {
+		return nil
+	}
+
+	merged := make(map[string]string, size)
+	for _, annotations := range []map[string]string{loadTest, global, backend} {
+		for key, value := range annotations {
+			merged[key] = value
+		}
+	}
+	return merged
+}
@@ -0,0 +1,64 @@
+package backends
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergePodAnnotations(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		global   map[string]string
+		backend  map[string]string
+		loadTest map[string]string
+		expected map[string]string
+	}{
+		{
+			name: "no annotations",
+		},
+		{
+			name:     "distinct keys",
+			global:   map[string]string{"global": "1"},
+			backend:  map[string]string{"backend": "2"},
+			loadTest: map[string]string{"loadtest": "3"},
+			expected: map[string]string{"global": "1", "backend": "2", "loadtest": "3"},
+		},
+		{
+			name:     "backend overrides global",
+			global:   map[string]string{"iam.amazonaws.com/role": "global-role", "global": "1"},
+			backend:  map[string]string{"iam.amazonaws.com/role": "backend-role"},
+			expected: map[string]string{"iam.amazonaws.com/role": "backend-role", "global": "1"},
+		},
+		{
+			name:     "backend and global override loadtest",
+			global:   map[string]string{"iam.amazonaws.com/role": "global-role"},
+			backend:  map[string]string{"iam.amazonaws.com/role": "backend-role"},
+			loadTest: map[string]string{"iam.amazonaws.com/role": "loadtest-role", "loadtest": "3"},
+			expected: map[string]string{"iam.amazonaws.com/role": "backend-role", "loadtest": "3"},
+		},
+		{
+			name:     "global overrides loadtest",
+			global:   map[string]string{"iam.amazonaws.com/role": "global-role"},
+			loadTest: map[string]string{"iam.amazonaws.com/role": "loadtest-role"},
+			expected: map[string]string{"iam.amazonaws.com/role": "global-role"},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			global := copyAnnotations(tt.global)
+			assert.Equal(t, tt.expected, MergePodAnnotations(tt.global, tt.backend, tt.loadTest))
+			assert.Equal(t, global, tt.global, "Expected sources not to be modified")
+		})
+	}
+}
+
+func copyAnnotations(annotations map[string]string) map[string]string {
+	if annotations == nil {
+		return nil
+	}
+	copied := make(map[string]string, len(annotations))
+	for key, value := range annotations {
+		copied[key] = value
+	}
+	return copied
+}
@@ -2,6 +2,7 @@ package backends
 
 import (
 	"context"
+	"time"
 
 	"go.uber.org/zap"
 	kubeCoreV1 "k8s.io/api/core/v1"
@@ -26,6 +27,14 @@ type Backend interface {
 	SyncStatus(ctx context.Context, loadTest loadTestV1.LoadTest, loadTestStatus *loadTestV1.LoadTestStatus) error
 }
 
+// BackendCapabilities interface can be implemented by backend to describe the load test fields it supports,
+// backends not implementing it are listed without capabilities and their load tests are not checked for ignored fields
+// This method is called by both commands, Proxy and Controller
+type BackendCapabilities interface {
+	// Capabilities describes the load test fields supported by the backend
+	Capabilities() Capabilities
+}
+
 // BackendGetEnvConfig backend must implement this if it has Environment Variables
 // This method is called by both commands, Proxy and Controller
 type BackendGetEnvConfig interface {
@@ -68,6 +77,86 @@ type BackendSetPodTolerations interface {
 	SetPodTolerations([]kubeCoreV1.Toleration)
 }
 
+// BackendSetTagLabels interface can be implemented by backend to receive whether the tags of a loadtest are
+// added as labels to its jobs and pods
+// This method is called only by command Controller
+type BackendSetTagLabels interface {
+	// SetTagLabels gives backend whether to label loadtest jobs and pods with the loadtest tags
+	SetTagLabels(bool)
+}
+
+// BackendSetSeccompProfile interface can be implemented by backend to receive the seccomp profile of loadtest pods
+// This method is called only by command Controller
+type BackendSetSeccompProfile interface {
+	// SetSeccompProfile gives backend the seccomp profile to set in the security context of loadtest pods
+	SetSeccompProfile(*kubeCoreV1.SeccompProfile)
+}
+
+// BackendSetImagePullSecrets interface can be implemented by backend to receive the names of the secrets
+// used to pull the images of loadtest pods from private registries
+// This method is called only by command Controller
+type BackendSetImagePullSecrets interface {
+	// SetImagePullSecrets gives backend the image pull secrets of loadtest pods, overridden per loadtest
+	SetImagePullSecrets([]string)
+}
+
+// BackendSetPodServiceAccount interface can be implemented by backend to receive the name of the service account
+// loadtest pods run with, e.g. to upload reports with cloud workload identities
+// This method is called only by command Controller
+type BackendSetPodServiceAccount interface {
+	// SetPodServiceAccount gives backend the service account of loadtest pods, overridden per loadtest
+	SetPodServiceAccount(string)
+}
+
+// BackendSetTestFileFetcher interface can be implemented by backend to receive the configuration of the
+// init container fetching test files from LoadTestSpec.TestFileURL
+// This method is called only by command Controller
+type BackendSetTestFileFetcher interface {
+	// SetTestFileFetcher gives backend the configuration used to fetch remote test files
+	SetTestFileFetcher(TestFileFetcher)
+}
+
+// BackendSetJobConditions interface can be implemented by backend to receive whether the loadtest
+// phase is determined from the conditions of its jobs before their counters
+// This method is called only by command Controller
+type BackendSetJobConditions interface {
+	// SetJobConditions enables determining the loadtest phase from job conditions
+	SetJobConditions(bool)
+}
+
+// BackendSetJobBackoffLimit interface can be implemented by backend to receive the number of retries
+// of failed loadtest job pods, overriding the backend default
+// This method is called only by command Controller
+type BackendSetJobBackoffLimit interface {
+	// SetJobBackoffLimit gives backend the backoff limit of its jobs
+	SetJobBackoffLimit(int32)
+}
+
+// BackendSetJobTimeout interface can be implemented by backend to receive the default timeout of its jobs,
+// set as their ActiveDeadlineSeconds so that Kubernetes kills runaway loadtests
+// This method is called only by command Controller
+type BackendSetJobTimeout interface {
+	// SetJobTimeout gives backend the default timeout of its jobs, overridden per loadtest
+	SetJobTimeout(time.Duration)
+}
+
+// BackendSyncTimeout interface can be implemented by backend to recommend a time limit for the sync of its
+// loadtests, e.g. when it waits for distributed pods to run. The controller uses the longest of the recommended
+// time limit and SYNC_HANDLER_TIMEOUT, backends not implementing it are synced within SYNC_HANDLER_TIMEOUT
+// This method is called only by command Controller
+type BackendSyncTimeout interface {
+	// SyncTimeout returns the recommended time limit for the sync of a loadtest, 0 for no recommendation
+	SyncTimeout() time.Duration
+}
+
+// BackendCleanup interface can be implemented by backend to remove the state of a loadtest outside of its
+// namespace, e.g. its uploaded report, which is not garbage collected with the loadtest
+// This method is called only by command Controller
+type BackendCleanup interface {
+	// Cleanup is called before the loadtest is deleted, reportURL is the URL its report was uploaded to
+	Cleanup(ctx context.Context, loadTest loadTestV1.LoadTest, reportURL string) error
+}
+
 // BackendSetKubeClientSet interface can be implemented by backend to receive an kubeClientSet
 // This method is called only by command Controller
 type BackendSetKubeClientSet interface {
@@ -88,3 +177,35 @@ type BackendSetNamespaceLister interface {
 	// SetNamespaceLister gives backend a namespaceLister instance
 	SetNamespaceLister(coreListersV1.NamespaceLister)
 }
+
+// BackendValidateConcurrency interface can be implemented by backend to reject load tests
+// that would run without any concurrency, e.g. zero threads or users, and therefore generate no load
+// This method is called by both commands, Proxy and Controller
+type BackendValidateConcurrency interface {
+	// ValidateConcurrency returns an error when the effective concurrency of the spec is zero
+	ValidateConcurrency(spec loadTestV1.LoadTestSpec) error
+}
+
+// BackendEstimateResources interface can be implemented by backend to report the resources requested by
+// the pods of a load test, so load tests exceeding the resource budget can be rejected
+// This method is called only by command Controller
+type BackendEstimateResources interface {
+	// EstimateResources returns the total of resources requested by the pods created for the spec
+	EstimateResources(spec loadTestV1.LoadTestSpec) ResourceEstimate
+}
+
+// BackendInit interface can be implemented by backend that needs to prepare itself before load tests are synced,
+// e.g. by checking its images or external dependencies. Backends are initialized in parallel
+// This method is called only by command Controller
+type BackendInit interface {
+	// Init prepares the backend, it should return once ctx is done
+	Init(ctx context.Context) error
+}
+
+// BackendValidate interface can be implemented by backend to reject load tests with backend specific
+// fields it can't run, e.g. when they are created with kubectl instead of through the Proxy
+// This method is called only by command Webhook
+type BackendValidate interface {
+	// Validate returns an error when the load test is not valid for the backend
+	Validate(loadTest loadTestV1.LoadTest) error
+}
@@ -0,0 +1,97 @@
+package backends
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// ResourceEstimate is the total of resources requested by the pods of a loadtest
+type ResourceEstimate struct {
+	Pods   int32
+	CPU    resource.Quantity
+	Memory resource.Quantity
+}
+
+// EstimatePods returns the estimate of the given number of pods, each requesting the given resources.
+// Limits are used when requests are not set, as Kubernetes defaults requests to limits.
+func EstimatePods(pods int32, resources Resources) ResourceEstimate {
+	estimate := ResourceEstimate{Pods: pods}
+
+	if cpu, ok := parseRequest(resources.CPURequests, resources.CPULimits); ok {
+		estimate.CPU = multiply(cpu, pods)
+	}
+	if memory, ok := parseRequest(resources.MemoryRequests, resources.MemoryLimits); ok {
+		estimate.Memory = multiply(memory, pods)
+	}
+	return estimate
+}
+
+// Add returns the sum of both estimates
+func (e ResourceEstimate) Add(other ResourceEstimate) ResourceEstimate {
+	e.Pods += other.Pods
+	e.CPU = e.CPU.DeepCopy()
+	e.CPU.Add(other.CPU)
+	e.Memory = e.Memory.DeepCopy()
+	e.Memory.Add(other.Memory)
+	return e
+}
+
+// ResourceBudget is the maximum of resources a single loadtest may request, zero values are not limited
+type ResourceBudget struct {
+	Pods   int32
+	CPU    resource.Quantity
+	Memory resource.Quantity
+}
+
+// ParseResourceBudget builds a ResourceBudget from the given quantities, empty quantities are not limited
+func ParseResourceBudget(pods int32, cpu, memory string) (ResourceBudget, error) {
+	budget := ResourceBudget{Pods: pods}
+
+	var err error
+	if cpu != "" {
+		if budget.CPU, err = resource.ParseQuantity(cpu); err != nil {
+			return ResourceBudget{}, fmt.Errorf("invalid CPU budget %q: %w", cpu, err)
+		}
+	}
+	if memory != "" {
+		if budget.Memory, err = resource.ParseQuantity(memory); err != nil {
+			return ResourceBudget{}, fmt.Errorf("invalid memory budget %q: %w", memory, err)
+		}
+	}
+	return budget, nil
+}
+
+// Check returns an error stating the limits and the requests when the estimate exceeds the budget
+func (b ResourceBudget) Check(estimate ResourceEstimate) error {
+	var exceeded []string
+
+	if b.Pods > 0 && estimate.Pods > b.Pods {
+		exceeded = append(exceeded, fmt.Sprintf("%d pods requested, limit is %d", estimate.Pods, b.Pods))
+	}
+	if !b.CPU.IsZero() && estimate.CPU.Cmp(b.CPU) > 0 {
+		exceeded = append(exceeded, fmt.Sprintf("%s CPU requested, limit is %s", estimate.CPU.String(), b.CPU.String()))
+	}
+	if !b.Memory.IsZero() && estimate.Memory.Cmp(b.Memory) > 0 {
+		exceeded = append(exceeded, fmt.Sprintf("%s memory requested, limit is %s", estimate.Memory.String(), b.Memory.String()))
+	}
+
+	if len(exceeded) == 0 {
+		return nil
+	}
+	return fmt.Errorf("loadtest exceeds the resource budget: %s", strings.Join(exceeded, ", "))
+}
+
+func parseRequest(request, limit string) (resource.Quantity, bool) {
+	for _, value := range []string{request, limit} {
+		if quantity, err := resource.ParseQuantity(value); err == nil {
+			return quantity, true
+		}
+	}
+	return resource.Quantity{}, false
+}
+
+func multiply(quantity resource.Quantity, n int32) resource.Quantity {
+	return *resource.NewMilliQuantity(quantity.MilliValue()*int64(n), quantity.Format)
+}
@@ -0,0 +1,68 @@
+package backends_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/hellofresh/kangal/pkg/backends"
+)
+
+func TestEstimatePods(t *testing.T) {
+	estimate := backends.EstimatePods(1, backends.Resources{
+		CPURequests:  "500m",
+		CPULimits:    "1",
+		MemoryLimits: "1Gi",
+	}).Add(backends.EstimatePods(2, backends.Resources{
+		CPURequests:    "250m",
+		MemoryRequests: "512Mi",
+	}))
+
+	assert.Equal(t, int32(3), estimate.Pods)
+	assert.Equal(t, 0, estimate.CPU.Cmp(resource.MustParse("1")))
+	assert.Equal(t, 0, estimate.Memory.Cmp(resource.MustParse("2Gi")))
+}
+
+func TestResourceBudgetCheck(t *testing.T) {
+	budget, err := backends.ParseResourceBudget(5, "2", "4Gi")
+	require.NoError(t, err)
+
+	for _, tt := range []struct {
+		name      string
+		resources backends.Resources
+		pods      int32
+		expectErr bool
+	}{
+		{"within budget", backends.Resources{CPURequests: "400m", MemoryRequests: "800Mi"}, 5, false},
+		{"no resources set", backends.Resources{}, 5, false},
+		{"too many pods", backends.Resources{}, 6, true},
+		{"too much CPU", backends.Resources{CPURequests: "500m"}, 5, true},
+		{"too much memory", backends.Resources{MemoryLimits: "1Gi"}, 5, true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			err := budget.Check(backends.EstimatePods(tt.pods, tt.resources))
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+
+	assert.NoError(t, backends.ResourceBudget{}.Check(backends.EstimatePods(100, backends.Resources{CPURequests: "10"})))
+}
+
+func TestParseResourceBudget(t *testing.T) {
+	_, err := backends.ParseResourceBudget(0, "two", "")
+	assert.Error(t, err)
+
+	_, err = backends.ParseResourceBudget(0, "", "lots")
+	assert.Error(t, err)
+
+	budget, err := backends.ParseResourceBudget(0, "", "")
+	require.NoError(t, err)
+	assert.True(t, budget.CPU.IsZero())
+	assert.True(t, budget.Memory.IsZero())
+}
@@ -0,0 +1,152 @@
+package backends
+
+import (
+	"sort"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+// Capabilities describes which load test fields a backend supports, so clients can render a form per backend
+type Capabilities struct {
+	// Type is the backend type, it is set by the registry
+	Type loadTestV1.LoadTestType `json:"type"`
+	// Distributed is true when the load test can run on more than one pod
+	Distributed bool `json:"distributed"`
+	// TestFileFormats are the extensions of the test files the backend accepts
+	TestFileFormats []string `json:"testFileFormats,omitempty"`
+	// TestDataFormats are the extensions of the test data files the backend accepts
+	TestDataFormats []string `json:"testDataFormats,omitempty"`
+	// RequiredFields are the load test fields the backend requires
+	RequiredFields []string `json:"requiredFields,omitempty"`
+	// OptionalFields are the other load test fields the backend uses
+	OptionalFields []string `json:"optionalFields,omitempty"`
+}
+
+// Capabilities returns the capabilities of the registered backends, sorted by type. Backends not implementing
+// BackendCapabilities are listed with their type only.
+func (b *registry) Capabilities() []Capabilities {
+	capabilities := make([]Capabilities, 0, len(b.registry))
+	for loadTestType, reg := range b.registry {
+		if b.disabled[loadTestType] {
+			continue
+		}
+		c := Capabilities{}
+		if describer, ok := reg.(BackendCapabilities); ok {
+			c = describer.Capabilities()
+		}
+		c.Type = loadTestType
+		capabilities = append(capabilities, c)
+	}
+
+	sort.Slice(capabilities, func(i, j int) bool {
+		return capabilities[i].Type < capabilities[j].Type
+	})
+
+	return capabilities
+}
+
+// Requires returns true if the backend requires the given load test field
+func (c Capabilities) Requires(field string) bool {
+	for _, required := range c.RequiredFields {
+		if required == field {
+			return true
+		}
+	}
+	return false
+}
+
+// Supports returns true if the backend requires or uses the given load test field
+func (c Capabilities) Supports(field string) bool {
+	if c.Requires(field) {
+		return true
+	}
+	for _, optional := range c.OptionalFields {
+		if optional == field {
+			return true
+		}
+	}
+	return false
+}
+
+// SupportsThresholds returns true if the backend fills in the summary of its load tests, which their thresholds
+// are evaluated against. Backends not implementing BackendCapabilities don't support thresholds
+func SupportsThresholds(backend Backend) bool {
+	describer, ok := backend.(BackendCapabilities)
+	if !ok {
+		return false
+	}
+	return describer.Capabilities().Supports("thresholds")
+}
+
+// RequiresTestFile returns true if load tests of the backend need a testFile or testFileURL, backends not
+// implementing BackendCapabilities always need one
+func RequiresTestFile(backend Backend) bool {
+	describer, ok := backend.(BackendCapabilities)
+	if !ok {
+		return true
+	}
+	return describer.Capabilities().Requires("testFile")
+}
+
+// UnsupportedFields returns the backend specific fields set in the spec which are neither required nor optional
+// for the backend, the backend ignores them
+func (c Capabilities) UnsupportedFields(spec loadTestV1.LoadTestSpec) []string {
+	supported := make(map[string]bool, len(c.RequiredFields)+len(c.OptionalFields))
+	for _, field := range c.RequiredFields {
+		supported[field] = true
+	}
+	for _, field := range c.OptionalFields {
+		supported[field] = true
+	}
+
+	var unsupported []string
+	for _, field := range setSpecFields(spec) {
+		if !supported[field] {
+			unsupported = append(unsupported, field)
+		}
+	}
+	return unsupported
+}
+
+// setSpecFields returns the backend specific fields set in the spec, fields handled by the controller
+// for every backend, e.g. dependsOn or podAnnotations, are left out
+func setSpecFields(spec loadTestV1.LoadTestSpec) []string {
+	resources := loadTestV1.LoadTestResources{}
+	if spec.Resources != nil {
+		resources = *spec.Resources
+	}
+
+	var fields []string
+	for _, field := range []struct {
+		name string
+		set  bool
+	}{
+		{"testFileURL", spec.TestFileURL != ""},
+		{"testData", len(spec.TestData) != 0},
+		{"envVars", len(spec.EnvVars) != 0},
+		{"targetURL", spec.TargetURL != ""},
+		{"duration", spec.Duration != 0},
+		{"rate", spec.Rate != ""},
+		{"vus", spec.VUs != 0},
+		{"connections", spec.Connections != 0},
+		{"threads", spec.Threads != 0},
+		{"args", len(spec.Args) != 0},
+		{"reportFormat", spec.ReportFormat != ""},
+		{"initContainers", len(spec.InitContainers) != 0},
+		{"sidecars", len(spec.Sidecars) != 0},
+		{"envFrom", len(spec.EnvFrom) != 0},
+		{"serviceAccountName", spec.ServiceAccountName != ""},
+		{"thresholds", len(spec.Thresholds) != 0},
+		{"masterImage", spec.MasterConfig != ""},
+		{"workerImage", spec.WorkerConfig != ""},
+		{"cpuLimits", resources.CPULimits != ""},
+		{"cpuRequests", resources.CPURequests != ""},
+		{"memoryLimits", resources.MemoryLimits != ""},
+		{"memoryRequests", resources.MemoryRequests != ""},
+	} {
+		if field.set {
+			fields = append(fields, field.name)
+		}
+	}
+	return fields
+}
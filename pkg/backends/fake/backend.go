@@ -36,6 +36,11 @@ func (*Backend) Type() loadTestV1.LoadTestType {
 	return loadTestV1.LoadTestTypeFake
 }
 
+// Capabilities returns the load test fields supported by the Fake backend
+func (*Backend) Capabilities() backends.Capabilities {
+	return backends.Capabilities{}
+}
+
 // SetDefaults must set default values
 func (b *Backend) SetDefaults() {
 	b.config = loadTestV1.ImageDetails(fmt.Sprintf("%s:%s", imageName, imageTag))
@@ -125,7 +130,7 @@ func (b *Backend) newMasterJob(loadTest loadTestV1.LoadTest) *batchV1.Job {
 
 	// For fake provider we don't really create load test and just use alpine image with some sleep
 	// to simulate load test job. Please don't use Fake provider in production.
-	return &batchV1.Job{
+	job := &batchV1.Job{
 		ObjectMeta: metaV1.ObjectMeta{
 			Name: "loadtest-master",
 			Labels: map[string]string{
@@ -157,6 +162,8 @@ func (b *Backend) newMasterJob(loadTest loadTestV1.LoadTest) *batchV1.Job {
 			},
 		},
 	}
+	backends.SetSpecHash(job, loadTest)
+	return job
 }
 
 // determineLoadTestPhaseFromJob reads existing job statuses and determines what the loadtest phase should be
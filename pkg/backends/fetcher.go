@@ -0,0 +1,149 @@
+package backends
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	coreV1 "k8s.io/api/core/v1"
+)
+
+const (
+	// TestFileFetcherContainerName is the name of the init container fetching remote test files
+	TestFileFetcherContainerName = "testfile-fetcher"
+	// TestFileCredentialsSecretName is the name of the optional Secret in the load test namespace holding
+	// the rclone environment variables used to fetch test files from object storage
+	TestFileCredentialsSecretName = "testfile-credentials"
+
+	testFileFetcherMountPath = "/fetch"
+)
+
+// objectStorageRemotes maps the supported object storage URL schemes to rclone backends
+var objectStorageRemotes = map[string]string{
+	"s3": "s3",
+	"gs": "gcs",
+}
+
+// TestFileFetcher configures the init container fetching test files from LoadTestSpec.TestFileURL
+type TestFileFetcher struct {
+	// Image fetches test files from HTTP URLs, it must be curl compatible
+	Image string
+	// ObjectStorageImage fetches test files from s3:// and gs:// URLs, it must contain rclone
+	ObjectStorageImage string
+	// MaxSize is the maximum size of a test file in bytes, 0 means no limit
+	MaxSize int64
+}
+
+// IsObjectStorageURL tells if the given test file URL points to object storage instead of an HTTP server
+func IsObjectStorageURL(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	_, ok := objectStorageRemotes[u.Scheme]
+	return ok
+}
+
+// Inject makes the test file volume of the given pod an emptyDir volume and adds an init
+// container that downloads the test file from url into it as fileName, so containers mounting the volume
+// find the test file where they would find it in the ConfigMap. HTTP URLs are fetched with Image, that
+// gets the url and the destination path as curl compatible arguments and as TEST_FILE_URL and
+// TEST_FILE_PATH env vars. Object storage URLs are fetched with rclone from ObjectStorageImage,
+// using the credentials of the TestFileCredentialsSecretName Secret when it exists.
+func (f TestFileFetcher) Inject(podSpec *coreV1.PodSpec, url, volumeName, fileName string) {
+	testFileVolume := coreV1.Volume{
+		Name:         volumeName,
+		VolumeSource: coreV1.VolumeSource{EmptyDir: &coreV1.EmptyDirVolumeSource{}},
+	}
+
+	// volumes are copied as the slice may be shared with other pods
+	volumes := make([]coreV1.Volume, 0, len(podSpec.Volumes)+1)
+	found := false
+	for _, v := range podSpec.Volumes {
+		if v.Name == volumeName {
+			v = testFileVolume
+			found = true
+		}
+		volumes = append(volumes, v)
+	}
+	if !found {
+		volumes = append(volumes, testFileVolume)
+	}
+	podSpec.Volumes = volumes
+
+	path := fmt.Sprintf("%s/%s", testFileFetcherMountPath, fileName)
+
+	var container coreV1.Container
+	if IsObjectStorageURL(url) {
+		container = f.objectStorageContainer(url, path)
+	} else {
+		container = f.httpContainer(url, path)
+	}
+
+	container.Name = TestFileFetcherContainerName
+	container.ImagePullPolicy = coreV1.PullIfNotPresent
+	// the last lines of the fetcher output explain auth, not found and size errors in the pod status
+	container.TerminationMessagePolicy = coreV1.TerminationMessageFallbackToLogsOnError
+	container.Env = append([]coreV1.EnvVar{
+		{Name: "TEST_FILE_URL", Value: url},
+		{Name: "TEST_FILE_PATH", Value: path},
+	}, container.Env...)
+	container.VolumeMounts = []coreV1.VolumeMount{
+		{
+			Name:      volumeName,
+			MountPath: testFileFetcherMountPath,
+		},
+	}
+
+	podSpec.InitContainers = append(podSpec.InitContainers, container)
+}
+
+func (f TestFileFetcher) httpContainer(url, path string) coreV1.Container {
+	args := []string{"--fail", "--silent", "--show-error", "--location"}
+	if f.MaxSize > 0 {
+		args = append(args, "--max-filesize", strconv.FormatInt(f.MaxSize, 10))
+	}
+	args = append(args, "--output", path, url)
+
+	return coreV1.Container{
+		Image: f.Image,
+		Args:  args,
+	}
+}
+
+func (f TestFileFetcher) objectStorageContainer(rawURL, path string) coreV1.Container {
+	// IsObjectStorageURL already parsed the URL successfully
+	u, _ := url.Parse(rawURL)
+	remote := fmt.Sprintf(":%s:%s/%s", objectStorageRemotes[u.Scheme], u.Host, strings.TrimPrefix(u.Path, "/"))
+
+	script := `rclone copyto "$TEST_FILE_REMOTE" "$TEST_FILE_PATH"`
+	if f.MaxSize > 0 {
+		// rclone skips files over --max-size silently, a missing test file means it is too large
+		script = fmt.Sprintf(`%s --max-size %dB || exit 1
+test -f "$TEST_FILE_PATH" || { echo "test file $TEST_FILE_URL is larger than %d bytes" >&2; exit 1; }`, script, f.MaxSize, f.MaxSize)
+	}
+
+	return coreV1.Container{
+		Image:   f.ObjectStorageImage,
+		Command: []string{"/bin/sh", "-c"},
+		Args:    []string{script},
+		Env: []coreV1.EnvVar{
+			{Name: "TEST_FILE_REMOTE", Value: remote},
+		},
+		EnvFrom: []coreV1.EnvFromSource{
+			{
+				SecretRef: &coreV1.SecretEnvSource{
+					LocalObjectReference: coreV1.LocalObjectReference{
+						Name: TestFileCredentialsSecretName,
+					},
+					Optional: boolPtr(true),
+				},
+			},
+		},
+	}
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
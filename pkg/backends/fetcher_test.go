@@ -0,0 +1,136 @@
+package backends
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	coreV1 "k8s.io/api/core/v1"
+)
+
+func TestTestFileFetcherInject(t *testing.T) {
+	podSpec := coreV1.PodSpec{
+		Volumes: []coreV1.Volume{
+			{
+				Name: "testfile",
+				VolumeSource: coreV1.VolumeSource{
+					ConfigMap: &coreV1.ConfigMapVolumeSource{
+						LocalObjectReference: coreV1.LocalObjectReference{Name: "loadtest-testfile"},
+					},
+				},
+			},
+			{
+				Name:         "other",
+				VolumeSource: coreV1.VolumeSource{EmptyDir: &coreV1.EmptyDirVolumeSource{}},
+			},
+		},
+	}
+
+	TestFileFetcher{Image: "fetcher:latest"}.Inject(&podSpec, "https://example.com/test.js", "testfile", "test.js")
+
+	require.Len(t, podSpec.Volumes, 2)
+	assert.Nil(t, podSpec.Volumes[0].ConfigMap)
+	assert.NotNil(t, podSpec.Volumes[0].EmptyDir)
+
+	require.Len(t, podSpec.InitContainers, 1)
+	fetcher := podSpec.InitContainers[0]
+	assert.Equal(t, "fetcher:latest", fetcher.Image)
+	assert.Equal(t, []string{"--fail", "--silent", "--show-error", "--location", "--output", "/fetch/test.js", "https://example.com/test.js"}, fetcher.Args)
+	assert.Contains(t, fetcher.Env, coreV1.EnvVar{Name: "TEST_FILE_URL", Value: "https://example.com/test.js"})
+	assert.Contains(t, fetcher.Env, coreV1.EnvVar{Name: "TEST_FILE_PATH", Value: "/fetch/test.js"})
+	assert.Equal(t, []coreV1.VolumeMount{{Name: "testfile", MountPath: "/fetch"}}, fetcher.VolumeMounts)
+}
+
+func TestTestFileFetcherInjectMissingVolume(t *testing.T) {
+	podSpec := coreV1.PodSpec{}
+
+	TestFileFetcher{Image: "fetcher:latest"}.Inject(&podSpec, "https://example.com/test.js", "testfile", "test.js")
+
+	require.Len(t, podSpec.Volumes, 1)
+	assert.Equal(t, "testfile", podSpec.Volumes[0].Name)
+	assert.NotNil(t, podSpec.Volumes[0].EmptyDir)
+}
+
+func TestTestFileFetcherInjectSharedVolumes(t *testing.T) {
+	// backends build the volumes of several pods from the same slice
+	volumes := []coreV1.Volume{{
+		Name: "testfile",
+		VolumeSource: coreV1.VolumeSource{
+			ConfigMap: &coreV1.ConfigMapVolumeSource{LocalObjectReference: coreV1.LocalObjectReference{Name: "loadtest-testfile"}},
+		},
+	}}
+	podSpec := coreV1.PodSpec{
+		Volumes:        volumes,
+		InitContainers: []coreV1.Container{{Name: "seed"}},
+	}
+
+	TestFileFetcher{Image: "fetcher:latest"}.Inject(&podSpec, "https://example.com/test.js", "testfile", "test.js")
+
+	assert.NotNil(t, volumes[0].ConfigMap, "Expected the shared volumes not to be modified")
+	assert.NotNil(t, podSpec.Volumes[0].EmptyDir)
+
+	// the fetcher runs after the init containers already in the pod
+	require.Len(t, podSpec.InitContainers, 2)
+	assert.Equal(t, "seed", podSpec.InitContainers[0].Name)
+	assert.Equal(t, TestFileFetcherContainerName, podSpec.InitContainers[1].Name)
+	assert.Equal(t, coreV1.TerminationMessageFallbackToLogsOnError, podSpec.InitContainers[1].TerminationMessagePolicy)
+}
+
+func TestTestFileFetcherInjectMaxSize(t *testing.T) {
+	podSpec := coreV1.PodSpec{}
+
+	TestFileFetcher{Image: "fetcher:latest", MaxSize: 1024}.Inject(&podSpec, "https://example.com/test.js", "testfile", "test.js")
+
+	require.Len(t, podSpec.InitContainers, 1)
+	assert.Equal(t, []string{"--fail", "--silent", "--show-error", "--location", "--max-filesize", "1024", "--output", "/fetch/test.js", "https://example.com/test.js"}, podSpec.InitContainers[0].Args)
+}
+
+func TestTestFileFetcherInjectObjectStorage(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		url     string
+		maxSize int64
+		remote  string
+		script  string
+	}{
+		{
+			name:   "s3 without size cap",
+			url:    "s3://bucket/tests/test.js",
+			remote: ":s3:bucket/tests/test.js",
+			script: `rclone copyto "$TEST_FILE_REMOTE" "$TEST_FILE_PATH"`,
+		},
+		{
+			name:    "gs with size cap",
+			url:     "gs://bucket/test.js",
+			maxSize: 1024,
+			remote:  ":gcs:bucket/test.js",
+			script: `rclone copyto "$TEST_FILE_REMOTE" "$TEST_FILE_PATH" --max-size 1024B || exit 1
+test -f "$TEST_FILE_PATH" || { echo "test file $TEST_FILE_URL is larger than 1024 bytes" >&2; exit 1; }`,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			podSpec := coreV1.PodSpec{}
+
+			fetcher := TestFileFetcher{Image: "fetcher:latest", ObjectStorageImage: "rclone:latest", MaxSize: tt.maxSize}
+			fetcher.Inject(&podSpec, tt.url, "testfile", "test.js")
+
+			require.Len(t, podSpec.InitContainers, 1)
+			container := podSpec.InitContainers[0]
+			assert.Equal(t, "rclone:latest", container.Image)
+			assert.Equal(t, []string{"/bin/sh", "-c"}, container.Command)
+			assert.Equal(t, []string{tt.script}, container.Args)
+			assert.Equal(t, coreV1.TerminationMessageFallbackToLogsOnError, container.TerminationMessagePolicy)
+			assert.Contains(t, container.Env, coreV1.EnvVar{Name: "TEST_FILE_REMOTE", Value: tt.remote})
+			assert.Contains(t, container.Env, coreV1.EnvVar{Name: "TEST_FILE_PATH", Value: "/fetch/test.js"})
+			require.Len(t, container.EnvFrom, 1)
+			assert.Equal(t, TestFileCredentialsSecretName, container.EnvFrom[0].SecretRef.Name)
+		})
+	}
+}
+
+func TestIsObjectStorageURL(t *testing.T) {
+	assert.True(t, IsObjectStorageURL("s3://bucket/test.js"))
+	assert.True(t, IsObjectStorageURL("gs://bucket/test.js"))
+	assert.False(t, IsObjectStorageURL("https://example.com/test.js"))
+	assert.False(t, IsObjectStorageURL(":not a url"))
+}
@@ -0,0 +1,270 @@
+package gatling
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	coreV1 "k8s.io/api/core/v1"
+	k8sAPIErrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/hellofresh/kangal/pkg/backends"
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+var (
+	// ErrRequireMinOneDistributedPod Backend spec requires 1 or more DistributedPods
+	ErrRequireMinOneDistributedPod = errors.New("LoadTest must specify 1 or more DistributedPods")
+	// ErrRequireTestFile the TestFile field is required to not be an empty string
+	ErrRequireTestFile = errors.New("LoadTest TestFile is required")
+	// ErrRequireSimulation the TestFile must define a class extending Simulation, which is the simulation run
+	ErrRequireSimulation = errors.New("LoadTest TestFile must define a class extending Simulation")
+)
+
+func init() {
+	backends.Register(&Backend{})
+}
+
+// Backend is the Gatling implementation of backend interface
+type Backend struct {
+	logger           *zap.Logger
+	kubeClientSet    kubernetes.Interface
+	config           *Config
+	podAnnotations   map[string]string
+	nodeSelector     map[string]string
+	tolerations      []coreV1.Toleration
+	seccompProfile   *coreV1.SeccompProfile
+	imagePullSecrets []string
+
+	jobConditions   bool
+	jobBackoffLimit *int32
+	jobTimeout      time.Duration
+
+	// defined on SetDefaults
+	image        loadTestV1.ImageDetails
+	resources    backends.Resources
+	startupProbe *coreV1.Probe
+
+	backendPodAnnotations map[string]string
+}
+
+// Type returns backend type name
+func (*Backend) Type() loadTestV1.LoadTestType {
+	return loadTestV1.LoadTestTypeGatling
+}
+
+// Capabilities returns the load test fields supported by the Gatling backend
+func (*Backend) Capabilities() backends.Capabilities {
+	return backends.Capabilities{
+		Distributed:     true,
+		TestFileFormats: []string{"scala", "java"},
+		TestDataFormats: []string{"tar"},
+		RequiredFields:  []string{"distributedPods", "testFile"},
+		OptionalFields: []string{"testData", "masterImage",
+			"cpuLimits", "cpuRequests", "memoryLimits", "memoryRequests"},
+	}
+}
+
+// GetEnvConfig must return config struct pointer
+func (b *Backend) GetEnvConfig() interface{} {
+	b.config = &Config{}
+	return b.config
+}
+
+// SetDefaults must set default values
+func (b *Backend) SetDefaults() {
+	b.image = loadTestV1.ImageDetails(fmt.Sprintf("%s:%s", b.config.ImageName, b.config.ImageTag))
+
+	b.resources = backends.Resources{
+		CPULimits:      b.config.CPULimits,
+		CPURequests:    b.config.CPURequests,
+		MemoryLimits:   b.config.MemoryLimits,
+		MemoryRequests: b.config.MemoryRequests,
+	}
+
+	b.startupProbe = backends.BuildStartupProbe(b.config.StartupProbe)
+	b.backendPodAnnotations = b.config.PodAnnotations
+}
+
+// SetPodAnnotations receives a copy of pod annotations
+func (b *Backend) SetPodAnnotations(podAnnotations map[string]string) {
+	b.podAnnotations = podAnnotations
+}
+
+// SetKubeClientSet receives a copy of kubeClientSet
+func (b *Backend) SetKubeClientSet(kubeClientSet kubernetes.Interface) {
+	b.kubeClientSet = kubeClientSet
+}
+
+// SetLogger receives a copy of logger
+func (b *Backend) SetLogger(logger *zap.Logger) {
+	b.logger = logger
+}
+
+// SetPodNodeSelector receives a copy of pod node selectors
+func (b *Backend) SetPodNodeSelector(nodeselector map[string]string) {
+	b.nodeSelector = nodeselector
+}
+
+// SetJobConditions receives whether the loadtest phase is determined from job conditions first
+func (b *Backend) SetJobConditions(enabled bool) {
+	b.jobConditions = enabled
+}
+
+// SetJobBackoffLimit receives the number of retries of failed job pods
+func (b *Backend) SetJobBackoffLimit(limit int32) {
+	b.jobBackoffLimit = &limit
+}
+
+// SetJobTimeout receives the default timeout of loadtest jobs
+func (b *Backend) SetJobTimeout(timeout time.Duration) {
+	b.jobTimeout = timeout
+}
+
+// SetPodTolerations receives a copy of pod tolerations
+func (b *Backend) SetPodTolerations(tolerations []coreV1.Toleration) {
+	b.tolerations = tolerations
+}
+
+// SetImagePullSecrets receives the image pull secrets of the loadtest pods
+func (b *Backend) SetImagePullSecrets(secrets []string) {
+	b.imagePullSecrets = secrets
+}
+
+// SetSeccompProfile receives the seccomp profile of the loadtest pods
+func (b *Backend) SetSeccompProfile(profile *coreV1.SeccompProfile) {
+	b.seccompProfile = profile
+}
+
+// TransformLoadTestSpec use given spec to validate and return a new one or error
+func (b *Backend) TransformLoadTestSpec(spec *loadTestV1.LoadTestSpec) error {
+	if nil == spec.DistributedPods {
+		return ErrRequireMinOneDistributedPod
+	}
+
+	if *spec.DistributedPods <= int32(0) {
+		return ErrRequireMinOneDistributedPod
+	}
+
+	if len(spec.TestFile) == 0 {
+		return ErrRequireTestFile
+	}
+
+	if _, err := parseSimulation(spec.TestFile); err != nil {
+		return err
+	}
+
+	if err := backends.ValidateResources(b.resources, spec.Resources); err != nil {
+		return err
+	}
+
+	if spec.MasterConfig == "" {
+		spec.MasterConfig = b.image
+	}
+
+	return nil
+}
+
+// EstimateResources returns the resources requested by the Gatling pods
+func (b *Backend) EstimateResources(spec loadTestV1.LoadTestSpec) backends.ResourceEstimate {
+	var pods int32
+	if spec.DistributedPods != nil {
+		pods = *spec.DistributedPods
+	}
+	return backends.EstimatePods(pods, backends.OverrideResources(b.resources, spec.Resources))
+}
+
+// Sync checks if Gatling kubernetes resources have been created, create them if they haven't
+func (b *Backend) Sync(ctx context.Context, loadTest loadTestV1.LoadTest, reportURL string) error {
+	jobs, err := b.kubeClientSet.
+		BatchV1().
+		Jobs(loadTest.Status.Namespace).
+		List(ctx, metaV1.ListOptions{
+			FieldSelector: fmt.Sprintf("metadata.name=%s", loadTestJobName),
+		})
+	if err != nil {
+		b.logger.Error("Error on listing jobs", zap.Error(err))
+		return err
+	}
+
+	// Jobs already created, do nothing
+	if len(jobs.Items) > 0 {
+		return nil
+	}
+
+	sim, err := parseSimulation(loadTest.Spec.TestFile)
+	if err != nil {
+		b.logger.Error("Error parsing simulation", zap.String("loadtest", loadTest.GetName()), zap.Error(err))
+		return err
+	}
+
+	tfCfgMap, err := NewFileConfigMap(loadTest, loadTestFileConfigMapName, sim.fileName, loadTest.Spec.TestFile)
+	if err != nil {
+		b.logger.Error("Error creating testfile configmap resource", zap.Error(err))
+		return err
+	}
+	configMaps := []*coreV1.ConfigMap{tfCfgMap}
+
+	if len(loadTest.Spec.TestData) != 0 {
+		rsCfgMap, err := NewFileConfigMap(loadTest, loadTestResourcesConfigMapName, resourcesFileName, loadTest.Spec.TestData)
+		if err != nil {
+			b.logger.Error("Error creating resources configmap resource", zap.Error(err))
+			return err
+		}
+		configMaps = append(configMaps, rsCfgMap)
+	}
+
+	// Create simulation and resources configmaps
+	for _, cfg := range configMaps {
+		_, err = b.kubeClientSet.
+			CoreV1().
+			ConfigMaps(loadTest.Status.Namespace).
+			Create(ctx, cfg, metaV1.CreateOptions{})
+		if err != nil && !k8sAPIErrors.IsAlreadyExists(err) {
+			b.logger.Error("Error creating configmap", zap.String("configmap", cfg.GetName()), zap.Error(err))
+			return err
+		}
+	}
+
+	// Create Job
+	job := b.NewJob(loadTest, sim, reportURL)
+	_, err = b.kubeClientSet.
+		BatchV1().
+		Jobs(loadTest.Status.Namespace).
+		Create(ctx, job, metaV1.CreateOptions{})
+	if err != nil && !k8sAPIErrors.IsAlreadyExists(err) {
+		b.logger.Error("Error on creating job", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// SyncStatus checks Gatling resources and updates the status of the LoadTest resource
+func (b *Backend) SyncStatus(ctx context.Context, _ loadTestV1.LoadTest, loadTestStatus *loadTestV1.LoadTestStatus) error {
+	if loadTestStatus.Phase == "" {
+		loadTestStatus.Phase = loadTestV1.LoadTestCreating
+		return nil
+	}
+
+	if loadTestStatus.Phase == loadTestV1.LoadTestErrored {
+		return nil
+	}
+
+	job, err := b.kubeClientSet.
+		BatchV1().
+		Jobs(loadTestStatus.Namespace).
+		Get(ctx, loadTestJobName, metaV1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	loadTestStatus.Phase = backends.PreferJobConditions(b.jobConditions, determineLoadTestStatusFromJobs(job), job.Status)
+	loadTestStatus.JobStatus = job.Status
+	backends.SetPodCounters(&loadTestStatus.Pods, job.Status)
+	return nil
+}
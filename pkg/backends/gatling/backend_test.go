@@ -0,0 +1,151 @@
+package gatling
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+	batchV1 "k8s.io/api/batch/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+func TestSync(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	kubeClient := k8sfake.NewSimpleClientset()
+	namespace := "test"
+	distributedPods := int32(2)
+
+	loadTest := loadTestV1.LoadTest{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name: "loadtest-name",
+		},
+		Spec: loadTestV1.LoadTestSpec{
+			DistributedPods: &distributedPods,
+			TestFile:        []byte(scalaSimulation),
+			TestData:        []byte("resources"),
+		},
+		Status: loadTestV1.LoadTestStatus{
+			Phase:     "running",
+			Namespace: namespace,
+		},
+	}
+
+	b := Backend{
+		logger:        zaptest.NewLogger(t),
+		kubeClientSet: kubeClient,
+	}
+
+	err := b.Sync(ctx, loadTest, "http://kangal-proxy.local/load-test/loadtest-name/report")
+	require.NoError(t, err, "Error when Sync")
+
+	jobs, err := kubeClient.BatchV1().Jobs(namespace).List(ctx, metaV1.ListOptions{})
+	require.NoError(t, err, "Error when listing jobs")
+	assert.Len(t, jobs.Items, 1)
+
+	testFile, err := kubeClient.CoreV1().ConfigMaps(namespace).Get(ctx, loadTestFileConfigMapName, metaV1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, []byte(scalaSimulation), testFile.BinaryData["BasicSimulation.scala"])
+
+	resources, err := kubeClient.CoreV1().ConfigMaps(namespace).Get(ctx, loadTestResourcesConfigMapName, metaV1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, []byte("resources"), resources.BinaryData[resourcesFileName])
+}
+
+func TestSyncStatus(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	kubeClient := k8sfake.NewSimpleClientset()
+	namespace := "test"
+	distributedPods := int32(1)
+
+	loadTest := loadTestV1.LoadTest{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name: "loadtest-name",
+		},
+		Spec: loadTestV1.LoadTestSpec{
+			DistributedPods: &distributedPods,
+			TestFile:        []byte(scalaSimulation),
+		},
+		Status: loadTestV1.LoadTestStatus{
+			Namespace: namespace,
+		},
+	}
+
+	b := Backend{
+		logger:        zaptest.NewLogger(t),
+		kubeClientSet: kubeClient,
+	}
+
+	err := b.Sync(ctx, loadTest, "")
+	require.NoError(t, err, "Sync error")
+
+	// First sync should update status to creating
+	err = b.SyncStatus(ctx, loadTest, &loadTest.Status)
+	require.NoError(t, err, "SyncStatus error")
+	assert.Equal(t, loadTestV1.LoadTestCreating, loadTest.Status.Phase)
+
+	job, err := kubeClient.BatchV1().Jobs(namespace).Get(ctx, loadTestJobName, metaV1.GetOptions{})
+	require.NoError(t, err)
+	job.Status = batchV1.JobStatus{Active: 1}
+	_, err = kubeClient.BatchV1().Jobs(namespace).UpdateStatus(ctx, job, metaV1.UpdateOptions{})
+	require.NoError(t, err)
+
+	err = b.SyncStatus(ctx, loadTest, &loadTest.Status)
+	require.NoError(t, err, "SyncStatus error")
+	assert.Equal(t, loadTestV1.LoadTestRunning, loadTest.Status.Phase)
+	assert.Equal(t, int32(1), loadTest.Status.Pods.Active)
+}
+
+func TestTransformLoadTestSpec(t *testing.T) {
+	distributedPods := int32(2)
+	noPods := int32(0)
+
+	b := Backend{image: "denvazh/gatling:latest"}
+
+	for _, tt := range []struct {
+		name          string
+		spec          loadTestV1.LoadTestSpec
+		expectedError error
+	}{
+		{
+			name:          "missing distributed pods",
+			spec:          loadTestV1.LoadTestSpec{TestFile: []byte(scalaSimulation)},
+			expectedError: ErrRequireMinOneDistributedPod,
+		},
+		{
+			name:          "zero distributed pods",
+			spec:          loadTestV1.LoadTestSpec{DistributedPods: &noPods, TestFile: []byte(scalaSimulation)},
+			expectedError: ErrRequireMinOneDistributedPod,
+		},
+		{
+			name:          "missing test file",
+			spec:          loadTestV1.LoadTestSpec{DistributedPods: &distributedPods},
+			expectedError: ErrRequireTestFile,
+		},
+		{
+			name:          "no simulation",
+			spec:          loadTestV1.LoadTestSpec{DistributedPods: &distributedPods, TestFile: []byte("object Helper")},
+			expectedError: ErrRequireSimulation,
+		},
+		{
+			name: "valid",
+			spec: loadTestV1.LoadTestSpec{DistributedPods: &distributedPods, TestFile: []byte(javaSimulation)},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			err := b.TransformLoadTestSpec(&tt.spec)
+			assert.ErrorIs(t, err, tt.expectedError)
+			if tt.expectedError == nil {
+				assert.Equal(t, loadTestV1.ImageDetails("denvazh/gatling:latest"), tt.spec.MasterConfig)
+			}
+		})
+	}
+}
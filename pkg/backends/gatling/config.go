@@ -0,0 +1,18 @@
+package gatling
+
+import "github.com/hellofresh/kangal/pkg/backends"
+
+// Config specific to Gatling backend
+type Config struct {
+	ImageName      string `envconfig:"GATLING_IMAGE_NAME" default:"denvazh/gatling"`
+	ImageTag       string `envconfig:"GATLING_IMAGE_TAG" default:"latest"`
+	CPULimits      string `envconfig:"GATLING_CPU_LIMITS"`
+	CPURequests    string `envconfig:"GATLING_CPU_REQUESTS"`
+	MemoryLimits   string `envconfig:"GATLING_MEMORY_LIMITS"`
+	MemoryRequests string `envconfig:"GATLING_MEMORY_REQUESTS"`
+
+	StartupProbe backends.StartupProbe `envconfig:"GATLING_STARTUP_PROBE"`
+
+	// PodAnnotations are the default annotations of the loadtest pods of the backend
+	PodAnnotations map[string]string `envconfig:"GATLING_POD_ANNOTATIONS"`
+}
@@ -0,0 +1,216 @@
+package gatling
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+
+	"go.uber.org/zap"
+	batchV1 "k8s.io/api/batch/v1"
+	coreV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/hellofresh/kangal/pkg/backends"
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+const (
+	loadTestJobName                = "loadtest-job"
+	loadTestFileConfigMapName      = "loadtest-testfile"
+	loadTestResourcesConfigMapName = "loadtest-resources"
+	loadTestFileVolumeName         = "loadtest-testfile-volume"
+	loadTestResourcesVolumeName    = "loadtest-resources-volume"
+
+	simulationsDir    = "/data/simulations"
+	resourcesFileName = "resources.tar"
+)
+
+var (
+	packageRegexp    = regexp.MustCompile(`(?m)^\s*package\s+([\w.]+)`)
+	simulationRegexp = regexp.MustCompile(`class\s+(\w+)(?:\s*\([^)]*\))?\s+extends\s+Simulation\b`)
+	javaClassRegexp  = regexp.MustCompile(`public\s+(?:final\s+)?class\s+\w+`)
+)
+
+// simulationScript extracts the resources bundle, runs the simulation and uploads the HTML report of the first
+// injector. The simulation class is passed as env var so user input never ends up in the shell script
+var simulationScript = fmt.Sprintf(`set -e
+resources="${GATLING_HOME:-/opt/gatling}/user-files/resources"
+if [ -f /data/%[1]s ]; then
+  resources=/tmp/resources
+  mkdir -p "$resources"
+  tar -xf /data/%[1]s -C "$resources"
+fi
+gatling.sh -sf %[2]s -rsf "$resources" -rf /results -s "$GATLING_SIMULATION_CLASS"
+if [ -n "$REPORT_PRESIGNED_URL" ] && [ "${JOB_COMPLETION_INDEX:-0}" = "0" ]; then
+  report=$(ls -d /results/*/ | head -n 1)
+  tar -C "$report" -cf /tmp/report-archive.tar .
+  curl -sf -X PUT -H "Content-Type: application/x-tar" -T /tmp/report-archive.tar -L "$REPORT_PRESIGNED_URL"
+fi`, resourcesFileName, simulationsDir)
+
+// simulation is the fully qualified class name and the file name of a simulation source file
+type simulation struct {
+	class    string
+	fileName string
+}
+
+// parseSimulation finds the class extending Simulation in the scala or java source file
+func parseSimulation(testFile []byte) (simulation, error) {
+	match := simulationRegexp.FindSubmatch(testFile)
+	if match == nil {
+		return simulation{}, ErrRequireSimulation
+	}
+
+	name := string(match[1])
+	class := name
+	if pkg := packageRegexp.FindSubmatch(testFile); pkg != nil {
+		class = fmt.Sprintf("%s.%s", pkg[1], name)
+	}
+
+	extension := "scala"
+	if javaClassRegexp.Match(testFile) {
+		extension = "java"
+	}
+
+	return simulation{class: class, fileName: fmt.Sprintf("%s.%s", name, extension)}, nil
+}
+
+// NewJob creates a new job that runs the Gatling simulation on each of the distributed pods
+func (b *Backend) NewJob(loadTest loadTestV1.LoadTest, sim simulation, reportURL string) *batchV1.Job {
+	logger := b.logger.With(
+		zap.String("loadtest", loadTest.GetName()),
+		zap.String("namespace", loadTest.Status.Namespace),
+	)
+
+	ownerRef := metaV1.NewControllerRef(&loadTest, loadTestV1.SchemeGroupVersion.WithKind("LoadTest"))
+
+	imageRef := loadTest.Spec.MasterConfig
+	if imageRef == ":" || imageRef == "" {
+		imageRef = b.image
+		logger.Warn("Loadtest.Spec.MasterConfig is empty; using default image", zap.String("imageRef", string(imageRef)))
+	}
+
+	envVars := []coreV1.EnvVar{
+		{Name: "GATLING_SIMULATION_CLASS", Value: sim.class},
+	}
+	if reportURL != "" {
+		envVars = append(envVars, coreV1.EnvVar{
+			Name:  "REPORT_PRESIGNED_URL",
+			Value: reportURL,
+		})
+	}
+
+	volumes := []coreV1.Volume{newConfigMapVolume(loadTestFileVolumeName, loadTestFileConfigMapName)}
+	mounts := []coreV1.VolumeMount{{Name: loadTestFileVolumeName, MountPath: simulationsDir}}
+	if len(loadTest.Spec.TestData) > 0 {
+		volumes = append(volumes, newConfigMapVolume(loadTestResourcesVolumeName, loadTestResourcesConfigMapName))
+		mounts = append(mounts, coreV1.VolumeMount{
+			Name:      loadTestResourcesVolumeName,
+			MountPath: fmt.Sprintf("/data/%s", resourcesFileName),
+			SubPath:   resourcesFileName,
+		})
+	}
+
+	labels := map[string]string{
+		"name": loadTestJobName,
+	}
+	completionMode := batchV1.IndexedCompletion
+
+	job := &batchV1.Job{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:            loadTestJobName,
+			Namespace:       loadTest.Status.Namespace,
+			Labels:          labels,
+			OwnerReferences: []metaV1.OwnerReference{*ownerRef},
+		},
+		Spec: batchV1.JobSpec{
+			Parallelism:           loadTest.Spec.DistributedPods,
+			Completions:           loadTest.Spec.DistributedPods,
+			CompletionMode:        &completionMode,
+			BackoffLimit:          backends.JobBackoffLimit(b.jobBackoffLimit, 0),
+			ActiveDeadlineSeconds: backends.JobActiveDeadlineSeconds(b.jobTimeout, loadTest.Spec.Timeout),
+			Template: coreV1.PodTemplateSpec{
+				ObjectMeta: metaV1.ObjectMeta{
+					Labels:      maps.Clone(labels),
+					Annotations: backends.MergePodAnnotations(b.podAnnotations, b.backendPodAnnotations, loadTest.Spec.PodAnnotations),
+				},
+				Spec: coreV1.PodSpec{
+					NodeSelector:     b.nodeSelector,
+					RestartPolicy:    "Never",
+					Volumes:          volumes,
+					Tolerations:      b.tolerations,
+					SecurityContext:  backends.PodSecurityContext(b.seccompProfile),
+					ImagePullSecrets: backends.ImagePullSecrets(b.imagePullSecrets, loadTest.Spec.ImagePullSecrets),
+					Containers: []coreV1.Container{
+						{
+							Name:         "gatling",
+							Image:        string(imageRef),
+							Env:          envVars,
+							Resources:    backends.BuildResourceRequirements(backends.OverrideResources(b.resources, loadTest.Spec.Resources)),
+							StartupProbe: b.startupProbe,
+							Command:      []string{"/bin/sh", "-c", simulationScript},
+							VolumeMounts: mounts,
+						},
+					},
+				},
+			},
+		},
+	}
+	backends.SetSpecHash(job, loadTest)
+	return job
+}
+
+// newConfigMapVolume creates a volume of the given configmap
+func newConfigMapVolume(name, cfgName string) coreV1.Volume {
+	return coreV1.Volume{
+		Name: name,
+		VolumeSource: coreV1.VolumeSource{
+			ConfigMap: &coreV1.ConfigMapVolumeSource{
+				LocalObjectReference: coreV1.LocalObjectReference{
+					Name: cfgName,
+				},
+			},
+		},
+	}
+}
+
+// NewFileConfigMap creates a configmap storing the given file, owned by the loadtest
+func NewFileConfigMap(loadTest loadTestV1.LoadTest, cfgName, filename string, content []byte) (*coreV1.ConfigMap, error) {
+	if len(content) == 0 {
+		return nil, fmt.Errorf("invalid file %s for configmap %s, empty content", filename, cfgName)
+	}
+
+	return &coreV1.ConfigMap{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name: cfgName,
+			OwnerReferences: []metaV1.OwnerReference{
+				*metaV1.NewControllerRef(&loadTest, loadTestV1.SchemeGroupVersion.WithKind("LoadTest")),
+			},
+		},
+		BinaryData: map[string][]byte{
+			filename: content,
+		},
+	}, nil
+}
+
+// determineLoadTestStatusFromJobs reads existing job statuses and determines what the loadtest status should be
+func determineLoadTestStatusFromJobs(job *batchV1.Job) loadTestV1.LoadTestPhase {
+	if backends.JobFailed(*job) {
+		return loadTestV1.LoadTestErrored
+	}
+
+	if job.Status.Active > int32(0) {
+		return loadTestV1.LoadTestRunning
+	}
+
+	// failed pods within the backoff limit are being replaced
+	if job.Status.Succeeded == 0 {
+		return loadTestV1.LoadTestStarting
+	}
+
+	// distributed injectors finish one by one, the loadtest is finished once all of them completed
+	if job.Spec.Completions != nil && job.Status.Succeeded < *job.Spec.Completions {
+		return loadTestV1.LoadTestRunning
+	}
+
+	return loadTestV1.LoadTestFinished
+}
@@ -0,0 +1,142 @@
+package gatling
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+	batchV1 "k8s.io/api/batch/v1"
+	coreV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+const scalaSimulation = `package computerdatabase
+
+import io.gatling.core.Predef._
+import io.gatling.http.Predef._
+
+class BasicSimulation extends Simulation {
+  setUp(scenario("basic").exec(http("home").get("/")).inject(atOnceUsers(1)))
+}
+`
+
+const javaSimulation = `import io.gatling.javaapi.core.*;
+
+public class BasicSimulation extends Simulation {
+  { setUp(scenario("basic").injectOpen(atOnceUsers(1))); }
+}
+`
+
+func TestParseSimulation(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		testFile string
+		expected simulation
+		err      error
+	}{
+		{
+			name:     "scala with package",
+			testFile: scalaSimulation,
+			expected: simulation{class: "computerdatabase.BasicSimulation", fileName: "BasicSimulation.scala"},
+		},
+		{
+			name:     "java without package",
+			testFile: javaSimulation,
+			expected: simulation{class: "BasicSimulation", fileName: "BasicSimulation.java"},
+		},
+		{
+			name:     "scala with constructor",
+			testFile: `class ParamSimulation(users: Int) extends Simulation {}`,
+			expected: simulation{class: "ParamSimulation", fileName: "ParamSimulation.scala"},
+		},
+		{
+			name:     "no simulation",
+			testFile: `class Helper extends Object {}`,
+			err:      ErrRequireSimulation,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			sim, err := parseSimulation([]byte(tt.testFile))
+			assert.ErrorIs(t, err, tt.err)
+			assert.Equal(t, tt.expected, sim)
+		})
+	}
+}
+
+func TestNewJob(t *testing.T) {
+	distributedPods := int32(3)
+	b := Backend{
+		logger: zaptest.NewLogger(t),
+		image:  "denvazh/gatling:latest",
+	}
+
+	loadTest := loadTestV1.LoadTest{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name: "loadtest-name",
+		},
+		Spec: loadTestV1.LoadTestSpec{
+			DistributedPods: &distributedPods,
+			TestFile:        []byte(scalaSimulation),
+			TestData:        []byte("resources"),
+		},
+		Status: loadTestV1.LoadTestStatus{
+			Namespace: "test",
+		},
+	}
+	sim := simulation{class: "computerdatabase.BasicSimulation", fileName: "BasicSimulation.scala"}
+
+	job := b.NewJob(loadTest, sim, "http://kangal-proxy.local/load-test/loadtest-name/report")
+
+	assert.Equal(t, &distributedPods, job.Spec.Parallelism)
+	assert.Equal(t, &distributedPods, job.Spec.Completions)
+	require.NotNil(t, job.Spec.CompletionMode)
+	assert.Equal(t, batchV1.IndexedCompletion, *job.Spec.CompletionMode)
+	assert.Len(t, job.Spec.Template.Spec.Volumes, 2)
+
+	container := job.Spec.Template.Spec.Containers[0]
+	assert.Equal(t, "denvazh/gatling:latest", container.Image)
+	assert.Contains(t, container.Env, coreV1.EnvVar{Name: "GATLING_SIMULATION_CLASS", Value: "computerdatabase.BasicSimulation"})
+	assert.Contains(t, container.Env, coreV1.EnvVar{Name: "REPORT_PRESIGNED_URL", Value: "http://kangal-proxy.local/load-test/loadtest-name/report"})
+	assert.Len(t, container.VolumeMounts, 2)
+}
+
+func TestDetermineLoadTestStatusFromJobs(t *testing.T) {
+	completions := int32(2)
+
+	for _, tt := range []struct {
+		name     string
+		status   batchV1.JobStatus
+		expected loadTestV1.LoadTestPhase
+	}{
+		{
+			name:     "pending",
+			expected: loadTestV1.LoadTestStarting,
+		},
+		{
+			name:     "active",
+			status:   batchV1.JobStatus{Active: 2},
+			expected: loadTestV1.LoadTestRunning,
+		},
+		{
+			name:     "some injectors completed",
+			status:   batchV1.JobStatus{Succeeded: 1},
+			expected: loadTestV1.LoadTestRunning,
+		},
+		{
+			name:     "all injectors completed",
+			status:   batchV1.JobStatus{Succeeded: 2},
+			expected: loadTestV1.LoadTestFinished,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			job := &batchV1.Job{
+				Spec:   batchV1.JobSpec{Completions: &completions},
+				Status: tt.status,
+			}
+			assert.Equal(t, tt.expected, determineLoadTestStatusFromJobs(job))
+		})
+	}
+}
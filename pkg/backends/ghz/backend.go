@@ -2,8 +2,11 @@ package ghz
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
+	"time"
 
 	"go.uber.org/zap"
 	coreV1 "k8s.io/api/core/v1"
@@ -18,8 +21,20 @@ import (
 var (
 	// ErrRequireMinOneDistributedPod Backend spec requires 1 or more DistributedPods
 	ErrRequireMinOneDistributedPod = errors.New("LoadTest must specify 1 or more DistributedPods")
-	// ErrRequireTestFile the TestFile filed is required to not be an empty string
-	ErrRequireTestFile = errors.New("LoadTest TestFile is required")
+	// ErrRequireTestFile the TestFile field is required to not be an empty string, unless TestFileURL is set
+	ErrRequireTestFile = errors.New("LoadTest TestFile or TestFileURL is required")
+	// ErrRequireConcurrency the TestFile must not set concurrency or total number of requests to zero
+	ErrRequireConcurrency = errors.New("LoadTest TestFile must specify 1 or more concurrency and total requests")
+	// ErrReservedArg the Args must not set the config or output file of ghz, which are set by the backend
+	ErrReservedArg = errors.New("LoadTest Args must not set the ghz flags set by the backend")
+	// ErrInvalidArg the Args set a ghz flag to an invalid value, e.g. an unknown report format
+	ErrInvalidArg = errors.New("LoadTest Args set a ghz flag to an invalid value")
+	// ErrInvalidReportFormat the ReportFormat is not one of the ghz output formats
+	ErrInvalidReportFormat = errors.New("LoadTest ReportFormat must be one of html, json, pretty, csv or summary")
+	// ErrReservedEnvVar the EnvVars must not set the report variables, which are set by the backend
+	ErrReservedEnvVar = errors.New("LoadTest EnvVars must not set the REPORT_ variables set by the backend")
+	// ErrInvalidEnvFrom every EnvFrom source must reference either a Secret or a ConfigMap
+	ErrInvalidEnvFrom = errors.New("LoadTest EnvFrom sources must set either a secret or a config map name")
 )
 
 func init() {
@@ -28,16 +43,32 @@ func init() {
 
 // Backend is the ghz implementation of backend interface
 type Backend struct {
-	logger         *zap.Logger
-	kubeClientSet  kubernetes.Interface
-	config         *Config
-	podAnnotations map[string]string
-	nodeSelector   map[string]string
-	tolerations    []coreV1.Toleration
+	logger           *zap.Logger
+	kubeClientSet    kubernetes.Interface
+	config           *Config
+	podAnnotations   map[string]string
+	nodeSelector     map[string]string
+	tolerations      []coreV1.Toleration
+	seccompProfile   *coreV1.SeccompProfile
+	imagePullSecrets []string
+	serviceAccount   string
+
+	testFileFetcher backends.TestFileFetcher
+	jobConditions   bool
+	jobBackoffLimit *int32
+	jobTimeout      time.Duration
+	tagLabels       bool
+
+	// defined on Init
+	nativeSidecars bool
 
 	// defined on SetDefaults
-	image     loadTestV1.ImageDetails
-	resources backends.Resources
+	image        loadTestV1.ImageDetails
+	resources    backends.Resources
+	startupProbe *coreV1.Probe
+	reportFormat string
+
+	backendPodAnnotations map[string]string
 }
 
 // Type returns backend type name
@@ -45,6 +76,31 @@ func (*Backend) Type() loadTestV1.LoadTestType {
 	return loadTestV1.LoadTestTypeGhz
 }
 
+// Capabilities returns the load test fields supported by the ghz backend
+func (*Backend) Capabilities() backends.Capabilities {
+	return backends.Capabilities{
+		Distributed:     true,
+		TestFileFormats: []string{"json", "toml"},
+		TestDataFormats: []string{"protoset"},
+		RequiredFields:  []string{"distributedPods", "testFile"},
+		OptionalFields: []string{"testFileURL", "testData", "masterImage", "envVars", "args", "reportFormat", "initContainers",
+			"sidecars", "envFrom", "serviceAccountName", "cpuLimits", "cpuRequests", "memoryLimits", "memoryRequests"},
+	}
+}
+
+// Init detects whether the cluster runs native sidecars, the sidecars of loadtests fall back to regular containers
+// when it doesn't or when it can't be told
+func (b *Backend) Init(context.Context) error {
+	supported, err := backends.NativeSidecarsSupported(b.kubeClientSet.Discovery())
+	if err != nil {
+		b.logger.Warn("Could not detect native sidecar support, running sidecars as regular containers", zap.Error(err))
+		return nil
+	}
+
+	b.nativeSidecars = supported
+	return nil
+}
+
 // GetEnvConfig must return config struct pointer
 func (b *Backend) GetEnvConfig() interface{} {
 	b.config = &Config{}
@@ -61,6 +117,17 @@ func (b *Backend) SetDefaults() {
 		MemoryLimits:   b.config.MemoryLimits,
 		MemoryRequests: b.config.MemoryRequests,
 	}
+
+	b.startupProbe = backends.BuildStartupProbe(b.config.StartupProbe)
+	b.backendPodAnnotations = b.config.PodAnnotations
+
+	b.reportFormat = defaultReportFormat
+	if _, ok := reportFormats[b.config.ReportFormat]; ok {
+		b.reportFormat = b.config.ReportFormat
+	} else if b.logger != nil {
+		b.logger.Warn("Unsupported ghz report format, using default format",
+			zap.String("format", b.config.ReportFormat), zap.String("default", defaultReportFormat))
+	}
 }
 
 // SetPodAnnotations receives a copy of pod annotations
@@ -83,11 +150,51 @@ func (b *Backend) SetPodNodeSelector(nodeselector map[string]string) {
 	b.nodeSelector = nodeselector
 }
 
+// SetJobConditions receives whether the loadtest phase is determined from job conditions first
+func (b *Backend) SetJobConditions(enabled bool) {
+	b.jobConditions = enabled
+}
+
+// SetJobBackoffLimit receives the number of retries of failed job pods
+func (b *Backend) SetJobBackoffLimit(limit int32) {
+	b.jobBackoffLimit = &limit
+}
+
+// SetJobTimeout receives the default timeout of loadtest jobs
+func (b *Backend) SetJobTimeout(timeout time.Duration) {
+	b.jobTimeout = timeout
+}
+
+// SetTestFileFetcher receives the configuration of the init container fetching remote test files
+func (b *Backend) SetTestFileFetcher(fetcher backends.TestFileFetcher) {
+	b.testFileFetcher = fetcher
+}
+
 // SetPodTolerations receives a copy of pod tolerations
 func (b *Backend) SetPodTolerations(tolerations []coreV1.Toleration) {
 	b.tolerations = tolerations
 }
 
+// SetTagLabels receives whether the loadtest tags are added as labels to the job and its pods
+func (b *Backend) SetTagLabels(enabled bool) {
+	b.tagLabels = enabled
+}
+
+// SetImagePullSecrets receives the image pull secrets of the loadtest pods
+func (b *Backend) SetImagePullSecrets(secrets []string) {
+	b.imagePullSecrets = secrets
+}
+
+// SetPodServiceAccount receives the service account of the loadtest pods
+func (b *Backend) SetPodServiceAccount(name string) {
+	b.serviceAccount = name
+}
+
+// SetSeccompProfile receives the seccomp profile of the loadtest pods
+func (b *Backend) SetSeccompProfile(profile *coreV1.SeccompProfile) {
+	b.seccompProfile = profile
+}
+
 // TransformLoadTestSpec use given spec to validate and return a new one or error
 func (b *Backend) TransformLoadTestSpec(spec *loadTestV1.LoadTestSpec) error {
 	if nil == spec.DistributedPods {
@@ -98,10 +205,22 @@ func (b *Backend) TransformLoadTestSpec(spec *loadTestV1.LoadTestSpec) error {
 		return ErrRequireMinOneDistributedPod
 	}
 
-	if len(spec.TestFile) == 0 {
+	if len(spec.TestFile) == 0 && spec.TestFileURL == "" {
 		return ErrRequireTestFile
 	}
 
+	if err := b.ValidateConcurrency(*spec); err != nil {
+		return err
+	}
+
+	if err := backends.ValidateResources(b.resources, spec.Resources); err != nil {
+		return err
+	}
+
+	if err := validateOptions(*spec); err != nil {
+		return err
+	}
+
 	if spec.MasterConfig == "" {
 		spec.MasterConfig = b.image
 	}
@@ -109,6 +228,85 @@ func (b *Backend) TransformLoadTestSpec(spec *loadTestV1.LoadTestSpec) error {
 	return nil
 }
 
+// Validate rejects load tests with a concurrency, report format or extra arguments ghz can't run with
+func (b *Backend) Validate(loadTest loadTestV1.LoadTest) error {
+	if err := b.ValidateConcurrency(loadTest.Spec); err != nil {
+		return err
+	}
+
+	return validateOptions(loadTest.Spec)
+}
+
+// validateOptions checks the report format and the extra arguments of the spec
+func validateOptions(spec loadTestV1.LoadTestSpec) error {
+	if spec.ReportFormat != "" {
+		if _, ok := reportFormats[spec.ReportFormat]; !ok {
+			return ErrInvalidReportFormat
+		}
+	}
+
+	argsFormat, _, err := parseArgs(spec.Args)
+	if err != nil {
+		return err
+	}
+	if argsFormat != "" && spec.ReportFormat != "" && argsFormat != spec.ReportFormat {
+		return fmt.Errorf("%w: --format %s conflicts with the report format %s", ErrInvalidArg, argsFormat, spec.ReportFormat)
+	}
+
+	for name := range spec.EnvVars {
+		if _, ok := reservedEnvVars[name]; ok {
+			return fmt.Errorf("%w: %s", ErrReservedEnvVar, name)
+		}
+	}
+
+	for i, source := range spec.EnvFrom {
+		if (source.SecretName == "") == (source.ConfigMapName == "") {
+			return fmt.Errorf("%w: source %d", ErrInvalidEnvFrom, i)
+		}
+	}
+
+	// the containers of the loadtest must not take the names of the containers of the pod
+	reserved := []string{backends.TestFileFetcherContainerName, ghzContainerName}
+	if err := backends.ValidateInitContainers(spec.InitContainers, reserved...); err != nil {
+		return err
+	}
+
+	reserved = append(reserved, backends.InitContainerNames(spec.InitContainers)...)
+	return backends.ValidateSidecars(spec.Sidecars, reserved...)
+}
+
+// ValidateConcurrency rejects JSON configs that explicitly set concurrency or, when not running for
+// a fixed duration, the total number of requests to zero. Other config formats are accepted as is.
+func (*Backend) ValidateConcurrency(spec loadTestV1.LoadTestSpec) error {
+	var cfg struct {
+		Concurrency *uint  `json:"concurrency"`
+		Total       *uint  `json:"total"`
+		Duration    string `json:"duration"`
+	}
+	if err := json.Unmarshal(spec.TestFile, &cfg); err != nil {
+		return nil
+	}
+
+	if cfg.Concurrency != nil && *cfg.Concurrency == 0 {
+		return ErrRequireConcurrency
+	}
+
+	if cfg.Total != nil && *cfg.Total == 0 && cfg.Duration == "" {
+		return ErrRequireConcurrency
+	}
+
+	return nil
+}
+
+// EstimateResources returns the resources requested by the ghz pods
+func (b *Backend) EstimateResources(spec loadTestV1.LoadTestSpec) backends.ResourceEstimate {
+	var pods int32
+	if spec.DistributedPods != nil {
+		pods = *spec.DistributedPods
+	}
+	return backends.EstimatePods(pods, backends.OverrideResources(b.resources, spec.Resources))
+}
+
 // Sync checks if ghz kubernetes resources have been created, create them if they haven't
 func (b *Backend) Sync(ctx context.Context, loadTest loadTestV1.LoadTest, reportURL string) error {
 	jobs, err := b.kubeClientSet.
@@ -129,20 +327,22 @@ func (b *Backend) Sync(ctx context.Context, loadTest loadTestV1.LoadTest, report
 
 	var (
 		tdCfgMap   *coreV1.ConfigMap
-		configMaps = make([]*coreV1.ConfigMap, 1)
+		configMaps = make([]*coreV1.ConfigMap, 0, 2)
 	)
 
-	// Create testfile ConfigMap
-	tfCfgMap, err := NewFileConfigMap(loadTestFileConfigMapName, configFileName, loadTest.Spec.TestFile)
-	if err != nil {
-		b.logger.Error("Error creating testfile configmap resource", zap.Error(err))
-		return err
+	// Create testfile ConfigMap, remote test files are fetched by an init container instead
+	if loadTest.Spec.TestFileURL == "" {
+		tfCfgMap, err := NewFileConfigMap(loadTest, loadTestFileConfigMapName, configFileName, loadTest.Spec.TestFile)
+		if err != nil {
+			b.logger.Error("Error creating testfile configmap resource", zap.Error(err))
+			return err
+		}
+		configMaps = append(configMaps, tfCfgMap)
 	}
-	configMaps[0] = tfCfgMap
 
 	// Prepare testdata ConfigMap
 	if len(loadTest.Spec.TestData) != 0 {
-		tdCfgMap, err = NewFileConfigMap(loadTestDataConfigMapName, testdataFileName, loadTest.Spec.TestData)
+		tdCfgMap, err = NewFileConfigMap(loadTest, loadTestDataConfigMapName, testdataFileName, loadTest.Spec.TestData)
 		if err != nil {
 			b.logger.Error("Error creating testdata configmap resource", zap.Error(err))
 			return err
@@ -168,7 +368,7 @@ func (b *Backend) Sync(ctx context.Context, loadTest loadTestV1.LoadTest, report
 		mounts  = make([]coreV1.VolumeMount, 1)
 	)
 
-	volumes[0], mounts[0] = NewFileVolumeAndMount(loadTestFileVolumeName, tfCfgMap.Name, configFileName)
+	volumes[0], mounts[0] = NewFileVolumeAndMount(loadTestFileVolumeName, loadTestFileConfigMapName, configFileName)
 
 	if tdCfgMap != nil {
 		v, m := NewFileVolumeAndMount(loadTestDataVolumeName, tdCfgMap.Name, testdataFileName)
@@ -176,6 +376,18 @@ func (b *Backend) Sync(ctx context.Context, loadTest loadTestV1.LoadTest, report
 		mounts = append(mounts, m)
 	}
 
+	// Create the secret of the environment variables, read by the job with envFrom
+	if len(loadTest.Spec.EnvVars) != 0 {
+		_, err = b.kubeClientSet.
+			CoreV1().
+			Secrets(loadTest.Status.Namespace).
+			Create(ctx, NewEnvVarsSecret(loadTest), metaV1.CreateOptions{})
+		if err != nil && !k8sAPIErrors.IsAlreadyExists(err) {
+			b.logger.Error("Error on creating secret", zap.Error(err))
+			return err
+		}
+	}
+
 	// Create Job
 	job := b.NewJob(loadTest, volumes, mounts, reportURL)
 	_, err = b.kubeClientSet.
@@ -209,7 +421,32 @@ func (b *Backend) SyncStatus(ctx context.Context, _ loadTestV1.LoadTest, loadTes
 		return err
 	}
 
-	loadTestStatus.Phase = determineLoadTestStatusFromJobs(job)
+	loadTestStatus.Phase = backends.PreferJobConditions(b.jobConditions, determineLoadTestStatusFromJobs(job), job.Status)
 	loadTestStatus.JobStatus = job.Status
+	backends.SetPodCounters(&loadTestStatus.Pods, job.Status)
+	return nil
+}
+
+// Cleanup deletes the report of the loadtest uploaded to the Kangal proxy, a missing report is not an error
+func (b *Backend) Cleanup(ctx context.Context, loadTest loadTestV1.LoadTest, reportURL string) error {
+	if reportURL == "" {
+		return nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, reportURL, nil)
+	if err != nil {
+		return fmt.Errorf("invalid report URL %q: %w", reportURL, err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("could not delete report of loadtest %s: %w", loadTest.GetName(), err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusMultipleChoices && resp.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("could not delete report of loadtest %s: proxy responded with status %d", loadTest.GetName(), resp.StatusCode)
+	}
+	b.logger.Debug("Deleted loadtest report", zap.String("loadtest", loadTest.GetName()))
 	return nil
 }
@@ -2,14 +2,20 @@ package ghz
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
+	"github.com/hellofresh/kangal/pkg/backends"
 	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap/zaptest"
 	batchV1 "k8s.io/api/batch/v1"
+	coreV1 "k8s.io/api/core/v1"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/version"
+	fakeDiscovery "k8s.io/client-go/discovery/fake"
 	k8sfake "k8s.io/client-go/kubernetes/fake"
 )
 
@@ -57,6 +63,62 @@ func TestSync(t *testing.T) {
 	assert.NotEmpty(t, configMaps.Items, "Expected configmap to be created but there's none")
 }
 
+func TestSyncEnvVars(t *testing.T) {
+	ctx := context.Background()
+	kubeClient := k8sfake.NewSimpleClientset()
+
+	distributedPods := int32(1)
+	loadTest := loadTestV1.LoadTest{
+		ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-name"},
+		Spec: loadTestV1.LoadTestSpec{
+			DistributedPods: &distributedPods,
+			TestFile:        []byte("test"),
+			EnvVars:         map[string]string{"API_TOKEN": "secret-token"},
+		},
+		Status: loadTestV1.LoadTestStatus{Namespace: "test"},
+	}
+
+	b := Backend{
+		logger:        zaptest.NewLogger(t),
+		kubeClientSet: kubeClient,
+	}
+	require.NoError(t, b.Sync(ctx, loadTest, ""))
+
+	secret, err := kubeClient.CoreV1().Secrets("test").Get(ctx, loadTestEnvVarsSecretName, metaV1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"API_TOKEN": "secret-token"}, secret.StringData)
+	assert.Equal(t, "loadtest-name", metaV1.GetControllerOf(secret).Name)
+}
+
+func TestSyncTestFileURL(t *testing.T) {
+	ctx := context.Background()
+	kubeClient := k8sfake.NewSimpleClientset()
+	distributedPods := int32(1)
+	loadTest := loadTestV1.LoadTest{
+		ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-name"},
+		Spec:       loadTestV1.LoadTestSpec{DistributedPods: &distributedPods, TestFileURL: "https://example.com/testfile"},
+		Status:     loadTestV1.LoadTestStatus{Phase: "running", Namespace: "test"},
+	}
+
+	// the fetcher itself is tested in the backends package, only the test file of the backend is checked here
+	b := Backend{
+		logger:          zaptest.NewLogger(t),
+		kubeClientSet:   kubeClient,
+		testFileFetcher: backends.TestFileFetcher{Image: "fetcher:latest"},
+	}
+	require.NoError(t, b.Sync(ctx, loadTest, ""))
+
+	jobs, err := kubeClient.BatchV1().Jobs("test").List(ctx, metaV1.ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, jobs.Items, 1)
+	require.Len(t, jobs.Items[0].Spec.Template.Spec.InitContainers, 1)
+	assert.Contains(t, jobs.Items[0].Spec.Template.Spec.InitContainers[0].Env, coreV1.EnvVar{Name: "TEST_FILE_PATH", Value: "/fetch/config"})
+
+	configMaps, err := kubeClient.CoreV1().ConfigMaps("test").List(ctx, metaV1.ListOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, configMaps.Items, "Expected no testfile configmap for remote test file")
+}
+
 func TestSyncStatus(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -107,4 +169,246 @@ func TestSyncStatus(t *testing.T) {
 	err = b.SyncStatus(ctx, loadTest, &loadTest.Status)
 	require.NoError(t, err, "SyncStatus error")
 	assert.Equal(t, loadTestV1.LoadTestFinished, loadTest.Status.Phase)
+	assert.Equal(t, int32(1), loadTest.Status.Pods.Succeeded)
+	assert.Equal(t, int32(0), loadTest.Status.Pods.Active)
+}
+
+func TestValidateConcurrency(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		testFile string
+		err      error
+	}{
+		{
+			name:     "valid config",
+			testFile: `{"call": "helloworld.Greeter.SayHello", "total": 2000, "concurrency": 50}`,
+		},
+		{
+			name:     "defaults",
+			testFile: `{"call": "helloworld.Greeter.SayHello"}`,
+		},
+		{
+			name:     "zero concurrency",
+			testFile: `{"call": "helloworld.Greeter.SayHello", "total": 2000, "concurrency": 0}`,
+			err:      ErrRequireConcurrency,
+		},
+		{
+			name:     "zero total",
+			testFile: `{"call": "helloworld.Greeter.SayHello", "total": 0, "concurrency": 50}`,
+			err:      ErrRequireConcurrency,
+		},
+		{
+			name:     "zero total with duration",
+			testFile: `{"call": "helloworld.Greeter.SayHello", "total": 0, "duration": "10s"}`,
+		},
+		{
+			name:     "not a JSON config",
+			testFile: `call = "helloworld.Greeter.SayHello"`,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			err := (&Backend{}).ValidateConcurrency(loadTestV1.LoadTestSpec{TestFile: []byte(tt.testFile)})
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestTransformLoadTestSpecReportFormat(t *testing.T) {
+	for _, tt := range []struct {
+		name         string
+		reportFormat string
+		args         []string
+		err          error
+	}{
+		{
+			name: "default format",
+		},
+		{
+			name:         "supported format",
+			reportFormat: "csv",
+		},
+		{
+			name:         "unsupported format",
+			reportFormat: "influx-details",
+			err:          ErrInvalidReportFormat,
+		},
+		{
+			name:         "same format in args",
+			reportFormat: "json",
+			args:         []string{"--format=json"},
+		},
+		{
+			name:         "conflicting format in args",
+			reportFormat: "json",
+			args:         []string{"--format=csv"},
+			err:          ErrInvalidArg,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			distributedPods := int32(1)
+			spec := loadTestV1.LoadTestSpec{
+				DistributedPods: &distributedPods,
+				TestFile:        []byte(`{"call": "helloworld.Greeter.SayHello"}`),
+				ReportFormat:    tt.reportFormat,
+				Args:            tt.args,
+			}
+			err := (&Backend{}).TransformLoadTestSpec(&spec)
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		testFile string
+		spec     loadTestV1.LoadTestSpec
+		err      error
+	}{
+		{
+			name:     "valid",
+			testFile: `{"call": "helloworld.Greeter.SayHello"}`,
+		},
+		{
+			name:     "zero concurrency",
+			testFile: `{"concurrency": 0}`,
+			err:      ErrRequireConcurrency,
+		},
+		{
+			name:     "unsupported format",
+			testFile: `{"call": "helloworld.Greeter.SayHello"}`,
+			spec:     loadTestV1.LoadTestSpec{ReportFormat: "influx-details"},
+			err:      ErrInvalidReportFormat,
+		},
+		{
+			name:     "reserved arg",
+			testFile: `{"call": "helloworld.Greeter.SayHello"}`,
+			spec:     loadTestV1.LoadTestSpec{Args: []string{"--output=/tmp/report"}},
+			err:      ErrReservedArg,
+		},
+		{
+			name:     "reserved env var",
+			testFile: `{"call": "helloworld.Greeter.SayHello"}`,
+			spec:     loadTestV1.LoadTestSpec{EnvVars: map[string]string{"REPORT_PRESIGNED_URL": "http://example.com"}},
+			err:      ErrReservedEnvVar,
+		},
+		{
+			name:     "env from source without name",
+			testFile: `{"call": "helloworld.Greeter.SayHello"}`,
+			spec:     loadTestV1.LoadTestSpec{EnvFrom: []loadTestV1.EnvFromSource{{Prefix: "GRPC_"}}},
+			err:      ErrInvalidEnvFrom,
+		},
+		{
+			name:     "env from both a secret and a config map",
+			testFile: `{"call": "helloworld.Greeter.SayHello"}`,
+			spec: loadTestV1.LoadTestSpec{EnvFrom: []loadTestV1.EnvFromSource{
+				{SecretName: "grpc-credentials", ConfigMapName: "grpc-settings"},
+			}},
+			err: ErrInvalidEnvFrom,
+		},
+		{
+			name:     "init container without image",
+			testFile: `{"call": "helloworld.Greeter.SayHello"}`,
+			spec:     loadTestV1.LoadTestSpec{InitContainers: []loadTestV1.InitContainer{{Name: "seed"}}},
+			err:      backends.ErrRequireInitContainerImage,
+		},
+		{
+			name:     "sidecar without image",
+			testFile: `{"call": "helloworld.Greeter.SayHello"}`,
+			spec:     loadTestV1.LoadTestSpec{Sidecars: []loadTestV1.Sidecar{{Name: "prometheus"}}},
+			err:      backends.ErrRequireSidecarImage,
+		},
+		{
+			name:     "init container named like ghz",
+			testFile: `{"call": "helloworld.Greeter.SayHello"}`,
+			spec:     loadTestV1.LoadTestSpec{InitContainers: []loadTestV1.InitContainer{{Name: "ghz", Image: "busybox"}}},
+			err:      backends.ErrInvalidContainerName,
+		},
+		{
+			name:     "sidecar named like the test file fetcher",
+			testFile: `{"call": "helloworld.Greeter.SayHello"}`,
+			spec: loadTestV1.LoadTestSpec{Sidecars: []loadTestV1.Sidecar{
+				{Name: backends.TestFileFetcherContainerName, Image: "prom/prometheus"},
+			}},
+			err: backends.ErrInvalidContainerName,
+		},
+		{
+			name:     "sidecar named like an init container",
+			testFile: `{"call": "helloworld.Greeter.SayHello"}`,
+			spec: loadTestV1.LoadTestSpec{
+				InitContainers: []loadTestV1.InitContainer{{Image: "busybox"}},
+				Sidecars:       []loadTestV1.Sidecar{{Name: "init-0", Image: "prom/prometheus"}},
+			},
+			err: backends.ErrInvalidContainerName,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.spec.TestFile = []byte(tt.testFile)
+			err := (&Backend{}).Validate(loadTestV1.LoadTest{Spec: tt.spec})
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
+func TestInitNativeSidecars(t *testing.T) {
+	for gitVersion, expected := range map[string]bool{
+		"v1.28.5": false,
+		"v1.29.1": true,
+		"unknown": false,
+	} {
+		kubeClient := k8sfake.NewSimpleClientset()
+		kubeClient.Discovery().(*fakeDiscovery.FakeDiscovery).FakedServerVersion = &version.Info{GitVersion: gitVersion}
+
+		b := &Backend{kubeClientSet: kubeClient, logger: zaptest.NewLogger(t)}
+		require.NoError(t, b.Init(context.Background()), gitVersion)
+		assert.Equal(t, expected, b.nativeSidecars, gitVersion)
+	}
+}
+
+func TestSetDefaultsStartupProbe(t *testing.T) {
+	b := Backend{config: &Config{}}
+	b.SetDefaults()
+	assert.Nil(t, b.startupProbe)
+
+	b = Backend{config: &Config{StartupProbe: backends.StartupProbe{TCPPort: 50051, PeriodSeconds: 10}}}
+	b.SetDefaults()
+	require.NotNil(t, b.startupProbe)
+	assert.Equal(t, int32(50051), b.startupProbe.TCPSocket.Port.IntVal)
+}
+
+func TestCleanup(t *testing.T) {
+	ctx := context.Background()
+	loadTest := loadTestV1.LoadTest{ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-name"}}
+	b := Backend{logger: zaptest.NewLogger(t)}
+
+	for _, tt := range []struct {
+		name        string
+		statusCode  int
+		expectError bool
+	}{
+		{name: "report deleted", statusCode: http.StatusNoContent},
+		{name: "no report", statusCode: http.StatusNotFound},
+		{name: "storage failure", statusCode: http.StatusInternalServerError, expectError: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var deleted string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodDelete, r.Method)
+				deleted = r.URL.Path
+				w.WriteHeader(tt.statusCode)
+			}))
+			defer server.Close()
+
+			err := b.Cleanup(ctx, loadTest, server.URL+"/load-test/loadtest-name/report")
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, "/load-test/loadtest-name/report", deleted)
+		})
+	}
+
+	// without report storage there is nothing to clean up
+	assert.NoError(t, b.Cleanup(ctx, loadTest, ""))
 }
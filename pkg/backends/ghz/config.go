@@ -1,5 +1,7 @@
 package ghz
 
+import "github.com/hellofresh/kangal/pkg/backends"
+
 // Config specific to ghz backend
 type Config struct {
 	ImageName      string `envconfig:"GHZ_IMAGE_NAME" default:"hellofresh/kangal-ghz"`
@@ -8,4 +10,12 @@ type Config struct {
 	CPURequests    string `envconfig:"GHZ_CPU_REQUESTS"`
 	MemoryLimits   string `envconfig:"GHZ_MEMORY_LIMITS"`
 	MemoryRequests string `envconfig:"GHZ_MEMORY_REQUESTS"`
+
+	StartupProbe backends.StartupProbe `envconfig:"GHZ_STARTUP_PROBE"`
+
+	// ReportFormat is the ghz output format of the report, one of html, json, pretty, csv or summary
+	ReportFormat string `envconfig:"GHZ_REPORT_FORMAT" default:"html"`
+
+	// PodAnnotations are the default annotations of the loadtest pods of the backend
+	PodAnnotations map[string]string `envconfig:"GHZ_POD_ANNOTATIONS"`
 }
@@ -1,8 +1,11 @@
 package ghz
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
+	"slices"
 	"strings"
 
 	"go.uber.org/zap"
@@ -18,17 +21,88 @@ const (
 	loadTestJobName           = "loadtest-job"
 	loadTestFileConfigMapName = "loadtest-testfile"
 	loadTestDataConfigMapName = "loadtest-testdata"
+	loadTestEnvVarsSecretName = "loadtest-envvars"
 	loadTestFileVolumeName    = "loadtest-testfile-volume"
 	loadTestDataVolumeName    = "loadtest-testdata-volume"
 
 	configFileName   = "config"
 	testdataFileName = "testdata.protoset"
+
+	// ghzContainerName is the name of the container running ghz
+	ghzContainerName = "ghz"
+)
+
+const defaultReportFormat = "html"
+
+// reportFormat is a ghz output format with the extension and content type of its report
+type reportFormat struct {
+	extension   string
+	contentType string
+}
+
+// reportFormats are the ghz output formats supported as report
+var reportFormats = map[string]reportFormat{
+	"html":    {extension: "html", contentType: "text/html; charset=utf-8"},
+	"json":    {extension: "json", contentType: "application/json"},
+	"pretty":  {extension: "json", contentType: "application/json"},
+	"csv":     {extension: "csv", contentType: "text/csv; charset=utf-8"},
+	"summary": {extension: "txt", contentType: "text/plain; charset=utf-8"},
+}
+
+var (
+	// reservedArgs are the ghz flags set by the backend, loadtest args must not override them
+	reservedArgs = []string{"--config", "--output", "-o"}
+	// formatArgs are the ghz flags selecting the output format, which is the format of the report
+	formatArgs = []string{"--format", "-O"}
+	// reservedEnvVars are the variables set by the backend for the report uploader, loadtest env vars must not set them
+	reservedEnvVars = map[string]struct{}{
+		"REPORT_FORMAT":        {},
+		"REPORT_FILE":          {},
+		"REPORT_CONTENT_TYPE":  {},
+		"REPORT_PRESIGNED_URL": {},
+	}
 )
 
-var defaultArgs = []string{
-	"--config=/data/config",
-	"--output=/results.html",
-	"--format=html",
+// parseArgs validates the extra ghz args of a loadtest, it returns the report format selected by them, if any,
+// and the other args to append to the ones of the backend
+func parseArgs(args []string) (format string, extra []string, err error) {
+	for i := 0; i < len(args); i++ {
+		name, value, hasValue := strings.Cut(args[i], "=")
+		if slices.Contains(reservedArgs, name) {
+			return "", nil, fmt.Errorf("%w: %s", ErrReservedArg, name)
+		}
+		if !slices.Contains(formatArgs, name) {
+			extra = append(extra, args[i])
+			continue
+		}
+
+		if !hasValue {
+			if i+1 == len(args) {
+				return "", nil, fmt.Errorf("%w: %s requires a value", ErrInvalidArg, name)
+			}
+			i++
+			value = args[i]
+		}
+		if _, ok := reportFormats[value]; !ok {
+			return "", nil, fmt.Errorf("%w: unsupported report format %q", ErrInvalidArg, value)
+		}
+		format = value
+	}
+	return format, extra, nil
+}
+
+// reportArgs returns the ghz args writing the report in the given format
+func reportArgs(format string) []string {
+	return []string{
+		"--config=/data/config",
+		fmt.Sprintf("--output=%s", reportFile(format)),
+		fmt.Sprintf("--format=%s", format),
+	}
+}
+
+// reportFile returns the path of the report written in the given format
+func reportFile(format string) string {
+	return fmt.Sprintf("/results.%s", reportFormats[format].extension)
 }
 
 // NewJob creates a new job that runs ghz
@@ -51,7 +125,40 @@ func (b *Backend) NewJob(
 		logger.Warn("Loadtest.Spec.MasterConfig is empty; using default image", zap.String("imageRef", string(imageRef)))
 	}
 
-	envVars := []coreV1.EnvVar{}
+	format := b.reportFormat
+	if format == "" {
+		format = defaultReportFormat
+	}
+	if _, ok := reportFormats[loadTest.Spec.ReportFormat]; ok {
+		format = loadTest.Spec.ReportFormat
+	}
+	argsFormat, extraArgs, err := parseArgs(loadTest.Spec.Args)
+	if err != nil {
+		logger.Warn("Ignoring invalid loadtest args", zap.Strings("args", loadTest.Spec.Args), zap.Error(err))
+	}
+	if argsFormat != "" {
+		format = argsFormat
+	}
+
+	// variables of the loadtest are read from the referenced sources and then from its secret, so its own
+	// variables win over the referenced ones, the report variables set in Env take precedence over all of them
+	envFrom := envFromSources(loadTest.Spec.EnvFrom)
+	if len(loadTest.Spec.EnvVars) != 0 {
+		envFrom = append(envFrom, coreV1.EnvFromSource{
+			SecretRef: &coreV1.SecretEnvSource{
+				LocalObjectReference: coreV1.LocalObjectReference{
+					Name: loadTestEnvVarsSecretName,
+				},
+			},
+		})
+	}
+
+	// the report uploader sends the report file with its content type, so the proxy serves it as such
+	envVars := []coreV1.EnvVar{
+		{Name: "REPORT_FORMAT", Value: format},
+		{Name: "REPORT_FILE", Value: reportFile(format)},
+		{Name: "REPORT_CONTENT_TYPE", Value: reportFormats[format].contentType},
+	}
 	if reportURL != "" {
 		envVars = append(envVars, coreV1.EnvVar{
 			Name:  "REPORT_PRESIGNED_URL",
@@ -59,40 +166,50 @@ func (b *Backend) NewJob(
 		})
 	}
 
-	backoffLimit := int32(0)
+	args := reportArgs(format)
+	if total, ok := totalPerPod(loadTest.Spec.TestFile, loadTest.Spec.DistributedPods); ok {
+		args = append(args, fmt.Sprintf("--total=%d", total))
+	} else if loadTest.Spec.DistributedPods != nil && *loadTest.Spec.DistributedPods > 1 {
+		logger.Debug("Could not split the total number of requests, each pod runs the test file as is")
+	}
+	args = append(args, extraArgs...)
+
+	labels := b.labels(loadTest, logger)
 
-	return &batchV1.Job{
+	job := &batchV1.Job{
 		ObjectMeta: metaV1.ObjectMeta{
-			Name:      loadTestJobName,
-			Namespace: loadTest.Status.Namespace,
-			Labels: map[string]string{
-				"name": loadTestJobName,
-			},
+			Name:            loadTestJobName,
+			Namespace:       loadTest.Status.Namespace,
+			Labels:          labels,
 			OwnerReferences: []metaV1.OwnerReference{*ownerRef},
 		},
 		Spec: batchV1.JobSpec{
-			Parallelism:  loadTest.Spec.DistributedPods,
-			Completions:  loadTest.Spec.DistributedPods,
-			BackoffLimit: &backoffLimit,
+			Parallelism:           loadTest.Spec.DistributedPods,
+			Completions:           loadTest.Spec.DistributedPods,
+			BackoffLimit:          backends.JobBackoffLimit(b.jobBackoffLimit, 0),
+			ActiveDeadlineSeconds: backends.JobActiveDeadlineSeconds(b.jobTimeout, loadTest.Spec.Timeout),
 			Template: coreV1.PodTemplateSpec{
 				ObjectMeta: metaV1.ObjectMeta{
-					Labels: map[string]string{
-						"name": loadTestJobName,
-					},
-					Annotations: b.podAnnotations,
+					Labels:      maps.Clone(labels),
+					Annotations: backends.MergePodAnnotations(b.podAnnotations, b.backendPodAnnotations, loadTest.Spec.PodAnnotations),
 				},
 				Spec: coreV1.PodSpec{
-					NodeSelector:  b.nodeSelector,
-					RestartPolicy: "Never",
-					Volumes:       volumes,
-					Tolerations:   b.tolerations,
+					NodeSelector:       b.nodeSelector,
+					RestartPolicy:      "Never",
+					Volumes:            volumes,
+					Tolerations:        b.tolerations,
+					SecurityContext:    backends.PodSecurityContext(b.seccompProfile),
+					ImagePullSecrets:   backends.ImagePullSecrets(b.imagePullSecrets, loadTest.Spec.ImagePullSecrets),
+					ServiceAccountName: backends.PodServiceAccount(b.serviceAccount, loadTest.Spec.ServiceAccountName),
 					Containers: []coreV1.Container{
 						{
-							Name:         "ghz",
+							Name:         ghzContainerName,
 							Image:        string(imageRef),
 							Env:          envVars,
-							Resources:    backends.BuildResourceRequirements(b.resources),
-							Args:         defaultArgs,
+							EnvFrom:      envFrom,
+							Resources:    backends.BuildResourceRequirements(backends.OverrideResources(b.resources, loadTest.Spec.Resources)),
+							StartupProbe: b.startupProbe,
+							Args:         args,
 							VolumeMounts: mounts,
 						},
 					},
@@ -100,6 +217,56 @@ func (b *Backend) NewJob(
 			},
 		},
 	}
+
+	if loadTest.Spec.TestFileURL != "" {
+		b.testFileFetcher.Inject(&job.Spec.Template.Spec, loadTest.Spec.TestFileURL, loadTestFileVolumeName, configFileName)
+	}
+
+	// the init containers and sidecars of the loadtest share a writable volume with ghz, the test file volume is
+	// read-only
+	podSpec := &job.Spec.Template.Spec
+	containerMounts := mounts
+	if len(loadTest.Spec.InitContainers) > 0 || len(loadTest.Spec.Sidecars) > 0 {
+		sharedVolume, sharedMount := backends.SharedVolumeAndMount()
+		podSpec.Volumes = append(podSpec.Volumes, sharedVolume)
+		containerMounts = append(slices.Clone(mounts), sharedMount)
+		podSpec.Containers[0].VolumeMounts = containerMounts
+	}
+
+	// the init containers of the loadtest run after the test file is fetched, so they can use it
+	podSpec.InitContainers = append(podSpec.InitContainers, backends.InitContainers(loadTest.Spec.InitContainers, containerMounts)...)
+
+	// native sidecars are started after the other init containers, right before ghz
+	sidecarInitContainers, sidecarContainers := backends.Sidecars(loadTest.Spec.Sidecars, containerMounts, b.nativeSidecars)
+	podSpec.InitContainers = append(podSpec.InitContainers, sidecarInitContainers...)
+	podSpec.Containers = append(podSpec.Containers, sidecarContainers...)
+
+	backends.SetSpecHash(job, loadTest)
+	return job
+}
+
+// totalPerPod returns the number of requests each of the given pods runs, so distributed pods run the total
+// number of requests of the test file together. The share is rounded up, so the total is never undershot.
+// Only JSON test files requesting a total number of requests without a fixed duration are split.
+func totalPerPod(testFile []byte, pods *int32) (uint, bool) {
+	if pods == nil || *pods <= 1 {
+		return 0, false
+	}
+
+	var cfg struct {
+		Total    uint   `json:"total"`
+		Duration string `json:"duration"`
+	}
+	if err := json.Unmarshal(testFile, &cfg); err != nil {
+		return 0, false
+	}
+
+	if cfg.Total == 0 || cfg.Duration != "" {
+		return 0, false
+	}
+
+	n := uint(*pods)
+	return (cfg.Total + n - 1) / n, true
 }
 
 // NewFileVolumeAndMount creates a new volume and volume mount for a configmap file
@@ -124,8 +291,40 @@ func NewFileVolumeAndMount(name, cfg, filename string) (coreV1.Volume, coreV1.Vo
 	return v, m
 }
 
-// NewFileConfigMap creates a configmap for the provided file information
-func NewFileConfigMap(cfgName, filename string, content []byte) (*coreV1.ConfigMap, error) {
+// envFromSources returns the envFrom sources of the Secrets and ConfigMaps referenced by the loadtest
+func envFromSources(sources []loadTestV1.EnvFromSource) []coreV1.EnvFromSource {
+	var envFrom []coreV1.EnvFromSource
+	for _, source := range sources {
+		envSource := coreV1.EnvFromSource{Prefix: source.Prefix}
+		if source.SecretName != "" {
+			envSource.SecretRef = &coreV1.SecretEnvSource{
+				LocalObjectReference: coreV1.LocalObjectReference{Name: source.SecretName},
+			}
+		} else {
+			envSource.ConfigMapRef = &coreV1.ConfigMapEnvSource{
+				LocalObjectReference: coreV1.LocalObjectReference{Name: source.ConfigMapName},
+			}
+		}
+		envFrom = append(envFrom, envSource)
+	}
+	return envFrom
+}
+
+// NewEnvVarsSecret creates a new secret containing the environment variables of the loadtest
+func NewEnvVarsSecret(loadTest loadTestV1.LoadTest) *coreV1.Secret {
+	return &coreV1.Secret{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name: loadTestEnvVarsSecretName,
+			OwnerReferences: []metaV1.OwnerReference{
+				*metaV1.NewControllerRef(&loadTest, loadTestV1.SchemeGroupVersion.WithKind("LoadTest")),
+			},
+		},
+		StringData: loadTest.Spec.EnvVars,
+	}
+}
+
+// NewFileConfigMap creates a configmap for the provided file information, owned by the loadtest
+func NewFileConfigMap(loadTest loadTestV1.LoadTest, cfgName, filename string, content []byte) (*coreV1.ConfigMap, error) {
 	if strings.TrimSpace(cfgName) == "" {
 		return nil, errors.New("empty config name")
 	}
@@ -141,6 +340,9 @@ func NewFileConfigMap(cfgName, filename string, content []byte) (*coreV1.ConfigM
 	return &coreV1.ConfigMap{
 		ObjectMeta: metaV1.ObjectMeta{
 			Name: cfgName,
+			OwnerReferences: []metaV1.OwnerReference{
+				*metaV1.NewControllerRef(&loadTest, loadTestV1.SchemeGroupVersion.WithKind("LoadTest")),
+			},
 		},
 		BinaryData: map[string][]byte{
 			filename: content,
@@ -150,7 +352,7 @@ func NewFileConfigMap(cfgName, filename string, content []byte) (*coreV1.ConfigM
 
 // determineLoadTestStatusFromJobs reads existing job statuses and determines what the loadtest status should be
 func determineLoadTestStatusFromJobs(job *batchV1.Job) loadTestV1.LoadTestPhase {
-	if job.Status.Failed > int32(0) {
+	if backends.JobFailed(*job) {
 		return loadTestV1.LoadTestErrored
 	}
 
@@ -158,9 +360,30 @@ func determineLoadTestStatusFromJobs(job *batchV1.Job) loadTestV1.LoadTestPhase
 		return loadTestV1.LoadTestRunning
 	}
 
-	if job.Status.Succeeded == 0 && job.Status.Failed == 0 {
+	// failed pods within the backoff limit are being replaced
+	if job.Status.Succeeded == 0 {
 		return loadTestV1.LoadTestStarting
 	}
 
+	// distributed pods finish one by one, the loadtest is finished once all of them completed
+	if job.Spec.Completions != nil && job.Status.Succeeded < *job.Spec.Completions {
+		return loadTestV1.LoadTestRunning
+	}
+
 	return loadTestV1.LoadTestFinished
 }
+
+// labels returns the labels of the job and its pods, with the loadtest tags when tag labels are enabled.
+// Tags which can't be sanitized to a valid label are skipped with a warning.
+func (b *Backend) labels(loadTest loadTestV1.LoadTest, logger *zap.Logger) map[string]string {
+	labels := map[string]string{}
+	if b.tagLabels {
+		var skipped []string
+		labels, skipped = backends.TagLabels(loadTest.Spec.Tags)
+		if len(skipped) > 0 {
+			logger.Warn("Skipped loadtest tags which are not valid labels", zap.Strings("tags", skipped))
+		}
+	}
+	labels["name"] = loadTestJobName
+	return labels
+}
@@ -2,11 +2,16 @@ package ghz
 
 import (
 	"testing"
+	"time"
 
+	"github.com/hellofresh/kangal/pkg/backends"
 	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
 	batchV1 "k8s.io/api/batch/v1"
 	coreV1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -44,6 +49,8 @@ func TestGetLoadTestStatusFromJobs(t *testing.T) {
 }
 
 func TestNewFileConfigMap(t *testing.T) {
+	loadTest := loadTestV1.LoadTest{ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-name", UID: "loadtest-uid"}}
+
 	for _, ti := range []struct {
 		tag         string
 		cfgName     string
@@ -84,6 +91,9 @@ func TestNewFileConfigMap(t *testing.T) {
 			expected: &coreV1.ConfigMap{
 				ObjectMeta: metaV1.ObjectMeta{
 					Name: "test",
+					OwnerReferences: []metaV1.OwnerReference{
+						*metaV1.NewControllerRef(&loadTest, loadTestV1.SchemeGroupVersion.WithKind("LoadTest")),
+					},
 				},
 				BinaryData: map[string][]byte{
 					"file": []byte("file content"),
@@ -93,7 +103,7 @@ func TestNewFileConfigMap(t *testing.T) {
 		},
 	} {
 		t.Run(ti.tag, func(t *testing.T) {
-			cfgmap, err := NewFileConfigMap(ti.cfgName, ti.filename, ti.content)
+			cfgmap, err := NewFileConfigMap(loadTest, ti.cfgName, ti.filename, ti.content)
 
 			assert.Equal(t, ti.expected, cfgmap)
 			if ti.expectError {
@@ -143,3 +153,534 @@ func TestNewFileVolumeAndMount(t *testing.T) {
 		})
 	}
 }
+
+func TestNewJobReportFormat(t *testing.T) {
+	distributedPods := int32(1)
+	loadTest := loadTestV1.LoadTest{
+		ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-name"},
+		Spec:       loadTestV1.LoadTestSpec{DistributedPods: &distributedPods, MasterConfig: "hellofresh/kangal-ghz:latest"},
+		Status:     loadTestV1.LoadTestStatus{Namespace: "test"},
+	}
+
+	for _, tt := range []struct {
+		format              string
+		expectedArgs        []string
+		expectedContentType string
+	}{
+		{
+			format:              "",
+			expectedArgs:        []string{"--config=/data/config", "--output=/results.html", "--format=html"},
+			expectedContentType: "text/html; charset=utf-8",
+		},
+		{
+			format:              "json",
+			expectedArgs:        []string{"--config=/data/config", "--output=/results.json", "--format=json"},
+			expectedContentType: "application/json",
+		},
+		{
+			format:              "csv",
+			expectedArgs:        []string{"--config=/data/config", "--output=/results.csv", "--format=csv"},
+			expectedContentType: "text/csv; charset=utf-8",
+		},
+	} {
+		t.Run(tt.format, func(t *testing.T) {
+			b := Backend{logger: zap.NewNop(), reportFormat: tt.format}
+
+			container := b.NewJob(loadTest, nil, nil, "").Spec.Template.Spec.Containers[0]
+			assert.Equal(t, tt.expectedArgs, container.Args)
+			assert.Contains(t, container.Env, coreV1.EnvVar{Name: "REPORT_CONTENT_TYPE", Value: tt.expectedContentType})
+		})
+	}
+}
+
+func TestNewJobSpecReportFormat(t *testing.T) {
+	distributedPods := int32(1)
+	loadTest := loadTestV1.LoadTest{
+		ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-name"},
+		Spec: loadTestV1.LoadTestSpec{
+			DistributedPods: &distributedPods,
+			MasterConfig:    "hellofresh/kangal-ghz:latest",
+			ReportFormat:    "csv",
+		},
+		Status: loadTestV1.LoadTestStatus{Namespace: "test"},
+	}
+
+	b := Backend{logger: zap.NewNop(), reportFormat: "json"}
+	container := b.NewJob(loadTest, nil, nil, "").Spec.Template.Spec.Containers[0]
+	assert.Equal(t, []string{"--config=/data/config", "--output=/results.csv", "--format=csv"}, container.Args)
+	assert.Contains(t, container.Env, coreV1.EnvVar{Name: "REPORT_FILE", Value: "/results.csv"})
+	assert.Contains(t, container.Env, coreV1.EnvVar{Name: "REPORT_CONTENT_TYPE", Value: "text/csv; charset=utf-8"})
+}
+
+func TestSetDefaultsReportFormat(t *testing.T) {
+	b := Backend{config: &Config{ReportFormat: "json"}}
+	b.SetDefaults()
+	assert.Equal(t, "json", b.reportFormat)
+
+	b = Backend{config: &Config{ReportFormat: "influx-details"}, logger: zap.NewNop()}
+	b.SetDefaults()
+	assert.Equal(t, defaultReportFormat, b.reportFormat)
+}
+
+func TestParseArgs(t *testing.T) {
+	for _, tt := range []struct {
+		name           string
+		args           []string
+		expectedFormat string
+		expectedExtra  []string
+		expectedError  error
+	}{
+		{name: "no args"},
+		{
+			name:          "extra flags",
+			args:          []string{"--insecure", "--connections=5"},
+			expectedExtra: []string{"--insecure", "--connections=5"},
+		},
+		{
+			name:           "format with value",
+			args:           []string{"--format=json", "--insecure"},
+			expectedFormat: "json",
+			expectedExtra:  []string{"--insecure"},
+		},
+		{
+			name:           "short format flag",
+			args:           []string{"-O", "csv"},
+			expectedFormat: "csv",
+		},
+		{
+			name:          "unsupported format",
+			args:          []string{"--format=influx-details"},
+			expectedError: ErrInvalidArg,
+		},
+		{
+			name:          "format without value",
+			args:          []string{"--format"},
+			expectedError: ErrInvalidArg,
+		},
+		{
+			name:          "config is reserved",
+			args:          []string{"--config=/tmp/config"},
+			expectedError: ErrReservedArg,
+		},
+		{
+			name:          "output is reserved",
+			args:          []string{"-o", "/tmp/report"},
+			expectedError: ErrReservedArg,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			format, extra, err := parseArgs(tt.args)
+			assert.ErrorIs(t, err, tt.expectedError)
+			assert.Equal(t, tt.expectedFormat, format)
+			assert.Equal(t, tt.expectedExtra, extra)
+		})
+	}
+}
+
+func TestNewJobArgs(t *testing.T) {
+	distributedPods := int32(1)
+	loadTest := loadTestV1.LoadTest{
+		ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-name"},
+		Spec: loadTestV1.LoadTestSpec{
+			DistributedPods: &distributedPods,
+			MasterConfig:    "hellofresh/kangal-ghz:latest",
+			Args:            []string{"--format=json", "--insecure", "--connections=5"},
+		},
+		Status: loadTestV1.LoadTestStatus{Namespace: "test"},
+	}
+
+	container := (&Backend{logger: zap.NewNop()}).NewJob(loadTest, nil, nil, "").Spec.Template.Spec.Containers[0]
+	assert.Equal(t, []string{"--config=/data/config", "--output=/results.json", "--format=json", "--insecure", "--connections=5"}, container.Args)
+	assert.Contains(t, container.Env, coreV1.EnvVar{Name: "REPORT_CONTENT_TYPE", Value: "application/json"})
+
+	loadTest.Spec.Args = []string{"--config=/tmp/config"}
+	container = (&Backend{logger: zap.NewNop()}).NewJob(loadTest, nil, nil, "").Spec.Template.Spec.Containers[0]
+	assert.Equal(t, []string{"--config=/data/config", "--output=/results.html", "--format=html"}, container.Args)
+}
+
+func TestTotalPerPod(t *testing.T) {
+	one, three := int32(1), int32(3)
+
+	for _, tt := range []struct {
+		name          string
+		testFile      string
+		pods          *int32
+		expectedTotal uint
+		expectedOK    bool
+	}{
+		{name: "unset pods", testFile: `{"total": 100}`},
+		{name: "single pod", testFile: `{"total": 100}`, pods: &one},
+		{name: "even split", testFile: `{"total": 99}`, pods: &three, expectedTotal: 33, expectedOK: true},
+		{name: "rounded up", testFile: `{"total": 100}`, pods: &three, expectedTotal: 34, expectedOK: true},
+		{name: "fixed duration", testFile: `{"total": 100, "duration": "10s"}`, pods: &three},
+		{name: "no total", testFile: `{"concurrency": 10}`, pods: &three},
+		{name: "toml", testFile: `total = 100`, pods: &three},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			total, ok := totalPerPod([]byte(tt.testFile), tt.pods)
+			assert.Equal(t, tt.expectedOK, ok)
+			assert.Equal(t, tt.expectedTotal, total)
+		})
+	}
+}
+
+func TestNewJobDistributedPods(t *testing.T) {
+	distributedPods := int32(4)
+	loadTest := loadTestV1.LoadTest{
+		ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-name"},
+		Spec: loadTestV1.LoadTestSpec{
+			DistributedPods: &distributedPods,
+			MasterConfig:    "hellofresh/kangal-ghz:latest",
+			TestFile:        []byte(`{"total": 1000, "concurrency": 10}`),
+		},
+		Status: loadTestV1.LoadTestStatus{Namespace: "test"},
+	}
+
+	job := (&Backend{logger: zap.NewNop()}).NewJob(loadTest, nil, nil, "")
+	assert.Equal(t, &distributedPods, job.Spec.Parallelism)
+	assert.Equal(t, &distributedPods, job.Spec.Completions)
+	assert.Contains(t, job.Spec.Template.Spec.Containers[0].Args, "--total=250")
+}
+
+func TestGetLoadTestStatusFromDistributedJobs(t *testing.T) {
+	completions := int32(3)
+
+	for _, tt := range []struct {
+		status        batchV1.JobStatus
+		expectedPhase loadTestV1.LoadTestPhase
+	}{
+		{batchV1.JobStatus{Active: 3}, loadTestV1.LoadTestRunning},
+		{batchV1.JobStatus{Active: 1, Succeeded: 2}, loadTestV1.LoadTestRunning},
+		{batchV1.JobStatus{Succeeded: 2}, loadTestV1.LoadTestRunning},
+		{batchV1.JobStatus{Succeeded: 3}, loadTestV1.LoadTestFinished},
+		{batchV1.JobStatus{Failed: 1, Succeeded: 2}, loadTestV1.LoadTestErrored},
+	} {
+		job := &batchV1.Job{
+			Spec:   batchV1.JobSpec{Completions: &completions},
+			Status: tt.status,
+		}
+		assert.Equal(t, tt.expectedPhase, determineLoadTestStatusFromJobs(job))
+	}
+}
+
+func TestNewJobResources(t *testing.T) {
+	distributedPods := int32(1)
+	loadTest := loadTestV1.LoadTest{
+		ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-name"},
+		Spec: loadTestV1.LoadTestSpec{
+			DistributedPods: &distributedPods,
+			MasterConfig:    "hellofresh/kangal-ghz:latest",
+			Resources:       &loadTestV1.LoadTestResources{CPULimits: "2"},
+		},
+		Status: loadTestV1.LoadTestStatus{Namespace: "test"},
+	}
+
+	b := Backend{
+		logger:    zap.NewNop(),
+		resources: backends.Resources{CPULimits: "500m", MemoryLimits: "128Mi"},
+	}
+
+	resources := b.NewJob(loadTest, nil, nil, "").Spec.Template.Spec.Containers[0].Resources
+	assert.Equal(t, resource.MustParse("2"), resources.Limits[coreV1.ResourceCPU])
+	assert.Equal(t, resource.MustParse("128Mi"), resources.Limits[coreV1.ResourceMemory])
+
+	loadTest.Spec.Resources = nil
+	resources = b.NewJob(loadTest, nil, nil, "").Spec.Template.Spec.Containers[0].Resources
+	assert.Equal(t, resource.MustParse("500m"), resources.Limits[coreV1.ResourceCPU])
+}
+
+func TestGetLoadTestStatusFromRetriedJobs(t *testing.T) {
+	backoffLimit := int32(2)
+
+	for _, tt := range []struct {
+		status        batchV1.JobStatus
+		expectedPhase loadTestV1.LoadTestPhase
+	}{
+		{batchV1.JobStatus{Failed: 1}, loadTestV1.LoadTestStarting},
+		{batchV1.JobStatus{Active: 1, Failed: 2}, loadTestV1.LoadTestRunning},
+		{batchV1.JobStatus{Failed: 2, Succeeded: 1}, loadTestV1.LoadTestFinished},
+		{batchV1.JobStatus{Failed: 3}, loadTestV1.LoadTestErrored},
+	} {
+		job := &batchV1.Job{
+			Spec:   batchV1.JobSpec{BackoffLimit: &backoffLimit},
+			Status: tt.status,
+		}
+		assert.Equal(t, tt.expectedPhase, determineLoadTestStatusFromJobs(job))
+	}
+}
+
+func TestNewJobBackoffLimit(t *testing.T) {
+	distributedPods := int32(1)
+	loadTest := loadTestV1.LoadTest{
+		ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-name"},
+		Spec: loadTestV1.LoadTestSpec{
+			DistributedPods: &distributedPods,
+			MasterConfig:    "hellofresh/kangal-ghz:latest",
+		},
+		Status: loadTestV1.LoadTestStatus{Namespace: "test"},
+	}
+
+	b := &Backend{logger: zap.NewNop()}
+	assert.Equal(t, int32(0), *b.NewJob(loadTest, nil, nil, "").Spec.BackoffLimit)
+
+	b.SetJobBackoffLimit(3)
+	assert.Equal(t, int32(3), *b.NewJob(loadTest, nil, nil, "").Spec.BackoffLimit)
+}
+
+func TestNewJobPodScheduling(t *testing.T) {
+	distributedPods := int32(1)
+	loadTest := loadTestV1.LoadTest{
+		ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-name"},
+		Spec: loadTestV1.LoadTestSpec{
+			DistributedPods: &distributedPods,
+			MasterConfig:    "hellofresh/kangal-ghz:latest",
+			PodAnnotations:  map[string]string{"sidecar.istio.io/inject": "false"},
+		},
+		Status: loadTestV1.LoadTestStatus{Namespace: "test"},
+	}
+
+	nodeSelector := map[string]string{"nodetype": "loadtest"}
+	tolerations := []coreV1.Toleration{{
+		Key:      "app",
+		Operator: coreV1.TolerationOpEqual,
+		Value:    "loadtest",
+		Effect:   coreV1.TaintEffectNoSchedule,
+	}}
+
+	b := &Backend{logger: zap.NewNop()}
+	b.SetPodNodeSelector(nodeSelector)
+	b.SetPodTolerations(tolerations)
+	b.SetPodAnnotations(map[string]string{"iam.amazonaws.com/role": "loadtest"})
+
+	template := b.NewJob(loadTest, nil, nil, "").Spec.Template
+	assert.Equal(t, nodeSelector, template.Spec.NodeSelector)
+	assert.Equal(t, tolerations, template.Spec.Tolerations)
+	assert.Equal(t, "loadtest", template.Annotations["iam.amazonaws.com/role"])
+	assert.Equal(t, "false", template.Annotations["sidecar.istio.io/inject"])
+}
+
+func TestNewJobSeccompProfile(t *testing.T) {
+	distributedPods := int32(1)
+	loadTest := loadTestV1.LoadTest{
+		ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-name"},
+		Spec: loadTestV1.LoadTestSpec{
+			DistributedPods: &distributedPods,
+			MasterConfig:    "hellofresh/kangal-ghz:latest",
+		},
+		Status: loadTestV1.LoadTestStatus{Namespace: "test"},
+	}
+
+	b := &Backend{logger: zap.NewNop()}
+	assert.Nil(t, b.NewJob(loadTest, nil, nil, "").Spec.Template.Spec.SecurityContext)
+
+	b.SetSeccompProfile(&coreV1.SeccompProfile{Type: coreV1.SeccompProfileTypeRuntimeDefault})
+	securityContext := b.NewJob(loadTest, nil, nil, "").Spec.Template.Spec.SecurityContext
+	assert.Equal(t, coreV1.SeccompProfileTypeRuntimeDefault, securityContext.SeccompProfile.Type)
+}
+
+func TestNewJobEnvVars(t *testing.T) {
+	distributedPods := int32(1)
+	loadTest := loadTestV1.LoadTest{
+		ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-name"},
+		Spec: loadTestV1.LoadTestSpec{
+			DistributedPods: &distributedPods,
+			MasterConfig:    "registry.example.com/kangal-ghz:latest",
+		},
+		Status: loadTestV1.LoadTestStatus{Namespace: "test"},
+	}
+
+	b := &Backend{logger: zap.NewNop()}
+	assert.Nil(t, b.NewJob(loadTest, nil, nil, "").Spec.Template.Spec.Containers[0].EnvFrom)
+
+	loadTest.Spec.EnvVars = map[string]string{"API_TOKEN": "secret-token"}
+	container := b.NewJob(loadTest, nil, nil, "http://kangal-proxy.local/report").Spec.Template.Spec.Containers[0]
+	require.Len(t, container.EnvFrom, 1)
+	assert.Equal(t, loadTestEnvVarsSecretName, container.EnvFrom[0].SecretRef.Name)
+	assert.Contains(t, container.Env, coreV1.EnvVar{Name: "REPORT_PRESIGNED_URL", Value: "http://kangal-proxy.local/report"})
+
+	// referenced sources come first, so the variables of the loadtest win
+	loadTest.Spec.EnvFrom = []loadTestV1.EnvFromSource{
+		{SecretName: "grpc-credentials"},
+		{ConfigMapName: "grpc-settings", Prefix: "GRPC_"},
+	}
+	container = b.NewJob(loadTest, nil, nil, "").Spec.Template.Spec.Containers[0]
+	assert.Equal(t, []coreV1.EnvFromSource{
+		{SecretRef: &coreV1.SecretEnvSource{LocalObjectReference: coreV1.LocalObjectReference{Name: "grpc-credentials"}}},
+		{Prefix: "GRPC_", ConfigMapRef: &coreV1.ConfigMapEnvSource{LocalObjectReference: coreV1.LocalObjectReference{Name: "grpc-settings"}}},
+		{SecretRef: &coreV1.SecretEnvSource{LocalObjectReference: coreV1.LocalObjectReference{Name: loadTestEnvVarsSecretName}}},
+	}, container.EnvFrom)
+}
+
+func TestNewJobServiceAccount(t *testing.T) {
+	distributedPods := int32(1)
+	loadTest := loadTestV1.LoadTest{
+		ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-name"},
+		Spec: loadTestV1.LoadTestSpec{
+			DistributedPods: &distributedPods,
+			MasterConfig:    "registry.example.com/kangal-ghz:latest",
+		},
+		Status: loadTestV1.LoadTestStatus{Namespace: "test"},
+	}
+
+	b := &Backend{logger: zap.NewNop()}
+	assert.Empty(t, b.NewJob(loadTest, nil, nil, "").Spec.Template.Spec.ServiceAccountName)
+
+	b.SetPodServiceAccount("kangal-load")
+	assert.Equal(t, "kangal-load", b.NewJob(loadTest, nil, nil, "").Spec.Template.Spec.ServiceAccountName)
+
+	loadTest.Spec.ServiceAccountName = "team-load"
+	assert.Equal(t, "team-load", b.NewJob(loadTest, nil, nil, "").Spec.Template.Spec.ServiceAccountName)
+}
+
+func TestNewJobTimeout(t *testing.T) {
+	distributedPods := int32(1)
+	loadTest := loadTestV1.LoadTest{
+		ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-name"},
+		Spec: loadTestV1.LoadTestSpec{
+			DistributedPods: &distributedPods,
+			MasterConfig:    "registry.example.com/kangal-ghz:latest",
+		},
+		Status: loadTestV1.LoadTestStatus{Namespace: "test"},
+	}
+
+	b := &Backend{logger: zap.NewNop()}
+	assert.Nil(t, b.NewJob(loadTest, nil, nil, "").Spec.ActiveDeadlineSeconds)
+
+	b.SetJobTimeout(time.Hour)
+	assert.Equal(t, int64(3600), *b.NewJob(loadTest, nil, nil, "").Spec.ActiveDeadlineSeconds)
+
+	loadTest.Spec.Timeout = 5 * time.Minute
+	assert.Equal(t, int64(300), *b.NewJob(loadTest, nil, nil, "").Spec.ActiveDeadlineSeconds)
+}
+
+func TestNewJobImagePullSecrets(t *testing.T) {
+	distributedPods := int32(1)
+	loadTest := loadTestV1.LoadTest{
+		ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-name"},
+		Spec: loadTestV1.LoadTestSpec{
+			DistributedPods: &distributedPods,
+			MasterConfig:    "registry.example.com/kangal-ghz:latest",
+		},
+		Status: loadTestV1.LoadTestStatus{Namespace: "test"},
+	}
+
+	b := &Backend{logger: zap.NewNop()}
+	assert.Nil(t, b.NewJob(loadTest, nil, nil, "").Spec.Template.Spec.ImagePullSecrets)
+
+	b.SetImagePullSecrets([]string{"registry"})
+	podSpec := b.NewJob(loadTest, nil, nil, "").Spec.Template.Spec
+	assert.Equal(t, []coreV1.LocalObjectReference{{Name: "registry"}}, podSpec.ImagePullSecrets)
+
+	loadTest.Spec.ImagePullSecrets = []string{"team-registry"}
+	podSpec = b.NewJob(loadTest, nil, nil, "").Spec.Template.Spec
+	assert.Equal(t, []coreV1.LocalObjectReference{{Name: "team-registry"}}, podSpec.ImagePullSecrets)
+}
+
+func TestNewJobInitContainers(t *testing.T) {
+	distributedPods := int32(1)
+	loadTest := loadTestV1.LoadTest{
+		ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-name"},
+		Spec: loadTestV1.LoadTestSpec{
+			DistributedPods: &distributedPods,
+			MasterConfig:    "hellofresh/kangal-ghz:latest",
+			TestFileURL:     "https://example.com/config.json",
+			InitContainers: []loadTestV1.InitContainer{
+				{Name: "seed", Image: "postgres:16", Command: []string{"psql", "-f", "/data/seed.sql"}},
+				{Name: "fixtures", Image: "curlimages/curl:8.5.0"},
+			},
+		},
+		Status: loadTestV1.LoadTestStatus{Namespace: "test"},
+	}
+
+	volume, mount := NewFileVolumeAndMount(loadTestFileVolumeName, "config-map", configFileName)
+	b := &Backend{
+		logger:          zap.NewNop(),
+		testFileFetcher: backends.TestFileFetcher{Image: "curlimages/curl:8.5.0"},
+	}
+	podSpec := b.NewJob(loadTest, []coreV1.Volume{volume}, []coreV1.VolumeMount{mount}, "").Spec.Template.Spec
+
+	// the test file is fetched first, then the init containers of the loadtest run in order
+	require.Len(t, podSpec.InitContainers, 3)
+	assert.Equal(t, backends.TestFileFetcherContainerName, podSpec.InitContainers[0].Name)
+	assert.Equal(t, "seed", podSpec.InitContainers[1].Name)
+	assert.Equal(t, "fixtures", podSpec.InitContainers[2].Name)
+
+	// the init containers share a writable volume with ghz
+	sharedVolume, sharedMount := backends.SharedVolumeAndMount()
+	assert.Contains(t, podSpec.Volumes, sharedVolume)
+	assert.Equal(t, []coreV1.VolumeMount{mount, sharedMount}, podSpec.InitContainers[1].VolumeMounts)
+	assert.Equal(t, []coreV1.VolumeMount{mount, sharedMount}, podSpec.Containers[0].VolumeMounts)
+
+	loadTest.Spec.TestFileURL = ""
+	loadTest.Spec.InitContainers = nil
+	podSpec = b.NewJob(loadTest, []coreV1.Volume{volume}, []coreV1.VolumeMount{mount}, "").Spec.Template.Spec
+	assert.Empty(t, podSpec.InitContainers)
+	assert.NotContains(t, podSpec.Volumes, sharedVolume)
+	assert.Equal(t, []coreV1.VolumeMount{mount}, podSpec.Containers[0].VolumeMounts)
+}
+
+func TestNewJobSidecars(t *testing.T) {
+	distributedPods := int32(1)
+	loadTest := loadTestV1.LoadTest{
+		ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-name"},
+		Spec: loadTestV1.LoadTestSpec{
+			DistributedPods: &distributedPods,
+			MasterConfig:    "hellofresh/kangal-ghz:latest",
+			InitContainers:  []loadTestV1.InitContainer{{Name: "seed", Image: "postgres:16"}},
+			Sidecars: []loadTestV1.Sidecar{
+				{Name: "prometheus", Image: "prom/prometheus", Native: true},
+				{Name: "exporter", Image: "prom/pushgateway"},
+			},
+		},
+		Status: loadTestV1.LoadTestStatus{Namespace: "test"},
+	}
+
+	volume, mount := NewFileVolumeAndMount(loadTestFileVolumeName, "config-map", configFileName)
+	b := &Backend{logger: zap.NewNop()}
+
+	// without native sidecar support all sidecars run next to ghz
+	podSpec := b.NewJob(loadTest, []coreV1.Volume{volume}, []coreV1.VolumeMount{mount}, "").Spec.Template.Spec
+	require.Len(t, podSpec.InitContainers, 1)
+	require.Len(t, podSpec.Containers, 3)
+	assert.Equal(t, "ghz", podSpec.Containers[0].Name)
+	assert.Equal(t, "prometheus", podSpec.Containers[1].Name)
+	assert.Nil(t, podSpec.Containers[1].RestartPolicy)
+	_, sharedMount := backends.SharedVolumeAndMount()
+	assert.Equal(t, []coreV1.VolumeMount{mount, sharedMount}, podSpec.Containers[1].VolumeMounts)
+	assert.Equal(t, "exporter", podSpec.Containers[2].Name)
+
+	// native sidecars are started after the init containers of the loadtest
+	b.nativeSidecars = true
+	podSpec = b.NewJob(loadTest, []coreV1.Volume{volume}, []coreV1.VolumeMount{mount}, "").Spec.Template.Spec
+	require.Len(t, podSpec.InitContainers, 2)
+	assert.Equal(t, "seed", podSpec.InitContainers[0].Name)
+	assert.Equal(t, "prometheus", podSpec.InitContainers[1].Name)
+	require.NotNil(t, podSpec.InitContainers[1].RestartPolicy)
+	assert.Equal(t, coreV1.ContainerRestartPolicyAlways, *podSpec.InitContainers[1].RestartPolicy)
+	require.Len(t, podSpec.Containers, 2)
+	assert.Equal(t, "exporter", podSpec.Containers[1].Name)
+}
+
+func TestNewJobTagLabels(t *testing.T) {
+	distributedPods := int32(1)
+	loadTest := loadTestV1.LoadTest{
+		ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-name"},
+		Spec: loadTestV1.LoadTestSpec{
+			DistributedPods: &distributedPods,
+			MasterConfig:    "hellofresh/kangal-ghz:latest",
+			Tags:            loadTestV1.LoadTestTags{"team": "Payments & Billing", "name": "other", "owner": "!!!"},
+		},
+		Status: loadTestV1.LoadTestStatus{Namespace: "test"},
+	}
+
+	b := &Backend{logger: zap.NewNop()}
+	job := b.NewJob(loadTest, nil, nil, "")
+	assert.Equal(t, map[string]string{"name": loadTestJobName}, job.Labels)
+	assert.Equal(t, map[string]string{"name": loadTestJobName}, job.Spec.Template.Labels)
+
+	b.SetTagLabels(true)
+	job = b.NewJob(loadTest, nil, nil, "")
+	expected := map[string]string{"name": loadTestJobName, "team": "Payments-Billing"}
+	assert.Equal(t, expected, job.Labels)
+	assert.Equal(t, expected, job.Spec.Template.Labels)
+}
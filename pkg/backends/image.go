@@ -0,0 +1,105 @@
+package backends
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+// maxImageNameLength is the maximum length of the name of an image reference, registry included
+const maxImageNameLength = 255
+
+var (
+	// ErrInvalidImageReference is returned for images that are not valid OCI image references
+	ErrInvalidImageReference = errors.New("invalid image reference")
+
+	// imageDomainRegexp matches the optional registry of an image name, e.g. registry.example.com:5000
+	imageDomainRegexp = regexp.MustCompile(`^(?:[a-zA-Z0-9]|[a-zA-Z0-9][a-zA-Z0-9-]*[a-zA-Z0-9])(?:\.(?:[a-zA-Z0-9]|[a-zA-Z0-9][a-zA-Z0-9-]*[a-zA-Z0-9]))*(?::[0-9]+)?$`)
+	// imagePathRegexp matches a single path component of an image name
+	imagePathRegexp = regexp.MustCompile(`^[a-z0-9]+(?:(?:[._]|__|-+)[a-z0-9]+)*$`)
+	// imageTagRegexp matches the tag of an image reference
+	imageTagRegexp = regexp.MustCompile(`^[\w][\w.-]{0,127}$`)
+	// imageDigestRegexp matches the digest of an image reference
+	imageDigestRegexp = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9]*(?:[-_+.][A-Za-z][A-Za-z0-9]*)*:[0-9a-fA-F]{32,}$`)
+)
+
+// ValidateImageReference returns an error stating what is wrong when the image is not a valid OCI
+// image reference of the form [registry/]repository[:tag][@digest]
+func ValidateImageReference(image string) error {
+	invalid := func(format string, args ...interface{}) error {
+		return fmt.Errorf("%w %q: %s", ErrInvalidImageReference, image, fmt.Sprintf(format, args...))
+	}
+
+	if image == "" {
+		return invalid("image is empty")
+	}
+	if strings.IndexFunc(image, unicode.IsSpace) >= 0 {
+		return invalid("must not contain whitespace")
+	}
+
+	name, digest, hasDigest := strings.Cut(image, "@")
+	if hasDigest && !imageDigestRegexp.MatchString(digest) {
+		return invalid("digest %q is not of the form algorithm:hex", digest)
+	}
+
+	if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		tag := name[i+1:]
+		name = name[:i]
+		if tag == "" {
+			return invalid("tag is empty")
+		}
+		if !imageTagRegexp.MatchString(tag) {
+			return invalid("tag %q may only contain letters, digits, '_', '.' and '-' and must not start with '.' or '-'", tag)
+		}
+	}
+
+	if name == "" {
+		return invalid("repository is empty")
+	}
+	if len(name) > maxImageNameLength {
+		return invalid("name is longer than %d characters", maxImageNameLength)
+	}
+
+	components := strings.Split(name, "/")
+	// the first component is a registry when it has a dot, a port or is localhost, as Docker tells them apart
+	if len(components) > 1 && (strings.ContainsAny(components[0], ".:") || components[0] == "localhost") {
+		if !imageDomainRegexp.MatchString(components[0]) {
+			return invalid("registry %q is not a valid host name", components[0])
+		}
+		components = components[1:]
+	}
+	for _, component := range components {
+		if !imagePathRegexp.MatchString(component) {
+			if component != strings.ToLower(component) {
+				return invalid("repository %q must be lowercase", strings.Join(components, "/"))
+			}
+			return invalid("repository %q may only contain lowercase letters, digits and separators", strings.Join(components, "/"))
+		}
+	}
+	return nil
+}
+
+// ValidateImages returns an error when the master or worker image of the spec is set but is not
+// a valid image reference, unset images fall back to the backend defaults
+func ValidateImages(spec loadTestV1.LoadTestSpec) error {
+	images := []struct {
+		role  string
+		image loadTestV1.ImageDetails
+	}{
+		{"master", spec.MasterConfig},
+		{"worker", spec.WorkerConfig},
+	}
+	for _, i := range images {
+		if i.image == "" {
+			continue
+		}
+		if err := ValidateImageReference(string(i.image)); err != nil {
+			return fmt.Errorf("%s image: %w", i.role, err)
+		}
+	}
+	return nil
+}
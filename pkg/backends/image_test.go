@@ -0,0 +1,53 @@
+package backends
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+func TestValidateImageReference(t *testing.T) {
+	for _, tt := range []struct {
+		image   string
+		wantErr string
+	}{
+		{image: "grafana/k6"},
+		{image: "grafana/k6:0.49.0"},
+		{image: "busybox"},
+		{image: "registry.example.com:5000/team/load_test-runner:v1.2"},
+		{image: "localhost/k6:latest"},
+		{image: "grafana/k6@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"},
+		{image: "grafana/k6:0.49.0@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"},
+		{image: "", wantErr: "image is empty"},
+		{image: "my image:v1", wantErr: "must not contain whitespace"},
+		{image: "grafana/k6:", wantErr: "tag is empty"},
+		{image: ":@sha256:", wantErr: `digest "sha256:" is not of the form algorithm:hex`},
+		{image: ":v1", wantErr: "repository is empty"},
+		{image: "grafana/k6:-v1", wantErr: `tag "-v1" may only contain`},
+		{image: "grafana/k6:v1+build", wantErr: `tag "v1+build" may only contain`},
+		{image: "Grafana/k6", wantErr: `repository "Grafana/k6" must be lowercase`},
+		{image: "grafana//k6", wantErr: `repository "grafana//k6" may only contain`},
+		{image: "-registry.example.com/k6", wantErr: `registry "-registry.example.com" is not a valid host name`},
+	} {
+		t.Run(tt.image, func(t *testing.T) {
+			err := ValidateImageReference(tt.image)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, ErrInvalidImageReference)
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestValidateImages(t *testing.T) {
+	assert.NoError(t, ValidateImages(loadTestV1.LoadTestSpec{}))
+	assert.NoError(t, ValidateImages(loadTestV1.LoadTestSpec{MasterConfig: "grafana/k6:latest"}))
+
+	err := ValidateImages(loadTestV1.LoadTestSpec{MasterConfig: "grafana/k6:latest", WorkerConfig: "locust io:2"})
+	assert.ErrorIs(t, err, ErrInvalidImageReference)
+	assert.ErrorContains(t, err, "worker image")
+}
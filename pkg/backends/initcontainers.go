@@ -0,0 +1,132 @@
+package backends
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	coreV1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+const (
+	// SharedVolumeName is the name of the writable volume the load generator shares with the init containers and
+	// sidecars of the loadtest, the test file volume is read-only
+	SharedVolumeName = "shared"
+	// SharedVolumeMountPath is where the shared volume is mounted in the containers of the loadtest pods
+	SharedVolumeMountPath = "/shared"
+)
+
+var (
+	// ErrRequireInitContainerImage the Image field of every init container of the loadtest is required
+	ErrRequireInitContainerImage = errors.New("LoadTest init containers require an image")
+	// ErrInvalidContainerName the names of the init containers and sidecars of the loadtest must be unique DNS-1123
+	// labels in the pod, including the containers added by the backend
+	ErrInvalidContainerName = errors.New("LoadTest container names must be unique DNS-1123 labels")
+)
+
+// ValidateInitContainers checks the init containers of the loadtest can be run. Their names must be valid and unique,
+// and differ from the reserved names of the containers the backend adds to the pod
+func ValidateInitContainers(containers []loadTestV1.InitContainer, reserved ...string) error {
+	seen := containerNameSet(reserved)
+	for i, c := range containers {
+		if c.Image == "" {
+			return fmt.Errorf("%w: init container %d", ErrRequireInitContainerImage, i)
+		}
+		if err := checkContainerName(initContainerName(i, c.Name), seen); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// InitContainerNames returns the names of the init containers of the loadtest in the pod
+func InitContainerNames(containers []loadTestV1.InitContainer) []string {
+	names := make([]string, 0, len(containers))
+	for i, c := range containers {
+		names = append(names, initContainerName(i, c.Name))
+	}
+	return names
+}
+
+// SharedVolumeAndMount returns the writable emptyDir volume shared by the containers of the loadtest pods, e.g. for
+// init containers to write fixtures the load generator reads, and its mount at SharedVolumeMountPath
+func SharedVolumeAndMount() (coreV1.Volume, coreV1.VolumeMount) {
+	volume := coreV1.Volume{
+		Name:         SharedVolumeName,
+		VolumeSource: coreV1.VolumeSource{EmptyDir: &coreV1.EmptyDirVolumeSource{}},
+	}
+	mount := coreV1.VolumeMount{
+		Name:      SharedVolumeName,
+		MountPath: SharedVolumeMountPath,
+	}
+	return volume, mount
+}
+
+// InitContainers returns the init containers of the loadtest, run in the given order before the load generator,
+// with the given volume mounts so they share the test file and shared volumes with it
+func InitContainers(containers []loadTestV1.InitContainer, mounts []coreV1.VolumeMount) []coreV1.Container {
+	if len(containers) == 0 {
+		return nil
+	}
+
+	initContainers := make([]coreV1.Container, 0, len(containers))
+	for i, c := range containers {
+		initContainers = append(initContainers, coreV1.Container{
+			Name:         initContainerName(i, c.Name),
+			Image:        c.Image,
+			Command:      c.Command,
+			Env:          containerEnv(c.Env),
+			VolumeMounts: mounts,
+		})
+	}
+	return initContainers
+}
+
+// containerEnv returns the env vars of a container declared in the loadtest spec, sorted by name so the pod spec
+// does not change between syncs
+func containerEnv(env map[string]string) []coreV1.EnvVar {
+	keys := make([]string, 0, len(env))
+	for key := range env {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var vars []coreV1.EnvVar
+	for _, key := range keys {
+		vars = append(vars, coreV1.EnvVar{Name: key, Value: env[key]})
+	}
+	return vars
+}
+
+// initContainerName returns the name of the init container at the given position, init-<position> if it has none
+func initContainerName(i int, name string) string {
+	if name == "" {
+		return fmt.Sprintf("init-%d", i)
+	}
+	return name
+}
+
+// containerNameSet returns the given container names as a set
+func containerNameSet(names []string) map[string]bool {
+	set := make(map[string]bool, len(names))
+	for _, name := range names {
+		set[name] = true
+	}
+	return set
+}
+
+// checkContainerName checks the container name is a DNS-1123 label not in seen yet, and adds it to seen
+func checkContainerName(name string, seen map[string]bool) error {
+	if errs := validation.IsDNS1123Label(name); len(errs) > 0 {
+		return fmt.Errorf("%w: %q: %s", ErrInvalidContainerName, name, strings.Join(errs, ", "))
+	}
+	if seen[name] {
+		return fmt.Errorf("%w: %q is used by another container", ErrInvalidContainerName, name)
+	}
+	seen[name] = true
+	return nil
+}
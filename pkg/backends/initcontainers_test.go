@@ -0,0 +1,61 @@
+package backends
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	coreV1 "k8s.io/api/core/v1"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+func TestValidateInitContainers(t *testing.T) {
+	assert.NoError(t, ValidateInitContainers(nil))
+	assert.NoError(t, ValidateInitContainers([]loadTestV1.InitContainer{{Image: "busybox"}}))
+	assert.ErrorIs(t, ValidateInitContainers([]loadTestV1.InitContainer{{Image: "busybox"}, {Name: "seed"}}), ErrRequireInitContainerImage)
+
+	// names must be unique DNS-1123 labels, including the default ones
+	assert.ErrorIs(t, ValidateInitContainers([]loadTestV1.InitContainer{{Name: "Seed_DB", Image: "busybox"}}), ErrInvalidContainerName)
+	assert.ErrorIs(t, ValidateInitContainers([]loadTestV1.InitContainer{{Image: "busybox"}, {Name: "init-0", Image: "busybox"}}), ErrInvalidContainerName)
+	assert.ErrorIs(t, ValidateInitContainers([]loadTestV1.InitContainer{{Name: "ghz", Image: "busybox"}}, "ghz"), ErrInvalidContainerName)
+	assert.NoError(t, ValidateInitContainers([]loadTestV1.InitContainer{{Name: "seed", Image: "busybox"}}, "ghz"))
+}
+
+func TestInitContainerNames(t *testing.T) {
+	assert.Equal(t, []string{"seed", "init-1"}, InitContainerNames([]loadTestV1.InitContainer{{Name: "seed"}, {}}))
+}
+
+func TestInitContainers(t *testing.T) {
+	assert.Nil(t, InitContainers(nil, nil))
+
+	mounts := []coreV1.VolumeMount{{Name: "testfile", MountPath: "/data"}}
+	containers := InitContainers([]loadTestV1.InitContainer{
+		{
+			Name:    "seed",
+			Image:   "postgres:16",
+			Command: []string{"psql", "-f", "/data/seed.sql"},
+			Env:     map[string]string{"PGUSER": "kangal", "PGHOST": "db"},
+		},
+		{
+			Image: "busybox",
+		},
+	}, mounts)
+
+	assert.Equal(t, []coreV1.Container{
+		{
+			Name:    "seed",
+			Image:   "postgres:16",
+			Command: []string{"psql", "-f", "/data/seed.sql"},
+			Env: []coreV1.EnvVar{
+				{Name: "PGHOST", Value: "db"},
+				{Name: "PGUSER", Value: "kangal"},
+			},
+			VolumeMounts: mounts,
+		},
+		{
+			Name:         "init-1",
+			Image:        "busybox",
+			VolumeMounts: mounts,
+		},
+	}, containers)
+}
@@ -7,6 +7,7 @@ import (
 	"encoding/base64"
 	"errors"
 	"fmt"
+	"regexp"
 	"time"
 
 	"go.uber.org/zap"
@@ -27,10 +28,17 @@ var (
 	MaxWaitTimeForPods = time.Minute * 10
 	// LoadTestWorkerLabelSelector is the selector used for selecting jmeter worker resources
 	LoadTestWorkerLabelSelector = fmt.Sprintf("%s=%s", loadTestWorkerPodLabelKey, loadTestWorkerPodLabelValue)
+	// loadTestServiceLabelSelector is the selector used for selecting the jmeter worker service
+	loadTestServiceLabelSelector = fmt.Sprintf("app=%s", LoadTestLabel)
 	// ErrRequireMinOneDistributedPod Backend spec requires 1 or more DistributedPods
 	ErrRequireMinOneDistributedPod = errors.New("LoadTest must specify 1 or more DistributedPods")
-	// ErrRequireTestFile the TestFile filed is required to not be an empty string
-	ErrRequireTestFile = errors.New("LoadTest TestFile is required")
+	// ErrRequireTestFile the TestFile field is required to not be an empty string, unless TestFileURL is set
+	ErrRequireTestFile = errors.New("LoadTest TestFile or TestFileURL is required")
+	// ErrRequireMinOneThread the TestFile must have at least one thread group with 1 or more threads
+	ErrRequireMinOneThread = errors.New("LoadTest TestFile must have a thread group with 1 or more threads")
+
+	threadGroupRegexp = regexp.MustCompile(`testclass="[^"]*ThreadGroup"`)
+	zeroThreadsRegexp = regexp.MustCompile(`<(?:stringProp|intProp) name="ThreadGroup\.num_threads">\s*0+\s*</(?:stringProp|intProp)>`)
 )
 
 const (
@@ -58,6 +66,16 @@ type Backend struct {
 	podAnnotations  map[string]string
 	nodeSelector    map[string]string
 	tolerations     []coreV1.Toleration
+	seccompProfile  *coreV1.SeccompProfile
+
+	testFileFetcher    backends.TestFileFetcher
+	imagePullSecrets   []string
+	jobConditions      bool
+	jobBackoffLimit    *int32
+	jobTimeout         time.Duration
+	workerStartupProbe *coreV1.Probe
+	// backendPodAnnotations are the default pod annotations of the backend, defined on SetDefaults
+	backendPodAnnotations map[string]string
 }
 
 // Type returns backend type name
@@ -65,6 +83,17 @@ func (*Backend) Type() loadTestV1.LoadTestType {
 	return loadTestV1.LoadTestTypeJMeter
 }
 
+// Capabilities returns the load test fields supported by the JMeter backend
+func (*Backend) Capabilities() backends.Capabilities {
+	return backends.Capabilities{
+		Distributed:     true,
+		TestFileFormats: []string{"jmx"},
+		TestDataFormats: []string{"csv"},
+		RequiredFields:  []string{"distributedPods", "testFile"},
+		OptionalFields:  []string{"testFileURL", "testData", "envVars", "masterImage", "workerImage"},
+	}
+}
+
 // GetEnvConfig must return config struct pointer
 func (b *Backend) GetEnvConfig() interface{} {
 	b.config = &Config{}
@@ -102,6 +131,9 @@ func (b *Backend) SetDefaults() {
 		MemoryLimits:   b.config.WorkerMemoryLimits,
 		MemoryRequests: b.config.WorkerMemoryRequests,
 	}
+
+	b.workerStartupProbe = backends.BuildStartupProbe(b.config.WorkerStartupProbe)
+	b.backendPodAnnotations = b.config.PodAnnotations
 }
 
 // SetPodAnnotations receives a copy of pod annotations
@@ -134,11 +166,49 @@ func (b *Backend) SetPodNodeSelector(nodeselector map[string]string) {
 	b.nodeSelector = nodeselector
 }
 
+// SetJobConditions receives whether the loadtest phase is determined from job conditions first
+func (b *Backend) SetJobConditions(enabled bool) {
+	b.jobConditions = enabled
+}
+
+// SetJobBackoffLimit receives the number of retries of failed job pods
+func (b *Backend) SetJobBackoffLimit(limit int32) {
+	b.jobBackoffLimit = &limit
+}
+
+// SetJobTimeout receives the default timeout of loadtest jobs
+func (b *Backend) SetJobTimeout(timeout time.Duration) {
+	b.jobTimeout = timeout
+}
+
+// SyncTimeout returns the time limit recommended for the sync of a loadtest
+func (b *Backend) SyncTimeout() time.Duration {
+	if b.config == nil {
+		return 0
+	}
+	return b.config.SyncTimeout
+}
+
+// SetTestFileFetcher receives the configuration of the init container fetching remote test files
+func (b *Backend) SetTestFileFetcher(fetcher backends.TestFileFetcher) {
+	b.testFileFetcher = fetcher
+}
+
 // SetPodTolerations receives a copy of pod tolerations
 func (b *Backend) SetPodTolerations(tolerations []coreV1.Toleration) {
 	b.tolerations = tolerations
 }
 
+// SetImagePullSecrets receives the image pull secrets of the loadtest pods
+func (b *Backend) SetImagePullSecrets(secrets []string) {
+	b.imagePullSecrets = secrets
+}
+
+// SetSeccompProfile receives the seccomp profile of the loadtest pods
+func (b *Backend) SetSeccompProfile(profile *coreV1.SeccompProfile) {
+	b.seccompProfile = profile
+}
+
 // TransformLoadTestSpec use given spec to validate and return a new one or error
 func (b *Backend) TransformLoadTestSpec(spec *loadTestV1.LoadTestSpec) error {
 	if nil == spec.DistributedPods {
@@ -149,10 +219,14 @@ func (b *Backend) TransformLoadTestSpec(spec *loadTestV1.LoadTestSpec) error {
 		return ErrRequireMinOneDistributedPod
 	}
 
-	if len(spec.TestFile) == 0 {
+	if len(spec.TestFile) == 0 && spec.TestFileURL == "" {
 		return ErrRequireTestFile
 	}
 
+	if err := b.ValidateConcurrency(*spec); err != nil {
+		return err
+	}
+
 	if spec.MasterConfig == "" {
 		spec.MasterConfig = b.masterConfig
 	}
@@ -173,6 +247,30 @@ func (b *Backend) TransformLoadTestSpec(spec *loadTestV1.LoadTestSpec) error {
 	return nil
 }
 
+// ValidateConcurrency rejects test files where every thread group is configured with zero threads,
+// thread counts given as properties or variables can't be resolved here and are accepted
+func (*Backend) ValidateConcurrency(spec loadTestV1.LoadTestSpec) error {
+	threadGroups := len(threadGroupRegexp.FindAll(spec.TestFile, -1))
+	if threadGroups == 0 {
+		return nil
+	}
+
+	if len(zeroThreadsRegexp.FindAll(spec.TestFile, -1)) >= threadGroups {
+		return ErrRequireMinOneThread
+	}
+
+	return nil
+}
+
+// EstimateResources returns the resources requested by the master and the worker pods
+func (b *Backend) EstimateResources(spec loadTestV1.LoadTestSpec) backends.ResourceEstimate {
+	var workers int32
+	if spec.DistributedPods != nil {
+		workers = *spec.DistributedPods
+	}
+	return backends.EstimatePods(1, b.masterResources).Add(backends.EstimatePods(workers, b.workerResources))
+}
+
 // Sync check if JMeter kubernetes resources have been create, if they have not been create them
 func (b *Backend) Sync(ctx context.Context, loadTest loadTestV1.LoadTest, reportURL string) error {
 	logger := b.logger.With(
@@ -180,7 +278,10 @@ func (b *Backend) Sync(ctx context.Context, loadTest loadTestV1.LoadTest, report
 		zap.String("namespace", loadTest.Status.Namespace),
 	)
 
-	JMeterServices, err := b.kubeClientSet.CoreV1().Services(loadTest.Status.Namespace).List(ctx, metaV1.ListOptions{})
+	// only the services of the backend count, the namespace may be an existing one with services of its own
+	JMeterServices, err := b.kubeClientSet.CoreV1().Services(loadTest.Status.Namespace).List(ctx, metaV1.ListOptions{
+		LabelSelector: loadTestServiceLabelSelector,
+	})
 	if err != nil {
 		return err
 	}
@@ -214,7 +315,7 @@ func (b *Backend) Sync(ctx context.Context, loadTest loadTestV1.LoadTest, report
 			return err
 		}
 
-		_, err = b.kubeClientSet.CoreV1().Services(loadTest.Status.Namespace).Create(ctx, b.NewJMeterService(), metaV1.CreateOptions{})
+		_, err = b.kubeClientSet.CoreV1().Services(loadTest.Status.Namespace).Create(ctx, b.NewJMeterService(loadTest), metaV1.CreateOptions{})
 		if err != nil && !kerrors.IsAlreadyExists(err) {
 			logger.Error("Error on creating new JMeter service", zap.Error(err))
 			return err
@@ -226,7 +327,7 @@ func (b *Backend) Sync(ctx context.Context, loadTest loadTestV1.LoadTest, report
 			Jobs(loadTest.Status.Namespace).
 			Create(
 				ctx,
-				b.NewJMeterMasterJob(loadTest, reportURL, b.podAnnotations),
+				b.NewJMeterMasterJob(loadTest, reportURL, backends.MergePodAnnotations(b.podAnnotations, b.backendPodAnnotations, loadTest.Spec.PodAnnotations)),
 				metaV1.CreateOptions{},
 			)
 		if err != nil && !kerrors.IsAlreadyExists(err) {
@@ -299,7 +400,7 @@ func (b *Backend) SyncStatus(ctx context.Context, loadTest loadTestV1.LoadTest,
 
 	// Get jmeter job in namespace and update the LoadTest status with
 	// the Job status
-	loadTestStatus.Phase = determineLoadTestPhaseFromJob(job.Status)
+	loadTestStatus.Phase = backends.PreferJobConditions(b.jobConditions, determineLoadTestPhaseFromJob(job.Status), job.Status)
 	loadTestStatus.JobStatus = job.Status
 
 	return nil
@@ -352,6 +453,10 @@ func workerPodHasTimeout(startTime *metaV1.Time, loadtestStatus loadTestV1.LoadT
 
 // determineLoadTestPhaseFromJob
 func determineLoadTestPhaseFromJob(status batchV1.JobStatus) loadTestV1.LoadTestPhase {
+	if backends.JobDeadlineExceeded(status) {
+		return loadTestV1.LoadTestErrored
+	}
+
 	if status.Active > 0 {
 		return loadTestV1.LoadTestRunning
 	}
@@ -11,6 +11,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap/zaptest"
 	batchV1 "k8s.io/api/batch/v1"
+	coreV1 "k8s.io/api/core/v1"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/informers"
 	k8sfake "k8s.io/client-go/kubernetes/fake"
@@ -129,7 +130,10 @@ func TestSync(t *testing.T) {
 
 	// Fake clients
 	logger := zaptest.NewLogger(t)
-	kubeClient := k8sfake.NewSimpleClientset()
+	// services of other workloads of the namespace don't count as jmeter resources
+	kubeClient := k8sfake.NewSimpleClientset(&coreV1.Service{
+		ObjectMeta: metaV1.ObjectMeta{Name: "other", Namespace: namespace},
+	})
 	client := fake.NewSimpleClientset()
 	namespaceLister := informers.NewSharedInformerFactory(kubeClient, 0).Core().V1().Namespaces().Lister()
 
@@ -163,9 +167,13 @@ func TestSync(t *testing.T) {
 	err := b.Sync(ctx, loadTest, reportURL)
 	require.NoError(t, err, "Error when syncing")
 
-	services, err := kubeClient.CoreV1().Services(namespace).List(ctx, metaV1.ListOptions{})
+	services, err := kubeClient.CoreV1().Services(namespace).List(ctx, metaV1.ListOptions{
+		LabelSelector: loadTestServiceLabelSelector,
+	})
 	require.NoError(t, err, "Error when listing services")
-	assert.NotEmpty(t, services.Items, "Expected non-zero service amount after CheckOrCreateResources but found zero services")
+	require.NotEmpty(t, services.Items, "Expected non-zero service amount after CheckOrCreateResources but found zero services")
+	// the service is deleted with the loadtest in existing namespaces
+	assert.Equal(t, "loadtest-name", metaV1.GetControllerOf(&services.Items[0]).Name)
 }
 
 func TestTransformLoadTestSpec(t *testing.T) {
@@ -195,6 +203,16 @@ func TestTransformLoadTestSpec(t *testing.T) {
 		assert.EqualError(t, err, ErrRequireTestFile.Error())
 	})
 
+	t.Run("Zero threads", func(t *testing.T) {
+		distributedPods := int32(2)
+		spec.DistributedPods = &distributedPods
+		spec.TestFile = []byte(`<ThreadGroup guiclass="ThreadGroupGui" testclass="ThreadGroup" testname="Thread Group" enabled="true">
+  <stringProp name="ThreadGroup.num_threads">0</stringProp>
+</ThreadGroup>`)
+		err := jmeter.TransformLoadTestSpec(spec)
+		assert.EqualError(t, err, ErrRequireMinOneThread.Error())
+	})
+
 	t.Run("All valid", func(t *testing.T) {
 		distributedPods := int32(2)
 		spec.DistributedPods = &distributedPods
@@ -234,3 +252,45 @@ func TestSetDefaults(t *testing.T) {
 		assert.Equal(t, string(jmeter.workerConfig), fmt.Sprintf("%s:%s", defaultWorkerImageName, defaultWorkerImageTag))
 	})
 }
+
+func TestValidateConcurrency(t *testing.T) {
+	threadGroup := func(threads string) string {
+		return `<ThreadGroup guiclass="ThreadGroupGui" testclass="ThreadGroup" testname="Thread Group" enabled="true">
+  <stringProp name="ThreadGroup.num_threads">` + threads + `</stringProp>
+</ThreadGroup>
+`
+	}
+
+	for _, tt := range []struct {
+		name     string
+		testFile string
+		err      error
+	}{
+		{
+			name:     "no thread groups",
+			testFile: "my-test",
+		},
+		{
+			name:     "single thread group with threads",
+			testFile: threadGroup("10"),
+		},
+		{
+			name:     "single thread group without threads",
+			testFile: threadGroup("0"),
+			err:      ErrRequireMinOneThread,
+		},
+		{
+			name:     "one of thread groups with threads",
+			testFile: threadGroup("0") + threadGroup("5"),
+		},
+		{
+			name:     "threads from property",
+			testFile: threadGroup("${__P(threads,0)}"),
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			err := (&Backend{}).ValidateConcurrency(loadTestV1.LoadTestSpec{TestFile: []byte(tt.testFile)})
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
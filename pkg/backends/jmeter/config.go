@@ -2,6 +2,8 @@ package jmeter
 
 import (
 	"time"
+
+	"github.com/hellofresh/kangal/pkg/backends"
 )
 
 // Config specific to JMeter backend
@@ -21,4 +23,12 @@ type Config struct {
 	TestDataDecompressImage string        `envconfig:"JMETER_TESTDATA_DECOMPRESS_IMAGE" default:"alpine:latest"`
 	RemoteCustomDataImage   string        `envconfig:"JMETER_WORKER_REMOTE_CUSTOM_DATA_IMAGE" default:"rclone/rclone:latest"`
 	WaitForResourceTimeout  time.Duration `envconfig:"WAIT_FOR_RESOURCE_TIMEOUT" default:"30s"`
+	// SyncTimeout is the time limit recommended for the sync of JMeter loadtests, which wait for each worker pod
+	// to run, the controller uses it when it is longer than its SyncHandlerTimeout
+	SyncTimeout time.Duration `envconfig:"JMETER_SYNC_TIMEOUT" default:"5m"`
+
+	WorkerStartupProbe backends.StartupProbe `envconfig:"JMETER_WORKER_STARTUP_PROBE"`
+
+	// PodAnnotations are the default annotations of the loadtest pods of the backend
+	PodAnnotations map[string]string `envconfig:"JMETER_POD_ANNOTATIONS"`
 }
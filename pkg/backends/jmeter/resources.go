@@ -76,6 +76,9 @@ func (b *Backend) NewConfigMap(loadTest loadTestV1.LoadTest) *coreV1.ConfigMap {
 			Labels: map[string]string{
 				"app": "hf-jmeter",
 			},
+			OwnerReferences: []metaV1.OwnerReference{
+				*metaV1.NewControllerRef(&loadTest, loadTestV1.SchemeGroupVersion.WithKind("LoadTest")),
+			},
 		},
 		Data: data,
 	}
@@ -89,6 +92,9 @@ func (b *Backend) NewSecret(loadTest loadTestV1.LoadTest) (*coreV1.Secret, error
 		ObjectMeta: metaV1.ObjectMeta{
 			Name:   loadTestEnvVars,
 			Labels: loadTestSecretLabels,
+			OwnerReferences: []metaV1.OwnerReference{
+				*metaV1.NewControllerRef(&loadTest, loadTestV1.SchemeGroupVersion.WithKind("LoadTest")),
+			},
 		},
 		StringData: secretMap,
 	}, nil
@@ -122,6 +128,7 @@ func (b *Backend) NewTestdataConfigMap(loadTest loadTestV1.LoadTest) ([]*coreV1.
 	n := int(*loadTest.Spec.DistributedPods)
 
 	cMaps := make([]*coreV1.ConfigMap, n)
+	ownerRef := metaV1.NewControllerRef(&loadTest, loadTestV1.SchemeGroupVersion.WithKind("LoadTest"))
 
 	chunks, err := splitTestData(string(testdata), n, logger)
 	if err != nil {
@@ -152,7 +159,8 @@ func (b *Backend) NewTestdataConfigMap(loadTest loadTestV1.LoadTest) ([]*coreV1.
 
 		cMaps[i] = &coreV1.ConfigMap{
 			ObjectMeta: metaV1.ObjectMeta{
-				Name: cmName,
+				Name:            cmName,
+				OwnerReferences: []metaV1.OwnerReference{*ownerRef},
 			},
 			Data: data,
 		}
@@ -219,8 +227,10 @@ func (b *Backend) NewPod(loadTest loadTestV1.LoadTest, i int, configMap *coreV1.
 			},
 		},
 		Spec: coreV1.PodSpec{
-			NodeSelector: b.nodeSelector,
-			Tolerations:  b.tolerations,
+			NodeSelector:     b.nodeSelector,
+			Tolerations:      b.tolerations,
+			SecurityContext:  backends.PodSecurityContext(b.seccompProfile),
+			ImagePullSecrets: backends.ImagePullSecrets(b.imagePullSecrets, loadTest.Spec.ImagePullSecrets),
 			Affinity: &coreV1.Affinity{
 				PodAntiAffinity: &coreV1.PodAntiAffinity{
 					PreferredDuringSchedulingIgnoredDuringExecution: []coreV1.WeightedPodAffinityTerm{
@@ -269,7 +279,8 @@ func (b *Backend) NewPod(loadTest loadTestV1.LoadTest, i int, configMap *coreV1.
 							MountPath: "/testdata",
 						},
 					},
-					Resources: backends.BuildResourceRequirements(b.workerResources),
+					Resources:    backends.BuildResourceRequirements(b.workerResources),
+					StartupProbe: b.workerStartupProbe,
 					EnvFrom: []coreV1.EnvFromSource{
 						{
 							SecretRef: &coreV1.SecretEnvSource{
@@ -363,8 +374,6 @@ func (b *Backend) NewJMeterMasterJob(loadTest loadTestV1.LoadTest, reportURL str
 		zap.String("namespace", loadTest.Status.Namespace),
 	)
 
-	var one int32 = 1
-
 	imageRef := loadTest.Spec.MasterConfig
 	if imageRef == "" {
 		imageRef = b.masterConfig
@@ -389,7 +398,7 @@ func (b *Backend) NewJMeterMasterJob(loadTest loadTestV1.LoadTest, reportURL str
 		})
 	}
 
-	return &batchV1.Job{
+	job := &batchV1.Job{
 		ObjectMeta: metaV1.ObjectMeta{
 			Name: loadTestJobName,
 			Labels: map[string]string{
@@ -401,7 +410,8 @@ func (b *Backend) NewJMeterMasterJob(loadTest loadTestV1.LoadTest, reportURL str
 			Annotations: podAnnotations,
 		},
 		Spec: batchV1.JobSpec{
-			BackoffLimit: &one,
+			BackoffLimit:          backends.JobBackoffLimit(b.jobBackoffLimit, 1),
+			ActiveDeadlineSeconds: backends.JobActiveDeadlineSeconds(b.jobTimeout, loadTest.Spec.Timeout),
 			Template: coreV1.PodTemplateSpec{
 				ObjectMeta: metaV1.ObjectMeta{
 					Labels: map[string]string{
@@ -411,9 +421,11 @@ func (b *Backend) NewJMeterMasterJob(loadTest loadTestV1.LoadTest, reportURL str
 				},
 
 				Spec: coreV1.PodSpec{
-					NodeSelector:  b.nodeSelector,
-					Tolerations:   b.tolerations,
-					RestartPolicy: "Never",
+					NodeSelector:     b.nodeSelector,
+					Tolerations:      b.tolerations,
+					SecurityContext:  backends.PodSecurityContext(b.seccompProfile),
+					ImagePullSecrets: backends.ImagePullSecrets(b.imagePullSecrets, loadTest.Spec.ImagePullSecrets),
+					RestartPolicy:    "Never",
 					Containers: []coreV1.Container{
 						{
 							Name:            loadTestJobName,
@@ -445,16 +457,26 @@ func (b *Backend) NewJMeterMasterJob(loadTest loadTestV1.LoadTest, reportURL str
 			},
 		},
 	}
+
+	if loadTest.Spec.TestFileURL != "" {
+		b.testFileFetcher.Inject(&job.Spec.Template.Spec, loadTest.Spec.TestFileURL, "tests", "testfile.jmx")
+	}
+
+	backends.SetSpecHash(job, loadTest)
+	return job
 }
 
 // NewJMeterService creates a new services to talk to jmeter worker pods
-func (b *Backend) NewJMeterService() *coreV1.Service {
+func (b *Backend) NewJMeterService(loadTest loadTestV1.LoadTest) *coreV1.Service {
 	return &coreV1.Service{
 		ObjectMeta: metaV1.ObjectMeta{
 			Name: loadTestWorkerServiceName,
 			Labels: map[string]string{
 				"app": LoadTestLabel,
 			},
+			OwnerReferences: []metaV1.OwnerReference{
+				*metaV1.NewControllerRef(&loadTest, loadTestV1.SchemeGroupVersion.WithKind("LoadTest")),
+			},
 		},
 		Spec: coreV1.ServiceSpec{
 			Selector:  loadTestWorkerPodLabels,
@@ -520,7 +542,7 @@ func (b *Backend) CreatePodsWithTestdata(ctx context.Context, configMaps []*core
 			waitfor.Resource(watchObjPvc, (waitfor.Condition{}).PvcReady, b.config.WaitForResourceTimeout)
 		}
 
-		pod := b.NewPod(*loadTest, i, configMap, b.podAnnotations)
+		pod := b.NewPod(*loadTest, i, configMap, backends.MergePodAnnotations(b.podAnnotations, b.backendPodAnnotations, loadTest.Spec.PodAnnotations))
 		_, err = b.kubeClientSet.CoreV1().Pods(namespace).Create(ctx, pod, metaV1.CreateOptions{})
 		if err != nil && !kerrors.IsAlreadyExists(err) {
 			logger.Error("Error on creating distributed pods", zap.Error(err))
@@ -143,3 +143,25 @@ func TestPodResourceConfiguration(t *testing.T) {
 	assert.Equal(t, c.workerResources.MemoryLimits, workerPod.Spec.Containers[0].Resources.Limits.Memory().String())
 	assert.Equal(t, c.workerResources.MemoryRequests, workerPod.Spec.Containers[0].Resources.Requests.Memory().String())
 }
+
+func TestPodImagePullSecrets(t *testing.T) {
+	lt := loadTestV1.LoadTest{
+		Spec: loadTestV1.LoadTestSpec{
+			MasterConfig: loadTestV1.ImageDetails(fmt.Sprintf("%s:%s", defaultMasterImageName, defaultMasterImageTag)),
+			WorkerConfig: loadTestV1.ImageDetails(fmt.Sprintf("%s:%s", defaultWorkerImageName, defaultWorkerImageTag)),
+		},
+	}
+
+	c := &Backend{
+		logger: zaptest.NewLogger(t),
+		config: &Config{},
+	}
+	c.SetImagePullSecrets([]string{"registry"})
+	expected := []coreV1.LocalObjectReference{{Name: "registry"}}
+
+	masterJob := c.NewJMeterMasterJob(lt, "", map[string]string{})
+	assert.Equal(t, expected, masterJob.Spec.Template.Spec.ImagePullSecrets)
+
+	workerPod := c.NewPod(lt, 0, &coreV1.ConfigMap{}, map[string]string{})
+	assert.Equal(t, expected, workerPod.Spec.ImagePullSecrets)
+}
@@ -0,0 +1,135 @@
+package backends
+
+import (
+	"time"
+
+	batchV1 "k8s.io/api/batch/v1"
+	coreV1 "k8s.io/api/core/v1"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+// jobReasonDeadlineExceeded is the reason of the Failed condition of jobs running longer than their active deadline
+const jobReasonDeadlineExceeded = "DeadlineExceeded"
+
+// PhaseFromJobConditions determines the loadtest phase from the conditions of its jobs, ok is false
+// when the conditions are not conclusive and the phase must be determined from the job counters.
+// A Failed or FailureTarget condition on any job, e.g. with DeadlineExceeded or BackoffLimitExceeded
+// reason, means errored, a suspended job means starting and all jobs complete means finished.
+func PhaseFromJobConditions(statuses ...batchV1.JobStatus) (phase loadTestV1.LoadTestPhase, ok bool) {
+	if len(statuses) == 0 {
+		return "", false
+	}
+
+	complete := 0
+	suspended := false
+	for _, status := range statuses {
+		if hasJobCondition(status, batchV1.JobFailed) || hasJobCondition(status, batchV1.JobFailureTarget) {
+			return loadTestV1.LoadTestErrored, true
+		}
+		if hasJobCondition(status, batchV1.JobSuspended) {
+			suspended = true
+		}
+		if hasJobCondition(status, batchV1.JobComplete) {
+			complete++
+		}
+	}
+
+	if suspended {
+		return loadTestV1.LoadTestStarting, true
+	}
+	if complete == len(statuses) {
+		return loadTestV1.LoadTestFinished, true
+	}
+	return "", false
+}
+
+// PreferJobConditions returns the phase from the job conditions when enabled and conclusive,
+// and the given phase determined from the job counters otherwise
+func PreferJobConditions(enabled bool, phase loadTestV1.LoadTestPhase, statuses ...batchV1.JobStatus) loadTestV1.LoadTestPhase {
+	if !enabled {
+		return phase
+	}
+	if conditionsPhase, ok := PhaseFromJobConditions(statuses...); ok {
+		return conditionsPhase
+	}
+	return phase
+}
+
+func hasJobCondition(status batchV1.JobStatus, conditionType batchV1.JobConditionType) bool {
+	for _, condition := range status.Conditions {
+		if condition.Type == conditionType && condition.Status == coreV1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+// SetPodCounters sets the active, succeeded and failed pod counters of the loadtest status to the sums of the
+// counters of its jobs
+func SetPodCounters(pods *loadTestV1.LoadTestPodsStatus, statuses ...batchV1.JobStatus) {
+	pods.Active, pods.Succeeded, pods.Failed = 0, 0, 0
+	for _, status := range statuses {
+		pods.Active += status.Active
+		pods.Succeeded += status.Succeeded
+		pods.Failed += status.Failed
+	}
+}
+
+// JobBackoffLimit returns the backoff limit of a loadtest job, the configured limit when set and the
+// backend default otherwise
+func JobBackoffLimit(limit *int32, fallback int32) *int32 {
+	if limit != nil {
+		fallback = *limit
+	}
+	return &fallback
+}
+
+// JobFailed reports whether the failed pods of the job exhausted its backoff limit or the job ran longer than its
+// active deadline, failed pods within the limit are retried by the job controller and do not error the loadtest yet
+func JobFailed(job batchV1.Job) bool {
+	if JobDeadlineExceeded(job.Status) {
+		return true
+	}
+
+	limit := int32(0)
+	if job.Spec.BackoffLimit != nil {
+		limit = *job.Spec.BackoffLimit
+	}
+	return job.Status.Failed > limit
+}
+
+// JobDeadlineExceeded reports whether the job was failed by the job controller for running longer than its
+// ActiveDeadlineSeconds, its pods are killed then
+func JobDeadlineExceeded(status batchV1.JobStatus) bool {
+	for _, condition := range status.Conditions {
+		if condition.Type != batchV1.JobFailed && condition.Type != batchV1.JobFailureTarget {
+			continue
+		}
+		if condition.Status == coreV1.ConditionTrue && condition.Reason == jobReasonDeadlineExceeded {
+			return true
+		}
+	}
+	return false
+}
+
+// JobTimeout returns the timeout of the jobs of a loadtest, the timeout of the loadtest when set and the
+// configured default otherwise, 0 means no timeout
+func JobTimeout(configured, loadTest time.Duration) time.Duration {
+	if loadTest > 0 {
+		return loadTest
+	}
+	return configured
+}
+
+// JobActiveDeadlineSeconds returns the ActiveDeadlineSeconds of a loadtest job, rounded up to whole seconds,
+// nil when the loadtest has no timeout
+func JobActiveDeadlineSeconds(configured, loadTest time.Duration) *int64 {
+	timeout := JobTimeout(configured, loadTest)
+	if timeout <= 0 {
+		return nil
+	}
+
+	seconds := int64((timeout + time.Second - 1) / time.Second)
+	return &seconds
+}
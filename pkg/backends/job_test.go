@@ -0,0 +1,145 @@
+package backends
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	batchV1 "k8s.io/api/batch/v1"
+	coreV1 "k8s.io/api/core/v1"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+func jobStatusWithCondition(conditionType batchV1.JobConditionType, status coreV1.ConditionStatus, reason string) batchV1.JobStatus {
+	return batchV1.JobStatus{
+		Conditions: []batchV1.JobCondition{
+			{Type: conditionType, Status: status, Reason: reason},
+		},
+	}
+}
+
+func TestPhaseFromJobConditions(t *testing.T) {
+	for _, tt := range []struct {
+		name          string
+		statuses      []batchV1.JobStatus
+		expectedPhase loadTestV1.LoadTestPhase
+		expectedOK    bool
+	}{
+		{
+			name: "no jobs",
+		},
+		{
+			name:     "no conditions",
+			statuses: []batchV1.JobStatus{{Active: 1}},
+		},
+		{
+			name:          "deadline exceeded",
+			statuses:      []batchV1.JobStatus{jobStatusWithCondition(batchV1.JobFailed, coreV1.ConditionTrue, "DeadlineExceeded")},
+			expectedPhase: loadTestV1.LoadTestErrored,
+			expectedOK:    true,
+		},
+		{
+			name:          "failure target",
+			statuses:      []batchV1.JobStatus{jobStatusWithCondition(batchV1.JobFailureTarget, coreV1.ConditionTrue, "BackoffLimitExceeded")},
+			expectedPhase: loadTestV1.LoadTestErrored,
+			expectedOK:    true,
+		},
+		{
+			name:          "suspended",
+			statuses:      []batchV1.JobStatus{jobStatusWithCondition(batchV1.JobSuspended, coreV1.ConditionTrue, "JobSuspended")},
+			expectedPhase: loadTestV1.LoadTestStarting,
+			expectedOK:    true,
+		},
+		{
+			name:     "resumed",
+			statuses: []batchV1.JobStatus{jobStatusWithCondition(batchV1.JobSuspended, coreV1.ConditionFalse, "JobResumed")},
+		},
+		{
+			name: "all complete",
+			statuses: []batchV1.JobStatus{
+				jobStatusWithCondition(batchV1.JobComplete, coreV1.ConditionTrue, ""),
+				jobStatusWithCondition(batchV1.JobComplete, coreV1.ConditionTrue, ""),
+			},
+			expectedPhase: loadTestV1.LoadTestFinished,
+			expectedOK:    true,
+		},
+		{
+			name: "partially complete",
+			statuses: []batchV1.JobStatus{
+				jobStatusWithCondition(batchV1.JobComplete, coreV1.ConditionTrue, ""),
+				{Active: 1},
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			phase, ok := PhaseFromJobConditions(tt.statuses...)
+			assert.Equal(t, tt.expectedOK, ok)
+			assert.Equal(t, tt.expectedPhase, phase)
+		})
+	}
+}
+
+func TestPreferJobConditions(t *testing.T) {
+	failed := jobStatusWithCondition(batchV1.JobFailed, coreV1.ConditionTrue, "DeadlineExceeded")
+
+	assert.Equal(t, loadTestV1.LoadTestErrored, PreferJobConditions(true, loadTestV1.LoadTestFinished, failed))
+	assert.Equal(t, loadTestV1.LoadTestFinished, PreferJobConditions(false, loadTestV1.LoadTestFinished, failed))
+	assert.Equal(t, loadTestV1.LoadTestRunning, PreferJobConditions(true, loadTestV1.LoadTestRunning, batchV1.JobStatus{Active: 1}))
+}
+
+func TestJobBackoffLimit(t *testing.T) {
+	limit := int32(3)
+
+	assert.Equal(t, int32(3), *JobBackoffLimit(&limit, 0))
+	assert.Equal(t, int32(1), *JobBackoffLimit(nil, 1))
+}
+
+func TestJobFailed(t *testing.T) {
+	limit := int32(2)
+
+	for _, tt := range []struct {
+		backoffLimit *int32
+		failed       int32
+		expected     bool
+	}{
+		{nil, 0, false},
+		{nil, 1, true},
+		{&limit, 1, false},
+		{&limit, 2, false},
+		{&limit, 3, true},
+	} {
+		job := batchV1.Job{
+			Spec:   batchV1.JobSpec{BackoffLimit: tt.backoffLimit},
+			Status: batchV1.JobStatus{Failed: tt.failed},
+		}
+		assert.Equal(t, tt.expected, JobFailed(job))
+	}
+
+	deadline := batchV1.Job{Status: jobStatusWithCondition(batchV1.JobFailed, coreV1.ConditionTrue, "DeadlineExceeded")}
+	assert.True(t, JobFailed(deadline))
+}
+
+func TestJobDeadlineExceeded(t *testing.T) {
+	assert.True(t, JobDeadlineExceeded(jobStatusWithCondition(batchV1.JobFailed, coreV1.ConditionTrue, "DeadlineExceeded")))
+	assert.True(t, JobDeadlineExceeded(jobStatusWithCondition(batchV1.JobFailureTarget, coreV1.ConditionTrue, "DeadlineExceeded")))
+	assert.False(t, JobDeadlineExceeded(jobStatusWithCondition(batchV1.JobFailed, coreV1.ConditionTrue, "BackoffLimitExceeded")))
+	assert.False(t, JobDeadlineExceeded(jobStatusWithCondition(batchV1.JobFailed, coreV1.ConditionFalse, "DeadlineExceeded")))
+	assert.False(t, JobDeadlineExceeded(batchV1.JobStatus{Active: 1}))
+}
+
+func TestJobActiveDeadlineSeconds(t *testing.T) {
+	assert.Nil(t, JobActiveDeadlineSeconds(0, 0))
+	assert.Equal(t, int64(600), *JobActiveDeadlineSeconds(10*time.Minute, 0))
+	assert.Equal(t, int64(30), *JobActiveDeadlineSeconds(10*time.Minute, 30*time.Second))
+	assert.Equal(t, int64(2), *JobActiveDeadlineSeconds(0, 1500*time.Millisecond))
+}
+
+func TestSetPodCounters(t *testing.T) {
+	pods := loadTestV1.LoadTestPodsStatus{Active: 5}
+
+	SetPodCounters(&pods, batchV1.JobStatus{Active: 1, Succeeded: 2}, batchV1.JobStatus{Succeeded: 1, Failed: 1})
+	assert.Equal(t, int32(1), pods.Active)
+	assert.Equal(t, int32(3), pods.Succeeded)
+	assert.Equal(t, int32(1), pods.Failed)
+}
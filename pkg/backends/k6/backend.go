@@ -4,8 +4,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
 	"go.uber.org/zap"
+	batchV1 "k8s.io/api/batch/v1"
 	coreV1 "k8s.io/api/core/v1"
 	k8sAPIErrors "k8s.io/apimachinery/pkg/api/errors"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -18,8 +22,10 @@ import (
 var (
 	// ErrRequireMinOneDistributedPod Backend spec requires 1 or more DistributedPods
 	ErrRequireMinOneDistributedPod = errors.New("LoadTest must specify 1 or more DistributedPods")
-	// ErrRequireTestFile the TestFile filed is required to not be an empty string
-	ErrRequireTestFile = errors.New("LoadTest TestFile is required")
+	// ErrRequireTestFile the TestFile field is required to not be an empty string, unless TestFileURL is set
+	ErrRequireTestFile = errors.New("LoadTest TestFile or TestFileURL is required")
+	// ErrRequireMinOneVU the K6_VUS env var must not set the number of virtual users to zero
+	ErrRequireMinOneVU = errors.New("LoadTest must specify 1 or more virtual users in K6_VUS")
 )
 
 func init() {
@@ -28,16 +34,27 @@ func init() {
 
 // Backend is the k6 implementation of backend interface
 type Backend struct {
-	logger         *zap.Logger
-	kubeClientSet  kubernetes.Interface
-	config         *Config
-	podAnnotations map[string]string
-	podTolerations []coreV1.Toleration
+	logger           *zap.Logger
+	kubeClientSet    kubernetes.Interface
+	config           *Config
+	podAnnotations   map[string]string
+	podTolerations   []coreV1.Toleration
+	seccompProfile   *coreV1.SeccompProfile
+	imagePullSecrets []string
 
 	nodeSelector map[string]string
+
+	testFileFetcher backends.TestFileFetcher
+	jobConditions   bool
+	jobBackoffLimit *int32
+	jobTimeout      time.Duration
+
 	// defined on SetDefaults
-	image     loadTestV1.ImageDetails
-	resources backends.Resources
+	image        loadTestV1.ImageDetails
+	resources    backends.Resources
+	startupProbe *coreV1.Probe
+
+	backendPodAnnotations map[string]string
 }
 
 // Type returns backend type name
@@ -45,6 +62,17 @@ func (*Backend) Type() loadTestV1.LoadTestType {
 	return loadTestV1.LoadTestTypeK6
 }
 
+// Capabilities returns the load test fields supported by the k6 backend
+func (*Backend) Capabilities() backends.Capabilities {
+	return backends.Capabilities{
+		Distributed:     true,
+		TestFileFormats: []string{"js", "tar"},
+		TestDataFormats: []string{"csv"},
+		RequiredFields:  []string{"distributedPods", "testFile"},
+		OptionalFields:  []string{"testFileURL", "testData", "envVars", "duration", "vus", "masterImage"},
+	}
+}
+
 // GetEnvConfig must return config struct pointer
 func (b *Backend) GetEnvConfig() interface{} {
 	b.config = &Config{}
@@ -61,6 +89,9 @@ func (b *Backend) SetDefaults() {
 		MemoryLimits:   b.config.MemoryLimits,
 		MemoryRequests: b.config.MemoryRequests,
 	}
+
+	b.startupProbe = backends.BuildStartupProbe(b.config.StartupProbe)
+	b.backendPodAnnotations = b.config.PodAnnotations
 }
 
 // SetPodAnnotations receives a copy of pod annotations
@@ -73,6 +104,16 @@ func (b *Backend) SetPodTolerations(tolerations []coreV1.Toleration) {
 	b.podTolerations = tolerations
 }
 
+// SetImagePullSecrets receives the image pull secrets of the loadtest pods
+func (b *Backend) SetImagePullSecrets(secrets []string) {
+	b.imagePullSecrets = secrets
+}
+
+// SetSeccompProfile receives the seccomp profile of the loadtest pods
+func (b *Backend) SetSeccompProfile(profile *coreV1.SeccompProfile) {
+	b.seccompProfile = profile
+}
+
 // SetKubeClientSet receives a copy of kubeClientSet
 func (b *Backend) SetKubeClientSet(kubeClientSet kubernetes.Interface) {
 	b.kubeClientSet = kubeClientSet
@@ -88,6 +129,26 @@ func (b *Backend) SetPodNodeSelector(nodeselector map[string]string) {
 	b.nodeSelector = nodeselector
 }
 
+// SetJobConditions receives whether the loadtest phase is determined from job conditions first
+func (b *Backend) SetJobConditions(enabled bool) {
+	b.jobConditions = enabled
+}
+
+// SetJobBackoffLimit receives the number of retries of failed job pods
+func (b *Backend) SetJobBackoffLimit(limit int32) {
+	b.jobBackoffLimit = &limit
+}
+
+// SetJobTimeout receives the default timeout of loadtest jobs
+func (b *Backend) SetJobTimeout(timeout time.Duration) {
+	b.jobTimeout = timeout
+}
+
+// SetTestFileFetcher receives the configuration of the init container fetching remote test files
+func (b *Backend) SetTestFileFetcher(fetcher backends.TestFileFetcher) {
+	b.testFileFetcher = fetcher
+}
+
 // TransformLoadTestSpec use given spec to validate and return a new one or error
 func (b *Backend) TransformLoadTestSpec(spec *loadTestV1.LoadTestSpec) error {
 	if nil == spec.DistributedPods {
@@ -98,10 +159,14 @@ func (b *Backend) TransformLoadTestSpec(spec *loadTestV1.LoadTestSpec) error {
 		return ErrRequireMinOneDistributedPod
 	}
 
-	if len(spec.TestFile) == 0 {
+	if len(spec.TestFile) == 0 && spec.TestFileURL == "" {
 		return ErrRequireTestFile
 	}
 
+	if err := b.ValidateConcurrency(*spec); err != nil {
+		return err
+	}
+
 	if spec.MasterConfig == "" {
 		spec.MasterConfig = b.image
 	}
@@ -109,6 +174,30 @@ func (b *Backend) TransformLoadTestSpec(spec *loadTestV1.LoadTestSpec) error {
 	return nil
 }
 
+// ValidateConcurrency rejects specs that set the number of virtual users to zero with K6_VUS env var,
+// the options exported by the test script itself are not checked
+func (*Backend) ValidateConcurrency(spec loadTestV1.LoadTestSpec) error {
+	value, ok := spec.EnvVars["K6_VUS"]
+	if !ok {
+		return nil
+	}
+
+	if n, err := strconv.Atoi(strings.TrimSpace(value)); err == nil && n == 0 {
+		return ErrRequireMinOneVU
+	}
+
+	return nil
+}
+
+// EstimateResources returns the resources requested by the k6 pods
+func (b *Backend) EstimateResources(spec loadTestV1.LoadTestSpec) backends.ResourceEstimate {
+	var pods int32
+	if spec.DistributedPods != nil {
+		pods = *spec.DistributedPods
+	}
+	return backends.EstimatePods(pods, b.resources)
+}
+
 // Sync checks if k6 kubernetes resources have been created, create them if they haven't
 func (b *Backend) Sync(ctx context.Context, loadTest loadTestV1.LoadTest, reportURL string) error {
 	jobs, err := b.kubeClientSet.
@@ -129,20 +218,22 @@ func (b *Backend) Sync(ctx context.Context, loadTest loadTestV1.LoadTest, report
 
 	var (
 		tdCfgMap   *coreV1.ConfigMap
-		configMaps = make([]*coreV1.ConfigMap, 1)
+		configMaps = make([]*coreV1.ConfigMap, 0, 2)
 	)
 
-	// Create testfile ConfigMap
-	tfCfgMap, err := NewFileConfigMap(loadTestFileConfigMapName, scriptTestFileName, loadTest.Spec.TestFile)
-	if err != nil {
-		b.logger.Error("Error creating testfile configmap resource", zap.Error(err))
-		return err
+	// Create testfile ConfigMap, remote test files are fetched by an init container instead
+	if loadTest.Spec.TestFileURL == "" {
+		tfCfgMap, err := NewFileConfigMap(loadTest, loadTestFileConfigMapName, scriptTestFileName, loadTest.Spec.TestFile)
+		if err != nil {
+			b.logger.Error("Error creating testfile configmap resource", zap.Error(err))
+			return err
+		}
+		configMaps = append(configMaps, tfCfgMap)
 	}
-	configMaps[0] = tfCfgMap
 
 	// Prepare testdata ConfigMap
 	if len(loadTest.Spec.TestData) != 0 {
-		tdCfgMap, err = NewFileConfigMap(loadTestDataConfigMapName, testdataFileName, loadTest.Spec.TestData)
+		tdCfgMap, err = NewFileConfigMap(loadTest, loadTestDataConfigMapName, testdataFileName, loadTest.Spec.TestData)
 		if err != nil {
 			b.logger.Error("Error creating testdata configmap resource", zap.Error(err))
 			return err
@@ -168,7 +259,7 @@ func (b *Backend) Sync(ctx context.Context, loadTest loadTestV1.LoadTest, report
 		mounts  = make([]coreV1.VolumeMount, 1)
 	)
 
-	volumes[0], mounts[0] = NewFileVolumeAndMount(loadTestFileVolumeName, tfCfgMap.Name, scriptTestFileName)
+	volumes[0], mounts[0] = NewFileVolumeAndMount(loadTestFileVolumeName, loadTestFileConfigMapName, scriptTestFileName)
 
 	if tdCfgMap != nil {
 		v, m := NewFileVolumeAndMount(loadTestDataVolumeName, tdCfgMap.Name, testdataFileName)
@@ -206,7 +297,7 @@ func (b *Backend) Sync(ctx context.Context, loadTest loadTestV1.LoadTest, report
 }
 
 // SyncStatus checks k6 resources and updates the status of the LoadTest resource
-func (b *Backend) SyncStatus(ctx context.Context, _ loadTestV1.LoadTest, loadTestStatus *loadTestV1.LoadTestStatus) error {
+func (b *Backend) SyncStatus(ctx context.Context, loadTest loadTestV1.LoadTest, loadTestStatus *loadTestV1.LoadTestStatus) error {
 	if loadTestStatus.Phase == "" {
 		loadTestStatus.Phase = loadTestV1.LoadTestCreating
 		return nil
@@ -216,15 +307,28 @@ func (b *Backend) SyncStatus(ctx context.Context, _ loadTestV1.LoadTest, loadTes
 		return nil
 	}
 
-	jobs, err := b.kubeClientSet.
+	jobList, err := b.kubeClientSet.
 		BatchV1().
 		Jobs(loadTestStatus.Namespace).
-		List(ctx, metaV1.ListOptions{})
+		List(ctx, metaV1.ListOptions{
+			LabelSelector: fmt.Sprintf("%s=%s", loadTestLabelKey, loadTestWorkerLabelValue),
+		})
 	if err != nil {
 		return err
 	}
 
-	loadTestStatus.Phase = determineLoadTestPhaseFromJobs(jobs.Items)
-	loadTestStatus.JobStatus = determineLoadTestStatusFromJobs(jobs.Items)
+	// only the jobs of the loadtest count, the namespace may be an existing one with jobs of its own
+	jobs := make([]batchV1.Job, 0, len(jobList.Items))
+	statuses := make([]batchV1.JobStatus, 0, len(jobList.Items))
+	for i := range jobList.Items {
+		if !metaV1.IsControlledBy(&jobList.Items[i], &loadTest) {
+			continue
+		}
+		jobs = append(jobs, jobList.Items[i])
+		statuses = append(statuses, jobList.Items[i].Status)
+	}
+
+	loadTestStatus.Phase = backends.PreferJobConditions(b.jobConditions, determineLoadTestPhaseFromJobs(jobs), statuses...)
+	loadTestStatus.JobStatus = determineLoadTestStatusFromJobs(jobs)
 	return nil
 }
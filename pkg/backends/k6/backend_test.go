@@ -4,11 +4,13 @@ import (
 	"context"
 	"testing"
 
+	"github.com/hellofresh/kangal/pkg/backends"
 	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap/zaptest"
 	batchV1 "k8s.io/api/batch/v1"
+	coreV1 "k8s.io/api/core/v1"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	k8sfake "k8s.io/client-go/kubernetes/fake"
 )
@@ -57,6 +59,35 @@ func TestSync(t *testing.T) {
 	assert.NotEmpty(t, configMaps.Items, "Expected configmap to be created but there's none")
 }
 
+func TestSyncTestFileURL(t *testing.T) {
+	ctx := context.Background()
+	kubeClient := k8sfake.NewSimpleClientset()
+	distributedPods := int32(1)
+	loadTest := loadTestV1.LoadTest{
+		ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-name"},
+		Spec:       loadTestV1.LoadTestSpec{DistributedPods: &distributedPods, TestFileURL: "https://example.com/testfile"},
+		Status:     loadTestV1.LoadTestStatus{Phase: "running", Namespace: "test"},
+	}
+
+	// the fetcher itself is tested in the backends package, only the test file of the backend is checked here
+	b := Backend{
+		logger:          zaptest.NewLogger(t),
+		kubeClientSet:   kubeClient,
+		testFileFetcher: backends.TestFileFetcher{Image: "fetcher:latest"},
+	}
+	require.NoError(t, b.Sync(ctx, loadTest, ""))
+
+	jobs, err := kubeClient.BatchV1().Jobs("test").List(ctx, metaV1.ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, jobs.Items, 1)
+	require.Len(t, jobs.Items[0].Spec.Template.Spec.InitContainers, 1)
+	assert.Contains(t, jobs.Items[0].Spec.Template.Spec.InitContainers[0].Env, coreV1.EnvVar{Name: "TEST_FILE_PATH", Value: "/fetch/test.js"})
+
+	configMaps, err := kubeClient.CoreV1().ConfigMaps("test").List(ctx, metaV1.ListOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, configMaps.Items, "Expected no testfile configmap for remote test file")
+}
+
 func TestSyncStatus(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -104,8 +135,41 @@ func TestSyncStatus(t *testing.T) {
 	_, err = kubeClient.BatchV1().Jobs(namespace).UpdateStatus(ctx, job, metaV1.UpdateOptions{})
 	require.NoError(t, err, "UpdateStatus error")
 
+	// jobs of other workloads of the namespace are ignored
+	_, err = kubeClient.BatchV1().Jobs(namespace).Create(ctx, &batchV1.Job{
+		ObjectMeta: metaV1.ObjectMeta{Name: "other", Labels: map[string]string{loadTestLabelKey: loadTestWorkerLabelValue}},
+		Status:     batchV1.JobStatus{Active: 1},
+	}, metaV1.CreateOptions{})
+	require.NoError(t, err)
+
 	// Sync should now update loadtest status to finished
 	err = b.SyncStatus(ctx, loadTest, &loadTest.Status)
 	require.NoError(t, err, "SyncStatus error")
 	assert.Equal(t, loadTestV1.LoadTestFinished, loadTest.Status.Phase)
 }
+
+func TestValidateConcurrency(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		envVars map[string]string
+		err     error
+	}{
+		{
+			name: "no env vars",
+		},
+		{
+			name:    "vus set",
+			envVars: map[string]string{"K6_VUS": "10"},
+		},
+		{
+			name:    "zero vus",
+			envVars: map[string]string{"K6_VUS": "0"},
+			err:     ErrRequireMinOneVU,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			err := (&Backend{}).ValidateConcurrency(loadTestV1.LoadTestSpec{EnvVars: tt.envVars})
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
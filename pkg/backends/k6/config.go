@@ -1,5 +1,7 @@
 package k6
 
+import "github.com/hellofresh/kangal/pkg/backends"
+
 // Config specific to k6 backend
 type Config struct {
 	ImageName      string `envconfig:"K6_IMAGE_NAME" default:"grafana/k6"`
@@ -8,4 +10,9 @@ type Config struct {
 	CPURequests    string `envconfig:"K6_CPU_REQUESTS"`
 	MemoryLimits   string `envconfig:"K6_MEMORY_LIMITS"`
 	MemoryRequests string `envconfig:"K6_MEMORY_REQUESTS"`
+
+	StartupProbe backends.StartupProbe `envconfig:"K6_STARTUP_PROBE"`
+
+	// PodAnnotations are the default annotations of the loadtest pods of the backend
+	PodAnnotations map[string]string `envconfig:"K6_POD_ANNOTATIONS"`
 }
@@ -3,6 +3,7 @@ package k6
 import (
 	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 
 	"go.uber.org/zap"
@@ -79,14 +80,17 @@ func (b *Backend) NewJob(
 	if loadTest.Spec.Duration != 0 {
 		args = append(args, "--duration", loadTest.Spec.Duration.String())
 	}
+	// k6 splits the virtual users across the execution segments of the distributed pods
+	if loadTest.Spec.VUs != 0 {
+		args = append(args, "--vus", strconv.Itoa(int(loadTest.Spec.VUs)))
+	}
 	if *loadTest.Spec.DistributedPods > 1 {
 		args = append(args, segmentArgs(index, *loadTest.Spec.DistributedPods)...)
 		args = append(args, sequenceArgs(*loadTest.Spec.DistributedPods)...)
 	}
 
-	backoffLimit := int32(0)
 	distributedPod := int32(1)
-	return &batchV1.Job{
+	job := &batchV1.Job{
 		ObjectMeta: metaV1.ObjectMeta{
 			Name:      jobName(index),
 			Namespace: loadTest.Status.Namespace,
@@ -97,20 +101,23 @@ func (b *Backend) NewJob(
 			OwnerReferences: []metaV1.OwnerReference{*ownerRef},
 		},
 		Spec: batchV1.JobSpec{
-			Parallelism:  &distributedPod,
-			Completions:  &distributedPod,
-			BackoffLimit: &backoffLimit,
+			Parallelism:           &distributedPod,
+			Completions:           &distributedPod,
+			BackoffLimit:          backends.JobBackoffLimit(b.jobBackoffLimit, 0),
+			ActiveDeadlineSeconds: backends.JobActiveDeadlineSeconds(b.jobTimeout, loadTest.Spec.Timeout),
 			Template: coreV1.PodTemplateSpec{
 				ObjectMeta: metaV1.ObjectMeta{
 					Labels: map[string]string{
 						"name":           loadTestJobName,
 						loadTestLabelKey: loadTestWorkerLabelValue,
 					},
-					Annotations: b.podAnnotations,
+					Annotations: backends.MergePodAnnotations(b.podAnnotations, b.backendPodAnnotations, loadTest.Spec.PodAnnotations),
 				},
 				Spec: coreV1.PodSpec{
-					NodeSelector: b.nodeSelector,
-					Tolerations:  b.podTolerations,
+					NodeSelector:     b.nodeSelector,
+					Tolerations:      b.podTolerations,
+					SecurityContext:  backends.PodSecurityContext(b.seccompProfile),
+					ImagePullSecrets: backends.ImagePullSecrets(b.imagePullSecrets, loadTest.Spec.ImagePullSecrets),
 					Affinity: &coreV1.Affinity{
 						PodAntiAffinity: &coreV1.PodAntiAffinity{
 							PreferredDuringSchedulingIgnoredDuringExecution: []coreV1.WeightedPodAffinityTerm{
@@ -136,6 +143,7 @@ func (b *Backend) NewJob(
 							Image:        string(imageRef),
 							Env:          envVars,
 							Resources:    backends.BuildResourceRequirements(b.resources),
+							StartupProbe: b.startupProbe,
 							Args:         args,
 							VolumeMounts: mounts,
 							EnvFrom:      envFrom,
@@ -145,6 +153,13 @@ func (b *Backend) NewJob(
 			},
 		},
 	}
+
+	if loadTest.Spec.TestFileURL != "" {
+		b.testFileFetcher.Inject(&job.Spec.Template.Spec, loadTest.Spec.TestFileURL, loadTestFileVolumeName, scriptTestFileName)
+	}
+
+	backends.SetSpecHash(job, loadTest)
+	return job
 }
 
 // NewFileVolumeAndMount creates a new volume and volume mount for a configmap file
@@ -169,8 +184,8 @@ func NewFileVolumeAndMount(name, cfg, filename string) (coreV1.Volume, coreV1.Vo
 	return v, m
 }
 
-// NewFileConfigMap creates a configmap for the provided file information
-func NewFileConfigMap(cfgName, filename string, content []byte) (*coreV1.ConfigMap, error) {
+// NewFileConfigMap creates a configmap for the provided file information, owned by the loadtest
+func NewFileConfigMap(loadTest loadTestV1.LoadTest, cfgName, filename string, content []byte) (*coreV1.ConfigMap, error) {
 	if strings.TrimSpace(cfgName) == "" {
 		return nil, errors.New("empty config name")
 	}
@@ -186,6 +201,9 @@ func NewFileConfigMap(cfgName, filename string, content []byte) (*coreV1.ConfigM
 	return &coreV1.ConfigMap{
 		ObjectMeta: metaV1.ObjectMeta{
 			Name: cfgName,
+			OwnerReferences: []metaV1.OwnerReference{
+				*metaV1.NewControllerRef(&loadTest, loadTestV1.SchemeGroupVersion.WithKind("LoadTest")),
+			},
 		},
 		BinaryData: map[string][]byte{
 			filename: content,
@@ -232,13 +250,14 @@ func sequenceArgs(total int32) []string {
 // determineLoadTestPhaseFromJobs reads existing job statuses and determines what the loadtest status should be
 func determineLoadTestPhaseFromJobs(jobs []batchV1.Job) loadTestV1.LoadTestPhase {
 	for _, job := range jobs {
-		if job.Status.Failed > int32(0) {
+		if backends.JobFailed(job) {
 			return loadTestV1.LoadTestErrored
 		}
 		if job.Status.Active > int32(0) {
 			return loadTestV1.LoadTestRunning
 		}
-		if job.Status.Succeeded == 0 && job.Status.Failed == 0 {
+		// failed pods within the backoff limit are being replaced
+		if job.Status.Succeeded == 0 {
 			return loadTestV1.LoadTestStarting
 		}
 	}
@@ -251,7 +270,7 @@ func jobName(index int32) string {
 
 func determineLoadTestStatusFromJobs(jobs []batchV1.Job) batchV1.JobStatus {
 	for _, job := range jobs {
-		if job.Status.Failed > int32(0) {
+		if backends.JobFailed(job) {
 			return job.Status
 		}
 	}
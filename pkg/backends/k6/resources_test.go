@@ -2,9 +2,11 @@ package k6
 
 import (
 	"testing"
+	"time"
 
 	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
 	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zaptest"
 	batchV1 "k8s.io/api/batch/v1"
 	coreV1 "k8s.io/api/core/v1"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -44,6 +46,8 @@ func TestGetLoadTestStatusFromJobs(t *testing.T) {
 }
 
 func TestNewFileConfigMap(t *testing.T) {
+	loadTest := loadTestV1.LoadTest{ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-name", UID: "loadtest-uid"}}
+
 	for _, ti := range []struct {
 		tag         string
 		cfgName     string
@@ -84,6 +88,9 @@ func TestNewFileConfigMap(t *testing.T) {
 			expected: &coreV1.ConfigMap{
 				ObjectMeta: metaV1.ObjectMeta{
 					Name: "test",
+					OwnerReferences: []metaV1.OwnerReference{
+						*metaV1.NewControllerRef(&loadTest, loadTestV1.SchemeGroupVersion.WithKind("LoadTest")),
+					},
 				},
 				BinaryData: map[string][]byte{
 					"file": []byte("file content"),
@@ -93,7 +100,7 @@ func TestNewFileConfigMap(t *testing.T) {
 		},
 	} {
 		t.Run(ti.tag, func(t *testing.T) {
-			cfgmap, err := NewFileConfigMap(ti.cfgName, ti.filename, ti.content)
+			cfgmap, err := NewFileConfigMap(loadTest, ti.cfgName, ti.filename, ti.content)
 
 			assert.Equal(t, ti.expected, cfgmap)
 			if ti.expectError {
@@ -143,3 +150,35 @@ func TestNewFileVolumeAndMount(t *testing.T) {
 		})
 	}
 }
+
+func TestNewJobArgs(t *testing.T) {
+	distributedPods := int32(1)
+
+	for _, tt := range []struct {
+		name     string
+		spec     loadTestV1.LoadTestSpec
+		expected []string
+	}{
+		{
+			name:     "test file options",
+			spec:     loadTestV1.LoadTestSpec{DistributedPods: &distributedPods},
+			expected: []string{"run", "/data/test.js"},
+		},
+		{
+			name:     "duration and virtual users",
+			spec:     loadTestV1.LoadTestSpec{DistributedPods: &distributedPods, Duration: 10 * time.Minute, VUs: 50},
+			expected: []string{"run", "/data/test.js", "--duration", "10m0s", "--vus", "50"},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			b := &Backend{logger: zaptest.NewLogger(t)}
+			loadTest := loadTestV1.LoadTest{
+				ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-name"},
+				Spec:       tt.spec,
+			}
+
+			job := b.NewJob(loadTest, nil, nil, nil, "", 0)
+			assert.Equal(t, tt.expected, job.Spec.Template.Spec.Containers[0].Args)
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package backends
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+// invalidLabelValueChars matches the characters that are not allowed in label values
+var invalidLabelValueChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// SanitizeLabelValue replaces invalid characters of a label value with dashes and trims it to
+// the maximum length of 63 characters, starting and ending with an alphanumeric character
+func SanitizeLabelValue(value string) string {
+	value = invalidLabelValueChars.ReplaceAllString(value, "-")
+	if len(value) > 63 {
+		value = value[:63]
+	}
+	return strings.Trim(value, "._-")
+}
+
+// TagLabels returns all tags of the loadtest as labels, with their keys and values sanitized to valid label syntax.
+// Keys keep their prefix when it is a valid DNS subdomain, e.g. example.com/team. Tags whose key or value has no
+// valid characters are skipped and returned sorted, so they can be reported.
+func TagLabels(tags loadTestV1.LoadTestTags) (labels map[string]string, skipped []string) {
+	labels = make(map[string]string, len(tags))
+	for key, value := range tags {
+		labelKey := sanitizeLabelKey(key)
+		labelValue := SanitizeLabelValue(value)
+		if labelKey == "" || labelValue == "" {
+			skipped = append(skipped, key)
+			continue
+		}
+		labels[labelKey] = labelValue
+	}
+	sort.Strings(skipped)
+	return labels, skipped
+}
+
+// sanitizeLabelKey sanitizes the name of a label key like a label value, empty if the key is not a valid
+// qualified name after sanitizing
+func sanitizeLabelKey(key string) string {
+	prefix, name, found := strings.Cut(key, "/")
+	if !found {
+		prefix, name = "", key
+	}
+	if name = SanitizeLabelValue(name); name == "" {
+		return ""
+	}
+	if prefix != "" {
+		name = prefix + "/" + name
+	}
+	if len(validation.IsQualifiedName(name)) > 0 {
+		return ""
+	}
+	return name
+}
@@ -0,0 +1,45 @@
+package backends
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+func TestSanitizeLabelValue(t *testing.T) {
+	for _, tt := range []struct {
+		value    string
+		expected string
+	}{
+		{"team-a", "team-a"},
+		{"Team A/B", "Team-A-B"},
+		{"_leading.and.trailing_", "leading.and.trailing"},
+		{strings.Repeat("a", 70), strings.Repeat("a", 63)},
+		{"€€€", ""},
+	} {
+		t.Run(tt.value, func(t *testing.T) {
+			assert.Equal(t, tt.expected, SanitizeLabelValue(tt.value))
+		})
+	}
+}
+
+func TestTagLabels(t *testing.T) {
+	labels, skipped := TagLabels(loadTestV1.LoadTestTags{
+		"team":             "Payments & Billing",
+		"cost center":      "cc-1234",
+		"example.com/team": "payments",
+		"Bad_Prefix/team":  "payments",
+		"owner":            "!!!",
+		"€€€":              "value",
+	})
+
+	assert.Equal(t, map[string]string{
+		"team":             "Payments-Billing",
+		"cost-center":      "cc-1234",
+		"example.com/team": "payments",
+	}, labels)
+	assert.Equal(t, []string{"Bad_Prefix/team", "owner", "€€€"}, skipped)
+}
@@ -4,8 +4,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
 	"go.uber.org/zap"
+	batchV1 "k8s.io/api/batch/v1"
 	coreV1 "k8s.io/api/core/v1"
 	k8sAPIErrors "k8s.io/apimachinery/pkg/api/errors"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -18,8 +22,12 @@ import (
 var (
 	// ErrRequireMinOneDistributedPod spec requires 1 or more DistributedPods
 	ErrRequireMinOneDistributedPod = errors.New("LoadTest must specify 1 or more DistributedPods")
-	// ErrRequireTestFile the TestFile filed is required to not be an empty string
-	ErrRequireTestFile = errors.New("LoadTest TestFile is required")
+	// ErrRequireTestFile the TestFile field is required to not be an empty string, unless TestFileURL is set
+	ErrRequireTestFile = errors.New("LoadTest TestFile or TestFileURL is required")
+	// ErrRequireMinOneUser the LOCUST_USERS env var must not set the number of users to zero
+	ErrRequireMinOneUser = errors.New("LoadTest must specify 1 or more users in LOCUST_USERS")
+	// ErrInvalidSpawnRate the Rate field must be the positive number of users started per second
+	ErrInvalidSpawnRate = errors.New("LoadTest Rate must be the positive number of users started per second, e.g. 2.5")
 )
 
 func init() {
@@ -28,17 +36,27 @@ func init() {
 
 // Backend is the Locust implementation of backend interface
 type Backend struct {
-	logger         *zap.Logger
-	kubeClientSet  kubernetes.Interface
-	config         *Config
-	podAnnotations map[string]string
-	podTolerations []coreV1.Toleration
-	nodeSelector   map[string]string
+	logger           *zap.Logger
+	kubeClientSet    kubernetes.Interface
+	config           *Config
+	podAnnotations   map[string]string
+	podTolerations   []coreV1.Toleration
+	seccompProfile   *coreV1.SeccompProfile
+	imagePullSecrets []string
+	nodeSelector     map[string]string
+
+	testFileFetcher backends.TestFileFetcher
+	jobConditions   bool
+	jobTimeout      time.Duration
 
 	// defined on SetDefaults
-	image           loadTestV1.ImageDetails
-	masterResources backends.Resources
-	workerResources backends.Resources
+	image              loadTestV1.ImageDetails
+	masterResources    backends.Resources
+	workerResources    backends.Resources
+	masterStartupProbe *coreV1.Probe
+	workerStartupProbe *coreV1.Probe
+
+	backendPodAnnotations map[string]string
 }
 
 // Type returns backend type name
@@ -46,6 +64,16 @@ func (*Backend) Type() loadTestV1.LoadTestType {
 	return loadTestV1.LoadTestTypeLocust
 }
 
+// Capabilities returns the load test fields supported by the Locust backend
+func (*Backend) Capabilities() backends.Capabilities {
+	return backends.Capabilities{
+		Distributed:     true,
+		TestFileFormats: []string{"py"},
+		RequiredFields:  []string{"distributedPods", "testFile"},
+		OptionalFields:  []string{"testFileURL", "envVars", "targetURL", "duration", "vus", "rate", "masterImage", "workerImage"},
+	}
+}
+
 // GetEnvConfig must return config struct pointer
 func (b *Backend) GetEnvConfig() interface{} {
 	b.config = &Config{}
@@ -74,6 +102,10 @@ func (b *Backend) SetDefaults() {
 		MemoryLimits:   b.config.MasterMemoryLimits,
 		MemoryRequests: b.config.MasterMemoryRequests,
 	}
+
+	b.masterStartupProbe = backends.BuildStartupProbe(b.config.MasterStartupProbe)
+	b.workerStartupProbe = backends.BuildStartupProbe(b.config.WorkerStartupProbe)
+	b.backendPodAnnotations = b.config.PodAnnotations
 }
 
 // SetPodAnnotations receives a copy of pod annotations
@@ -86,6 +118,16 @@ func (b *Backend) SetPodTolerations(tolerations []coreV1.Toleration) {
 	b.podTolerations = tolerations
 }
 
+// SetImagePullSecrets receives the image pull secrets of the loadtest pods
+func (b *Backend) SetImagePullSecrets(secrets []string) {
+	b.imagePullSecrets = secrets
+}
+
+// SetSeccompProfile receives the seccomp profile of the loadtest pods
+func (b *Backend) SetSeccompProfile(profile *coreV1.SeccompProfile) {
+	b.seccompProfile = profile
+}
+
 // SetKubeClientSet receives a copy of kubeClientSet
 func (b *Backend) SetKubeClientSet(kubeClientSet kubernetes.Interface) {
 	b.kubeClientSet = kubeClientSet
@@ -101,6 +143,21 @@ func (b *Backend) SetPodNodeSelector(nodeselector map[string]string) {
 	b.nodeSelector = nodeselector
 }
 
+// SetJobConditions receives whether the loadtest phase is determined from job conditions first
+func (b *Backend) SetJobConditions(enabled bool) {
+	b.jobConditions = enabled
+}
+
+// SetJobTimeout receives the default timeout of loadtest jobs
+func (b *Backend) SetJobTimeout(timeout time.Duration) {
+	b.jobTimeout = timeout
+}
+
+// SetTestFileFetcher receives the configuration of the init container fetching remote test files
+func (b *Backend) SetTestFileFetcher(fetcher backends.TestFileFetcher) {
+	b.testFileFetcher = fetcher
+}
+
 // TransformLoadTestSpec use given spec to validate and return a new one or error
 func (b *Backend) TransformLoadTestSpec(spec *loadTestV1.LoadTestSpec) error {
 	if nil == spec.DistributedPods {
@@ -111,10 +168,18 @@ func (b *Backend) TransformLoadTestSpec(spec *loadTestV1.LoadTestSpec) error {
 		return ErrRequireMinOneDistributedPod
 	}
 
-	if len(spec.TestFile) == 0 {
+	if len(spec.TestFile) == 0 && spec.TestFileURL == "" {
 		return ErrRequireTestFile
 	}
 
+	if err := b.ValidateConcurrency(*spec); err != nil {
+		return err
+	}
+
+	if err := validateSpawnRate(spec.Rate); err != nil {
+		return err
+	}
+
 	if spec.MasterConfig == "" {
 		spec.MasterConfig = b.image
 	}
@@ -126,6 +191,44 @@ func (b *Backend) TransformLoadTestSpec(spec *loadTestV1.LoadTestSpec) error {
 	return nil
 }
 
+// validateSpawnRate checks the rate is a number accepted by locust --spawn-rate, empty uses the Locust default
+func validateSpawnRate(rate string) error {
+	if rate == "" {
+		return nil
+	}
+
+	n, err := strconv.ParseFloat(rate, 64)
+	if err != nil || n <= 0 {
+		return ErrInvalidSpawnRate
+	}
+
+	return nil
+}
+
+// ValidateConcurrency rejects specs that set the number of users to zero with LOCUST_USERS env var,
+// the number of users set in the locustfile itself is not checked
+func (*Backend) ValidateConcurrency(spec loadTestV1.LoadTestSpec) error {
+	value, ok := spec.EnvVars["LOCUST_USERS"]
+	if !ok {
+		return nil
+	}
+
+	if n, err := strconv.Atoi(strings.TrimSpace(value)); err == nil && n == 0 {
+		return ErrRequireMinOneUser
+	}
+
+	return nil
+}
+
+// EstimateResources returns the resources requested by the master and the worker pods
+func (b *Backend) EstimateResources(spec loadTestV1.LoadTestSpec) backends.ResourceEstimate {
+	var workers int32
+	if spec.DistributedPods != nil {
+		workers = *spec.DistributedPods
+	}
+	return backends.EstimatePods(1, b.masterResources).Add(backends.EstimatePods(workers, b.workerResources))
+}
+
 // Sync check if Backend kubernetes resources have been create, if they have not been create them
 func (b *Backend) Sync(ctx context.Context, loadTest loadTestV1.LoadTest, reportURL string) error {
 	workerJobs, err := b.kubeClientSet.
@@ -167,7 +270,14 @@ func (b *Backend) Sync(ctx context.Context, loadTest loadTestV1.LoadTest, report
 		}
 	}
 
-	masterJob := newMasterJob(loadTest, configMap, secret, reportURL, b.masterResources, b.podAnnotations, b.nodeSelector, b.podTolerations, loadTest.Spec.MasterConfig, b.logger)
+	podAnnotations := backends.MergePodAnnotations(b.podAnnotations, b.backendPodAnnotations, loadTest.Spec.PodAnnotations)
+
+	masterJob := newMasterJob(loadTest, configMap, secret, reportURL, b.masterResources, podAnnotations, b.nodeSelector, b.podTolerations, loadTest.Spec.MasterConfig, b.logger)
+	b.injectTestFileFetcher(loadTest, masterJob)
+	masterJob.Spec.Template.Spec.Containers[0].StartupProbe = b.masterStartupProbe
+	masterJob.Spec.Template.Spec.SecurityContext = backends.PodSecurityContext(b.seccompProfile)
+	masterJob.Spec.Template.Spec.ImagePullSecrets = backends.ImagePullSecrets(b.imagePullSecrets, loadTest.Spec.ImagePullSecrets)
+	masterJob.Spec.ActiveDeadlineSeconds = backends.JobActiveDeadlineSeconds(b.jobTimeout, loadTest.Spec.Timeout)
 	_, err = b.kubeClientSet.
 		BatchV1().
 		Jobs(loadTest.Status.Namespace).
@@ -184,7 +294,12 @@ func (b *Backend) Sync(ctx context.Context, loadTest loadTestV1.LoadTest, report
 		return err
 	}
 
-	workerJob := newWorkerJob(loadTest, configMap, secret, masterService, b.workerResources, b.podAnnotations, b.nodeSelector, b.podTolerations, loadTest.Spec.WorkerConfig, b.logger)
+	workerJob := newWorkerJob(loadTest, configMap, secret, masterService, b.workerResources, podAnnotations, b.nodeSelector, b.podTolerations, loadTest.Spec.WorkerConfig, b.logger)
+	b.injectTestFileFetcher(loadTest, workerJob)
+	workerJob.Spec.Template.Spec.Containers[0].StartupProbe = b.workerStartupProbe
+	workerJob.Spec.Template.Spec.SecurityContext = backends.PodSecurityContext(b.seccompProfile)
+	workerJob.Spec.Template.Spec.ImagePullSecrets = backends.ImagePullSecrets(b.imagePullSecrets, loadTest.Spec.ImagePullSecrets)
+	workerJob.Spec.ActiveDeadlineSeconds = backends.JobActiveDeadlineSeconds(b.jobTimeout, loadTest.Spec.Timeout)
 	_, err = b.kubeClientSet.
 		BatchV1().
 		Jobs(loadTest.Status.Namespace).
@@ -197,6 +312,14 @@ func (b *Backend) Sync(ctx context.Context, loadTest loadTestV1.LoadTest, report
 	return nil
 }
 
+// injectTestFileFetcher makes the job fetch the locustfile from TestFileURL instead of the testfile ConfigMap
+func (b *Backend) injectTestFileFetcher(loadTest loadTestV1.LoadTest, job *batchV1.Job) {
+	if loadTest.Spec.TestFileURL == "" {
+		return
+	}
+	b.testFileFetcher.Inject(&job.Spec.Template.Spec, loadTest.Spec.TestFileURL, testFileVolumeName, testFileName)
+}
+
 // SyncStatus check the Backend resources and calculate the current status of the LoadTest from them
 func (b *Backend) SyncStatus(ctx context.Context, loadTest loadTestV1.LoadTest, loadTestStatus *loadTestV1.LoadTestStatus) error {
 	if loadTestStatus.Phase == "" {
@@ -235,7 +358,7 @@ func (b *Backend) SyncStatus(ctx context.Context, loadTest loadTestV1.LoadTest,
 		return err
 	}
 
-	loadTestStatus.Phase = determineLoadTestStatusFromJobs(masterJob, workerJob)
+	loadTestStatus.Phase = backends.PreferJobConditions(b.jobConditions, determineLoadTestStatusFromJobs(masterJob, workerJob), masterJob.Status, workerJob.Status)
 	loadTestStatus.JobStatus = masterJob.Status
 
 	return nil
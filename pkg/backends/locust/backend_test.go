@@ -283,6 +283,7 @@ func TestTransformLoadTestSpec(t *testing.T) {
 		envVarsStr      map[string]string
 		targetURL       string
 		duration        time.Duration
+		rate            string
 	}
 	tests := []struct {
 		name    string
@@ -319,6 +320,31 @@ func TestTransformLoadTestSpec(t *testing.T) {
 			want:    loadTestV1.LoadTestSpec{},
 			wantErr: true,
 		},
+		{
+			name: "Spec invalid - zero users",
+			args: args{
+				distributedPods: 3,
+				testFile:        []byte("something in the file"),
+				envVarsStr:      map[string]string{"LOCUST_USERS": "0"},
+			},
+			want: loadTestV1.LoadTestSpec{
+				TestFile: []byte("something in the file"),
+				EnvVars:  map[string]string{"LOCUST_USERS": "0"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Spec invalid - invalid spawn rate",
+			args: args{
+				distributedPods: 3,
+				testFile:        []byte("something in the file"),
+				rate:            "50/1s",
+			},
+			want: loadTestV1.LoadTestSpec{
+				TestFile: []byte("something in the file"),
+			},
+			wantErr: true,
+		},
 		{
 			name: "Spec invalid - require test file",
 			args: args{
@@ -338,6 +364,7 @@ func TestTransformLoadTestSpec(t *testing.T) {
 				EnvVars:         tt.args.envVarsStr,
 				TargetURL:       tt.args.targetURL,
 				Duration:        tt.args.duration,
+				Rate:            tt.args.rate,
 			}
 
 			cfg := Config{}
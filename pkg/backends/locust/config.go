@@ -1,5 +1,7 @@
 package locust
 
+import "github.com/hellofresh/kangal/pkg/backends"
+
 // Config specific to Locust backend
 type Config struct {
 	Image                string `envconfig:"LOCUST_IMAGE"`
@@ -13,4 +15,10 @@ type Config struct {
 	WorkerCPURequests    string `envconfig:"LOCUST_WORKER_CPU_REQUESTS"`
 	WorkerMemoryLimits   string `envconfig:"LOCUST_WORKER_MEMORY_LIMITS"`
 	WorkerMemoryRequests string `envconfig:"LOCUST_WORKER_MEMORY_REQUESTS"`
+
+	MasterStartupProbe backends.StartupProbe `envconfig:"LOCUST_MASTER_STARTUP_PROBE"`
+	WorkerStartupProbe backends.StartupProbe `envconfig:"LOCUST_WORKER_STARTUP_PROBE"`
+
+	// PodAnnotations are the default annotations of the loadtest pods of the backend
+	PodAnnotations map[string]string `envconfig:"LOCUST_POD_ANNOTATIONS"`
 }
@@ -2,6 +2,7 @@ package locust
 
 import (
 	"fmt"
+	"strconv"
 
 	"go.uber.org/zap"
 	batchV1 "k8s.io/api/batch/v1"
@@ -13,6 +14,11 @@ import (
 	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
 )
 
+const (
+	testFileVolumeName = "testfile"
+	testFileName       = "locustfile.py"
+)
+
 var (
 	loadTestLabelKey         = "app"
 	loadTestMasterLabelValue = "loadtest-master"
@@ -35,7 +41,7 @@ func newConfigMap(loadTest loadTestV1.LoadTest) *coreV1.ConfigMap {
 			OwnerReferences: []metaV1.OwnerReference{*ownerRef},
 		},
 		BinaryData: map[string][]byte{
-			"locustfile.py": loadTest.Spec.TestFile,
+			testFileName: loadTest.Spec.TestFile,
 		},
 	}
 }
@@ -97,6 +103,14 @@ func newMasterJob(
 		{Name: "LOCUST_RUN_TIME", Value: loadTest.Spec.Duration.String()},
 	}
 
+	// the spec fields take precedence over the LOCUST_USERS and LOCUST_SPAWN_RATE env vars of the loadtest
+	if loadTest.Spec.VUs > 0 {
+		envVars = append(envVars, coreV1.EnvVar{Name: "LOCUST_USERS", Value: strconv.Itoa(int(loadTest.Spec.VUs))})
+	}
+	if loadTest.Spec.Rate != "" {
+		envVars = append(envVars, coreV1.EnvVar{Name: "LOCUST_SPAWN_RATE", Value: loadTest.Spec.Rate})
+	}
+
 	if reportURL != "" {
 		envVars = append(envVars, coreV1.EnvVar{
 			Name:  "REPORT_PRESIGNED_URL",
@@ -118,7 +132,7 @@ func newMasterJob(
 	// Locust does not support recovering after a failure
 	backoffLimit := int32(0)
 
-	return &batchV1.Job{
+	job := &batchV1.Job{
 		ObjectMeta: metaV1.ObjectMeta{
 			Name:      name,
 			Namespace: loadTest.Status.Namespace,
@@ -150,9 +164,9 @@ func newMasterJob(
 							Env:             envVars,
 							VolumeMounts: []coreV1.VolumeMount{
 								{
-									Name:      "testfile",
-									MountPath: "/data/locustfile.py",
-									SubPath:   "locustfile.py",
+									Name:      testFileVolumeName,
+									MountPath: "/data/" + testFileName,
+									SubPath:   testFileName,
 								},
 							},
 							Resources: backends.BuildResourceRequirements(masterResources),
@@ -161,7 +175,7 @@ func newMasterJob(
 					},
 					Volumes: []coreV1.Volume{
 						{
-							Name: "testfile",
+							Name: testFileVolumeName,
 							VolumeSource: coreV1.VolumeSource{
 								ConfigMap: &coreV1.ConfigMapVolumeSource{
 									LocalObjectReference: coreV1.LocalObjectReference{
@@ -175,6 +189,8 @@ func newMasterJob(
 			},
 		},
 	}
+	backends.SetSpecHash(job, loadTest)
+	return job
 }
 
 func newMasterService(loadTest loadTestV1.LoadTest, masterJob *batchV1.Job) *coreV1.Service {
@@ -254,7 +270,7 @@ func newWorkerJob(
 	// Locust does not support recovering after a failure
 	backoffLimit := int32(0)
 
-	return &batchV1.Job{
+	job := &batchV1.Job{
 		ObjectMeta: metaV1.ObjectMeta{
 			Name:      name,
 			Namespace: loadTest.Status.Namespace,
@@ -286,9 +302,9 @@ func newWorkerJob(
 							Env:             envVars,
 							VolumeMounts: []coreV1.VolumeMount{
 								{
-									Name:      "testfile",
-									MountPath: "/data/locustfile.py",
-									SubPath:   "locustfile.py",
+									Name:      testFileVolumeName,
+									MountPath: "/data/" + testFileName,
+									SubPath:   testFileName,
 								},
 							},
 							Resources: backends.BuildResourceRequirements(workerResources),
@@ -297,7 +313,7 @@ func newWorkerJob(
 					},
 					Volumes: []coreV1.Volume{
 						{
-							Name: "testfile",
+							Name: testFileVolumeName,
 							VolumeSource: coreV1.VolumeSource{
 								ConfigMap: &coreV1.ConfigMapVolumeSource{
 									LocalObjectReference: coreV1.LocalObjectReference{
@@ -311,10 +327,16 @@ func newWorkerJob(
 			},
 		},
 	}
+	backends.SetSpecHash(job, loadTest)
+	return job
 }
 
 // determineLoadTestStatusFromJobs reads existing job statuses and determines what the loadtest status should be
 func determineLoadTestStatusFromJobs(masterJob *batchV1.Job, workerJob *batchV1.Job) loadTestV1.LoadTestPhase {
+	if backends.JobDeadlineExceeded(masterJob.Status) || backends.JobDeadlineExceeded(workerJob.Status) {
+		return loadTestV1.LoadTestErrored
+	}
+
 	if workerJob.Status.Failed > int32(0) || masterJob.Status.Failed > int32(0) {
 		return loadTestV1.LoadTestErrored
 	}
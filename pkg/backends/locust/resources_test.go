@@ -4,8 +4,11 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
 	batchV1 "k8s.io/api/batch/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	"github.com/hellofresh/kangal/pkg/backends"
 	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
 )
 
@@ -106,3 +109,33 @@ func TestGetLoadTestStatusFromJobs(t *testing.T) {
 		assert.Equal(t, scenario.Expected, actual)
 	}
 }
+
+func TestNewMasterJobUsersAndSpawnRate(t *testing.T) {
+	distributedPods := int32(2)
+	loadTest := loadTestV1.LoadTest{
+		ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-name"},
+		Spec: loadTestV1.LoadTestSpec{
+			DistributedPods: &distributedPods,
+			VUs:             100,
+			Rate:            "2.5",
+		},
+	}
+
+	job := newMasterJob(loadTest, newConfigMap(loadTest), nil, "", backends.Resources{}, nil, nil, nil, "locustio/locust:latest", zap.NewNop())
+
+	env := map[string]string{}
+	for _, e := range job.Spec.Template.Spec.Containers[0].Env {
+		env[e.Name] = e.Value
+	}
+	assert.Equal(t, "100", env["LOCUST_USERS"])
+	assert.Equal(t, "2.5", env["LOCUST_SPAWN_RATE"])
+
+	loadTest.Spec.VUs = 0
+	loadTest.Spec.Rate = ""
+	job = newMasterJob(loadTest, newConfigMap(loadTest), nil, "", backends.Resources{}, nil, nil, nil, "locustio/locust:latest", zap.NewNop())
+
+	for _, e := range job.Spec.Template.Spec.Containers[0].Env {
+		assert.NotEqual(t, "LOCUST_USERS", e.Name)
+		assert.NotEqual(t, "LOCUST_SPAWN_RATE", e.Name)
+	}
+}
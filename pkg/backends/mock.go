@@ -40,6 +40,20 @@ func (m *MockBackend) EXPECT() *MockBackendMockRecorder {
 	return m.recorder
 }
 
+// Capabilities mocks base method.
+func (m *MockBackend) Capabilities() Capabilities {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Capabilities")
+	ret0, _ := ret[0].(Capabilities)
+	return ret0
+}
+
+// Capabilities indicates an expected call of Capabilities.
+func (mr *MockBackendMockRecorder) Capabilities() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Capabilities", reflect.TypeOf((*MockBackend)(nil).Capabilities))
+}
+
 // Sync mocks base method.
 func (m *MockBackend) Sync(ctx context.Context, loadTest v1.LoadTest, reportURL string) error {
 	m.ctrl.T.Helper()
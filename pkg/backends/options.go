@@ -1,6 +1,8 @@
 package backends
 
 import (
+	"time"
+
 	"go.uber.org/zap"
 	kubeCoreV1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/kubernetes"
@@ -15,6 +17,7 @@ type Option func(*registry)
 // WithLogger adds given logger to each registered backend that implements BackendSetLogger
 func WithLogger(logger *zap.Logger) Option {
 	return func(b *registry) {
+		b.logger = logger
 		for _, item := range b.registry {
 			if iface, ok := item.(BackendSetLogger); ok {
 				iface.SetLogger(logger)
@@ -56,6 +59,113 @@ func WithTolerations(tolerations []kubeCoreV1.Toleration) Option {
 	}
 }
 
+// WithTagLabels adds given tag labels preference to each registered backend that implements BackendSetTagLabels
+func WithTagLabels(enabled bool) Option {
+	return func(b *registry) {
+		for _, item := range b.registry {
+			if iface, ok := item.(BackendSetTagLabels); ok {
+				iface.SetTagLabels(enabled)
+			}
+		}
+	}
+}
+
+// WithImagePullSecrets adds given image pull secrets to each registered backend that implements
+// BackendSetImagePullSecrets
+func WithImagePullSecrets(secrets []string) Option {
+	return func(b *registry) {
+		if len(secrets) == 0 {
+			return
+		}
+		for _, item := range b.registry {
+			if iface, ok := item.(BackendSetImagePullSecrets); ok {
+				iface.SetImagePullSecrets(secrets)
+			}
+		}
+	}
+}
+
+// WithPodServiceAccount adds given service account name to each registered backend that implements
+// BackendSetPodServiceAccount
+func WithPodServiceAccount(name string) Option {
+	return func(b *registry) {
+		if name == "" {
+			return
+		}
+		for _, item := range b.registry {
+			if iface, ok := item.(BackendSetPodServiceAccount); ok {
+				iface.SetPodServiceAccount(name)
+			}
+		}
+	}
+}
+
+// WithSeccompProfile adds given seccomp profile to each registered backend that implements BackendSetSeccompProfile,
+// a nil profile sets none
+func WithSeccompProfile(profile *kubeCoreV1.SeccompProfile) Option {
+	return func(b *registry) {
+		if profile == nil {
+			return
+		}
+		for _, item := range b.registry {
+			if iface, ok := item.(BackendSetSeccompProfile); ok {
+				iface.SetSeccompProfile(profile)
+			}
+		}
+	}
+}
+
+// WithTestFileFetcher adds given test file fetcher configuration to each registered backend that implements BackendSetTestFileFetcher
+func WithTestFileFetcher(fetcher TestFileFetcher) Option {
+	return func(b *registry) {
+		for _, item := range b.registry {
+			if iface, ok := item.(BackendSetTestFileFetcher); ok {
+				iface.SetTestFileFetcher(fetcher)
+			}
+		}
+	}
+}
+
+// WithJobConditions adds given job conditions preference to each registered backend that implements BackendSetJobConditions
+func WithJobConditions(enabled bool) Option {
+	return func(b *registry) {
+		for _, item := range b.registry {
+			if iface, ok := item.(BackendSetJobConditions); ok {
+				iface.SetJobConditions(enabled)
+			}
+		}
+	}
+}
+
+// WithJobTimeout adds given default job timeout to each registered backend that implements BackendSetJobTimeout
+func WithJobTimeout(timeout time.Duration) Option {
+	return func(b *registry) {
+		if timeout <= 0 {
+			return
+		}
+		for _, item := range b.registry {
+			if iface, ok := item.(BackendSetJobTimeout); ok {
+				iface.SetJobTimeout(timeout)
+			}
+		}
+	}
+}
+
+// WithJobBackoffLimit adds given job backoff limit to each registered backend that implements BackendSetJobBackoffLimit,
+// a negative limit keeps the backend defaults
+func WithJobBackoffLimit(limit int32) Option {
+	return func(b *registry) {
+		if limit < 0 {
+			return
+		}
+		for _, item := range b.registry {
+			if iface, ok := item.(BackendSetJobBackoffLimit); ok {
+				iface.SetJobBackoffLimit(limit)
+			}
+		}
+	}
+}
+
 // WithKubeClientSet adds given kubeClientSet to each registered backend that implements BackendKubeClientSet
 func WithKubeClientSet(kubeClientSet kubernetes.Interface) Option {
 	return func(b *registry) {
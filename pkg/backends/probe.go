@@ -0,0 +1,43 @@
+package backends
+
+import (
+	coreV1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// StartupProbe contains the startup probe configuration of load generator containers,
+// backends read it from env vars with their own prefix, e.g. GHZ_STARTUP_PROBE_COMMAND
+type StartupProbe struct {
+	// Command is run with /bin/sh -c in the container, it takes precedence over TCPPort
+	Command string `envconfig:"COMMAND"`
+	// TCPPort is the container port that must accept connections
+	TCPPort             int   `envconfig:"TCP_PORT"`
+	InitialDelaySeconds int32 `envconfig:"INITIAL_DELAY_SECONDS" default:"0"`
+	PeriodSeconds       int32 `envconfig:"PERIOD_SECONDS" default:"10"`
+	FailureThreshold    int32 `envconfig:"FAILURE_THRESHOLD" default:"30"`
+}
+
+// BuildStartupProbe creates the startup probe of a container, it returns nil
+// when neither a command nor a TCP port is configured
+func BuildStartupProbe(probe StartupProbe) *coreV1.Probe {
+	var handler coreV1.ProbeHandler
+	switch {
+	case probe.Command != "":
+		handler.Exec = &coreV1.ExecAction{
+			Command: []string{"/bin/sh", "-c", probe.Command},
+		}
+	case probe.TCPPort > 0:
+		handler.TCPSocket = &coreV1.TCPSocketAction{
+			Port: intstr.FromInt(probe.TCPPort),
+		}
+	default:
+		return nil
+	}
+
+	return &coreV1.Probe{
+		ProbeHandler:        handler,
+		InitialDelaySeconds: probe.InitialDelaySeconds,
+		PeriodSeconds:       probe.PeriodSeconds,
+		FailureThreshold:    probe.FailureThreshold,
+	}
+}
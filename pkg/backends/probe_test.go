@@ -0,0 +1,33 @@
+package backends_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/hellofresh/kangal/pkg/backends"
+)
+
+func TestBuildStartupProbe(t *testing.T) {
+	assert.Nil(t, backends.BuildStartupProbe(backends.StartupProbe{PeriodSeconds: 10}))
+
+	probe := backends.BuildStartupProbe(backends.StartupProbe{
+		Command:          "test -f /tmp/ready",
+		TCPPort:          1099,
+		PeriodSeconds:    5,
+		FailureThreshold: 12,
+	})
+	require.NotNil(t, probe)
+	require.NotNil(t, probe.Exec)
+	assert.Nil(t, probe.TCPSocket)
+	assert.Equal(t, []string{"/bin/sh", "-c", "test -f /tmp/ready"}, probe.Exec.Command)
+	assert.Equal(t, int32(5), probe.PeriodSeconds)
+	assert.Equal(t, int32(12), probe.FailureThreshold)
+
+	probe = backends.BuildStartupProbe(backends.StartupProbe{TCPPort: 1099})
+	require.NotNil(t, probe)
+	require.NotNil(t, probe.TCPSocket)
+	assert.Equal(t, intstr.FromInt(1099), probe.TCPSocket.Port)
+}
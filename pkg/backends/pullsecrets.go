@@ -0,0 +1,28 @@
+package backends
+
+import (
+	coreV1 "k8s.io/api/core/v1"
+)
+
+// ImagePullSecretNames returns the names of the image pull secrets of loadtest pods, the secrets of the
+// loadtest replace the configured ones when it sets any
+func ImagePullSecretNames(configured, loadTest []string) []string {
+	if len(loadTest) > 0 {
+		return loadTest
+	}
+	return configured
+}
+
+// ImagePullSecrets returns the image pull secrets to set in the spec of loadtest pods, nil if there are none
+func ImagePullSecrets(configured, loadTest []string) []coreV1.LocalObjectReference {
+	names := ImagePullSecretNames(configured, loadTest)
+	if len(names) == 0 {
+		return nil
+	}
+
+	refs := make([]coreV1.LocalObjectReference, 0, len(names))
+	for _, name := range names {
+		refs = append(refs, coreV1.LocalObjectReference{Name: name})
+	}
+	return refs
+}
@@ -0,0 +1,39 @@
+package backends
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	coreV1 "k8s.io/api/core/v1"
+)
+
+func TestImagePullSecrets(t *testing.T) {
+	for _, tt := range []struct {
+		name       string
+		configured []string
+		loadTest   []string
+		expected   []coreV1.LocalObjectReference
+	}{
+		{name: "none"},
+		{
+			name:       "configured",
+			configured: []string{"registry", "mirror"},
+			expected:   []coreV1.LocalObjectReference{{Name: "registry"}, {Name: "mirror"}},
+		},
+		{
+			name:       "loadtest override",
+			configured: []string{"registry"},
+			loadTest:   []string{"team-registry"},
+			expected:   []coreV1.LocalObjectReference{{Name: "team-registry"}},
+		},
+		{
+			name:     "loadtest only",
+			loadTest: []string{"team-registry"},
+			expected: []coreV1.LocalObjectReference{{Name: "team-registry"}},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ImagePullSecrets(tt.configured, tt.loadTest))
+		})
+	}
+}
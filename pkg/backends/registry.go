@@ -1,9 +1,15 @@
 package backends
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/kelseyhightower/envconfig"
+	"go.uber.org/zap"
 
 	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
 )
@@ -30,17 +36,36 @@ func Register(b Backend) {
 // Registry is the interface for backends registering/retrieving
 type Registry interface {
 	GetBackend(loadTestType loadTestV1.LoadTestType) (Backend, error)
+	Has(loadTestType loadTestV1.LoadTestType) bool
+	List() []string
+	Init(ctx context.Context, cfg InitConfig) error
+	Capabilities() []Capabilities
+}
+
+// InitConfig configures the initialization of the registered backends
+type InitConfig struct {
+	// Timeout is the time each backend has to initialize, 0 means no timeout
+	Timeout time.Duration
+	// Optional are the types of the backends that are skipped when they fail to initialize,
+	// any other backend failing to initialize fails the registry initialization
+	Optional []string
 }
 
 // registry you can use this to add information to backends and to resolve to then
 type registry struct {
 	registry map[loadTestV1.LoadTestType]Backend
+	// disabled are the types of the optional backends that failed to initialize, they are kept out of this registry
+	// only, the registered backends are shared by every registry
+	disabled map[loadTestV1.LoadTestType]bool
+	logger   *zap.Logger
 }
 
 // New creates a new backend registry instance
 func New(opts ...Option) Registry {
 	b := &registry{
 		registry: defaultRegistry,
+		disabled: map[loadTestV1.LoadTestType]bool{},
+		logger:   zap.NewNop(),
 	}
 
 	for _, opt := range opts {
@@ -65,8 +90,99 @@ func New(opts ...Option) Registry {
 // GetBackend return the given backend name from the registry
 func (b *registry) GetBackend(loadTestType loadTestV1.LoadTestType) (Backend, error) {
 	resolved, exists := b.registry[loadTestType]
-	if !exists {
+	if !exists || b.disabled[loadTestType] {
 		return nil, ErrNoBackendRegistered
 	}
 	return resolved, nil
 }
+
+// Has tells if a backend is registered for the given loadtest type
+func (b *registry) Has(loadTestType loadTestV1.LoadTestType) bool {
+	_, exists := b.registry[loadTestType]
+	return exists && !b.disabled[loadTestType]
+}
+
+// List returns the types of the registered backends, sorted by name
+func (b *registry) List() []string {
+	types := make([]string, 0, len(b.registry))
+	for loadTestType := range b.registry {
+		if b.disabled[loadTestType] {
+			continue
+		}
+		types = append(types, string(loadTestType))
+	}
+	sort.Strings(types)
+	return types
+}
+
+// Init initializes the backends implementing BackendInit in parallel. Optional backends that fail to initialize
+// are disabled in the registry, an error is returned if any other backend fails to initialize
+func (b *registry) Init(ctx context.Context, cfg InitConfig) error {
+	type initResult struct {
+		loadTestType loadTestV1.LoadTestType
+		err          error
+	}
+
+	results := make(chan initResult, len(b.registry))
+	pending := 0
+	for loadTestType, reg := range b.registry {
+		item, ok := reg.(BackendInit)
+		if !ok || b.disabled[loadTestType] {
+			continue
+		}
+
+		pending++
+		go func(loadTestType loadTestV1.LoadTestType, item BackendInit) {
+			results <- initResult{loadTestType: loadTestType, err: initBackend(ctx, item, cfg.Timeout)}
+		}(loadTestType, item)
+	}
+
+	var errs []error
+	for ; pending > 0; pending-- {
+		result := <-results
+		if result.err == nil {
+			continue
+		}
+
+		if cfg.isOptional(result.loadTestType) {
+			b.logger.Warn("Skipping optional backend that failed to initialize",
+				zap.String("type", string(result.loadTestType)), zap.Error(result.err))
+			b.disabled[result.loadTestType] = true
+			continue
+		}
+
+		errs = append(errs, fmt.Errorf("backend %s: %w", result.loadTestType, result.err))
+	}
+
+	return errors.Join(errs...)
+}
+
+// initBackend runs backend Init, giving up once timeout is exceeded even if the backend ignores its context
+func initBackend(ctx context.Context, item BackendInit, timeout time.Duration) error {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- item.Init(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (cfg InitConfig) isOptional(loadTestType loadTestV1.LoadTestType) bool {
+	for _, optional := range cfg.Optional {
+		if strings.EqualFold(optional, string(loadTestType)) {
+			return true
+		}
+	}
+	return false
+}
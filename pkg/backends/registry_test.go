@@ -1,9 +1,13 @@
 package backends
 
 import (
+	"context"
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
 	"go.uber.org/zap"
 	kubeFake "k8s.io/client-go/kubernetes/fake"
 
@@ -94,3 +98,183 @@ func TestNew(t *testing.T) {
 		})
 	}
 }
+
+type initTestBackend struct {
+	Backend
+	loadTestType loadTestV1.LoadTestType
+	err          error
+	block        bool
+}
+
+func (b *initTestBackend) Type() loadTestV1.LoadTestType {
+	return b.loadTestType
+}
+
+func (b *initTestBackend) Init(ctx context.Context) error {
+	if b.block {
+		select {}
+	}
+	return b.err
+}
+
+func TestRegistryInit(t *testing.T) {
+	errInit := errors.New("init failed")
+
+	tests := []struct {
+		name          string
+		backends      []*initTestBackend
+		optional      []string
+		expectedError bool
+		expectedTypes []loadTestV1.LoadTestType
+	}{
+		{
+			name: "all backends initialized",
+			backends: []*initTestBackend{
+				{loadTestType: "a"},
+				{loadTestType: "b"},
+			},
+			expectedTypes: []loadTestV1.LoadTestType{"a", "b"},
+		},
+		{
+			name: "required backend fails",
+			backends: []*initTestBackend{
+				{loadTestType: "a"},
+				{loadTestType: "b", err: errInit},
+			},
+			expectedError: true,
+			expectedTypes: []loadTestV1.LoadTestType{"a", "b"},
+		},
+		{
+			name: "optional backend fails",
+			backends: []*initTestBackend{
+				{loadTestType: "a"},
+				{loadTestType: "b", err: errInit},
+			},
+			optional:      []string{"B"},
+			expectedTypes: []loadTestV1.LoadTestType{"a"},
+		},
+		{
+			name: "required backend times out",
+			backends: []*initTestBackend{
+				{loadTestType: "a", block: true},
+			},
+			expectedError: true,
+			expectedTypes: []loadTestV1.LoadTestType{"a"},
+		},
+		{
+			name: "optional backend times out",
+			backends: []*initTestBackend{
+				{loadTestType: "a", block: true},
+				{loadTestType: "b"},
+			},
+			optional:      []string{"a"},
+			expectedTypes: []loadTestV1.LoadTestType{"b"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registered := map[loadTestV1.LoadTestType]Backend{}
+			for _, backend := range tt.backends {
+				registered[backend.Type()] = backend
+			}
+			b := &registry{
+				registry: registered,
+				disabled: map[loadTestV1.LoadTestType]bool{},
+				logger:   zap.NewNop(),
+			}
+
+			err := b.Init(context.Background(), InitConfig{
+				Timeout:  10 * time.Millisecond,
+				Optional: tt.optional,
+			})
+			if tt.expectedError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			for _, loadTestType := range tt.expectedTypes {
+				_, err := b.GetBackend(loadTestType)
+				assert.NoError(t, err)
+			}
+			assert.Len(t, b.List(), len(tt.expectedTypes))
+
+			// backends failing to initialize are disabled in this registry only
+			assert.Len(t, registered, len(tt.backends))
+			other := &registry{registry: registered, disabled: map[loadTestV1.LoadTestType]bool{}}
+			assert.Len(t, other.List(), len(tt.backends))
+		})
+	}
+}
+
+func TestRegistryCapabilities(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	first := NewMockBackend(ctrl)
+	first.EXPECT().Capabilities().Return(Capabilities{Distributed: true, RequiredFields: []string{"testFile"}})
+	second := NewMockBackend(ctrl)
+	second.EXPECT().Capabilities().Return(Capabilities{})
+
+	b := &registry{
+		registry: map[loadTestV1.LoadTestType]Backend{
+			"b": first,
+			"a": second,
+			// backends without capabilities are listed with their type only
+			"c": basicBackend{},
+		},
+	}
+
+	assert.Equal(t, []Capabilities{
+		{Type: "a"},
+		{Type: "b", Distributed: true, RequiredFields: []string{"testFile"}},
+		{Type: "c"},
+	}, b.Capabilities())
+}
+
+// basicBackend implements none of the optional backend interfaces
+type basicBackend struct {
+	Backend
+}
+
+func TestRegistryList(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	b := &registry{
+		registry: map[loadTestV1.LoadTestType]Backend{
+			"b": NewMockBackend(ctrl),
+			"a": NewMockBackend(ctrl),
+		},
+	}
+
+	assert.Equal(t, []string{"a", "b"}, b.List())
+	assert.True(t, b.Has("a"))
+	assert.False(t, b.Has("c"))
+
+	assert.Empty(t, (&registry{registry: map[loadTestV1.LoadTestType]Backend{}}).List())
+}
+
+func TestCapabilitiesUnsupportedFields(t *testing.T) {
+	capabilities := Capabilities{
+		RequiredFields: []string{"distributedPods", "testFile", "duration"},
+		OptionalFields: []string{"testFileURL", "rate", "cpuLimits"},
+	}
+
+	assert.Empty(t, capabilities.UnsupportedFields(loadTestV1.LoadTestSpec{
+		TestFile:    []byte("GET http://example.com"),
+		Duration:    time.Minute,
+		Rate:        "50/1s",
+		DependsOn:   "warmup",
+		Resources:   &loadTestV1.LoadTestResources{CPULimits: "1"},
+		TestFileURL: "https://example.com/targets.txt",
+	}))
+
+	// thresholds need a backend filling in the summary
+	assert.Equal(t, []string{"envVars", "targetURL", "thresholds", "memoryRequests"}, capabilities.UnsupportedFields(loadTestV1.LoadTestSpec{
+		EnvVars:    map[string]string{"foo": "bar"},
+		TargetURL:  "http://example.com",
+		Thresholds: []string{"rps>10"},
+		Resources:  &loadTestV1.LoadTestResources{CPULimits: "1", MemoryRequests: "128Mi"},
+	}))
+}
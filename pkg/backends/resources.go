@@ -1,10 +1,18 @@
 package backends
 
 import (
+	"errors"
+	"fmt"
+
 	coreV1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
 )
 
+// ErrInvalidResources is the error returned when the resources of a loadtest are not valid quantities
+var ErrInvalidResources = errors.New("invalid loadtest resources")
+
 // Resources contains resources limits/requests
 type Resources struct {
 	CPULimits      string
@@ -40,3 +48,61 @@ func BuildResourceRequirements(resources Resources) coreV1.ResourceRequirements
 		Requests: requests,
 	}
 }
+
+// OverrideResources returns the given backend resources with the fields set in the loadtest resources replacing them
+func OverrideResources(resources Resources, override *loadTestV1.LoadTestResources) Resources {
+	if override == nil {
+		return resources
+	}
+
+	if override.CPULimits != "" {
+		resources.CPULimits = override.CPULimits
+	}
+	if override.CPURequests != "" {
+		resources.CPURequests = override.CPURequests
+	}
+	if override.MemoryLimits != "" {
+		resources.MemoryLimits = override.MemoryLimits
+	}
+	if override.MemoryRequests != "" {
+		resources.MemoryRequests = override.MemoryRequests
+	}
+
+	return resources
+}
+
+// ValidateResources returns an error when a field set in the loadtest resources is not a valid quantity, or when
+// a request exceeds its limit once the loadtest resources are merged with the given backend defaults
+func ValidateResources(defaults Resources, resources *loadTestV1.LoadTestResources) error {
+	if resources == nil {
+		return nil
+	}
+
+	for _, field := range []struct {
+		name  string
+		value string
+	}{
+		{name: "cpuLimits", value: resources.CPULimits},
+		{name: "cpuRequests", value: resources.CPURequests},
+		{name: "memoryLimits", value: resources.MemoryLimits},
+		{name: "memoryRequests", value: resources.MemoryRequests},
+	} {
+		if field.value == "" {
+			continue
+		}
+		if _, err := resource.ParseQuantity(field.value); err != nil {
+			return fmt.Errorf("%w: %s %q: %s", ErrInvalidResources, field.name, field.value, err)
+		}
+	}
+
+	// Kubernetes rejects pods requesting more than their limit, which would leave the loadtest without pods
+	requirements := BuildResourceRequirements(OverrideResources(defaults, resources))
+	for name, request := range requirements.Requests {
+		limit, ok := requirements.Limits[name]
+		if ok && request.Cmp(limit) > 0 {
+			return fmt.Errorf("%w: %s request %s exceeds its limit %s", ErrInvalidResources, name, request.String(), limit.String())
+		}
+	}
+
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/hellofresh/kangal/pkg/backends"
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
 )
 
 func TestBuildResourceRequirements(t *testing.T) {
@@ -18,3 +19,33 @@ func TestBuildResourceRequirements(t *testing.T) {
 	assert.Equal(t, 2, len(req.Limits))
 	assert.Equal(t, 2, len(req.Requests))
 }
+
+func TestOverrideResources(t *testing.T) {
+	defaults := backends.Resources{
+		CPULimits:      "500m",
+		CPURequests:    "250m",
+		MemoryLimits:   "128Mi",
+		MemoryRequests: "64Mi",
+	}
+
+	assert.Equal(t, defaults, backends.OverrideResources(defaults, nil))
+	assert.Equal(t, backends.Resources{
+		CPULimits:      "2",
+		CPURequests:    "250m",
+		MemoryLimits:   "128Mi",
+		MemoryRequests: "1Gi",
+	}, backends.OverrideResources(defaults, &loadTestV1.LoadTestResources{CPULimits: "2", MemoryRequests: "1Gi"}))
+}
+
+func TestValidateResources(t *testing.T) {
+	defaults := backends.Resources{CPULimits: "1", CPURequests: "500m", MemoryLimits: "512Mi", MemoryRequests: "256Mi"}
+
+	assert.NoError(t, backends.ValidateResources(defaults, nil))
+	assert.NoError(t, backends.ValidateResources(defaults, &loadTestV1.LoadTestResources{CPULimits: "2", MemoryRequests: "512Mi"}))
+	assert.ErrorIs(t, backends.ValidateResources(defaults, &loadTestV1.LoadTestResources{MemoryLimits: "lots"}), backends.ErrInvalidResources)
+
+	// requests are checked against the limits once merged with the backend defaults
+	assert.ErrorIs(t, backends.ValidateResources(defaults, &loadTestV1.LoadTestResources{CPURequests: "2"}), backends.ErrInvalidResources)
+	assert.ErrorIs(t, backends.ValidateResources(defaults, &loadTestV1.LoadTestResources{MemoryLimits: "128Mi"}), backends.ErrInvalidResources)
+	assert.NoError(t, backends.ValidateResources(backends.Resources{}, &loadTestV1.LoadTestResources{CPURequests: "2"}))
+}
@@ -0,0 +1,45 @@
+package backends
+
+import (
+	"errors"
+	"fmt"
+
+	coreV1 "k8s.io/api/core/v1"
+)
+
+// NewSeccompProfile returns the seccomp profile of the given type, one of RuntimeDefault, Localhost or Unconfined,
+// nil if the type is empty. The localhost profile is the profile path of the Localhost type, relative to the
+// seccomp directory of the kubelet.
+func NewSeccompProfile(profileType, localhostProfile string) (*coreV1.SeccompProfile, error) {
+	if profileType == "" {
+		if localhostProfile != "" {
+			return nil, errors.New("seccomp localhost profile requires the Localhost seccomp profile type")
+		}
+		return nil, nil
+	}
+
+	profile := &coreV1.SeccompProfile{Type: coreV1.SeccompProfileType(profileType)}
+	switch profile.Type {
+	case coreV1.SeccompProfileTypeRuntimeDefault, coreV1.SeccompProfileTypeUnconfined:
+		if localhostProfile != "" {
+			return nil, fmt.Errorf("seccomp localhost profile can't be set with the %s seccomp profile type", profileType)
+		}
+	case coreV1.SeccompProfileTypeLocalhost:
+		if localhostProfile == "" {
+			return nil, errors.New("seccomp profile type Localhost requires a seccomp localhost profile")
+		}
+		profile.LocalhostProfile = &localhostProfile
+	default:
+		return nil, fmt.Errorf("unknown seccomp profile type %q, must be one of RuntimeDefault, Localhost or Unconfined", profileType)
+	}
+	return profile, nil
+}
+
+// PodSecurityContext returns the pod security context of loadtest pods running with the given seccomp profile,
+// nil if the profile is nil. Containers of the pod inherit the profile unless they set their own.
+func PodSecurityContext(seccompProfile *coreV1.SeccompProfile) *coreV1.PodSecurityContext {
+	if seccompProfile == nil {
+		return nil
+	}
+	return &coreV1.PodSecurityContext{SeccompProfile: seccompProfile.DeepCopy()}
+}
@@ -0,0 +1,55 @@
+package backends
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	coreV1 "k8s.io/api/core/v1"
+)
+
+func TestNewSeccompProfile(t *testing.T) {
+	localhostProfile := "profiles/loadtest.json"
+
+	for _, tt := range []struct {
+		name             string
+		profileType      string
+		localhostProfile string
+		expected         *coreV1.SeccompProfile
+		expectError      bool
+	}{
+		{name: "disabled"},
+		{
+			name:        "runtime default",
+			profileType: "RuntimeDefault",
+			expected:    &coreV1.SeccompProfile{Type: coreV1.SeccompProfileTypeRuntimeDefault},
+		},
+		{
+			name:             "localhost",
+			profileType:      "Localhost",
+			localhostProfile: localhostProfile,
+			expected:         &coreV1.SeccompProfile{Type: coreV1.SeccompProfileTypeLocalhost, LocalhostProfile: &localhostProfile},
+		},
+		{name: "localhost without profile", profileType: "Localhost", expectError: true},
+		{name: "profile without localhost", profileType: "RuntimeDefault", localhostProfile: localhostProfile, expectError: true},
+		{name: "profile without type", localhostProfile: localhostProfile, expectError: true},
+		{name: "unknown type", profileType: "Strict", expectError: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			profile, err := NewSeccompProfile(tt.profileType, tt.localhostProfile)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, profile)
+		})
+	}
+}
+
+func TestPodSecurityContext(t *testing.T) {
+	assert.Nil(t, PodSecurityContext(nil))
+
+	profile := &coreV1.SeccompProfile{Type: coreV1.SeccompProfileTypeRuntimeDefault}
+	assert.Equal(t, &coreV1.PodSecurityContext{SeccompProfile: profile}, PodSecurityContext(profile))
+}
@@ -0,0 +1,10 @@
+package backends
+
+// PodServiceAccount returns the name of the service account of loadtest pods, the service account of the
+// loadtest replaces the configured one when it sets any. An empty name keeps the namespace default
+func PodServiceAccount(configured, loadTest string) string {
+	if loadTest != "" {
+		return loadTest
+	}
+	return configured
+}
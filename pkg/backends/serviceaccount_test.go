@@ -0,0 +1,25 @@
+package backends
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPodServiceAccount(t *testing.T) {
+	for _, tt := range []struct {
+		name       string
+		configured string
+		loadTest   string
+		expected   string
+	}{
+		{name: "none"},
+		{name: "configured", configured: "kangal-load", expected: "kangal-load"},
+		{name: "loadtest override", configured: "kangal-load", loadTest: "team-load", expected: "team-load"},
+		{name: "loadtest only", loadTest: "team-load", expected: "team-load"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, PodServiceAccount(tt.configured, tt.loadTest))
+		})
+	}
+}
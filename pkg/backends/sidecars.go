@@ -0,0 +1,82 @@
+package backends
+
+import (
+	"errors"
+	"fmt"
+
+	coreV1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/version"
+	"k8s.io/client-go/discovery"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+// ErrRequireSidecarImage the Image field of every sidecar of the loadtest is required
+var ErrRequireSidecarImage = errors.New("LoadTest sidecars require an image")
+
+// nativeSidecarsVersion is the first Kubernetes version running native sidecars by default, behind the
+// SidecarContainers feature gate
+var nativeSidecarsVersion = version.MajorMinor(1, 29)
+
+// NativeSidecarsSupported tells if the cluster runs native sidecars, init containers with restartPolicy Always,
+// from the version of its API server
+func NativeSidecarsSupported(client discovery.ServerVersionInterface) (bool, error) {
+	info, err := client.ServerVersion()
+	if err != nil {
+		return false, err
+	}
+
+	serverVersion, err := version.ParseGeneric(info.GitVersion)
+	if err != nil {
+		return false, err
+	}
+	return serverVersion.AtLeast(nativeSidecarsVersion), nil
+}
+
+// ValidateSidecars checks the sidecars of the loadtest can be run. Their names must be valid and unique, and differ
+// from the reserved names of the other containers of the pod, including the init containers of the loadtest
+func ValidateSidecars(sidecars []loadTestV1.Sidecar, reserved ...string) error {
+	seen := containerNameSet(reserved)
+	for i, s := range sidecars {
+		if s.Image == "" {
+			return fmt.Errorf("%w: sidecar %d", ErrRequireSidecarImage, i)
+		}
+		if err := checkContainerName(sidecarName(i, s.Name), seen); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Sidecars returns the sidecars of the loadtest with the given volume mounts so they share the volumes of the load
+// generator. Native sidecars are returned as init containers with restartPolicy Always when the cluster supports
+// them, Kubernetes stops them once the load generator exited. The other sidecars are returned as regular containers,
+// the job is only complete once they exited on their own.
+func Sidecars(sidecars []loadTestV1.Sidecar, mounts []coreV1.VolumeMount, nativeSupported bool) (initContainers, containers []coreV1.Container) {
+	restartAlways := coreV1.ContainerRestartPolicyAlways
+
+	for i, s := range sidecars {
+		container := coreV1.Container{
+			Name:         sidecarName(i, s.Name),
+			Image:        s.Image,
+			Command:      s.Command,
+			Env:          containerEnv(s.Env),
+			VolumeMounts: mounts,
+		}
+		if s.Native && nativeSupported {
+			container.RestartPolicy = &restartAlways
+			initContainers = append(initContainers, container)
+			continue
+		}
+		containers = append(containers, container)
+	}
+	return initContainers, containers
+}
+
+// sidecarName returns the name of the sidecar at the given position, sidecar-<position> if it has none
+func sidecarName(i int, name string) string {
+	if name == "" {
+		return fmt.Sprintf("sidecar-%d", i)
+	}
+	return name
+}
@@ -0,0 +1,82 @@
+package backends
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	coreV1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/version"
+	fakeDiscovery "k8s.io/client-go/discovery/fake"
+	kubeFake "k8s.io/client-go/kubernetes/fake"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+func TestNativeSidecarsSupported(t *testing.T) {
+	for gitVersion, expected := range map[string]bool{
+		"v1.28.5":         false,
+		"v1.29.0":         true,
+		"v1.30.2-eks-1a2": true,
+	} {
+		discovery := kubeFake.NewSimpleClientset().Discovery().(*fakeDiscovery.FakeDiscovery)
+		discovery.FakedServerVersion = &version.Info{GitVersion: gitVersion}
+
+		supported, err := NativeSidecarsSupported(discovery)
+		require.NoError(t, err, gitVersion)
+		assert.Equal(t, expected, supported, gitVersion)
+	}
+}
+
+func TestValidateSidecars(t *testing.T) {
+	assert.NoError(t, ValidateSidecars(nil))
+	assert.NoError(t, ValidateSidecars([]loadTestV1.Sidecar{{Image: "prom/prometheus"}}))
+	assert.ErrorIs(t, ValidateSidecars([]loadTestV1.Sidecar{{Image: "prom/prometheus"}, {Name: "scraper"}}), ErrRequireSidecarImage)
+
+	// names must be unique DNS-1123 labels, including the default ones
+	assert.ErrorIs(t, ValidateSidecars([]loadTestV1.Sidecar{{Name: "scraper.v1", Image: "prom/prometheus"}}), ErrInvalidContainerName)
+	assert.ErrorIs(t, ValidateSidecars([]loadTestV1.Sidecar{{Image: "prom/prometheus"}, {Name: "sidecar-0", Image: "prom/prometheus"}}), ErrInvalidContainerName)
+	assert.ErrorIs(t, ValidateSidecars([]loadTestV1.Sidecar{{Name: "seed", Image: "prom/prometheus"}}, "ghz", "seed"), ErrInvalidContainerName)
+}
+
+func TestSidecars(t *testing.T) {
+	initContainers, containers := Sidecars(nil, nil, true)
+	assert.Nil(t, initContainers)
+	assert.Nil(t, containers)
+
+	mounts := []coreV1.VolumeMount{{Name: "testfile", MountPath: "/data"}}
+	sidecars := []loadTestV1.Sidecar{
+		{
+			Name:    "prometheus",
+			Image:   "prom/prometheus",
+			Command: []string{"prometheus", "--config.file=/data/prometheus.yml"},
+			Env:     map[string]string{"B": "2", "A": "1"},
+			Native:  true,
+		},
+		{
+			Image: "busybox",
+		},
+	}
+	restartAlways := coreV1.ContainerRestartPolicyAlways
+	prometheus := coreV1.Container{
+		Name:         "prometheus",
+		Image:        "prom/prometheus",
+		Command:      []string{"prometheus", "--config.file=/data/prometheus.yml"},
+		Env:          []coreV1.EnvVar{{Name: "A", Value: "1"}, {Name: "B", Value: "2"}},
+		VolumeMounts: mounts,
+	}
+	busybox := coreV1.Container{
+		Name:         "sidecar-1",
+		Image:        "busybox",
+		VolumeMounts: mounts,
+	}
+
+	initContainers, containers = Sidecars(sidecars, mounts, false)
+	assert.Nil(t, initContainers)
+	assert.Equal(t, []coreV1.Container{prometheus, busybox}, containers)
+
+	initContainers, containers = Sidecars(sidecars, mounts, true)
+	prometheus.RestartPolicy = &restartAlways
+	assert.Equal(t, []coreV1.Container{prometheus}, initContainers)
+	assert.Equal(t, []coreV1.Container{busybox}, containers)
+}
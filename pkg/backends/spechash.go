@@ -0,0 +1,27 @@
+package backends
+
+import (
+	"maps"
+
+	batchV1 "k8s.io/api/batch/v1"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+// SpecHashAnnotation is the annotation holding the hash of a loadtest spec. The controller sets it on the loadtest
+// handed to Sync when it checks spec drift, and backends copy it to the Jobs they create
+const SpecHashAnnotation = "kangal.hellofresh.com/spec-hash"
+
+// SetSpecHash annotates the Job with the spec hash of the loadtest it is created from, if the loadtest has one.
+// The annotations of the Job are copied first, backends may share them with the pod template.
+func SetSpecHash(job *batchV1.Job, loadTest loadTestV1.LoadTest) {
+	hash, ok := loadTest.GetAnnotations()[SpecHashAnnotation]
+	if !ok {
+		return
+	}
+
+	annotations := make(map[string]string, len(job.Annotations)+1)
+	maps.Copy(annotations, job.Annotations)
+	annotations[SpecHashAnnotation] = hash
+	job.Annotations = annotations
+}
@@ -0,0 +1,32 @@
+package backends
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	batchV1 "k8s.io/api/batch/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+func TestSetSpecHash(t *testing.T) {
+	podAnnotations := map[string]string{"team": "payments"}
+	newJob := func() *batchV1.Job {
+		job := &batchV1.Job{ObjectMeta: metaV1.ObjectMeta{Annotations: podAnnotations}}
+		job.Spec.Template.Annotations = podAnnotations
+		return job
+	}
+
+	job := newJob()
+	SetSpecHash(job, loadTestV1.LoadTest{})
+	assert.Equal(t, map[string]string{"team": "payments"}, job.Annotations)
+
+	job = newJob()
+	SetSpecHash(job, loadTestV1.LoadTest{
+		ObjectMeta: metaV1.ObjectMeta{Annotations: map[string]string{SpecHashAnnotation: "hash"}},
+	})
+	assert.Equal(t, map[string]string{"team": "payments", SpecHashAnnotation: "hash"}, job.Annotations)
+	// the annotations shared with the pod template are left alone
+	assert.Equal(t, map[string]string{"team": "payments"}, job.Spec.Template.Annotations)
+}
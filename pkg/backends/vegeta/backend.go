@@ -0,0 +1,288 @@
+package vegeta
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	coreV1 "k8s.io/api/core/v1"
+	k8sAPIErrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/hellofresh/kangal/pkg/backends"
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+var (
+	// ErrRequireMinOneDistributedPod Backend spec requires 1 or more DistributedPods
+	ErrRequireMinOneDistributedPod = errors.New("LoadTest must specify 1 or more DistributedPods")
+	// ErrRequireTestFile the TestFile field is required to not be an empty string, unless TestFileURL is set
+	ErrRequireTestFile = errors.New("LoadTest TestFile or TestFileURL is required")
+	// ErrRequireDuration the Duration field is required, Vegeta would attack forever otherwise
+	ErrRequireDuration = errors.New("LoadTest must specify a Duration")
+	// ErrInvalidRate the Rate field must be a Vegeta rate, e.g. 50/1s
+	ErrInvalidRate = errors.New("LoadTest Rate must have the form N or N/duration, e.g. 50/1s")
+)
+
+func init() {
+	backends.Register(&Backend{})
+}
+
+// Backend is the Vegeta implementation of backend interface
+type Backend struct {
+	logger           *zap.Logger
+	kubeClientSet    kubernetes.Interface
+	config           *Config
+	podAnnotations   map[string]string
+	nodeSelector     map[string]string
+	tolerations      []coreV1.Toleration
+	seccompProfile   *coreV1.SeccompProfile
+	imagePullSecrets []string
+
+	testFileFetcher backends.TestFileFetcher
+	jobConditions   bool
+	jobBackoffLimit *int32
+	jobTimeout      time.Duration
+
+	// defined on SetDefaults
+	image        loadTestV1.ImageDetails
+	resources    backends.Resources
+	startupProbe *coreV1.Probe
+
+	backendPodAnnotations map[string]string
+}
+
+// Type returns backend type name
+func (*Backend) Type() loadTestV1.LoadTestType {
+	return loadTestV1.LoadTestTypeVegeta
+}
+
+// Capabilities returns the load test fields supported by the Vegeta backend
+func (*Backend) Capabilities() backends.Capabilities {
+	return backends.Capabilities{
+		Distributed:     true,
+		TestFileFormats: []string{"txt"},
+		RequiredFields:  []string{"distributedPods", "testFile", "duration"},
+		OptionalFields:  []string{"testFileURL", "rate", "masterImage"},
+	}
+}
+
+// GetEnvConfig must return config struct pointer
+func (b *Backend) GetEnvConfig() interface{} {
+	b.config = &Config{}
+	return b.config
+}
+
+// SetDefaults must set default values
+func (b *Backend) SetDefaults() {
+	b.image = loadTestV1.ImageDetails(fmt.Sprintf("%s:%s", b.config.ImageName, b.config.ImageTag))
+
+	b.resources = backends.Resources{
+		CPULimits:      b.config.CPULimits,
+		CPURequests:    b.config.CPURequests,
+		MemoryLimits:   b.config.MemoryLimits,
+		MemoryRequests: b.config.MemoryRequests,
+	}
+
+	b.startupProbe = backends.BuildStartupProbe(b.config.StartupProbe)
+	b.backendPodAnnotations = b.config.PodAnnotations
+}
+
+// SetPodAnnotations receives a copy of pod annotations
+func (b *Backend) SetPodAnnotations(podAnnotations map[string]string) {
+	b.podAnnotations = podAnnotations
+}
+
+// SetKubeClientSet receives a copy of kubeClientSet
+func (b *Backend) SetKubeClientSet(kubeClientSet kubernetes.Interface) {
+	b.kubeClientSet = kubeClientSet
+}
+
+// SetLogger receives a copy of logger
+func (b *Backend) SetLogger(logger *zap.Logger) {
+	b.logger = logger
+}
+
+// SetPodNodeSelector receives a copy of pod node selectors
+func (b *Backend) SetPodNodeSelector(nodeselector map[string]string) {
+	b.nodeSelector = nodeselector
+}
+
+// SetJobConditions receives whether the loadtest phase is determined from job conditions first
+func (b *Backend) SetJobConditions(enabled bool) {
+	b.jobConditions = enabled
+}
+
+// SetJobBackoffLimit receives the number of retries of failed job pods
+func (b *Backend) SetJobBackoffLimit(limit int32) {
+	b.jobBackoffLimit = &limit
+}
+
+// SetJobTimeout receives the default timeout of loadtest jobs
+func (b *Backend) SetJobTimeout(timeout time.Duration) {
+	b.jobTimeout = timeout
+}
+
+// SetTestFileFetcher receives the configuration of the init container fetching remote test files
+func (b *Backend) SetTestFileFetcher(fetcher backends.TestFileFetcher) {
+	b.testFileFetcher = fetcher
+}
+
+// SetPodTolerations receives a copy of pod tolerations
+func (b *Backend) SetPodTolerations(tolerations []coreV1.Toleration) {
+	b.tolerations = tolerations
+}
+
+// SetImagePullSecrets receives the image pull secrets of the loadtest pods
+func (b *Backend) SetImagePullSecrets(secrets []string) {
+	b.imagePullSecrets = secrets
+}
+
+// SetSeccompProfile receives the seccomp profile of the loadtest pods
+func (b *Backend) SetSeccompProfile(profile *coreV1.SeccompProfile) {
+	b.seccompProfile = profile
+}
+
+// TransformLoadTestSpec use given spec to validate and return a new one or error
+func (b *Backend) TransformLoadTestSpec(spec *loadTestV1.LoadTestSpec) error {
+	if nil == spec.DistributedPods {
+		return ErrRequireMinOneDistributedPod
+	}
+
+	if *spec.DistributedPods <= int32(0) {
+		return ErrRequireMinOneDistributedPod
+	}
+
+	if len(spec.TestFile) == 0 && spec.TestFileURL == "" {
+		return ErrRequireTestFile
+	}
+
+	if spec.Duration <= 0 {
+		return ErrRequireDuration
+	}
+
+	if err := validateRate(spec.Rate); err != nil {
+		return err
+	}
+
+	if spec.MasterConfig == "" {
+		spec.MasterConfig = b.image
+	}
+
+	return nil
+}
+
+// validateRate checks the rate has the form accepted by vegeta attack -rate, empty uses the Vegeta default
+func validateRate(rate string) error {
+	if rate == "" {
+		return nil
+	}
+
+	freq, per, found := strings.Cut(rate, "/")
+	if _, err := strconv.ParseUint(freq, 10, 64); err != nil {
+		return ErrInvalidRate
+	}
+
+	if !found {
+		return nil
+	}
+
+	// vegeta accepts a unit without a number, e.g. 50/s
+	if per != "" && (per[0] < '0' || per[0] > '9') {
+		per = "1" + per
+	}
+
+	if d, err := time.ParseDuration(per); err != nil || d <= 0 {
+		return ErrInvalidRate
+	}
+
+	return nil
+}
+
+// EstimateResources returns the resources requested by the Vegeta pods
+func (b *Backend) EstimateResources(spec loadTestV1.LoadTestSpec) backends.ResourceEstimate {
+	var pods int32
+	if spec.DistributedPods != nil {
+		pods = *spec.DistributedPods
+	}
+	return backends.EstimatePods(pods, b.resources)
+}
+
+// Sync checks if Vegeta kubernetes resources have been created, create them if they haven't
+func (b *Backend) Sync(ctx context.Context, loadTest loadTestV1.LoadTest, reportURL string) error {
+	jobs, err := b.kubeClientSet.
+		BatchV1().
+		Jobs(loadTest.Status.Namespace).
+		List(ctx, metaV1.ListOptions{
+			FieldSelector: fmt.Sprintf("metadata.name=%s", loadTestJobName),
+		})
+	if err != nil {
+		b.logger.Error("Error on listing jobs", zap.Error(err))
+		return err
+	}
+
+	// Jobs already created, do nothing
+	if len(jobs.Items) > 0 {
+		return nil
+	}
+
+	// Create targets ConfigMap, remote test files are fetched by an init container instead
+	if loadTest.Spec.TestFileURL == "" {
+		cfgMap, err := NewTestFileConfigMap(loadTest, loadTest.Spec.TestFile)
+		if err != nil {
+			b.logger.Error("Error creating testfile configmap resource", zap.Error(err))
+			return err
+		}
+
+		_, err = b.kubeClientSet.
+			CoreV1().
+			ConfigMaps(loadTest.Status.Namespace).
+			Create(ctx, cfgMap, metaV1.CreateOptions{})
+		if err != nil && !k8sAPIErrors.IsAlreadyExists(err) {
+			b.logger.Error("Error creating configmap", zap.String("configmap", cfgMap.GetName()), zap.Error(err))
+			return err
+		}
+	}
+
+	// Create Job
+	job := b.NewJob(loadTest, reportURL)
+	_, err = b.kubeClientSet.
+		BatchV1().
+		Jobs(loadTest.Status.Namespace).
+		Create(ctx, job, metaV1.CreateOptions{})
+	if err != nil && !k8sAPIErrors.IsAlreadyExists(err) {
+		b.logger.Error("Error on creating job", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// SyncStatus checks Vegeta resources and updates the status of the LoadTest resource
+func (b *Backend) SyncStatus(ctx context.Context, _ loadTestV1.LoadTest, loadTestStatus *loadTestV1.LoadTestStatus) error {
+	if loadTestStatus.Phase == "" {
+		loadTestStatus.Phase = loadTestV1.LoadTestCreating
+		return nil
+	}
+
+	if loadTestStatus.Phase == loadTestV1.LoadTestErrored {
+		return nil
+	}
+
+	job, err := b.kubeClientSet.
+		BatchV1().
+		Jobs(loadTestStatus.Namespace).
+		Get(ctx, loadTestJobName, metaV1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	loadTestStatus.Phase = backends.PreferJobConditions(b.jobConditions, determineLoadTestStatusFromJobs(job), job.Status)
+	loadTestStatus.JobStatus = job.Status
+	return nil
+}
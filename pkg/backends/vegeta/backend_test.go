@@ -0,0 +1,201 @@
+package vegeta
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+	batchV1 "k8s.io/api/batch/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestSync(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	kubeClient := k8sfake.NewSimpleClientset()
+	logger := zaptest.NewLogger(t)
+
+	namespace := "test"
+	distributedPods := int32(1)
+	reportURL := "http://kangal-proxy.local/load-test/loadtest-name/report"
+
+	loadTest := loadTestV1.LoadTest{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name: "loadtest-name",
+		},
+		Spec: loadTestV1.LoadTestSpec{
+			DistributedPods: &distributedPods,
+			TestFile:        []byte("GET http://example.com"),
+			Duration:        time.Minute,
+		},
+		Status: loadTestV1.LoadTestStatus{
+			Phase:     "running",
+			Namespace: namespace,
+		},
+	}
+
+	b := Backend{
+		logger:        logger,
+		kubeClientSet: kubeClient,
+	}
+
+	err := b.Sync(ctx, loadTest, reportURL)
+	require.NoError(t, err, "Error when Sync")
+
+	jobs, err := kubeClient.BatchV1().Jobs(namespace).List(ctx, metaV1.ListOptions{})
+	require.NoError(t, err, "Error when listing jobs")
+	assert.Len(t, jobs.Items, 1)
+
+	configMaps, err := kubeClient.CoreV1().ConfigMaps(namespace).List(ctx, metaV1.ListOptions{})
+	require.NoError(t, err, "Error when listing configmaps")
+	require.Len(t, configMaps.Items, 1)
+	assert.Equal(t, []byte("GET http://example.com"), configMaps.Items[0].BinaryData[targetsFileName])
+}
+
+func TestSyncStatus(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	kubeClient := k8sfake.NewSimpleClientset()
+	logger := zaptest.NewLogger(t)
+
+	namespace := "test"
+	distributedPods := int32(1)
+
+	loadTest := loadTestV1.LoadTest{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name: "loadtest-name",
+		},
+		Spec: loadTestV1.LoadTestSpec{
+			DistributedPods: &distributedPods,
+			TestFile:        []byte("GET http://example.com"),
+			Duration:        time.Minute,
+		},
+		Status: loadTestV1.LoadTestStatus{
+			Namespace: namespace,
+		},
+	}
+
+	b := Backend{
+		logger:        logger,
+		kubeClientSet: kubeClient,
+	}
+
+	err := b.Sync(ctx, loadTest, "")
+	require.NoError(t, err, "Sync error")
+
+	// First sync should update status to creating
+	err = b.SyncStatus(ctx, loadTest, &loadTest.Status)
+	require.NoError(t, err, "SyncStatus error")
+	assert.Equal(t, loadTestV1.LoadTestCreating, loadTest.Status.Phase)
+
+	job, err := kubeClient.BatchV1().Jobs(namespace).Get(ctx, loadTestJobName, metaV1.GetOptions{})
+	require.NoError(t, err)
+	job.Status = batchV1.JobStatus{Active: 1}
+	_, err = kubeClient.BatchV1().Jobs(namespace).UpdateStatus(ctx, job, metaV1.UpdateOptions{})
+	require.NoError(t, err)
+
+	err = b.SyncStatus(ctx, loadTest, &loadTest.Status)
+	require.NoError(t, err, "SyncStatus error")
+	assert.Equal(t, loadTestV1.LoadTestRunning, loadTest.Status.Phase)
+}
+
+func TestTransformLoadTestSpec(t *testing.T) {
+	distributedPods := int32(2)
+	noPods := int32(0)
+
+	b := Backend{image: "peterevans/vegeta:12.8.4"}
+
+	for _, tt := range []struct {
+		name          string
+		spec          loadTestV1.LoadTestSpec
+		expectedError error
+	}{
+		{
+			name: "valid spec",
+			spec: loadTestV1.LoadTestSpec{
+				DistributedPods: &distributedPods,
+				TestFile:        []byte("GET http://example.com"),
+				Duration:        time.Minute,
+				Rate:            "100/1s",
+			},
+		},
+		{
+			name: "rate without period",
+			spec: loadTestV1.LoadTestSpec{
+				DistributedPods: &distributedPods,
+				TestFileURL:     "https://example.com/targets",
+				Duration:        time.Minute,
+				Rate:            "100",
+			},
+		},
+		{
+			name: "rate with unit only",
+			spec: loadTestV1.LoadTestSpec{
+				DistributedPods: &distributedPods,
+				TestFile:        []byte("GET http://example.com"),
+				Duration:        time.Minute,
+				Rate:            "100/m",
+			},
+		},
+		{
+			name: "no distributed pods",
+			spec: loadTestV1.LoadTestSpec{
+				DistributedPods: &noPods,
+				TestFile:        []byte("GET http://example.com"),
+				Duration:        time.Minute,
+			},
+			expectedError: ErrRequireMinOneDistributedPod,
+		},
+		{
+			name: "no targets",
+			spec: loadTestV1.LoadTestSpec{
+				DistributedPods: &distributedPods,
+				Duration:        time.Minute,
+			},
+			expectedError: ErrRequireTestFile,
+		},
+		{
+			name: "no duration",
+			spec: loadTestV1.LoadTestSpec{
+				DistributedPods: &distributedPods,
+				TestFile:        []byte("GET http://example.com"),
+			},
+			expectedError: ErrRequireDuration,
+		},
+		{
+			name: "invalid rate",
+			spec: loadTestV1.LoadTestSpec{
+				DistributedPods: &distributedPods,
+				TestFile:        []byte("GET http://example.com"),
+				Duration:        time.Minute,
+				Rate:            "100/1s; rm -rf /",
+			},
+			expectedError: ErrInvalidRate,
+		},
+		{
+			name: "negative rate",
+			spec: loadTestV1.LoadTestSpec{
+				DistributedPods: &distributedPods,
+				TestFile:        []byte("GET http://example.com"),
+				Duration:        time.Minute,
+				Rate:            "-1/1s",
+			},
+			expectedError: ErrInvalidRate,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			err := b.TransformLoadTestSpec(&tt.spec)
+			assert.Equal(t, tt.expectedError, err)
+			if tt.expectedError == nil {
+				assert.Equal(t, loadTestV1.ImageDetails("peterevans/vegeta:12.8.4"), tt.spec.MasterConfig)
+			}
+		})
+	}
+}
@@ -0,0 +1,18 @@
+package vegeta
+
+import "github.com/hellofresh/kangal/pkg/backends"
+
+// Config specific to Vegeta backend
+type Config struct {
+	ImageName      string `envconfig:"VEGETA_IMAGE_NAME" default:"peterevans/vegeta"`
+	ImageTag       string `envconfig:"VEGETA_IMAGE_TAG" default:"12.8.4"`
+	CPULimits      string `envconfig:"VEGETA_CPU_LIMITS"`
+	CPURequests    string `envconfig:"VEGETA_CPU_REQUESTS"`
+	MemoryLimits   string `envconfig:"VEGETA_MEMORY_LIMITS"`
+	MemoryRequests string `envconfig:"VEGETA_MEMORY_REQUESTS"`
+
+	StartupProbe backends.StartupProbe `envconfig:"VEGETA_STARTUP_PROBE"`
+
+	// PodAnnotations are the default annotations of the loadtest pods of the backend
+	PodAnnotations map[string]string `envconfig:"VEGETA_POD_ANNOTATIONS"`
+}
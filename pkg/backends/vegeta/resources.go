@@ -0,0 +1,180 @@
+package vegeta
+
+import (
+	"errors"
+	"fmt"
+
+	"go.uber.org/zap"
+	batchV1 "k8s.io/api/batch/v1"
+	coreV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/hellofresh/kangal/pkg/backends"
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+const (
+	loadTestJobName           = "loadtest-job"
+	loadTestFileConfigMapName = "loadtest-testfile"
+	loadTestFileVolumeName    = "loadtest-testfile-volume"
+
+	targetsFileName = "targets"
+
+	// defaultRate is the rate of vegeta attack when the loadtest doesn't specify one
+	defaultRate = "50/1s"
+)
+
+// attackScript runs vegeta attack, prints its report and uploads it as text. Rate and duration are passed as env vars
+// so user input never ends up in the shell script. The results are written to a file instead of piped into vegeta
+// report, so a failing attack fails the script without pipefail, which /bin/sh doesn't have everywhere
+var attackScript = fmt.Sprintf(`set -e
+vegeta attack -targets=/data/%s -rate="$VEGETA_RATE" -duration="$VEGETA_DURATION" -output=/tmp/results.bin
+vegeta report -output=/tmp/report.txt /tmp/results.bin
+cat /tmp/report.txt
+if [ -n "$REPORT_PRESIGNED_URL" ]; then
+  curl -sf -X PUT -H "Content-Type: text/plain; charset=utf-8" -T /tmp/report.txt -L "$REPORT_PRESIGNED_URL"
+fi`, targetsFileName)
+
+// NewJob creates a new job that runs vegeta attack
+func (b *Backend) NewJob(loadTest loadTestV1.LoadTest, reportURL string) *batchV1.Job {
+	logger := b.logger.With(
+		zap.String("loadtest", loadTest.GetName()),
+		zap.String("namespace", loadTest.Status.Namespace),
+	)
+
+	ownerRef := metaV1.NewControllerRef(&loadTest, loadTestV1.SchemeGroupVersion.WithKind("LoadTest"))
+
+	imageRef := loadTest.Spec.MasterConfig
+	if imageRef == ":" || imageRef == "" {
+		imageRef = b.image
+		logger.Warn("Loadtest.Spec.MasterConfig is empty; using default image", zap.String("imageRef", string(imageRef)))
+	}
+
+	rate := loadTest.Spec.Rate
+	if rate == "" {
+		rate = defaultRate
+	}
+
+	envVars := []coreV1.EnvVar{
+		{Name: "VEGETA_RATE", Value: rate},
+		{Name: "VEGETA_DURATION", Value: loadTest.Spec.Duration.String()},
+	}
+	if reportURL != "" {
+		envVars = append(envVars, coreV1.EnvVar{
+			Name:  "REPORT_PRESIGNED_URL",
+			Value: reportURL,
+		})
+	}
+
+	volume, mount := NewTestFileVolumeAndMount()
+
+	job := &batchV1.Job{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:      loadTestJobName,
+			Namespace: loadTest.Status.Namespace,
+			Labels: map[string]string{
+				"name": loadTestJobName,
+			},
+			OwnerReferences: []metaV1.OwnerReference{*ownerRef},
+		},
+		Spec: batchV1.JobSpec{
+			Parallelism:           loadTest.Spec.DistributedPods,
+			Completions:           loadTest.Spec.DistributedPods,
+			BackoffLimit:          backends.JobBackoffLimit(b.jobBackoffLimit, 0),
+			ActiveDeadlineSeconds: backends.JobActiveDeadlineSeconds(b.jobTimeout, loadTest.Spec.Timeout),
+			Template: coreV1.PodTemplateSpec{
+				ObjectMeta: metaV1.ObjectMeta{
+					Labels: map[string]string{
+						"name": loadTestJobName,
+					},
+					Annotations: backends.MergePodAnnotations(b.podAnnotations, b.backendPodAnnotations, loadTest.Spec.PodAnnotations),
+				},
+				Spec: coreV1.PodSpec{
+					NodeSelector:     b.nodeSelector,
+					RestartPolicy:    "Never",
+					Volumes:          []coreV1.Volume{volume},
+					Tolerations:      b.tolerations,
+					SecurityContext:  backends.PodSecurityContext(b.seccompProfile),
+					ImagePullSecrets: backends.ImagePullSecrets(b.imagePullSecrets, loadTest.Spec.ImagePullSecrets),
+					Containers: []coreV1.Container{
+						{
+							Name:         "vegeta",
+							Image:        string(imageRef),
+							Env:          envVars,
+							Resources:    backends.BuildResourceRequirements(b.resources),
+							StartupProbe: b.startupProbe,
+							Command:      []string{"/bin/sh", "-c", attackScript},
+							VolumeMounts: []coreV1.VolumeMount{mount},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	if loadTest.Spec.TestFileURL != "" {
+		b.testFileFetcher.Inject(&job.Spec.Template.Spec, loadTest.Spec.TestFileURL, loadTestFileVolumeName, targetsFileName)
+	}
+
+	backends.SetSpecHash(job, loadTest)
+	return job
+}
+
+// NewTestFileVolumeAndMount creates the volume and volume mount of the targets file
+func NewTestFileVolumeAndMount() (coreV1.Volume, coreV1.VolumeMount) {
+	v := coreV1.Volume{
+		Name: loadTestFileVolumeName,
+		VolumeSource: coreV1.VolumeSource{
+			ConfigMap: &coreV1.ConfigMapVolumeSource{
+				LocalObjectReference: coreV1.LocalObjectReference{
+					Name: loadTestFileConfigMapName,
+				},
+			},
+		},
+	}
+
+	m := coreV1.VolumeMount{
+		Name:      loadTestFileVolumeName,
+		MountPath: fmt.Sprintf("/data/%s", targetsFileName),
+		SubPath:   targetsFileName,
+	}
+
+	return v, m
+}
+
+// NewTestFileConfigMap creates a configmap storing the targets file, owned by the loadtest
+func NewTestFileConfigMap(loadTest loadTestV1.LoadTest, content []byte) (*coreV1.ConfigMap, error) {
+	if len(content) == 0 {
+		return nil, errors.New("invalid targets file, empty content")
+	}
+
+	return &coreV1.ConfigMap{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name: loadTestFileConfigMapName,
+			OwnerReferences: []metaV1.OwnerReference{
+				*metaV1.NewControllerRef(&loadTest, loadTestV1.SchemeGroupVersion.WithKind("LoadTest")),
+			},
+		},
+		BinaryData: map[string][]byte{
+			targetsFileName: content,
+		},
+	}, nil
+}
+
+// determineLoadTestStatusFromJobs reads existing job statuses and determines what the loadtest status should be
+func determineLoadTestStatusFromJobs(job *batchV1.Job) loadTestV1.LoadTestPhase {
+	if backends.JobFailed(*job) {
+		return loadTestV1.LoadTestErrored
+	}
+
+	if job.Status.Active > int32(0) {
+		return loadTestV1.LoadTestRunning
+	}
+
+	// failed pods within the backoff limit are being replaced
+	if job.Status.Succeeded == 0 {
+		return loadTestV1.LoadTestStarting
+	}
+
+	return loadTestV1.LoadTestFinished
+}
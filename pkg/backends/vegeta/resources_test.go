@@ -0,0 +1,115 @@
+package vegeta
+
+import (
+	"testing"
+	"time"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+	batchV1 "k8s.io/api/batch/v1"
+	coreV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNewJob(t *testing.T) {
+	distributedPods := int32(3)
+
+	b := Backend{
+		logger: zaptest.NewLogger(t),
+		image:  "peterevans/vegeta:12.8.4",
+	}
+
+	for _, tt := range []struct {
+		name          string
+		spec          loadTestV1.LoadTestSpec
+		expectedImage string
+		expectedRate  string
+	}{
+		{
+			name: "default image and rate",
+			spec: loadTestV1.LoadTestSpec{
+				DistributedPods: &distributedPods,
+				MasterConfig:    ":",
+				Duration:        time.Minute,
+			},
+			expectedImage: "peterevans/vegeta:12.8.4",
+			expectedRate:  defaultRate,
+		},
+		{
+			name: "custom image and rate",
+			spec: loadTestV1.LoadTestSpec{
+				DistributedPods: &distributedPods,
+				MasterConfig:    "my/vegeta:1.0",
+				Duration:        time.Minute,
+				Rate:            "10/1s",
+			},
+			expectedImage: "my/vegeta:1.0",
+			expectedRate:  "10/1s",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			loadTest := loadTestV1.LoadTest{
+				ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-name"},
+				Spec:       tt.spec,
+				Status:     loadTestV1.LoadTestStatus{Namespace: "test"},
+			}
+
+			job := b.NewJob(loadTest, "http://report")
+			assert.Equal(t, &distributedPods, job.Spec.Parallelism)
+			assert.Equal(t, &distributedPods, job.Spec.Completions)
+
+			require.Len(t, job.Spec.Template.Spec.Containers, 1)
+			container := job.Spec.Template.Spec.Containers[0]
+			assert.Equal(t, tt.expectedImage, container.Image)
+			assert.Equal(t, []string{"/bin/sh", "-c", attackScript}, container.Command)
+			assert.Equal(t, []coreV1.EnvVar{
+				{Name: "VEGETA_RATE", Value: tt.expectedRate},
+				{Name: "VEGETA_DURATION", Value: "1m0s"},
+				{Name: "REPORT_PRESIGNED_URL", Value: "http://report"},
+			}, container.Env)
+		})
+	}
+}
+
+func TestNewTestFileConfigMap(t *testing.T) {
+	loadTest := loadTestV1.LoadTest{ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-name", UID: "loadtest-uid"}}
+
+	_, err := NewTestFileConfigMap(loadTest, nil)
+	assert.Error(t, err)
+
+	cfgMap, err := NewTestFileConfigMap(loadTest, []byte("GET http://example.com"))
+	require.NoError(t, err)
+	assert.Equal(t, loadTestFileConfigMapName, cfgMap.Name)
+	require.Len(t, cfgMap.OwnerReferences, 1)
+	assert.Equal(t, "LoadTest", cfgMap.OwnerReferences[0].Kind)
+	assert.Equal(t, loadTest.UID, cfgMap.OwnerReferences[0].UID)
+	assert.Equal(t, map[string][]byte{targetsFileName: []byte("GET http://example.com")}, cfgMap.BinaryData)
+}
+
+func TestGetLoadTestStatusFromJobs(t *testing.T) {
+	var scenarios = []struct {
+		NumberActive    int32
+		NumberFailed    int32
+		NumberSucceeded int32
+		ExpectedPhase   loadTestV1.LoadTestPhase
+	}{
+		{0, 0, 0, loadTestV1.LoadTestStarting},
+		{1, 0, 0, loadTestV1.LoadTestRunning},
+		{0, 1, 0, loadTestV1.LoadTestErrored},
+		{1, 0, 1, loadTestV1.LoadTestRunning},
+		{0, 0, 1, loadTestV1.LoadTestFinished},
+	}
+
+	for _, scenario := range scenarios {
+		job := &batchV1.Job{
+			Status: batchV1.JobStatus{
+				Active:    scenario.NumberActive,
+				Failed:    scenario.NumberFailed,
+				Succeeded: scenario.NumberSucceeded,
+			},
+		}
+		assert.Equal(t, scenario.ExpectedPhase, determineLoadTestStatusFromJobs(job))
+	}
+}
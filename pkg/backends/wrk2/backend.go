@@ -0,0 +1,320 @@
+package wrk2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+	coreV1 "k8s.io/api/core/v1"
+	k8sAPIErrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/hellofresh/kangal/pkg/backends"
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+var (
+	// ErrRequireOneDistributedPod wrk2 runs on a single pod, so its latency distribution covers all requests
+	ErrRequireOneDistributedPod = errors.New("LoadTest must specify exactly 1 DistributedPods")
+	// ErrRequireTargetURL the TargetURL field is required, it is the URL wrk2 sends requests to
+	ErrRequireTargetURL = errors.New("LoadTest must specify a TargetURL")
+	// ErrRequireDuration the Duration field is required to be at least one second, wrk2 runs for whole seconds
+	ErrRequireDuration = errors.New("LoadTest must specify a Duration of at least 1s")
+	// ErrInvalidRate the Rate field must be the total number of requests per second, e.g. 2000
+	ErrInvalidRate = errors.New("LoadTest Rate must be a positive number of requests per second, e.g. 2000")
+	// ErrInvalidConnections the Connections and Threads fields must not be negative and each thread needs a connection
+	ErrInvalidConnections = errors.New("LoadTest Connections must be greater than or equal to Threads")
+)
+
+func init() {
+	backends.Register(&Backend{})
+}
+
+// Backend is the wrk2 implementation of backend interface
+type Backend struct {
+	logger           *zap.Logger
+	kubeClientSet    kubernetes.Interface
+	config           *Config
+	podAnnotations   map[string]string
+	nodeSelector     map[string]string
+	tolerations      []coreV1.Toleration
+	seccompProfile   *coreV1.SeccompProfile
+	imagePullSecrets []string
+
+	testFileFetcher backends.TestFileFetcher
+	jobConditions   bool
+	jobBackoffLimit *int32
+	jobTimeout      time.Duration
+
+	// defined on SetDefaults
+	image        loadTestV1.ImageDetails
+	resources    backends.Resources
+	startupProbe *coreV1.Probe
+
+	backendPodAnnotations map[string]string
+}
+
+// Type returns backend type name
+func (*Backend) Type() loadTestV1.LoadTestType {
+	return loadTestV1.LoadTestTypeWrk2
+}
+
+// Capabilities returns the load test fields supported by the wrk2 backend
+func (*Backend) Capabilities() backends.Capabilities {
+	return backends.Capabilities{
+		Distributed:     false,
+		TestFileFormats: []string{"lua"},
+		RequiredFields:  []string{"distributedPods", "targetURL", "duration", "rate"},
+		OptionalFields:  []string{"testFile", "testFileURL", "connections", "threads", "masterImage", "thresholds"},
+	}
+}
+
+// GetEnvConfig must return config struct pointer
+func (b *Backend) GetEnvConfig() interface{} {
+	b.config = &Config{}
+	return b.config
+}
+
+// SetDefaults must set default values
+func (b *Backend) SetDefaults() {
+	b.image = loadTestV1.ImageDetails(fmt.Sprintf("%s:%s", b.config.ImageName, b.config.ImageTag))
+
+	b.resources = backends.Resources{
+		CPULimits:      b.config.CPULimits,
+		CPURequests:    b.config.CPURequests,
+		MemoryLimits:   b.config.MemoryLimits,
+		MemoryRequests: b.config.MemoryRequests,
+	}
+
+	b.startupProbe = backends.BuildStartupProbe(b.config.StartupProbe)
+	b.backendPodAnnotations = b.config.PodAnnotations
+}
+
+// SetPodAnnotations receives a copy of pod annotations
+func (b *Backend) SetPodAnnotations(podAnnotations map[string]string) {
+	b.podAnnotations = podAnnotations
+}
+
+// SetKubeClientSet receives a copy of kubeClientSet
+func (b *Backend) SetKubeClientSet(kubeClientSet kubernetes.Interface) {
+	b.kubeClientSet = kubeClientSet
+}
+
+// SetLogger receives a copy of logger
+func (b *Backend) SetLogger(logger *zap.Logger) {
+	b.logger = logger
+}
+
+// SetPodNodeSelector receives a copy of pod node selectors
+func (b *Backend) SetPodNodeSelector(nodeselector map[string]string) {
+	b.nodeSelector = nodeselector
+}
+
+// SetJobConditions receives whether the loadtest phase is determined from job conditions first
+func (b *Backend) SetJobConditions(enabled bool) {
+	b.jobConditions = enabled
+}
+
+// SetJobBackoffLimit receives the number of retries of failed job pods
+func (b *Backend) SetJobBackoffLimit(limit int32) {
+	b.jobBackoffLimit = &limit
+}
+
+// SetJobTimeout receives the default timeout of loadtest jobs
+func (b *Backend) SetJobTimeout(timeout time.Duration) {
+	b.jobTimeout = timeout
+}
+
+// SetTestFileFetcher receives the configuration of the init container fetching remote test files
+func (b *Backend) SetTestFileFetcher(fetcher backends.TestFileFetcher) {
+	b.testFileFetcher = fetcher
+}
+
+// SetPodTolerations receives a copy of pod tolerations
+func (b *Backend) SetPodTolerations(tolerations []coreV1.Toleration) {
+	b.tolerations = tolerations
+}
+
+// SetImagePullSecrets receives the image pull secrets of the loadtest pods
+func (b *Backend) SetImagePullSecrets(secrets []string) {
+	b.imagePullSecrets = secrets
+}
+
+// SetSeccompProfile receives the seccomp profile of the loadtest pods
+func (b *Backend) SetSeccompProfile(profile *coreV1.SeccompProfile) {
+	b.seccompProfile = profile
+}
+
+// TransformLoadTestSpec use given spec to validate and return a new one or error
+func (b *Backend) TransformLoadTestSpec(spec *loadTestV1.LoadTestSpec) error {
+	if spec.DistributedPods == nil || *spec.DistributedPods != 1 {
+		return ErrRequireOneDistributedPod
+	}
+
+	if spec.TargetURL == "" {
+		return ErrRequireTargetURL
+	}
+
+	if spec.Duration < time.Second {
+		return ErrRequireDuration
+	}
+
+	if err := validateRate(spec.Rate); err != nil {
+		return err
+	}
+
+	if err := validateConnections(spec.Connections, spec.Threads); err != nil {
+		return err
+	}
+
+	if spec.MasterConfig == "" {
+		spec.MasterConfig = b.image
+	}
+
+	return nil
+}
+
+// validateRate checks the rate is the positive number of requests per second accepted by wrk2 -R
+func validateRate(rate string) error {
+	if r, err := strconv.ParseUint(rate, 10, 64); err != nil || r == 0 {
+		return ErrInvalidRate
+	}
+	return nil
+}
+
+// validateConnections checks wrk2 can open the connections with the threads, after the defaults are applied
+func validateConnections(connections, threads int32) error {
+	if connections < 0 || threads < 0 {
+		return ErrInvalidConnections
+	}
+	if valueOrDefault(connections, defaultConnections) < valueOrDefault(threads, defaultThreads) {
+		return ErrInvalidConnections
+	}
+	return nil
+}
+
+// EstimateResources returns the resources requested by the wrk2 pod
+func (b *Backend) EstimateResources(_ loadTestV1.LoadTestSpec) backends.ResourceEstimate {
+	return backends.EstimatePods(1, b.resources)
+}
+
+// Sync checks if wrk2 kubernetes resources have been created, create them if they haven't
+func (b *Backend) Sync(ctx context.Context, loadTest loadTestV1.LoadTest, reportURL string) error {
+	jobs, err := b.kubeClientSet.
+		BatchV1().
+		Jobs(loadTest.Status.Namespace).
+		List(ctx, metaV1.ListOptions{
+			FieldSelector: fmt.Sprintf("metadata.name=%s", loadTestJobName),
+		})
+	if err != nil {
+		b.logger.Error("Error on listing jobs", zap.Error(err))
+		return err
+	}
+
+	// Jobs already created, do nothing
+	if len(jobs.Items) > 0 {
+		return nil
+	}
+
+	// Create script ConfigMap, remote scripts are fetched by an init container instead
+	if len(loadTest.Spec.TestFile) != 0 && loadTest.Spec.TestFileURL == "" {
+		cfgMap := NewScriptConfigMap(loadTest, loadTest.Spec.TestFile)
+		_, err = b.kubeClientSet.
+			CoreV1().
+			ConfigMaps(loadTest.Status.Namespace).
+			Create(ctx, cfgMap, metaV1.CreateOptions{})
+		if err != nil && !k8sAPIErrors.IsAlreadyExists(err) {
+			b.logger.Error("Error creating configmap", zap.String("configmap", cfgMap.GetName()), zap.Error(err))
+			return err
+		}
+	}
+
+	// Create Job
+	job := b.NewJob(loadTest, reportURL)
+	_, err = b.kubeClientSet.
+		BatchV1().
+		Jobs(loadTest.Status.Namespace).
+		Create(ctx, job, metaV1.CreateOptions{})
+	if err != nil && !k8sAPIErrors.IsAlreadyExists(err) {
+		b.logger.Error("Error on creating job", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// SyncStatus checks wrk2 resources and updates the status of the LoadTest resource, the summary of finished
+// loadtests is parsed from the output of wrk2
+func (b *Backend) SyncStatus(ctx context.Context, _ loadTestV1.LoadTest, loadTestStatus *loadTestV1.LoadTestStatus) error {
+	if loadTestStatus.Phase == "" {
+		loadTestStatus.Phase = loadTestV1.LoadTestCreating
+		return nil
+	}
+
+	if loadTestStatus.Phase == loadTestV1.LoadTestErrored {
+		return nil
+	}
+
+	job, err := b.kubeClientSet.
+		BatchV1().
+		Jobs(loadTestStatus.Namespace).
+		Get(ctx, loadTestJobName, metaV1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	loadTestStatus.Phase = backends.PreferJobConditions(b.jobConditions, determineLoadTestStatusFromJobs(job), job.Status)
+	loadTestStatus.JobStatus = job.Status
+
+	if loadTestStatus.Phase == loadTestV1.LoadTestFinished && loadTestStatus.Summary == nil {
+		summary, err := b.readSummary(ctx, loadTestStatus.Namespace)
+		if err != nil {
+			// the loadtest is finished regardless, the summary is read again on the next sync
+			b.logger.Warn("Could not read wrk2 summary", zap.String("namespace", loadTestStatus.Namespace), zap.Error(err))
+			return nil
+		}
+		loadTestStatus.Summary = summary
+	}
+
+	return nil
+}
+
+// readSummary parses the summary printed by wrk2 in the logs of the succeeded pod of the job
+func (b *Backend) readSummary(ctx context.Context, namespace string) (map[string]string, error) {
+	pods, err := b.kubeClientSet.
+		CoreV1().
+		Pods(namespace).
+		List(ctx, metaV1.ListOptions{
+			LabelSelector: fmt.Sprintf("name=%s", loadTestJobName),
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, pod := range pods.Items {
+		if pod.Status.Phase != coreV1.PodSucceeded {
+			continue
+		}
+
+		logs, err := b.kubeClientSet.
+			CoreV1().
+			Pods(namespace).
+			GetLogs(pod.Name, &coreV1.PodLogOptions{Container: containerName, LimitBytes: &maxLogBytes}).
+			DoRaw(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		summary := parseSummary(string(logs))
+		if len(summary) == 0 {
+			return nil, errors.New("no wrk2 summary in the pod logs")
+		}
+		return summary, nil
+	}
+
+	return nil, errors.New("no succeeded wrk2 pod")
+}
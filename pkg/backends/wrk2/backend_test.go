@@ -0,0 +1,210 @@
+package wrk2
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+	batchV1 "k8s.io/api/batch/v1"
+	coreV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+func TestSync(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	kubeClient := k8sfake.NewSimpleClientset()
+	logger := zaptest.NewLogger(t)
+
+	namespace := "test"
+	distributedPods := int32(1)
+
+	loadTest := loadTestV1.LoadTest{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name: "loadtest-name",
+		},
+		Spec: loadTestV1.LoadTestSpec{
+			DistributedPods: &distributedPods,
+			TestFile:        []byte(`wrk.method = "POST"`),
+			TargetURL:       "http://my-app.example.com/",
+			Duration:        time.Minute,
+			Rate:            "2000",
+		},
+		Status: loadTestV1.LoadTestStatus{
+			Phase:     "running",
+			Namespace: namespace,
+		},
+	}
+
+	b := Backend{
+		logger:        logger,
+		kubeClientSet: kubeClient,
+	}
+
+	err := b.Sync(ctx, loadTest, "http://kangal-proxy.local/load-test/loadtest-name/report")
+	require.NoError(t, err, "Error when Sync")
+
+	jobs, err := kubeClient.BatchV1().Jobs(namespace).List(ctx, metaV1.ListOptions{})
+	require.NoError(t, err, "Error when listing jobs")
+	assert.Len(t, jobs.Items, 1)
+
+	configMaps, err := kubeClient.CoreV1().ConfigMaps(namespace).List(ctx, metaV1.ListOptions{})
+	require.NoError(t, err, "Error when listing configmaps")
+	require.Len(t, configMaps.Items, 1)
+	assert.Equal(t, []byte(`wrk.method = "POST"`), configMaps.Items[0].BinaryData[scriptFileName])
+}
+
+func TestSyncStatus(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	kubeClient := k8sfake.NewSimpleClientset()
+	logger := zaptest.NewLogger(t)
+
+	namespace := "test"
+	distributedPods := int32(1)
+
+	loadTest := loadTestV1.LoadTest{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name: "loadtest-name",
+		},
+		Spec: loadTestV1.LoadTestSpec{
+			DistributedPods: &distributedPods,
+			TargetURL:       "http://my-app.example.com/",
+			Duration:        time.Minute,
+			Rate:            "2000",
+		},
+		Status: loadTestV1.LoadTestStatus{
+			Namespace: namespace,
+		},
+	}
+
+	b := Backend{
+		logger:        logger,
+		kubeClientSet: kubeClient,
+	}
+
+	err := b.Sync(ctx, loadTest, "")
+	require.NoError(t, err, "Sync error")
+
+	// First sync should update status to creating
+	err = b.SyncStatus(ctx, loadTest, &loadTest.Status)
+	require.NoError(t, err, "SyncStatus error")
+	assert.Equal(t, loadTestV1.LoadTestCreating, loadTest.Status.Phase)
+
+	job, err := kubeClient.BatchV1().Jobs(namespace).Get(ctx, loadTestJobName, metaV1.GetOptions{})
+	require.NoError(t, err)
+	job.Status = batchV1.JobStatus{Active: 1}
+	job, err = kubeClient.BatchV1().Jobs(namespace).UpdateStatus(ctx, job, metaV1.UpdateOptions{})
+	require.NoError(t, err)
+
+	err = b.SyncStatus(ctx, loadTest, &loadTest.Status)
+	require.NoError(t, err, "SyncStatus error")
+	assert.Equal(t, loadTestV1.LoadTestRunning, loadTest.Status.Phase)
+
+	// the fake pod logs hold no wrk2 summary, the loadtest finishes without one
+	_, err = kubeClient.CoreV1().Pods(namespace).Create(ctx, &coreV1.Pod{
+		ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-job-abcde", Labels: map[string]string{"name": loadTestJobName}},
+		Status:     coreV1.PodStatus{Phase: coreV1.PodSucceeded},
+	}, metaV1.CreateOptions{})
+	require.NoError(t, err)
+	job.Status = batchV1.JobStatus{Succeeded: 1}
+	_, err = kubeClient.BatchV1().Jobs(namespace).UpdateStatus(ctx, job, metaV1.UpdateOptions{})
+	require.NoError(t, err)
+
+	err = b.SyncStatus(ctx, loadTest, &loadTest.Status)
+	require.NoError(t, err, "SyncStatus error")
+	assert.Equal(t, loadTestV1.LoadTestFinished, loadTest.Status.Phase)
+	assert.Nil(t, loadTest.Status.Summary)
+}
+
+func TestTransformLoadTestSpec(t *testing.T) {
+	onePod := int32(1)
+	twoPods := int32(2)
+
+	b := Backend{image: "hellofresh/kangal-wrk2:latest"}
+
+	valid := func() loadTestV1.LoadTestSpec {
+		return loadTestV1.LoadTestSpec{
+			DistributedPods: &onePod,
+			TargetURL:       "http://my-app.example.com/",
+			Duration:        time.Minute,
+			Rate:            "2000",
+		}
+	}
+
+	for _, tt := range []struct {
+		name          string
+		modify        func(spec *loadTestV1.LoadTestSpec)
+		expectedError error
+	}{
+		{
+			name:   "valid spec",
+			modify: func(*loadTestV1.LoadTestSpec) {},
+		},
+		{
+			name: "valid spec with options",
+			modify: func(spec *loadTestV1.LoadTestSpec) {
+				spec.TestFile = []byte(`wrk.method = "POST"`)
+				spec.Connections = 100
+				spec.Threads = 4
+			},
+		},
+		{
+			name:          "distributed pods",
+			modify:        func(spec *loadTestV1.LoadTestSpec) { spec.DistributedPods = &twoPods },
+			expectedError: ErrRequireOneDistributedPod,
+		},
+		{
+			name:          "no target URL",
+			modify:        func(spec *loadTestV1.LoadTestSpec) { spec.TargetURL = "" },
+			expectedError: ErrRequireTargetURL,
+		},
+		{
+			name:          "no duration",
+			modify:        func(spec *loadTestV1.LoadTestSpec) { spec.Duration = 0 },
+			expectedError: ErrRequireDuration,
+		},
+		{
+			name:          "no rate",
+			modify:        func(spec *loadTestV1.LoadTestSpec) { spec.Rate = "" },
+			expectedError: ErrInvalidRate,
+		},
+		{
+			name:          "rate with period",
+			modify:        func(spec *loadTestV1.LoadTestSpec) { spec.Rate = "100/1s" },
+			expectedError: ErrInvalidRate,
+		},
+		{
+			name: "fewer connections than threads",
+			modify: func(spec *loadTestV1.LoadTestSpec) {
+				spec.Connections = 2
+				spec.Threads = 4
+			},
+			expectedError: ErrInvalidConnections,
+		},
+		{
+			name:          "negative threads",
+			modify:        func(spec *loadTestV1.LoadTestSpec) { spec.Threads = -1 },
+			expectedError: ErrInvalidConnections,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := valid()
+			tt.modify(&spec)
+
+			err := b.TransformLoadTestSpec(&spec)
+			assert.Equal(t, tt.expectedError, err)
+			if tt.expectedError == nil {
+				assert.Equal(t, loadTestV1.ImageDetails("hellofresh/kangal-wrk2:latest"), spec.MasterConfig)
+			}
+		})
+	}
+}
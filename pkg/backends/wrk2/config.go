@@ -0,0 +1,18 @@
+package wrk2
+
+import "github.com/hellofresh/kangal/pkg/backends"
+
+// Config specific to wrk2 backend
+type Config struct {
+	ImageName      string `envconfig:"WRK2_IMAGE_NAME" default:"hellofresh/kangal-wrk2"`
+	ImageTag       string `envconfig:"WRK2_IMAGE_TAG" default:"latest"`
+	CPULimits      string `envconfig:"WRK2_CPU_LIMITS"`
+	CPURequests    string `envconfig:"WRK2_CPU_REQUESTS"`
+	MemoryLimits   string `envconfig:"WRK2_MEMORY_LIMITS"`
+	MemoryRequests string `envconfig:"WRK2_MEMORY_REQUESTS"`
+
+	StartupProbe backends.StartupProbe `envconfig:"WRK2_STARTUP_PROBE"`
+
+	// PodAnnotations are the default annotations of the loadtest pods of the backend
+	PodAnnotations map[string]string `envconfig:"WRK2_POD_ANNOTATIONS"`
+}
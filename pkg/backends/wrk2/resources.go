@@ -0,0 +1,198 @@
+package wrk2
+
+import (
+	"fmt"
+	"strconv"
+
+	"go.uber.org/zap"
+	batchV1 "k8s.io/api/batch/v1"
+	coreV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/hellofresh/kangal/pkg/backends"
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+const (
+	loadTestJobName           = "loadtest-job"
+	loadTestFileConfigMapName = "loadtest-testfile"
+	loadTestFileVolumeName    = "loadtest-testfile-volume"
+
+	containerName  = "wrk2"
+	scriptFileName = "script.lua"
+
+	// defaultConnections and defaultThreads are the wrk2 defaults, used when the loadtest doesn't specify them
+	defaultConnections = 10
+	defaultThreads     = 2
+)
+
+// maxLogBytes caps the pod logs read for the summary, the summary is printed last but the Lua script may log
+var maxLogBytes int64 = 1 << 20
+
+// attackScript runs wrk2 with the latency distribution, prints its report and uploads it as text. The options
+// are passed as env vars so user input never ends up in the shell script
+var attackScript = `set -o pipefail
+wrk --latency -R "$WRK2_RATE" -d "$WRK2_DURATION" -c "$WRK2_CONNECTIONS" -t "$WRK2_THREADS" \
+  ${WRK2_SCRIPT:+-s "$WRK2_SCRIPT"} "$WRK2_TARGET_URL" | tee /tmp/report.txt
+if [ -n "$REPORT_PRESIGNED_URL" ]; then
+  curl -sf -X PUT -H "Content-Type: text/plain; charset=utf-8" -T /tmp/report.txt -L "$REPORT_PRESIGNED_URL"
+fi`
+
+// NewJob creates a new job that runs wrk2 against the target URL of the loadtest
+func (b *Backend) NewJob(loadTest loadTestV1.LoadTest, reportURL string) *batchV1.Job {
+	logger := b.logger.With(
+		zap.String("loadtest", loadTest.GetName()),
+		zap.String("namespace", loadTest.Status.Namespace),
+	)
+
+	ownerRef := metaV1.NewControllerRef(&loadTest, loadTestV1.SchemeGroupVersion.WithKind("LoadTest"))
+
+	imageRef := loadTest.Spec.MasterConfig
+	if imageRef == ":" || imageRef == "" {
+		imageRef = b.image
+		logger.Warn("Loadtest.Spec.MasterConfig is empty; using default image", zap.String("imageRef", string(imageRef)))
+	}
+
+	// wrk2 only accepts durations in a single unit, e.g. 90s rather than 1m30s
+	duration := fmt.Sprintf("%ds", int64(loadTest.Spec.Duration.Seconds()))
+
+	envVars := []coreV1.EnvVar{
+		{Name: "WRK2_RATE", Value: loadTest.Spec.Rate},
+		{Name: "WRK2_DURATION", Value: duration},
+		{Name: "WRK2_CONNECTIONS", Value: strconv.Itoa(int(valueOrDefault(loadTest.Spec.Connections, defaultConnections)))},
+		{Name: "WRK2_THREADS", Value: strconv.Itoa(int(valueOrDefault(loadTest.Spec.Threads, defaultThreads)))},
+		{Name: "WRK2_TARGET_URL", Value: loadTest.Spec.TargetURL},
+	}
+	if reportURL != "" {
+		envVars = append(envVars, coreV1.EnvVar{
+			Name:  "REPORT_PRESIGNED_URL",
+			Value: reportURL,
+		})
+	}
+
+	var (
+		volumes []coreV1.Volume
+		mounts  []coreV1.VolumeMount
+	)
+	hasScript := len(loadTest.Spec.TestFile) != 0 || loadTest.Spec.TestFileURL != ""
+	if hasScript {
+		volume, mount := NewScriptVolumeAndMount()
+		volumes = append(volumes, volume)
+		mounts = append(mounts, mount)
+		envVars = append(envVars, coreV1.EnvVar{Name: "WRK2_SCRIPT", Value: mount.MountPath})
+	}
+
+	job := &batchV1.Job{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:      loadTestJobName,
+			Namespace: loadTest.Status.Namespace,
+			Labels: map[string]string{
+				"name": loadTestJobName,
+			},
+			OwnerReferences: []metaV1.OwnerReference{*ownerRef},
+		},
+		Spec: batchV1.JobSpec{
+			Parallelism:           loadTest.Spec.DistributedPods,
+			Completions:           loadTest.Spec.DistributedPods,
+			BackoffLimit:          backends.JobBackoffLimit(b.jobBackoffLimit, 0),
+			ActiveDeadlineSeconds: backends.JobActiveDeadlineSeconds(b.jobTimeout, loadTest.Spec.Timeout),
+			Template: coreV1.PodTemplateSpec{
+				ObjectMeta: metaV1.ObjectMeta{
+					Labels: map[string]string{
+						"name": loadTestJobName,
+					},
+					Annotations: backends.MergePodAnnotations(b.podAnnotations, b.backendPodAnnotations, loadTest.Spec.PodAnnotations),
+				},
+				Spec: coreV1.PodSpec{
+					NodeSelector:     b.nodeSelector,
+					RestartPolicy:    "Never",
+					Volumes:          volumes,
+					Tolerations:      b.tolerations,
+					SecurityContext:  backends.PodSecurityContext(b.seccompProfile),
+					ImagePullSecrets: backends.ImagePullSecrets(b.imagePullSecrets, loadTest.Spec.ImagePullSecrets),
+					Containers: []coreV1.Container{
+						{
+							Name:         containerName,
+							Image:        string(imageRef),
+							Env:          envVars,
+							Resources:    backends.BuildResourceRequirements(b.resources),
+							StartupProbe: b.startupProbe,
+							Command:      []string{"/bin/sh", "-c", attackScript},
+							VolumeMounts: mounts,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	if loadTest.Spec.TestFileURL != "" {
+		b.testFileFetcher.Inject(&job.Spec.Template.Spec, loadTest.Spec.TestFileURL, loadTestFileVolumeName, scriptFileName)
+	}
+
+	backends.SetSpecHash(job, loadTest)
+	return job
+}
+
+// NewScriptVolumeAndMount creates the volume and volume mount of the Lua script
+func NewScriptVolumeAndMount() (coreV1.Volume, coreV1.VolumeMount) {
+	v := coreV1.Volume{
+		Name: loadTestFileVolumeName,
+		VolumeSource: coreV1.VolumeSource{
+			ConfigMap: &coreV1.ConfigMapVolumeSource{
+				LocalObjectReference: coreV1.LocalObjectReference{
+					Name: loadTestFileConfigMapName,
+				},
+			},
+		},
+	}
+
+	m := coreV1.VolumeMount{
+		Name:      loadTestFileVolumeName,
+		MountPath: fmt.Sprintf("/data/%s", scriptFileName),
+		SubPath:   scriptFileName,
+	}
+
+	return v, m
+}
+
+// NewScriptConfigMap creates a configmap storing the Lua script, owned by the loadtest
+func NewScriptConfigMap(loadTest loadTestV1.LoadTest, content []byte) *coreV1.ConfigMap {
+	return &coreV1.ConfigMap{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name: loadTestFileConfigMapName,
+			OwnerReferences: []metaV1.OwnerReference{
+				*metaV1.NewControllerRef(&loadTest, loadTestV1.SchemeGroupVersion.WithKind("LoadTest")),
+			},
+		},
+		BinaryData: map[string][]byte{
+			scriptFileName: content,
+		},
+	}
+}
+
+// valueOrDefault returns value, or def when value is not set
+func valueOrDefault(value, def int32) int32 {
+	if value == 0 {
+		return def
+	}
+	return value
+}
+
+// determineLoadTestStatusFromJobs reads existing job statuses and determines what the loadtest status should be
+func determineLoadTestStatusFromJobs(job *batchV1.Job) loadTestV1.LoadTestPhase {
+	if backends.JobFailed(*job) {
+		return loadTestV1.LoadTestErrored
+	}
+
+	if job.Status.Active > int32(0) {
+		return loadTestV1.LoadTestRunning
+	}
+
+	// failed pods within the backoff limit are being replaced
+	if job.Status.Succeeded == 0 {
+		return loadTestV1.LoadTestStarting
+	}
+
+	return loadTestV1.LoadTestFinished
+}
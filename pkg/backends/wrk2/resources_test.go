@@ -0,0 +1,149 @@
+package wrk2
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+	batchV1 "k8s.io/api/batch/v1"
+	coreV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/hellofresh/kangal/pkg/backends"
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+func TestNewJob(t *testing.T) {
+	distributedPods := int32(1)
+
+	b := Backend{
+		logger: zaptest.NewLogger(t),
+		image:  "hellofresh/kangal-wrk2:latest",
+	}
+
+	for _, tt := range []struct {
+		name          string
+		spec          loadTestV1.LoadTestSpec
+		expectedImage string
+		expectedEnv   []coreV1.EnvVar
+		expectScript  bool
+	}{
+		{
+			name: "defaults",
+			spec: loadTestV1.LoadTestSpec{
+				DistributedPods: &distributedPods,
+				MasterConfig:    ":",
+				TargetURL:       "http://my-app.example.com/",
+				Duration:        90 * time.Second,
+				Rate:            "2000",
+			},
+			expectedImage: "hellofresh/kangal-wrk2:latest",
+			expectedEnv: []coreV1.EnvVar{
+				{Name: "WRK2_RATE", Value: "2000"},
+				{Name: "WRK2_DURATION", Value: "90s"},
+				{Name: "WRK2_CONNECTIONS", Value: "10"},
+				{Name: "WRK2_THREADS", Value: "2"},
+				{Name: "WRK2_TARGET_URL", Value: "http://my-app.example.com/"},
+				{Name: "REPORT_PRESIGNED_URL", Value: "http://report"},
+			},
+		},
+		{
+			name: "custom options and script",
+			spec: loadTestV1.LoadTestSpec{
+				DistributedPods: &distributedPods,
+				MasterConfig:    "my/wrk2:1.0",
+				TargetURL:       "http://my-app.example.com/",
+				Duration:        time.Minute,
+				Rate:            "500",
+				Connections:     100,
+				Threads:         4,
+				TestFile:        []byte(`wrk.method = "POST"`),
+			},
+			expectedImage: "my/wrk2:1.0",
+			expectedEnv: []coreV1.EnvVar{
+				{Name: "WRK2_RATE", Value: "500"},
+				{Name: "WRK2_DURATION", Value: "60s"},
+				{Name: "WRK2_CONNECTIONS", Value: "100"},
+				{Name: "WRK2_THREADS", Value: "4"},
+				{Name: "WRK2_TARGET_URL", Value: "http://my-app.example.com/"},
+				{Name: "REPORT_PRESIGNED_URL", Value: "http://report"},
+				{Name: "WRK2_SCRIPT", Value: "/data/script.lua"},
+			},
+			expectScript: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			loadTest := loadTestV1.LoadTest{
+				ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-name"},
+				Spec:       tt.spec,
+				Status:     loadTestV1.LoadTestStatus{Namespace: "test"},
+			}
+
+			job := b.NewJob(loadTest, "http://report")
+			assert.Equal(t, &distributedPods, job.Spec.Parallelism)
+
+			podSpec := job.Spec.Template.Spec
+			require.Len(t, podSpec.Containers, 1)
+			container := podSpec.Containers[0]
+			assert.Equal(t, tt.expectedImage, container.Image)
+			assert.Equal(t, tt.expectedEnv, container.Env)
+
+			if tt.expectScript {
+				require.Len(t, podSpec.Volumes, 1)
+				assert.Equal(t, loadTestFileConfigMapName, podSpec.Volumes[0].ConfigMap.Name)
+				require.Len(t, container.VolumeMounts, 1)
+				assert.Equal(t, "/data/script.lua", container.VolumeMounts[0].MountPath)
+			} else {
+				assert.Empty(t, podSpec.Volumes)
+				assert.Empty(t, container.VolumeMounts)
+			}
+		})
+	}
+}
+
+func TestNewJobTestFileURL(t *testing.T) {
+	distributedPods := int32(1)
+
+	b := Backend{
+		logger:          zaptest.NewLogger(t),
+		image:           "hellofresh/kangal-wrk2:latest",
+		testFileFetcher: backends.TestFileFetcher{Image: "curlimages/curl:latest"},
+	}
+
+	loadTest := loadTestV1.LoadTest{
+		ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-name"},
+		Spec: loadTestV1.LoadTestSpec{
+			DistributedPods: &distributedPods,
+			TargetURL:       "http://my-app.example.com/",
+			Duration:        time.Minute,
+			Rate:            "2000",
+			TestFileURL:     "https://example.com/script.lua",
+		},
+		Status: loadTestV1.LoadTestStatus{Namespace: "test"},
+	}
+
+	podSpec := b.NewJob(loadTest, "").Spec.Template.Spec
+	require.Len(t, podSpec.InitContainers, 1)
+	assert.Equal(t, backends.TestFileFetcherContainerName, podSpec.InitContainers[0].Name)
+	require.Len(t, podSpec.Volumes, 1)
+	assert.NotNil(t, podSpec.Volumes[0].EmptyDir)
+	assert.Contains(t, podSpec.Containers[0].Env, coreV1.EnvVar{Name: "WRK2_SCRIPT", Value: "/data/script.lua"})
+}
+
+func TestDetermineLoadTestStatusFromJobs(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		status   batchV1.JobStatus
+		expected loadTestV1.LoadTestPhase
+	}{
+		{name: "starting", expected: loadTestV1.LoadTestStarting},
+		{name: "running", status: batchV1.JobStatus{Active: 1}, expected: loadTestV1.LoadTestRunning},
+		{name: "finished", status: batchV1.JobStatus{Succeeded: 1}, expected: loadTestV1.LoadTestFinished},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, determineLoadTestStatusFromJobs(&batchV1.Job{Status: tt.status}))
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package wrk2
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+var (
+	// percentileRegexp matches the lines of the latency distribution printed with --latency, e.g. " 99.000%    2.84ms"
+	percentileRegexp = regexp.MustCompile(`(?m)^\s*(\d+\.\d+)%\s+(\d+(?:\.\d+)?(?:us|ms|s|m|h))\s*$`)
+	// requestsRegexp matches the number of requests sent, e.g. "  59987 requests in 1.00m, 21.34MB read"
+	requestsRegexp = regexp.MustCompile(`(?m)^\s*(\d+) requests in `)
+	// rpsRegexp matches the measured throughput, e.g. "Requests/sec:    999.72"
+	rpsRegexp = regexp.MustCompile(`(?m)^Requests/sec:\s+(\d+(?:\.\d+)?)`)
+	// non2xxRegexp matches the number of requests answered with an error status
+	non2xxRegexp = regexp.MustCompile(`Non-2xx or 3xx responses: (\d+)`)
+	// socketErrorsRegexp matches the number of requests failed with a socket error
+	socketErrorsRegexp = regexp.MustCompile(`Socket errors: connect (\d+), read (\d+), write (\d+), timeout (\d+)`)
+)
+
+// parseSummary reads the summary metrics of a loadtest from the wrk2 output, e.g. p99: 2.84ms, rps: 999.72 or
+// error_rate: 0.02%. Latencies keep the wrk2 unit, the metrics missing from the output are left out
+func parseSummary(output string) map[string]string {
+	summary := map[string]string{}
+
+	for _, match := range percentileRegexp.FindAllStringSubmatch(output, -1) {
+		percentile, err := strconv.ParseFloat(match[1], 64)
+		if err != nil {
+			continue
+		}
+		summary["p"+strconv.FormatFloat(percentile, 'f', -1, 64)] = match[2]
+	}
+
+	if match := rpsRegexp.FindStringSubmatch(output); match != nil {
+		summary["rps"] = match[1]
+	}
+
+	match := requestsRegexp.FindStringSubmatch(output)
+	if match == nil {
+		return summary
+	}
+	requests, err := strconv.ParseUint(match[1], 10, 64)
+	if err != nil {
+		return summary
+	}
+	summary["requests"] = match[1]
+
+	var failed uint64
+	if match := non2xxRegexp.FindStringSubmatch(output); match != nil {
+		n, _ := strconv.ParseUint(match[1], 10, 64)
+		failed += n
+	}
+	if match := socketErrorsRegexp.FindStringSubmatch(output); match != nil {
+		for _, count := range match[1:] {
+			n, _ := strconv.ParseUint(count, 10, 64)
+			failed += n
+		}
+	}
+
+	if requests > 0 {
+		summary["error_rate"] = fmt.Sprintf("%.2f%%", float64(failed)/float64(requests)*100)
+	}
+
+	return summary
+}
@@ -0,0 +1,85 @@
+package wrk2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const wrk2Output = `Running 1m test @ http://my-app.example.com/
+  2 threads and 10 connections
+  Thread calibration: mean lat.: 1.186ms, rate sampling interval: 10ms
+  Thread calibration: mean lat.: 1.199ms, rate sampling interval: 10ms
+  Thread Stats   Avg      Stdev     Max   +/- Stdev
+    Latency     1.18ms  554.12us   4.03ms   64.99%
+    Req/Sec   527.81    105.34     1.00k    73.71%
+  Latency Distribution (HdrHistogram - Recorded Latency)
+ 50.000%    1.16ms
+ 75.000%    1.60ms
+ 90.000%    2.02ms
+ 99.000%    2.84ms
+ 99.900%    3.39ms
+ 99.990%    3.81ms
+ 99.999%    4.03ms
+100.000%    4.03ms
+
+  Detailed Percentile spectrum:
+       Value   Percentile   TotalCount 1/(1-Percentile)
+
+       0.224     0.000000            1         1.00
+       0.530     0.100000         5011         1.11
+#[Mean    =        1.177, StdDeviation   =        0.554]
+#[Max     =        4.028, Total count    =        50000]
+----------------------------------------------------------
+  60000 requests in 1.00m, 21.34MB read
+  Non-2xx or 3xx responses: 30
+  Socket errors: connect 0, read 0, write 0, timeout 30
+Requests/sec:    999.72
+Transfer/sec:    364.17KB
+`
+
+func TestParseSummary(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		output   string
+		expected map[string]string
+	}{
+		{
+			name:   "full report",
+			output: wrk2Output,
+			expected: map[string]string{
+				"p50":        "1.16ms",
+				"p75":        "1.60ms",
+				"p90":        "2.02ms",
+				"p99":        "2.84ms",
+				"p99.9":      "3.39ms",
+				"p99.99":     "3.81ms",
+				"p99.999":    "4.03ms",
+				"p100":       "4.03ms",
+				"rps":        "999.72",
+				"requests":   "60000",
+				"error_rate": "0.10%",
+			},
+		},
+		{
+			name: "no errors",
+			output: `  100 requests in 10.00s, 35.00KB read
+Requests/sec:     10.00
+`,
+			expected: map[string]string{
+				"rps":        "10.00",
+				"requests":   "100",
+				"error_rate": "0.00%",
+			},
+		},
+		{
+			name:     "no report",
+			output:   "unable to connect to my-app.example.com:80 Connection refused",
+			expected: map[string]string{},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, parseSummary(tt.output))
+		})
+	}
+}
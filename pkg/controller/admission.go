@@ -0,0 +1,140 @@
+package controller
+
+import (
+	"context"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/labels"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+// admissionQueue keeps the LoadTests held back for lack of capacity, with their backend type
+type admissionQueue struct {
+	mu     sync.Mutex
+	queued map[string]loadTestV1.LoadTestType
+}
+
+func newAdmissionQueue() *admissionQueue {
+	return &admissionQueue{queued: map[string]loadTestV1.LoadTestType{}}
+}
+
+// hold records the given LoadTest key as waiting for capacity, it returns false if it was already waiting
+func (q *admissionQueue) hold(key string, loadTestType loadTestV1.LoadTestType) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if _, found := q.queued[key]; found {
+		return false
+	}
+	q.queued[key] = loadTestType
+	return true
+}
+
+// release drops the given LoadTest key from the waiting ones, ok is false if it was not waiting
+func (q *admissionQueue) release(key string) (loadTestType loadTestV1.LoadTestType, ok bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	loadTestType, ok = q.queued[key]
+	delete(q.queued, key)
+	return loadTestType, ok
+}
+
+// runningLoadTestsSource provides the number of running loadtests when the kangal_loadtests_running gauge is
+// collected. The metrics are registered before the controller, which sets the count once it is built
+type runningLoadTestsSource struct {
+	mu    sync.Mutex
+	count func() (int, error)
+}
+
+// set replaces the function counting the running loadtests
+func (s *runningLoadTestsSource) set(count func() (int, error)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.count = count
+}
+
+// observe reports the number of running loadtests, nothing is reported before the count is set
+func (s *runningLoadTestsSource) observe(_ context.Context, o metric.Int64Observer) error {
+	s.mu.Lock()
+	count := s.count
+	s.mu.Unlock()
+
+	if count == nil {
+		return nil
+	}
+
+	running, err := count()
+	if err != nil {
+		return err
+	}
+	o.Observe(int64(running))
+	return nil
+}
+
+// runningLoadTests returns the number of loadtests that have a namespace and are neither finished nor errored
+func (c *Controller) runningLoadTests() (int, error) {
+	loadTests, err := c.loadtestsLister.List(labels.Everything())
+	if err != nil {
+		return 0, err
+	}
+
+	running := 0
+	for _, loadTest := range loadTests {
+		if loadTest.Status.Namespace == "" {
+			continue
+		}
+		if loadTest.Status.Phase == loadTestV1.LoadTestFinished || loadTest.Status.Phase == loadTestV1.LoadTestErrored {
+			continue
+		}
+		running++
+	}
+	return running, nil
+}
+
+// admitLoadTest holds back loadtests without namespace while MaxRunningLoadTests loadtests are running, held back
+// loadtests are synced again after AdmissionRetryInterval. The running loadtests are counted from the informer
+// cache, so loadtests admitted concurrently may exceed the limit by a few. It returns false if the loadtest was
+// held back.
+func (c *Controller) admitLoadTest(ctx context.Context, key string, loadTest *loadTestV1.LoadTest) (bool, error) {
+	if c.cfg.MaxRunningLoadTests <= 0 || loadTest.Status.Namespace != "" {
+		return true, nil
+	}
+
+	running, err := c.runningLoadTests()
+	if err != nil {
+		return true, err
+	}
+
+	if running < c.cfg.MaxRunningLoadTests {
+		c.releaseAdmission(ctx, key)
+		return true, nil
+	}
+
+	if c.admissionQueue.hold(key, loadTest.Spec.Type) {
+		c.logger.Info("Holding back loadtest, maximum of running loadtests reached",
+			zap.String("loadtest", loadTest.GetName()),
+			zap.Int("running", running),
+		)
+		if c.cfg.AdmissionMetrics {
+			attrs := metric.WithAttributes(attribute.String("type", string(loadTest.Spec.Type)))
+			c.statsClient.loadTestsQueuedStat.Add(ctx, 1, attrs)
+			c.statsClient.admissionDelayedCountStat.Add(ctx, 1, attrs)
+		}
+	}
+	c.workQueue.AddAfter(key, c.cfg.AdmissionRetryInterval)
+	return false, nil
+}
+
+// releaseAdmission drops the given LoadTest key from the loadtests waiting for capacity, if it was waiting
+func (c *Controller) releaseAdmission(ctx context.Context, key string) {
+	loadTestType, ok := c.admissionQueue.release(key)
+	if !ok || !c.cfg.AdmissionMetrics {
+		return
+	}
+	c.statsClient.loadTestsQueuedStat.Add(ctx, -1, metric.WithAttributes(attribute.String("type", string(loadTestType))))
+}
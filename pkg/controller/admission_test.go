@@ -0,0 +1,141 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdkMetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.uber.org/zap/zaptest"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+	listers "github.com/hellofresh/kangal/pkg/kubernetes/generated/listers/loadtest/v1"
+)
+
+func TestAdmitLoadTest(t *testing.T) {
+	ctx := context.Background()
+	reader := sdkMetric.NewManualReader()
+	statsReporter, err := NewMetricsReporter(sdkMetric.NewMeterProvider(sdkMetric.WithReader(reader)).Meter("test"))
+	require.NoError(t, err)
+
+	newLoadTest := func(name, namespace string, phase loadTestV1.LoadTestPhase) *loadTestV1.LoadTest {
+		return &loadTestV1.LoadTest{
+			ObjectMeta: metaV1.ObjectMeta{Name: name},
+			Spec:       loadTestV1.LoadTestSpec{Type: loadTestV1.LoadTestTypeK6},
+			Status:     loadTestV1.LoadTestStatus{Phase: phase, Namespace: namespace},
+		}
+	}
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	require.NoError(t, indexer.Add(newLoadTest("loadtest-running", "loadtest-running", loadTestV1.LoadTestRunning)))
+	require.NoError(t, indexer.Add(newLoadTest("loadtest-finished", "loadtest-finished", loadTestV1.LoadTestFinished)))
+
+	c := &Controller{
+		cfg:             Config{MaxRunningLoadTests: 1, AdmissionRetryInterval: time.Hour, AdmissionMetrics: true},
+		loadtestsLister: listers.NewLoadTestLister(indexer),
+		workQueue: newPriorityQueue("test", 1, func(interface{}) priority {
+			return priorityNormal
+		}),
+		statsClient:    *statsReporter,
+		admissionQueue: newAdmissionQueue(),
+		logger:         zaptest.NewLogger(t),
+	}
+	defer c.workQueue.ShutDown()
+
+	pending := newLoadTest("loadtest-pending", "", loadTestV1.LoadTestCreating)
+	for i := 0; i < 2; i++ {
+		admitted, err := c.admitLoadTest(ctx, "loadtest-pending", pending)
+		require.NoError(t, err)
+		assert.False(t, admitted, "Expected loadtest to be held back while the maximum is reached")
+	}
+	assert.Equal(t, int64(1), collectInt64Sum(t, reader, "kangal_loadtests_queued"))
+	assert.Equal(t, int64(1), collectInt64Sum(t, reader, "kangal_loadtest_admission_delayed_total"))
+
+	// loadtests with a namespace are already admitted
+	admitted, err := c.admitLoadTest(ctx, "loadtest-running", newLoadTest("loadtest-running", "loadtest-running", loadTestV1.LoadTestRunning))
+	require.NoError(t, err)
+	assert.True(t, admitted)
+
+	require.NoError(t, indexer.Update(newLoadTest("loadtest-running", "loadtest-running", loadTestV1.LoadTestFinished)))
+	admitted, err = c.admitLoadTest(ctx, "loadtest-pending", pending)
+	require.NoError(t, err)
+	assert.True(t, admitted, "Expected loadtest to be admitted once capacity frees up")
+	assert.Equal(t, int64(0), collectInt64Sum(t, reader, "kangal_loadtests_queued"))
+	assert.Equal(t, int64(1), collectInt64Sum(t, reader, "kangal_loadtest_admission_delayed_total"))
+}
+
+func TestRunningLoadTestsGauge(t *testing.T) {
+	reader := sdkMetric.NewManualReader()
+	statsReporter, err := NewMetricsReporter(sdkMetric.NewMeterProvider(sdkMetric.WithReader(reader)).Meter("test"))
+	require.NoError(t, err)
+
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for _, lt := range []*loadTestV1.LoadTest{
+		{ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-running"}, Status: loadTestV1.LoadTestStatus{Phase: loadTestV1.LoadTestRunning, Namespace: "loadtest-running"}},
+		{ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-starting"}, Status: loadTestV1.LoadTestStatus{Phase: loadTestV1.LoadTestStarting, Namespace: "loadtest-starting"}},
+		{ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-finished"}, Status: loadTestV1.LoadTestStatus{Phase: loadTestV1.LoadTestFinished, Namespace: "loadtest-finished"}},
+		{ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-held-back"}, Status: loadTestV1.LoadTestStatus{Phase: loadTestV1.LoadTestCreating}},
+	} {
+		require.NoError(t, indexer.Add(lt))
+	}
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			assert.NotEqual(t, "kangal_loadtests_running", m.Name, "Expected no running loadtests reported before the controller is built")
+		}
+	}
+
+	c := &Controller{loadtestsLister: listers.NewLoadTestLister(indexer)}
+	statsReporter.runningLoadTests.set(c.runningLoadTests)
+	assert.Equal(t, int64(2), collectInt64Gauge(t, reader, "kangal_loadtests_running"))
+}
+
+// collectInt64Gauge returns the value of the named int64 gauge metric
+func collectInt64Gauge(t *testing.T, reader sdkMetric.Reader, name string) int64 {
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != name {
+				continue
+			}
+			gauge, ok := m.Data.(metricdata.Gauge[int64])
+			require.True(t, ok)
+			require.Len(t, gauge.DataPoints, 1)
+			return gauge.DataPoints[0].Value
+		}
+	}
+	t.Fatalf("metric %s not found", name)
+	return 0
+}
+
+// collectInt64Sum returns the sum of all data points of the named int64 sum metric
+func collectInt64Sum(t *testing.T, reader sdkMetric.Reader, name string) int64 {
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != name {
+				continue
+			}
+			sum, ok := m.Data.(metricdata.Sum[int64])
+			require.True(t, ok)
+
+			var total int64
+			for _, dp := range sum.DataPoints {
+				total += dp.Value
+			}
+			return total
+		}
+	}
+	t.Fatalf("metric %s not found", name)
+	return 0
+}
@@ -0,0 +1,25 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/go-chi/render"
+)
+
+// BackendLister lists the types of the backends registered in the controller
+type BackendLister interface {
+	List() []string
+}
+
+// BackendsResponse is the response of the backends endpoint
+type BackendsResponse struct {
+	Backends []string `json:"backends"`
+}
+
+// BackendsHandler returns the types of the backends registered in the controller, optional backends that failed
+// to initialize are not listed
+func BackendsHandler(lister BackendLister) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		render.JSON(w, r, BackendsResponse{Backends: lister.List()})
+	}
+}
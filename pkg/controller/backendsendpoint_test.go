@@ -0,0 +1,25 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeBackendLister []string
+
+func (l fakeBackendLister) List() []string { return l }
+
+func TestBackendsHandler(t *testing.T) {
+	w := httptest.NewRecorder()
+	BackendsHandler(fakeBackendLister{"Ghz", "JMeter"})(w, httptest.NewRequest(http.MethodGet, "/backends", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response BackendsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, []string{"Ghz", "JMeter"}, response.Backends)
+}
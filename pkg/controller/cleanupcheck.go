@@ -0,0 +1,38 @@
+package controller
+
+import (
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// verifyCleanUpThreshold checks that CleanUpThreshold and MaxTestLifetime are not shorter than CleanUpMinThreshold,
+// so a threshold deleting loadtests before they could have finished is noticed at startup. A short threshold is
+// logged, or returned as error when CleanUpMinThresholdRequired is set.
+func verifyCleanUpThreshold(cfg Config, logger *zap.Logger) error {
+	if cfg.CleanUpMinThreshold == 0 {
+		return nil
+	}
+
+	for _, threshold := range []struct {
+		name  string
+		value time.Duration
+	}{
+		{name: "CLEANUP_THRESHOLD", value: cfg.CleanUpThreshold},
+		{name: "MAX_TEST_LIFETIME", value: cfg.MaxTestLifetime},
+	} {
+		if threshold.value == 0 || threshold.value >= cfg.CleanUpMinThreshold {
+			continue
+		}
+		if cfg.CleanUpMinThresholdRequired {
+			return fmt.Errorf("%s of %s is shorter than CLEANUP_MIN_THRESHOLD of %s", threshold.name, threshold.value, cfg.CleanUpMinThreshold)
+		}
+		logger.Warn("Cleanup threshold is shorter than the expected loadtest duration, loadtests may be deleted early",
+			zap.String("threshold", threshold.name),
+			zap.Duration("value", threshold.value),
+			zap.Duration("min", cfg.CleanUpMinThreshold),
+		)
+	}
+	return nil
+}
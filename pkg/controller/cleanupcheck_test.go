@@ -0,0 +1,53 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestVerifyCleanUpThreshold(t *testing.T) {
+	for _, tt := range []struct {
+		name        string
+		cfg         Config
+		expectError bool
+	}{
+		{
+			name: "disabled",
+			cfg:  Config{CleanUpThreshold: time.Minute, CleanUpMinThresholdRequired: true},
+		},
+		{
+			name: "threshold above the minimum",
+			cfg:  Config{CleanUpThreshold: time.Hour, CleanUpMinThreshold: 30 * time.Minute, CleanUpMinThresholdRequired: true},
+		},
+		{
+			name: "cleanup disabled",
+			cfg:  Config{CleanUpMinThreshold: 30 * time.Minute, CleanUpMinThresholdRequired: true},
+		},
+		{
+			name: "short threshold is logged",
+			cfg:  Config{CleanUpThreshold: time.Minute, CleanUpMinThreshold: 30 * time.Minute},
+		},
+		{
+			name:        "short threshold is required",
+			cfg:         Config{CleanUpThreshold: time.Minute, CleanUpMinThreshold: 30 * time.Minute, CleanUpMinThresholdRequired: true},
+			expectError: true,
+		},
+		{
+			name:        "short max lifetime is required",
+			cfg:         Config{CleanUpThreshold: time.Hour, MaxTestLifetime: time.Minute, CleanUpMinThreshold: 30 * time.Minute, CleanUpMinThresholdRequired: true},
+			expectError: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			err := verifyCleanUpThreshold(tt.cfg, zaptest.NewLogger(t))
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package controller
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+// completionSummaryMessage is the message of the log line summarizing a loadtest that reached a terminal phase
+const completionSummaryMessage = "Loadtest completed"
+
+// logCompletionSummary logs a single structured line summarizing the loadtest once it reached a terminal phase,
+// for environments without a metrics pipeline, e.g. CI runners grepping the controller logs
+func (c *Controller) logCompletionSummary(loadTest *loadTestV1.LoadTest) {
+	if !c.cfg.CompletionSummaryLog {
+		return
+	}
+	if loadTest.Status.Phase != loadTestV1.LoadTestFinished && loadTest.Status.Phase != loadTestV1.LoadTestErrored {
+		return
+	}
+
+	c.logger.Info(completionSummaryMessage,
+		zap.String("loadtest", loadTest.GetName()),
+		zap.String("backend", loadTest.Spec.Type.String()),
+		zap.String("phase", loadTest.Status.Phase.String()),
+		zap.Duration("duration", loadTestDuration(loadTest, time.Now())),
+		zap.String("reportURL", c.reportURL(loadTest)),
+		zap.Any("summary", loadTest.Status.Summary),
+	)
+}
+
+// loadTestDuration returns the time the jobs of the loadtest ran, or the time since its creation when its jobs
+// never started, e.g. when it errored before. Loadtests without completion time are measured until now
+func loadTestDuration(loadTest *loadTestV1.LoadTest, now time.Time) time.Duration {
+	start := loadTest.ObjectMeta.CreationTimestamp.Time
+	if loadTest.Status.JobStatus.StartTime != nil {
+		start = loadTest.Status.JobStatus.StartTime.Time
+	}
+	end := now
+	if loadTest.Status.JobStatus.CompletionTime != nil {
+		end = loadTest.Status.JobStatus.CompletionTime.Time
+	}
+	return elapsedSince(start, end)
+}
@@ -0,0 +1,95 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	batchV1 "k8s.io/api/batch/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+func TestLogCompletionSummary(t *testing.T) {
+	startTime := metaV1.NewTime(time.Date(2021, 3, 4, 5, 0, 0, 0, time.UTC))
+	completionTime := metaV1.NewTime(time.Date(2021, 3, 4, 5, 10, 0, 0, time.UTC))
+
+	for _, tt := range []struct {
+		name    string
+		enabled bool
+		phase   loadTestV1.LoadTestPhase
+		logged  bool
+	}{
+		{
+			name:    "finished",
+			enabled: true,
+			phase:   loadTestV1.LoadTestFinished,
+			logged:  true,
+		},
+		{
+			name:    "errored",
+			enabled: true,
+			phase:   loadTestV1.LoadTestErrored,
+			logged:  true,
+		},
+		{
+			name:    "running",
+			enabled: true,
+			phase:   loadTestV1.LoadTestRunning,
+		},
+		{
+			name:  "disabled",
+			phase: loadTestV1.LoadTestFinished,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			core, logs := observer.New(zapcore.InfoLevel)
+			c := &Controller{
+				logger: zap.New(core),
+				cfg:    Config{CompletionSummaryLog: tt.enabled, KangalProxyURL: "http://kangal-proxy"},
+			}
+			loadTest := &loadTestV1.LoadTest{
+				ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-name"},
+				Spec:       loadTestV1.LoadTestSpec{Type: loadTestV1.LoadTestTypeGhz},
+				Status: loadTestV1.LoadTestStatus{
+					Phase:     tt.phase,
+					JobStatus: batchV1.JobStatus{StartTime: &startTime, CompletionTime: &completionTime},
+					Summary:   map[string]string{"p99": "120ms"},
+				},
+			}
+
+			c.logCompletionSummary(loadTest)
+
+			entries := logs.FilterMessage(completionSummaryMessage).All()
+			if !tt.logged {
+				assert.Empty(t, entries)
+				return
+			}
+			require.Len(t, entries, 1)
+			fields := entries[0].ContextMap()
+			assert.Equal(t, "loadtest-name", fields["loadtest"])
+			assert.Equal(t, "Ghz", fields["backend"])
+			assert.Equal(t, tt.phase.String(), fields["phase"])
+			assert.Equal(t, 10*time.Minute, fields["duration"])
+			assert.Equal(t, "http://kangal-proxy/load-test/loadtest-name/report", fields["reportURL"])
+			assert.Equal(t, map[string]string{"p99": "120ms"}, fields["summary"])
+		})
+	}
+}
+
+func TestLoadTestDuration(t *testing.T) {
+	now := time.Date(2021, 3, 4, 6, 0, 0, 0, time.UTC)
+	loadTest := &loadTestV1.LoadTest{
+		ObjectMeta: metaV1.ObjectMeta{CreationTimestamp: metaV1.NewTime(now.Add(-time.Hour))},
+	}
+	assert.Equal(t, time.Hour, loadTestDuration(loadTest, now), "errored before its jobs started")
+
+	startTime := metaV1.NewTime(now.Add(-30 * time.Minute))
+	loadTest.Status.JobStatus.StartTime = &startTime
+	assert.Equal(t, 30*time.Minute, loadTestDuration(loadTest, now), "not completed yet")
+}
@@ -3,6 +3,9 @@ package controller
 import (
 	"time"
 
+	coreV1 "k8s.io/api/core/v1"
+
+	"github.com/hellofresh/kangal/pkg/backends"
 	"github.com/hellofresh/kangal/pkg/core/observability"
 	"github.com/hellofresh/kangal/pkg/kubernetes"
 )
@@ -15,16 +18,278 @@ type Config struct {
 	// CleanUpThresholdEnvVar is used if we want to increase the amount of time a
 	// load test lives for, the default is 1 hour. (ex. 5h)
 	CleanUpThreshold time.Duration `envconfig:"CLEANUP_THRESHOLD" default:"1h"`
+	// CleanUpClockSkewTolerance extends CleanUpThreshold to tolerate the clock of the controller being ahead
+	// of the clocks setting the completion and creation times of loadtests
+	CleanUpClockSkewTolerance time.Duration `envconfig:"CLEANUP_CLOCK_SKEW_TOLERANCE" default:"0s"`
+	// CleanUpMinThreshold is the shortest CleanUpThreshold expected to outlive the loadtests, a shorter threshold
+	// is logged at startup, CleanUpMinThresholdRequired refuses to start instead. 0 disables the check
+	CleanUpMinThreshold         time.Duration `envconfig:"CLEANUP_MIN_THRESHOLD" default:"0s"`
+	CleanUpMinThresholdRequired bool          `envconfig:"CLEANUP_MIN_THRESHOLD_REQUIRED" default:"false"`
+	// MaxTestLifetime is the backstop deleting loadtests that are still active after this time since their creation,
+	// CleanUpThreshold never deletes them. 0 keeps active loadtests until they are finished or errored
+	MaxTestLifetime time.Duration `envconfig:"MAX_TEST_LIFETIME" default:"0s"`
+
+	// CleanUpEvents enables the Events recorded when loadtests are deleted after CleanUpThreshold,
+	// the cleanup metric is reported either way
+	CleanUpEvents bool `envconfig:"CLEANUP_EVENTS" default:"true"`
+
+	// PhaseEvents enables the Events recorded when loadtests change phase
+	PhaseEvents bool `envconfig:"PHASE_EVENTS" default:"true"`
+
+	// EventRateLimit is the number of Events each loadtest may emit per minute, Events over the limit are dropped
+	// and counted, 0 disables the limit
+	EventRateLimit int `envconfig:"EVENT_RATE_LIMIT" default:"0"`
 
 	// S3 compatible configuration access keys and endpoints needed to store load test reports
 	KangalProxyURL string `envconfig:"KANGAL_PROXY_URL" default:""`
 
+	// ReportStorageLocation is the URL of the object storage location reports are uploaded to through the proxy,
+	// e.g. s3://bucket, the location of the report of each loadtest is recorded in its status, empty disables it
+	ReportStorageLocation string `envconfig:"REPORT_STORAGE_LOCATION"`
+
+	// DefaultRetentionClass is the retention class recorded for the reports of loadtests without one
+	DefaultRetentionClass string `envconfig:"DEFAULT_RETENTION_CLASS" default:"standard"`
+
+	// ProxyCheck requests KangalProxyURL at startup and logs when it is unreachable within ProxyCheckTimeout,
+	// ProxyCheckRequired refuses to start instead
+	ProxyCheck         bool          `envconfig:"PROXY_CHECK" default:"false"`
+	ProxyCheckRequired bool          `envconfig:"PROXY_CHECK_REQUIRED" default:"false"`
+	ProxyCheckTimeout  time.Duration `envconfig:"PROXY_CHECK_TIMEOUT" default:"5s"`
+
+	// SchedulingCheck lists the nodes at startup and logs when loadtest pods with the configured NodeSelectors
+	// and Tolerations can't be scheduled on any of them, or when a toleration matches no node taint
+	SchedulingCheck bool `envconfig:"SCHEDULING_CHECK" default:"false"`
+
+	// DryRun logs the requests of the controller changing the cluster, e.g. creating the namespace and jobs of a
+	// loadtest or updating its status, instead of sending them. DryRunYAML logs their objects as YAML
+	DryRun     bool `envconfig:"DRY_RUN" default:"false"`
+	DryRunYAML bool `envconfig:"DRY_RUN_YAML" default:"false"`
+
+	// StatusSubresourceFallback writes loadtest statuses with plain updates when the LoadTest CRD has no status
+	// subresource instead of refusing to start
+	StatusSubresourceFallback bool `envconfig:"STATUS_SUBRESOURCE_FALLBACK" default:"false"`
+
 	// KubeClientTimeout specifies timeout for each operation done by kube client
 	KubeClientTimeout time.Duration `envconfig:"KUBE_CLIENT_TIMEOUT" default:"5s"`
 
 	// SyncHandlerTimeout specifies the time limit for each sync operation
 	SyncHandlerTimeout time.Duration `envconfig:"SYNC_HANDLER_TIMEOUT" default:"60s"`
 
+	// TolerateSyncStatusErrors keeps the last known status of a loadtest when its backend fails to
+	// sync the status instead of requeuing it, Sync errors are always requeued
+	TolerateSyncStatusErrors bool `envconfig:"TOLERATE_SYNC_STATUS_ERRORS" default:"false"`
+
+	// StatusUpdateInterval is the minimum time between two status updates of a loadtest that don't change
+	// its phase, phase changes are always written right away, 0 disables throttling
+	StatusUpdateInterval time.Duration `envconfig:"STATUS_UPDATE_INTERVAL" default:"0s"`
+
+	// StatusBatchInterval coalesces the status updates of all loadtests and writes them once per interval, only the
+	// latest update of each loadtest is written, 0 writes each status update right away
+	StatusBatchInterval time.Duration `envconfig:"STATUS_BATCH_INTERVAL" default:"0s"`
+
+	// SyncPhaseMetrics records the duration of backend Sync, backend SyncStatus and namespace creation
+	// separately, tagged by backend type
+	SyncPhaseMetrics bool `envconfig:"SYNC_PHASE_METRICS" default:"true"`
+
+	// ReconcileTriggerMetric counts the loadtests enqueued for reconciliation by the source of the trigger,
+	// e.g. a loadtest change, a pod or job event or an informer resync
+	ReconcileTriggerMetric bool `envconfig:"RECONCILE_TRIGGER_METRIC" default:"true"`
+
+	// MaxRunningLoadTests is the maximum of loadtests running at the same time, further loadtests are held back
+	// before their namespace is created and synced again after AdmissionRetryInterval, 0 means no limit
+	MaxRunningLoadTests    int           `envconfig:"MAX_RUNNING_LOAD_TESTS" default:"0"`
+	AdmissionRetryInterval time.Duration `envconfig:"ADMISSION_RETRY_INTERVAL" default:"10s"`
+
+	// MaxNamespaceJobs is the maximum of active jobs in a namespace, loadtests that have not created their jobs yet are
+	// deferred while it is reached and synced again after NamespaceJobsRetryInterval, 0 means no limit
+	MaxNamespaceJobs           int           `envconfig:"MAX_NAMESPACE_JOBS" default:"0"`
+	NamespaceJobsRetryInterval time.Duration `envconfig:"NAMESPACE_JOBS_RETRY_INTERVAL" default:"10s"`
+
+	// DependencyRetryInterval is the interval loadtests waiting for the loadtest they depend on are synced again
+	DependencyRetryInterval time.Duration `envconfig:"DEPENDENCY_RETRY_INTERVAL" default:"10s"`
+	// AdmissionMetrics reports the loadtests waiting for capacity and counts the loadtests held back,
+	// tagged by backend type
+	AdmissionMetrics bool `envconfig:"ADMISSION_METRICS" default:"true"`
+
+	// Paused starts the controller with the reconciliation of loadtests paused
+	Paused bool `envconfig:"PAUSED" default:"false"`
+	// PauseEndpoint enables the /pause endpoint on the HTTP port to pause and resume the reconciliation at runtime,
+	// pause and resume requests must carry DebugEndpointToken as bearer token
+	PauseEndpoint bool `envconfig:"PAUSE_ENDPOINT" default:"false"`
+	// DebugEndpoint enables the /debug/loadtest endpoints on the HTTP port returning the view of the controller on a
+	// single loadtest, requests must carry DebugEndpointToken as bearer token
+	DebugEndpoint      bool   `envconfig:"DEBUG_ENDPOINT" default:"false"`
+	DebugEndpointToken string `envconfig:"DEBUG_ENDPOINT_TOKEN"`
+	// ConfigEndpoint enables the /config endpoint on the HTTP port returning the effective configuration with
+	// its secrets redacted, requests must carry DebugEndpointToken as bearer token
+	ConfigEndpoint bool `envconfig:"CONFIG_ENDPOINT" default:"false"`
+	// SuspendJobsOnPause suspends the jobs of running loadtests when the reconciliation is paused,
+	// they are resumed once it is resumed
+	SuspendJobsOnPause bool `envconfig:"SUSPEND_JOBS_ON_PAUSE" default:"false"`
+	// SuspendJobsOnShutdown suspends the jobs of running loadtests when the controller shuts down,
+	// they are resumed once a controller syncs them again
+	SuspendJobsOnShutdown bool `envconfig:"SUSPEND_JOBS_ON_SHUTDOWN" default:"false"`
+
+	// ShutdownDrainTimeout is the time limit for processing the loadtests left in the work queue on
+	// shutdown before informers are stopped, 0 shuts down the work queue without draining it
+	ShutdownDrainTimeout time.Duration `envconfig:"SHUTDOWN_DRAIN_TIMEOUT" default:"0s"`
+	// ShutdownWorkerTimeout is the time limit for the workers to finish syncing their current loadtest once the
+	// work queue is shut down or drained, 0 exits without waiting for them
+	ShutdownWorkerTimeout time.Duration `envconfig:"SHUTDOWN_WORKER_TIMEOUT" default:"10s"`
+
+	// ExistingNamespaces are the namespaces loadtests may run in with spec.existingNamespace, instead of a
+	// namespace created for them, empty disables running loadtests in existing namespaces
+	ExistingNamespaces []string `envconfig:"EXISTING_NAMESPACES"`
+
+	// VerifyNamespace checks that the namespace in the status of a loadtest still exists before syncing it,
+	// and recreates the namespace if it was deleted out-of-band
+	VerifyNamespace bool `envconfig:"VERIFY_NAMESPACE" default:"false"`
+
+	// TestFileFetcherImage is the image of the init container fetching test files from LoadTestSpec.TestFileURL
+	TestFileFetcherImage string `envconfig:"TEST_FILE_FETCHER_IMAGE" default:"curlimages/curl:8.5.0"`
+	// TestFileObjectStorageImage is the rclone image of the init container fetching test files from s3:// and gs:// URLs
+	TestFileObjectStorageImage string `envconfig:"TEST_FILE_OBJECT_STORAGE_IMAGE" default:"rclone/rclone:1.65.2"`
+	// TestFileMaxSize is the maximum size in bytes of test files fetched from TestFileURL, 0 means no limit
+	TestFileMaxSize int64 `envconfig:"TEST_FILE_MAX_SIZE" default:"0"`
+
+	// JobConditions determines the loadtest phase from the conditions of its jobs when they are conclusive,
+	// e.g. a deadline exceeded job, before falling back to the job counters
+	JobConditions bool `envconfig:"JOB_CONDITIONS" default:"true"`
+	// MaxSyncRetries is the number of consecutive failed backend syncs before the loadtest is errored, 0 retries
+	// forever with the rate limiter of the work queue. Failed syncs are retried after SyncRetryBackoff, doubled on
+	// each retry up to SyncRetryMaxBackoff
+	MaxSyncRetries      int32         `envconfig:"MAX_SYNC_RETRIES" default:"0"`
+	SyncRetryBackoff    time.Duration `envconfig:"SYNC_RETRY_BACKOFF" default:"5s"`
+	SyncRetryMaxBackoff time.Duration `envconfig:"SYNC_RETRY_MAX_BACKOFF" default:"5m"`
+
+	// JobBackoffLimit is the number of retries of failed loadtest job pods before the loadtest is errored,
+	// a negative value keeps the backend defaults
+	JobBackoffLimit int32 `envconfig:"JOB_BACKOFF_LIMIT" default:"-1"`
+	// DefaultJobTimeout is the time limit of loadtest jobs without Timeout, Kubernetes kills the jobs and the
+	// loadtest is errored once exceeded, 0 means no limit
+	DefaultJobTimeout time.Duration `envconfig:"DEFAULT_JOB_TIMEOUT" default:"0s"`
+
+	// SeccompProfileType is the seccomp profile set in the security context of loadtest pods, one of RuntimeDefault,
+	// Localhost or Unconfined, empty sets none. SeccompLocalhostProfile is the profile path of the Localhost type,
+	// relative to the seccomp directory of the kubelet
+	SeccompProfileType      string `envconfig:"SECCOMP_PROFILE_TYPE" default:""`
+	SeccompLocalhostProfile string `envconfig:"SECCOMP_LOCALHOST_PROFILE" default:""`
+
+	// UnsupportedFieldsWarning emits a Warning Event and sets the UnsupportedFields condition on loadtests
+	// setting spec fields their backend ignores
+	UnsupportedFieldsWarning bool `envconfig:"UNSUPPORTED_FIELDS_WARNING" default:"true"`
+
+	// IdleScaleDown stops the pod informer while no loadtest is active, so a controller waiting for loadtests
+	// doesn't watch and cache the pods of the whole cluster, it is started again once a loadtest is active
+	IdleScaleDown bool `envconfig:"IDLE_SCALE_DOWN" default:"false"`
+	// IdleCheckInterval is how often the controller checks whether any loadtest is active with IdleScaleDown
+	IdleCheckInterval time.Duration `envconfig:"IDLE_CHECK_INTERVAL" default:"1m"`
+
+	// SyncErrorLogInterval logs a sync error repeating for a loadtest once per interval with its number of
+	// repeats instead of on every retry, 0 logs every sync error
+	SyncErrorLogInterval time.Duration `envconfig:"SYNC_ERROR_LOG_INTERVAL" default:"0s"`
+
+	// MaxLoadTestPods, MaxLoadTestCPU and MaxLoadTestMemory are the resource budget of a single loadtest, loadtests
+	// whose pods request more are errored, 0 or empty values are not limited
+	MaxLoadTestPods   int32  `envconfig:"MAX_LOADTEST_PODS" default:"0"`
+	MaxLoadTestCPU    string `envconfig:"MAX_LOADTEST_CPU"`
+	MaxLoadTestMemory string `envconfig:"MAX_LOADTEST_MEMORY"`
+
+	// Workers is the number of loadtests reconciled concurrently
+	Workers int `envconfig:"WORKERS" default:"1"`
+
+	// HighPriorityWeight is the number of high priority loadtests processed for every
+	// normal priority one when both are waiting in the work queue
+	HighPriorityWeight int `envconfig:"HIGH_PRIORITY_WEIGHT" default:"4"`
+
+	// FlappingThreshold is the number of oscillations of a loadtest within FlappingWindow that is reported as
+	// flapping, i.e. transitions back to a phase seen within the window or out of finished or errored phase.
+	// 0 disables flapping detection
+	FlappingThreshold int           `envconfig:"FLAPPING_THRESHOLD" default:"4"`
+	FlappingWindow    time.Duration `envconfig:"FLAPPING_WINDOW" default:"5m"`
+
+	// ImagePullCheck errors loadtests whose pods fail to pull their image permanently, transient failures of
+	// pods controlled by a Job are retried up to ImagePullRetries times per loadtest by recreating the pod
+	ImagePullCheck   bool `envconfig:"IMAGE_PULL_CHECK" default:"false"`
+	ImagePullRetries int  `envconfig:"IMAGE_PULL_RETRIES" default:"3"`
+
+	// ImageReferenceCheck rejects loadtests whose master or worker image is not a valid image reference
+	// before any of their resources are created
+	ImageReferenceCheck bool `envconfig:"IMAGE_REFERENCE_CHECK" default:"false"`
+
+	// QuotaCheck errors loadtests whose pods are rejected because they exceed a ResourceQuota of the loadtest namespace
+	QuotaCheck bool `envconfig:"QUOTA_CHECK" default:"false"`
+	// QuotaCheckGracePeriod is how long a Job of a loadtest can miss pods before a quota rejection errors the
+	// loadtest, so pods rejected once and created on retry don't
+	QuotaCheckGracePeriod time.Duration `envconfig:"QUOTA_CHECK_GRACE_PERIOD" default:"1m"`
+
+	// EvictionCheck moves loadtests to errored phase when one of their pods was evicted because of node pressure,
+	// naming the node and the resource in the Evicted condition of the loadtest
+	EvictionCheck bool `envconfig:"EVICTION_CHECK" default:"false"`
+
+	// SpecDriftCheck sets the SpecDrift condition of running loadtests whose spec changed after their jobs were
+	// created, SpecDriftError moves them to errored phase to force a clean re-run
+	SpecDriftCheck bool `envconfig:"SPEC_DRIFT_CHECK" default:"false"`
+	SpecDriftError bool `envconfig:"SPEC_DRIFT_ERROR" default:"false"`
+
+	// NamespacePhaseAnnotation and NamespaceCompletionTimeAnnotation are the annotations set on the namespace
+	// of a loadtest when it reaches a terminal phase, an empty key disables the annotation
+	NamespacePhaseAnnotation          string `envconfig:"NAMESPACE_PHASE_ANNOTATION" default:"kangal.hellofresh.com/phase"`
+	NamespaceCompletionTimeAnnotation string `envconfig:"NAMESPACE_COMPLETION_TIME_ANNOTATION" default:"kangal.hellofresh.com/completion-time"`
+
+	// LongRunningThreshold is the time a loadtest may run before it is labeled with LongRunningLabel,
+	// 0 disables the label
+	LongRunningThreshold time.Duration `envconfig:"LONG_RUNNING_THRESHOLD" default:"0s"`
+	LongRunningLabel     string        `envconfig:"LONG_RUNNING_LABEL" default:"kangal.hellofresh.com/long-running"`
+
+	// StatusMirrorNamespace is the namespace the status of each loadtest is mirrored to as a ConfigMap
+	// named after the loadtest, for tools that can't watch LoadTest resources, empty disables the mirror
+	StatusMirrorNamespace string `envconfig:"STATUS_MIRROR_NAMESPACE"`
+
+	// NodeName and NodeZone of the controller pod are added to the resource attributes of the
+	// controller metrics, both are expected to be set from the downward API
+	NodeName string `envconfig:"NODE_NAME"`
+	NodeZone string `envconfig:"NODE_ZONE"`
+
+	// TraceExporter exports a trace of each loadtest reconcile, either "otlp" or "none". The OTLP exporter is
+	// configured with the standard OTEL_EXPORTER_OTLP_* env vars
+	TraceExporter string `envconfig:"TRACE_EXPORTER" default:"none"`
+
+	// NamespaceLabelTags are the keys of loadtest tags added as labels to the loadtest namespace, e.g. for cost
+	// allocation, NamespaceLabels take precedence over them
+	NamespaceLabelTags []string `envconfig:"NAMESPACE_LABEL_TAGS"`
+	// PropagateTagsAsLabels adds all loadtest tags, sanitized to valid label syntax, as labels to the loadtest
+	// namespace and to the jobs and pods of backends supporting it. Labels set by Kangal take precedence over them
+	PropagateTagsAsLabels bool `envconfig:"PROPAGATE_TAGS_AS_LABELS" default:"false"`
+
+	// CompletionSummaryLog logs a single structured line per loadtest once it is finished or errored, with its
+	// backend, phase, duration, report URL and summary metrics, for environments without a metrics pipeline
+	CompletionSummaryLog bool `envconfig:"COMPLETION_SUMMARY_LOG" default:"false"`
+
+	// ReportGroupTag is the key of the loadtest tag grouping loadtests, e.g. the suite of a release candidate. Each
+	// loadtest of a group is listed with its report in the index of the group once it is finished or errored, the
+	// index is kept by the proxy. An empty key disables the group indexes
+	ReportGroupTag string `envconfig:"REPORT_GROUP_TAG" default:""`
+
+	// BackendInitTimeout is the time each backend has to initialize at startup, backends are initialized in
+	// parallel. OptionalBackends are the types of the backends skipped with a warning when they fail to
+	// initialize, any other backend failing to initialize fails the controller startup
+	BackendInitTimeout time.Duration `envconfig:"BACKEND_INIT_TIMEOUT" default:"30s"`
+	OptionalBackends   []string      `envconfig:"OPTIONAL_BACKENDS"`
+
+	// ImagePullSecrets are the names of the secrets used to pull the images of loadtest pods, loadtests can
+	// replace them with their own. They are copied to each loadtest namespace from ImagePullSecretsNamespace,
+	// usually the namespace of the controller, when it is set. Secrets of loadtests that are not listed here are
+	// never copied and must exist in the loadtest namespace
+	ImagePullSecrets          []string `envconfig:"IMAGE_PULL_SECRETS"`
+	ImagePullSecretsNamespace string   `envconfig:"IMAGE_PULL_SECRETS_NAMESPACE"`
+
+	// PodServiceAccount is the name of the service account loadtest pods run with, loadtests can replace it with
+	// their own. It is created in the namespaces created for loadtests with PodServiceAccountAnnotations, e.g. the
+	// IAM role of IRSA, when missing there. An empty name keeps the namespace default service account
+	PodServiceAccount            string            `envconfig:"POD_SERVICE_ACCOUNT"`
+	PodServiceAccountAnnotations map[string]string `envconfig:"POD_SERVICE_ACCOUNT_ANNOTATIONS"`
+
 	MasterURL            string
 	KubeConfig           string
 	NamespaceLabels      map[string]string
@@ -32,4 +297,7 @@ type Config struct {
 	PodAnnotations       map[string]string
 	NodeSelectors        map[string]string
 	Tolerations          kubernetes.Tolerations
+	TestFileCredentials  map[string]string
+	ResourceBudget       backends.ResourceBudget `ignored:"true"`
+	SeccompProfile       *coreV1.SeccompProfile  `ignored:"true"`
 }
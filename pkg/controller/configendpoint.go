@@ -0,0 +1,63 @@
+package controller
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/go-chi/render"
+	"go.uber.org/zap"
+)
+
+// redacted replaces the secrets of the configuration returned by the config endpoint
+const redacted = "REDACTED"
+
+// RedactedConfig returns a copy of the configuration without its secrets: the debug endpoint token and the values
+// of the test file credentials are replaced, and so is the user info of the URLs, which may hold credentials
+func RedactedConfig(cfg Config) Config {
+	if cfg.DebugEndpointToken != "" {
+		cfg.DebugEndpointToken = redacted
+	}
+	if cfg.TestFileCredentials != nil {
+		credentials := make(map[string]string, len(cfg.TestFileCredentials))
+		for key := range cfg.TestFileCredentials {
+			credentials[key] = redacted
+		}
+		cfg.TestFileCredentials = credentials
+	}
+	cfg.KangalProxyURL = redactURL(cfg.KangalProxyURL)
+	cfg.ReportStorageLocation = redactURL(cfg.ReportStorageLocation)
+	cfg.MasterURL = redactURL(cfg.MasterURL)
+	return cfg
+}
+
+// redactURL replaces the user info of the URL, URLs that can't be parsed are redacted entirely
+func redactURL(rawURL string) string {
+	if rawURL == "" {
+		return rawURL
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return redacted
+	}
+	if u.User == nil {
+		return rawURL
+	}
+	u.User = url.User(redacted)
+	return u.String()
+}
+
+// ConfigHandler returns the effective configuration of the controller, from env vars, flags and defaults, with its
+// secrets redacted. Requests must carry the given token as bearer token.
+func ConfigHandler(cfg Config, token string, logger *zap.Logger) func(w http.ResponseWriter, r *http.Request) {
+	redactedCfg := RedactedConfig(cfg)
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !authorized(r, token) {
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+
+		logger.Info("Controller config requested", zap.String("remote", r.RemoteAddr))
+		render.JSON(w, r, redactedCfg)
+	}
+}
@@ -1,7 +1,12 @@
 package controller
 
 import (
+	"context"
 	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 
 	"go.uber.org/zap"
 	kubeInformers "k8s.io/client-go/informers"
@@ -26,7 +31,30 @@ type Runner struct {
 
 // Run runs an instance of kubernetes kubeController
 func Run(cfg Config, rr Runner) error {
+	if cfg.Workers < 1 {
+		return fmt.Errorf("number of workers must be at least 1, got %d", cfg.Workers)
+	}
+	if err := verifyProxy(cfg, http.DefaultClient, rr.Logger); err != nil {
+		return fmt.Errorf("could not verify kangal proxy: %w", err)
+	}
+	if err := verifyCleanUpThreshold(cfg, rr.Logger); err != nil {
+		return fmt.Errorf("could not verify cleanup threshold: %w", err)
+	}
+	verifyScheduling(cfg, rr.KubeClient, rr.Logger)
+	statusViaUpdate, err := verifyStatusSubresource(cfg, rr.KangalClient.Discovery(), rr.Logger)
+	if err != nil {
+		return fmt.Errorf("could not verify loadtest status subresource: %w", err)
+	}
+
+	// stopCh stops informers, it is closed only after the controller stopped processing
+	// loadtests so the work queue can be drained with informer caches still running
 	stopCh := make(chan struct{})
+	defer close(stopCh)
+
+	// shutdownCh is closed on termination signal or metrics server failure
+	shutdownCh := make(chan struct{})
+	serverStopCh := make(chan struct{})
+	go waitForShutdown(rr.Logger, serverStopCh, shutdownCh)
 
 	registry := backends.New(
 		backends.WithLogger(rr.Logger),
@@ -36,21 +64,55 @@ func Run(cfg Config, rr Runner) error {
 		backends.WithPodAnnotations(cfg.PodAnnotations),
 		backends.WithNodeSelector(cfg.NodeSelectors),
 		backends.WithTolerations(cfg.Tolerations.KubeToleration()),
+		backends.WithJobConditions(cfg.JobConditions),
+		backends.WithJobBackoffLimit(cfg.JobBackoffLimit),
+		backends.WithJobTimeout(cfg.DefaultJobTimeout),
+		backends.WithSeccompProfile(cfg.SeccompProfile),
+		backends.WithImagePullSecrets(cfg.ImagePullSecrets),
+		backends.WithPodServiceAccount(cfg.PodServiceAccount),
+		backends.WithTagLabels(cfg.PropagateTagsAsLabels),
+		backends.WithTestFileFetcher(backends.TestFileFetcher{
+			Image:              cfg.TestFileFetcherImage,
+			ObjectStorageImage: cfg.TestFileObjectStorageImage,
+			MaxSize:            cfg.TestFileMaxSize,
+		}),
 	)
 
+	if err := registry.Init(context.Background(), backends.InitConfig{
+		Timeout:  cfg.BackendInitTimeout,
+		Optional: cfg.OptionalBackends,
+	}); err != nil {
+		return fmt.Errorf("could not initialise backends: %w", err)
+	}
+
 	c := NewController(cfg, rr.KubeClient, rr.KangalClient, rr.KubeInformer, rr.KangalInformer, *rr.StatsReporter, registry, rr.Logger)
+	c.statusViaUpdate = statusViaUpdate
 
 	// notice that there is no need to run Start methods in a separate goroutine. (i.e. go kubeInformerFactory.Start(stopCh)
 	// Start method is non-blocking and runs all registered informers in a dedicated goroutine.
 	rr.KangalInformer.Start(stopCh)
 	rr.KubeInformer.Start(stopCh)
 
-	if err := RunMetricsServer(cfg, rr, stopCh); err != nil {
+	if err := RunMetricsServer(cfg, rr, c, c, c, registry, serverStopCh); err != nil {
 		return fmt.Errorf("could not initialise Metrics Server: %w", err)
 	}
 
-	if err := c.Run(1, stopCh); err != nil {
+	if err := c.Run(cfg.Workers, shutdownCh); err != nil {
 		return fmt.Errorf("error running kubeController: %w", err)
 	}
 	return nil
 }
+
+// waitForShutdown closes shutdownCh on SIGTERM or SIGINT, or when serverStopCh is closed
+func waitForShutdown(logger *zap.Logger, serverStopCh <-chan struct{}, shutdownCh chan<- struct{}) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGTERM, os.Interrupt)
+	defer signal.Stop(signals)
+
+	select {
+	case sig := <-signals:
+		logger.Info("Received termination signal", zap.String("signal", sig.String()))
+	case <-serverStopCh:
+	}
+	close(shutdownCh)
+}
@@ -0,0 +1,150 @@
+package controller
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/errors"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+// LoadTestDebugger returns the view of the controller on a single loadtest
+type LoadTestDebugger interface {
+	DebugLoadTest(name string) (*LoadTestDebug, error)
+}
+
+// LoadTestDebug is the response of the loadtest debug endpoint
+type LoadTestDebug struct {
+	Name            string                    `json:"name"`
+	ResourceVersion string                    `json:"resourceVersion"`
+	Generation      int64                     `json:"generation"`
+	Status          loadTestV1.LoadTestStatus `json:"status"`
+
+	LastReconciled        *time.Time `json:"lastReconciled,omitempty"`
+	LastReconcileDuration string     `json:"lastReconcileDuration,omitempty"`
+	LastError             string     `json:"lastError,omitempty"`
+	Requeues              int        `json:"requeues"`
+	Paused                bool       `json:"paused"`
+
+	Backend     loadTestV1.LoadTestType `json:"backend"`
+	MasterImage string                  `json:"masterImage,omitempty"`
+	WorkerImage string                  `json:"workerImage,omitempty"`
+	ReportURL   string                  `json:"reportURL,omitempty"`
+}
+
+// reconcile is the outcome of the last reconciliation of a LoadTest
+type reconcile struct {
+	time     time.Time
+	duration time.Duration
+	err      error
+}
+
+// reconcileHistory keeps the last reconciliation of each LoadTest key
+type reconcileHistory struct {
+	mu         sync.Mutex
+	reconciles map[string]reconcile
+}
+
+func newReconcileHistory() *reconcileHistory {
+	return &reconcileHistory{reconciles: map[string]reconcile{}}
+}
+
+// record records the reconciliation of the given LoadTest key started at start
+func (h *reconcileHistory) record(key string, start time.Time, err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.reconciles[key] = reconcile{time: start, duration: time.Since(start), err: err}
+}
+
+// last returns the last reconciliation of the given LoadTest key, ok is false if it was not reconciled yet
+func (h *reconcileHistory) last(key string) (r reconcile, ok bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	r, ok = h.reconciles[key]
+	return r, ok
+}
+
+// forget drops the reconciliations of the given LoadTest key
+func (h *reconcileHistory) forget(key string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	delete(h.reconciles, key)
+}
+
+// DebugLoadTest assembles the cached status of the loadtest of the given name with the tracking of its
+// reconciliation and the configuration resolved for it
+func (c *Controller) DebugLoadTest(name string) (*LoadTestDebug, error) {
+	loadTest, err := c.loadtestsLister.Get(name)
+	if err != nil {
+		return nil, err
+	}
+
+	debug := &LoadTestDebug{
+		Name:            loadTest.GetName(),
+		ResourceVersion: loadTest.GetResourceVersion(),
+		Generation:      loadTest.GetGeneration(),
+		Status:          loadTest.Status,
+		Requeues:        c.workQueue.NumRequeues(name),
+		Paused:          c.Paused(),
+		Backend:         loadTest.Spec.Type,
+		MasterImage:     string(loadTest.Spec.MasterConfig),
+		WorkerImage:     string(loadTest.Spec.WorkerConfig),
+		ReportURL:       c.reportURL(loadTest),
+	}
+	if r, ok := c.reconciles.last(name); ok {
+		debug.LastReconciled = &r.time
+		debug.LastReconcileDuration = r.duration.String()
+		if r.err != nil {
+			debug.LastError = r.err.Error()
+		}
+	}
+	return debug, nil
+}
+
+// DebugHandler returns the view of the controller on the loadtest of the URL. LoadTests are cluster scoped,
+// the namespace of the URL is the namespace of the loadtest, it must match the one in its status when given.
+// Requests must carry the given token as bearer token.
+func DebugHandler(debugger LoadTestDebugger, token string, logger *zap.Logger) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !authorized(r, token) {
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+
+		name := chi.URLParam(r, "name")
+		debug, err := debugger.DebugLoadTest(name)
+		if errors.IsNotFound(err) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			logger.Error("Failed to debug loadtest", zap.String("loadtest", name), zap.Error(err))
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		if namespace := chi.URLParam(r, "namespace"); namespace != "" && namespace != debug.Status.Namespace {
+			http.Error(w, "loadtest does not run in namespace "+namespace, http.StatusNotFound)
+			return
+		}
+
+		logger.Info("Loadtest debug requested", zap.String("loadtest", name), zap.String("remote", r.RemoteAddr))
+		render.JSON(w, r, debug)
+	}
+}
+
+// authorized tells whether the request carries the given token as bearer token
+func authorized(r *http.Request, token string) bool {
+	bearer, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && subtle.ConstantTimeCompare([]byte(bearer), []byte(token)) == 1
+}
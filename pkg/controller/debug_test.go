@@ -0,0 +1,83 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+	listers "github.com/hellofresh/kangal/pkg/kubernetes/generated/listers/loadtest/v1"
+)
+
+func TestDebugHandler(t *testing.T) {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	require.NoError(t, indexer.Add(&loadTestV1.LoadTest{
+		ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-name", ResourceVersion: "42"},
+		Spec:       loadTestV1.LoadTestSpec{Type: loadTestV1.LoadTestTypeK6, MasterConfig: "grafana/k6:latest"},
+		Status:     loadTestV1.LoadTestStatus{Phase: loadTestV1.LoadTestRunning, Namespace: "loadtest-namespace"},
+	}))
+
+	c := &Controller{
+		cfg:             Config{KangalProxyURL: "http://kangal-proxy"},
+		loadtestsLister: listers.NewLoadTestLister(indexer),
+		workQueue: newPriorityQueue("test", 1, func(interface{}) priority {
+			return priorityNormal
+		}),
+		reconciles: newReconcileHistory(),
+		logger:     zaptest.NewLogger(t),
+	}
+	defer c.workQueue.ShutDown()
+	c.reconciles.record("loadtest-name", time.Now(), errors.New("sync failed"))
+
+	r := chi.NewRouter()
+	r.Get("/debug/loadtest/{name}", DebugHandler(c, "secret", zaptest.NewLogger(t)))
+	r.Get("/debug/loadtest/{namespace}/{name}", DebugHandler(c, "secret", zaptest.NewLogger(t)))
+
+	for _, tt := range []struct {
+		name       string
+		path       string
+		token      string
+		expectCode int
+	}{
+		{name: "no token", path: "/debug/loadtest/loadtest-namespace/loadtest-name", expectCode: http.StatusUnauthorized},
+		{name: "wrong token", path: "/debug/loadtest/loadtest-namespace/loadtest-name", token: "guess", expectCode: http.StatusUnauthorized},
+		{name: "unknown loadtest", path: "/debug/loadtest/loadtest-namespace/other", token: "secret", expectCode: http.StatusNotFound},
+		{name: "other namespace", path: "/debug/loadtest/other-namespace/loadtest-name", token: "secret", expectCode: http.StatusNotFound},
+		{name: "with namespace", path: "/debug/loadtest/loadtest-namespace/loadtest-name", token: "secret", expectCode: http.StatusOK},
+		{name: "without namespace", path: "/debug/loadtest/loadtest-name", token: "secret", expectCode: http.StatusOK},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			require.Equal(t, tt.expectCode, w.Code)
+			if tt.expectCode != http.StatusOK {
+				return
+			}
+
+			var debug LoadTestDebug
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &debug))
+			assert.Equal(t, "42", debug.ResourceVersion)
+			assert.Equal(t, loadTestV1.LoadTestRunning, debug.Status.Phase)
+			assert.Equal(t, loadTestV1.LoadTestTypeK6, debug.Backend)
+			assert.Equal(t, "grafana/k6:latest", debug.MasterImage)
+			assert.Equal(t, "http://kangal-proxy/load-test/loadtest-name/report", debug.ReportURL)
+			assert.Equal(t, "sync failed", debug.LastError)
+			assert.NotNil(t, debug.LastReconciled)
+		})
+	}
+}
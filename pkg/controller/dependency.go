@@ -0,0 +1,87 @@
+package controller
+
+import (
+	"fmt"
+
+	"go.uber.org/zap"
+	coreV1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+// checkDependency holds back loadtests without namespace in the waiting phase until the loadtest they depend on
+// is finished, waiting loadtests are synced again after DependencyRetryInterval. Loadtests whose dependency
+// errored, doesn't exist or depends on them in turn are errored. It returns false if the loadtest was held back
+// or errored.
+func (c *Controller) checkDependency(key string, loadTest *loadTestV1.LoadTest) bool {
+	if loadTest.Spec.DependsOn == "" || loadTest.Status.Namespace != "" {
+		return true
+	}
+
+	if loadTest.Status.Phase == loadTestV1.LoadTestErrored {
+		return false
+	}
+
+	dependency, err := c.dependency(loadTest)
+	if err == nil && dependency.Status.Phase == loadTestV1.LoadTestErrored {
+		err = fmt.Errorf("loadtest %q it depends on errored", dependency.GetName())
+	}
+	if err != nil {
+		c.logger.Info("Rejecting loadtest with failed dependency",
+			zap.String("loadtest", loadTest.GetName()),
+			zap.Error(err),
+		)
+		c.recorder.Event(loadTest, coreV1.EventTypeWarning, reasonDependencyFailed, err.Error())
+		setErrored(loadTest, reasonDependencyFailed, err.Error())
+		return false
+	}
+
+	if dependency.Status.Phase == loadTestV1.LoadTestFinished {
+		if loadTest.Status.Phase == loadTestV1.LoadTestWaiting {
+			loadTest.Status.Phase = loadTestV1.LoadTestCreating
+		}
+		return true
+	}
+
+	if loadTest.Status.Phase != loadTestV1.LoadTestWaiting {
+		c.logger.Info("Holding back loadtest until the loadtest it depends on is finished",
+			zap.String("loadtest", loadTest.GetName()),
+			zap.String("dependency", dependency.GetName()),
+		)
+		c.recorder.Eventf(loadTest, coreV1.EventTypeNormal, reasonDependencyWaiting,
+			"Waiting for loadtest %q to finish", dependency.GetName())
+	}
+	loadTest.Status.Phase = loadTestV1.LoadTestWaiting
+	c.workQueue.AddAfter(key, c.cfg.DependencyRetryInterval)
+
+	return false
+}
+
+// dependency returns the loadtest the given loadtest depends on, it fails if the loadtest doesn't exist or the
+// dependencies of the given loadtest lead back to it
+func (c *Controller) dependency(loadTest *loadTestV1.LoadTest) (*loadTestV1.LoadTest, error) {
+	dependency, err := c.loadtestsLister.Get(loadTest.Spec.DependsOn)
+	if errors.IsNotFound(err) {
+		return nil, fmt.Errorf("loadtest %q it depends on does not exist", loadTest.Spec.DependsOn)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[string]bool{loadTest.GetName(): true}
+	for current := dependency; current.Spec.DependsOn != ""; {
+		seen[current.GetName()] = true
+		if seen[current.Spec.DependsOn] {
+			return nil, fmt.Errorf("dependency cycle through loadtest %q", current.Spec.DependsOn)
+		}
+
+		// a missing dependency further down the chain errors the loadtest depending on it
+		current, err = c.loadtestsLister.Get(current.Spec.DependsOn)
+		if err != nil {
+			break
+		}
+	}
+
+	return dependency, nil
+}
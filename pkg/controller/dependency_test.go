@@ -0,0 +1,132 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+	listers "github.com/hellofresh/kangal/pkg/kubernetes/generated/listers/loadtest/v1"
+)
+
+func TestCheckDependency(t *testing.T) {
+	newLoadTest := func(name, dependsOn string, phase loadTestV1.LoadTestPhase) *loadTestV1.LoadTest {
+		return &loadTestV1.LoadTest{
+			ObjectMeta: metaV1.ObjectMeta{Name: name},
+			Spec:       loadTestV1.LoadTestSpec{DependsOn: dependsOn},
+			Status:     loadTestV1.LoadTestStatus{Phase: phase},
+		}
+	}
+
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for _, loadTest := range []*loadTestV1.LoadTest{
+		newLoadTest("finished", "", loadTestV1.LoadTestFinished),
+		newLoadTest("running", "", loadTestV1.LoadTestRunning),
+		newLoadTest("errored", "", loadTestV1.LoadTestErrored),
+		newLoadTest("cycle-a", "cycle-b", loadTestV1.LoadTestWaiting),
+		newLoadTest("cycle-b", "cycle-a", loadTestV1.LoadTestWaiting),
+		newLoadTest("dangling", "deleted", loadTestV1.LoadTestWaiting),
+	} {
+		require.NoError(t, indexer.Add(loadTest))
+	}
+
+	for _, tt := range []struct {
+		name          string
+		loadTest      *loadTestV1.LoadTest
+		expectedReady bool
+		expectedPhase loadTestV1.LoadTestPhase
+		expectedEvent string
+		expectedQueue int
+	}{
+		{
+			name:          "no dependency",
+			loadTest:      newLoadTest("test", "", loadTestV1.LoadTestCreating),
+			expectedReady: true,
+			expectedPhase: loadTestV1.LoadTestCreating,
+		},
+		{
+			name:          "dependency finished",
+			loadTest:      newLoadTest("test", "finished", loadTestV1.LoadTestWaiting),
+			expectedReady: true,
+			expectedPhase: loadTestV1.LoadTestCreating,
+		},
+		{
+			name:          "dependency running",
+			loadTest:      newLoadTest("test", "running", loadTestV1.LoadTestCreating),
+			expectedPhase: loadTestV1.LoadTestWaiting,
+			expectedEvent: reasonDependencyWaiting,
+			expectedQueue: 1,
+		},
+		{
+			name:          "still waiting",
+			loadTest:      newLoadTest("test", "running", loadTestV1.LoadTestWaiting),
+			expectedPhase: loadTestV1.LoadTestWaiting,
+			expectedQueue: 1,
+		},
+		{
+			name:          "dependency errored",
+			loadTest:      newLoadTest("test", "errored", loadTestV1.LoadTestWaiting),
+			expectedPhase: loadTestV1.LoadTestErrored,
+			expectedEvent: reasonDependencyFailed,
+		},
+		{
+			name:          "dependency deleted",
+			loadTest:      newLoadTest("test", "deleted", loadTestV1.LoadTestWaiting),
+			expectedPhase: loadTestV1.LoadTestErrored,
+			expectedEvent: reasonDependencyFailed,
+		},
+		{
+			name:          "self dependency",
+			loadTest:      newLoadTest("test", "test", loadTestV1.LoadTestCreating),
+			expectedPhase: loadTestV1.LoadTestErrored,
+			expectedEvent: reasonDependencyFailed,
+		},
+		{
+			name:          "dependency cycle",
+			loadTest:      newLoadTest("cycle-a", "cycle-b", loadTestV1.LoadTestWaiting),
+			expectedPhase: loadTestV1.LoadTestErrored,
+			expectedEvent: reasonDependencyFailed,
+		},
+		{
+			name:          "missing dependency down the chain",
+			loadTest:      newLoadTest("test", "dangling", loadTestV1.LoadTestCreating),
+			expectedPhase: loadTestV1.LoadTestWaiting,
+			expectedEvent: reasonDependencyWaiting,
+			expectedQueue: 1,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := record.NewFakeRecorder(10)
+			c := &Controller{
+				cfg:             Config{DependencyRetryInterval: time.Millisecond},
+				loadtestsLister: listers.NewLoadTestLister(indexer),
+				workQueue: newPriorityQueue("test", 1, func(interface{}) priority {
+					return priorityNormal
+				}),
+				recorder: recorder,
+				logger:   zaptest.NewLogger(t),
+			}
+			defer c.workQueue.ShutDown()
+
+			assert.Equal(t, tt.expectedReady, c.checkDependency(tt.loadTest.GetName(), tt.loadTest))
+			assert.Equal(t, tt.expectedPhase, tt.loadTest.Status.Phase)
+
+			if tt.expectedEvent == "" {
+				assert.Empty(t, recorder.Events)
+			} else {
+				require.Len(t, recorder.Events, 1)
+				assert.Contains(t, <-recorder.Events, tt.expectedEvent)
+			}
+
+			assert.Eventually(t, func() bool {
+				return c.workQueue.Len() == tt.expectedQueue
+			}, time.Second, time.Millisecond)
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package controller
+
+import (
+	"go.uber.org/zap"
+	coreV1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/hellofresh/kangal/pkg/backends"
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+// withSpecHash returns the loadtest handed to the backend annotated with the hash of the spec of the loadtest, so
+// the backend stamps the Jobs it creates with the spec they were created from. The hash is taken from the spec
+// before a templated test file is rendered, like the one compared by checkSpecDrift.
+func (c *Controller) withSpecHash(loadTest, syncLoadTest *loadTestV1.LoadTest) (*loadTestV1.LoadTest, error) {
+	if !c.cfg.SpecDriftCheck {
+		return syncLoadTest, nil
+	}
+
+	hash, err := loadTestV1.SpecHash(loadTest.Spec)
+	if err != nil {
+		return nil, err
+	}
+
+	annotated := syncLoadTest.DeepCopy()
+	if annotated.Annotations == nil {
+		annotated.Annotations = map[string]string{}
+	}
+	annotated.Annotations[backends.SpecHashAnnotation] = hash
+	return annotated, nil
+}
+
+// checkSpecDrift detects changes of the spec of a loadtest after its Jobs were created, which backends can't apply
+// to the immutable Jobs. Backends annotate the Jobs with the spec hash when they create them, a Job with another
+// hash sets the SpecDrift condition and, with SpecDriftError, moves the loadtest to errored phase. Jobs without
+// hash, e.g. created before the check was enabled, are not checked.
+func (c *Controller) checkSpecDrift(loadTest *loadTestV1.LoadTest) error {
+	if !c.cfg.SpecDriftCheck || loadTest.Status.Namespace == "" {
+		return nil
+	}
+	if loadTest.Status.Phase == loadTestV1.LoadTestFinished || loadTest.Status.Phase == loadTestV1.LoadTestErrored {
+		return nil
+	}
+
+	hash, err := loadTestV1.SpecHash(loadTest.Spec)
+	if err != nil {
+		return err
+	}
+
+	jobs, err := c.loadTestJobs(loadTest)
+	if err != nil {
+		return err
+	}
+
+	drifted := false
+	for _, job := range jobs {
+		stored, ok := job.GetAnnotations()[backends.SpecHashAnnotation]
+		if ok && stored != hash {
+			drifted = true
+		}
+	}
+
+	current := meta.FindStatusCondition(loadTest.Status.Conditions, loadTestV1.LoadTestConditionSpecDrift)
+	if !drifted {
+		// the spec may be changed back to the one the jobs were created from
+		if current != nil && current.Status == metaV1.ConditionTrue {
+			meta.SetStatusCondition(&loadTest.Status.Conditions, metaV1.Condition{
+				Type:               loadTestV1.LoadTestConditionSpecDrift,
+				Status:             metaV1.ConditionFalse,
+				Reason:             loadTestV1.LoadTestSpecInSync,
+				Message:            "Loadtest jobs were created from the current spec",
+				ObservedGeneration: loadTest.GetGeneration(),
+			})
+		}
+		return nil
+	}
+
+	message := "Spec changed after the loadtest jobs were created, the running loadtest doesn't reflect the current spec"
+	if current == nil || current.Status != metaV1.ConditionTrue {
+		c.logger.Warn("Loadtest spec changed while running",
+			zap.String("loadtest", loadTest.GetName()),
+			zap.Bool("error", c.cfg.SpecDriftError),
+		)
+		c.recorder.Event(loadTest, coreV1.EventTypeWarning, reasonSpecDrift, message)
+	}
+	meta.SetStatusCondition(&loadTest.Status.Conditions, metaV1.Condition{
+		Type:               loadTestV1.LoadTestConditionSpecDrift,
+		Status:             metaV1.ConditionTrue,
+		Reason:             loadTestV1.LoadTestSpecChanged,
+		Message:            message,
+		ObservedGeneration: loadTest.GetGeneration(),
+	})
+
+	if c.cfg.SpecDriftError {
+		setErrored(loadTest, reasonSpecDrift, message)
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+	batchV1 "k8s.io/api/batch/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	batchListersV1 "k8s.io/client-go/listers/batch/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/hellofresh/kangal/pkg/backends"
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+func TestCheckSpecDrift(t *testing.T) {
+	spec := loadTestV1.LoadTestSpec{Type: loadTestV1.LoadTestTypeK6, TestFile: []byte("export default function() {}")}
+	hash, err := loadTestV1.SpecHash(spec)
+	require.NoError(t, err)
+
+	newJob := func(annotations map[string]string) *batchV1.Job {
+		return &batchV1.Job{ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-job", Namespace: "loadtest-namespace", Annotations: annotations}}
+	}
+
+	for _, tt := range []struct {
+		name            string
+		job             *batchV1.Job
+		specDriftError  bool
+		expectPhase     loadTestV1.LoadTestPhase
+		expectCondition metaV1.ConditionStatus
+		expectEvents    int
+	}{
+		{
+			name:        "job without spec hash",
+			job:         newJob(nil),
+			expectPhase: loadTestV1.LoadTestRunning,
+		},
+		{
+			name:        "spec unchanged",
+			job:         newJob(map[string]string{backends.SpecHashAnnotation: hash}),
+			expectPhase: loadTestV1.LoadTestRunning,
+		},
+		{
+			name:            "spec changed",
+			job:             newJob(map[string]string{backends.SpecHashAnnotation: "previous-hash"}),
+			expectPhase:     loadTestV1.LoadTestRunning,
+			expectCondition: metaV1.ConditionTrue,
+			expectEvents:    1,
+		},
+		{
+			name:            "spec changed errors the loadtest",
+			job:             newJob(map[string]string{backends.SpecHashAnnotation: "previous-hash"}),
+			specDriftError:  true,
+			expectPhase:     loadTestV1.LoadTestErrored,
+			expectCondition: metaV1.ConditionTrue,
+			expectEvents:    1,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+			require.NoError(t, indexer.Add(tt.job))
+			recorder := record.NewFakeRecorder(10)
+
+			c := &Controller{
+				cfg:        Config{SpecDriftCheck: true, SpecDriftError: tt.specDriftError},
+				jobsLister: batchListersV1.NewJobLister(indexer),
+				recorder:   recorder,
+				logger:     zaptest.NewLogger(t),
+			}
+			loadTest := &loadTestV1.LoadTest{
+				ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-name"},
+				Spec:       spec,
+				Status: loadTestV1.LoadTestStatus{
+					Phase:     loadTestV1.LoadTestRunning,
+					Namespace: "loadtest-namespace",
+				},
+			}
+
+			require.NoError(t, c.checkSpecDrift(loadTest))
+			assert.Equal(t, tt.expectPhase, loadTest.Status.Phase)
+			assert.Len(t, recorder.Events, tt.expectEvents)
+
+			condition := meta.FindStatusCondition(loadTest.Status.Conditions, loadTestV1.LoadTestConditionSpecDrift)
+			if tt.expectCondition == "" {
+				assert.Nil(t, condition)
+			} else {
+				require.NotNil(t, condition)
+				assert.Equal(t, tt.expectCondition, condition.Status)
+			}
+		})
+	}
+}
+
+func TestWithSpecHash(t *testing.T) {
+	loadTest := &loadTestV1.LoadTest{
+		ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-name"},
+		Spec: loadTestV1.LoadTestSpec{
+			Type:             loadTestV1.LoadTestTypeK6,
+			TestFile:         []byte("{{.Name}}"),
+			TemplateTestFile: true,
+		},
+	}
+	hash, err := loadTestV1.SpecHash(loadTest.Spec)
+	require.NoError(t, err)
+
+	// the backend gets the rendered test file, the hash is the one of the spec with the template
+	rendered := loadTest.DeepCopy()
+	rendered.Spec.TestFile = []byte("loadtest-name")
+
+	c := &Controller{cfg: Config{SpecDriftCheck: true}}
+	syncLoadTest, err := c.withSpecHash(loadTest, rendered)
+	require.NoError(t, err)
+	assert.Equal(t, hash, syncLoadTest.Annotations[backends.SpecHashAnnotation])
+	assert.Nil(t, rendered.Annotations)
+
+	c.cfg.SpecDriftCheck = false
+	syncLoadTest, err = c.withSpecHash(loadTest, rendered)
+	require.NoError(t, err)
+	assert.Same(t, rendered, syncLoadTest)
+}
@@ -0,0 +1,97 @@
+package controller
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"go.uber.org/zap"
+	"sigs.k8s.io/yaml"
+)
+
+// dryRunSuccess is the response to requests deleting resources in dry run mode
+var dryRunSuccess = []byte(`{"kind":"Status","apiVersion":"v1","metadata":{},"status":"Success"}`)
+
+// dryRunTransport logs the requests changing the cluster instead of sending them and answers them as if they
+// succeeded, so the controller computes the namespaces, jobs and other resources of loadtests without creating them
+type dryRunTransport struct {
+	next   http.RoundTripper
+	logger *zap.Logger
+	yaml   bool
+}
+
+// DryRunTransport wraps the transport of the kubernetes clients of the controller when DryRun is enabled,
+// requests changing the cluster are logged, as YAML with DryRunYAML, and never sent
+func DryRunTransport(logger *zap.Logger, yaml bool) func(http.RoundTripper) http.RoundTripper {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return &dryRunTransport{next: next, logger: logger, yaml: yaml}
+	}
+}
+
+// RoundTrip sends read requests, and answers requests changing the cluster without sending them: created and
+// updated objects are returned as sent, patched objects are returned unchanged and deletions succeed
+func (t *dryRunTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return t.next.RoundTrip(req)
+	}
+
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	t.logger.Info("Dry run, skipping request",
+		zap.String("method", req.Method),
+		zap.String("path", req.URL.Path),
+		zap.String("body", t.format(body, req.Header.Get("Content-Type"))),
+	)
+
+	switch req.Method {
+	case http.MethodPost:
+		return dryRunResponse(req, http.StatusCreated, req.Header.Get("Content-Type"), body), nil
+	case http.MethodPut:
+		return dryRunResponse(req, http.StatusOK, req.Header.Get("Content-Type"), body), nil
+	case http.MethodPatch:
+		// the patch is not an object, answer with the object as it is
+		get := req.Clone(req.Context())
+		get.Method = http.MethodGet
+		get.Body = nil
+		get.ContentLength = 0
+		get.Header.Del("Content-Type")
+		return t.next.RoundTrip(get)
+	default:
+		return dryRunResponse(req, http.StatusOK, "application/json", dryRunSuccess), nil
+	}
+}
+
+// format returns the body of a request for the log, JSON bodies are converted to YAML when enabled
+func (t *dryRunTransport) format(body []byte, contentType string) string {
+	if t.yaml && strings.HasPrefix(contentType, "application/json") {
+		if converted, err := yaml.JSONToYAML(body); err == nil {
+			return string(converted)
+		}
+	}
+	return string(body)
+}
+
+func dryRunResponse(req *http.Request, status int, contentType string, body []byte) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{contentType}},
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
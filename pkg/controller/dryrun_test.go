@@ -0,0 +1,112 @@
+package controller
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestDryRunTransport(t *testing.T) {
+	const job = `{"apiVersion":"batch/v1","kind":"Job","metadata":{"name":"loadtest-master"}}`
+
+	for _, tt := range []struct {
+		name         string
+		method       string
+		body         string
+		yaml         bool
+		expectSent   string
+		expectStatus int
+		expectBody   string
+		expectLogged string
+	}{
+		{
+			name:         "read requests are sent",
+			method:       http.MethodGet,
+			expectSent:   http.MethodGet,
+			expectStatus: http.StatusOK,
+			expectBody:   "current",
+		},
+		{
+			name:         "created objects are returned as sent",
+			method:       http.MethodPost,
+			body:         job,
+			expectStatus: http.StatusCreated,
+			expectBody:   job,
+			expectLogged: job,
+		},
+		{
+			name:         "created objects are logged as YAML",
+			method:       http.MethodPost,
+			body:         job,
+			yaml:         true,
+			expectStatus: http.StatusCreated,
+			expectBody:   job,
+			expectLogged: "kind: Job",
+		},
+		{
+			name:         "updated objects are returned as sent",
+			method:       http.MethodPut,
+			body:         job,
+			expectStatus: http.StatusOK,
+			expectBody:   job,
+			expectLogged: job,
+		},
+		{
+			name:         "patched objects are returned unchanged",
+			method:       http.MethodPatch,
+			body:         `{"spec":{"suspend":true}}`,
+			expectSent:   http.MethodGet,
+			expectStatus: http.StatusOK,
+			expectBody:   "current",
+			expectLogged: `{"spec":{"suspend":true}}`,
+		},
+		{
+			name:         "deletions succeed",
+			method:       http.MethodDelete,
+			expectStatus: http.StatusOK,
+			expectBody:   string(dryRunSuccess),
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var sent string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				sent = r.Method
+				_, _ = w.Write([]byte("current"))
+			}))
+			defer server.Close()
+
+			core, logs := observer.New(zap.InfoLevel)
+			client := &http.Client{Transport: DryRunTransport(zap.New(core), tt.yaml)(http.DefaultTransport)}
+
+			req, err := http.NewRequest(tt.method, server.URL+"/apis/batch/v1/namespaces/loadtest/jobs", strings.NewReader(tt.body))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", "application/json")
+
+			resp, err := client.Do(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expectSent, sent)
+			assert.Equal(t, tt.expectStatus, resp.StatusCode)
+			assert.Equal(t, tt.expectBody, string(body))
+
+			if tt.method == http.MethodGet {
+				assert.Zero(t, logs.Len())
+				return
+			}
+			entries := logs.FilterMessage("Dry run, skipping request").All()
+			require.Len(t, entries, 1)
+			assert.Equal(t, tt.method, entries[0].ContextMap()["method"])
+			assert.Contains(t, entries[0].ContextMap()["body"], tt.expectLogged)
+		})
+	}
+}
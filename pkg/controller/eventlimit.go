@@ -0,0 +1,88 @@
+package controller
+
+import (
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+)
+
+// rateLimitedRecorder is an EventRecorder emitting at most a number of Events per minute for each object,
+// with a token bucket per object, so a single pathological loadtest can't flood the API server with Events.
+// Events over the limit are dropped.
+type rateLimitedRecorder struct {
+	record.EventRecorder
+
+	// perMinute is the number of Events per minute of each object, it is also the burst of Events
+	perMinute int
+	// dropped is called with the reason of each dropped Event
+	dropped func(reason string)
+
+	mu       sync.Mutex
+	limiters map[string]*rate.Limiter
+}
+
+func newRateLimitedRecorder(recorder record.EventRecorder, perMinute int, dropped func(reason string)) *rateLimitedRecorder {
+	return &rateLimitedRecorder{
+		EventRecorder: recorder,
+		perMinute:     perMinute,
+		dropped:       dropped,
+		limiters:      map[string]*rate.Limiter{},
+	}
+}
+
+// allow tells if an Event of the object can be emitted now
+func (r *rateLimitedRecorder) allow(object runtime.Object) bool {
+	key, err := cache.MetaNamespaceKeyFunc(object)
+	if err != nil {
+		return true
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	limiter, ok := r.limiters[key]
+	if !ok {
+		limiter = rate.NewLimiter(rate.Every(time.Minute/time.Duration(r.perMinute)), r.perMinute)
+		r.limiters[key] = limiter
+	}
+	return limiter.Allow()
+}
+
+// Event emits the Event unless the object exceeded its Event rate
+func (r *rateLimitedRecorder) Event(object runtime.Object, eventtype, reason, message string) {
+	if !r.allow(object) {
+		r.dropped(reason)
+		return
+	}
+	r.EventRecorder.Event(object, eventtype, reason, message)
+}
+
+// Eventf emits the Event unless the object exceeded its Event rate
+func (r *rateLimitedRecorder) Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...interface{}) {
+	if !r.allow(object) {
+		r.dropped(reason)
+		return
+	}
+	r.EventRecorder.Eventf(object, eventtype, reason, messageFmt, args...)
+}
+
+// AnnotatedEventf emits the Event unless the object exceeded its Event rate
+func (r *rateLimitedRecorder) AnnotatedEventf(object runtime.Object, annotations map[string]string, eventtype, reason, messageFmt string, args ...interface{}) {
+	if !r.allow(object) {
+		r.dropped(reason)
+		return
+	}
+	r.EventRecorder.AnnotatedEventf(object, annotations, eventtype, reason, messageFmt, args...)
+}
+
+// forget drops the token bucket of the object with the given key
+func (r *rateLimitedRecorder) forget(key string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.limiters, key)
+}
@@ -0,0 +1,39 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	coreV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+func TestRateLimitedRecorder(t *testing.T) {
+	fake := record.NewFakeRecorder(10)
+	dropped := map[string]int{}
+	recorder := newRateLimitedRecorder(fake, 2, func(reason string) {
+		dropped[reason]++
+	})
+
+	flapping := &loadTestV1.LoadTest{ObjectMeta: metaV1.ObjectMeta{Name: "flapping"}}
+	other := &loadTestV1.LoadTest{ObjectMeta: metaV1.ObjectMeta{Name: "other"}}
+
+	recorder.Event(flapping, coreV1.EventTypeWarning, reasonFlapping, "flapping")
+	recorder.Eventf(flapping, coreV1.EventTypeWarning, reasonFlapping, "flapping %d", 2)
+	recorder.Event(flapping, coreV1.EventTypeWarning, reasonFlapping, "flapping")
+	recorder.AnnotatedEventf(flapping, nil, coreV1.EventTypeWarning, reasonImagePullRetried, "retry")
+	assert.Len(t, fake.Events, 2, "Expected the burst of the loadtest to be emitted")
+	assert.Equal(t, map[string]int{reasonFlapping: 1, reasonImagePullRetried: 1}, dropped)
+
+	// the limit is per object
+	recorder.Event(other, coreV1.EventTypeNormal, reasonCleanUp, "deleted")
+	assert.Len(t, fake.Events, 3)
+
+	// a forgotten object starts with a full bucket
+	recorder.forget("flapping")
+	recorder.Event(flapping, coreV1.EventTypeWarning, reasonFlapping, "flapping")
+	assert.Len(t, fake.Events, 4)
+}
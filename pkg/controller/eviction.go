@@ -0,0 +1,93 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.uber.org/zap"
+	coreV1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+// evictedReason is the pod status reason set by the kubelet on pods it evicted
+const evictedReason = "Evicted"
+
+// evictionResourceRe matches the resource the node was low on in eviction messages of the kubelet,
+// e.g. "The node was low on resource: memory. Threshold quantity: 100Mi, available: 50Mi."
+var evictionResourceRe = regexp.MustCompile(`low on resource: ([\w.\-/]+?)\.?(\s|$)`)
+
+// evictionResource returns the resource the node was low on from the eviction message of a pod,
+// "unknown" when the message doesn't tell
+func evictionResource(message string) string {
+	match := evictionResourceRe.FindStringSubmatch(message)
+	if match == nil {
+		return "unknown"
+	}
+	return match[1]
+}
+
+// evictedPod returns the first pod evicted by the kubelet, e.g. because of node pressure, nil if there is none
+func evictedPod(pods []*coreV1.Pod) *coreV1.Pod {
+	for _, pod := range pods {
+		if pod.Status.Phase == coreV1.PodFailed && pod.Status.Reason == evictedReason {
+			return pod
+		}
+	}
+	return nil
+}
+
+// checkEvictions moves loadtests to errored phase when one of their pods was evicted by the kubelet because of
+// node pressure, as their results are compromised. The Evicted condition of the loadtest names the node and the
+// resource it was low on, so infrastructure failures can be told apart from failures of the load test or its
+// target. Once evicted, the loadtest stays errored even when the evicted pod is garbage collected.
+func (c *Controller) checkEvictions(ctx context.Context, loadTest *loadTestV1.LoadTest) error {
+	if !c.cfg.EvictionCheck || loadTest.Status.Namespace == "" {
+		return nil
+	}
+	if meta.IsStatusConditionTrue(loadTest.Status.Conditions, loadTestV1.LoadTestConditionEvicted) {
+		loadTest.Status.Phase = loadTestV1.LoadTestErrored
+		return nil
+	}
+	if loadTest.Status.Phase == loadTestV1.LoadTestErrored {
+		return nil
+	}
+
+	pods, err := c.loadTestPods(loadTest)
+	if err != nil {
+		return err
+	}
+	pod := evictedPod(pods)
+	if pod == nil {
+		return nil
+	}
+
+	resource := evictionResource(pod.Status.Message)
+	message := fmt.Sprintf("Pod %s was evicted from node %s under %s pressure: %s", pod.GetName(), pod.Spec.NodeName, resource, pod.Status.Message)
+	c.logger.Info("Loadtest pod was evicted, marking loadtest as errored",
+		zap.String("loadtest", loadTest.GetName()),
+		zap.String("pod", pod.GetName()),
+		zap.String("node", pod.Spec.NodeName),
+		zap.String("resource", resource),
+	)
+	c.recorder.Event(loadTest, coreV1.EventTypeWarning, reasonEvicted, message)
+	c.statsClient.evictionCountStat.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("type", loadTest.Spec.Type.String()),
+		attribute.String("resource", resource),
+	))
+
+	meta.SetStatusCondition(&loadTest.Status.Conditions, metaV1.Condition{
+		Type:               loadTestV1.LoadTestConditionEvicted,
+		Status:             metaV1.ConditionTrue,
+		Reason:             loadTestV1.LoadTestEvictedNodePressure,
+		Message:            message,
+		ObservedGeneration: loadTest.GetGeneration(),
+	})
+	setErrored(loadTest, reasonEvicted, message)
+	return nil
+}
@@ -0,0 +1,111 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/metric/noop"
+	"go.uber.org/zap/zaptest"
+	coreV1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	coreListersV1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+func TestEvictionResource(t *testing.T) {
+	for message, expected := range map[string]string{
+		"The node was low on resource: memory. Threshold quantity: 100Mi, available: 50Mi.":   "memory",
+		"The node was low on resource: ephemeral-storage. Container loadtest was using 10Gi.": "ephemeral-storage",
+		"The node was low on resource: memory":                                                "memory",
+		"The node had condition: [DiskPressure].":                                             "unknown",
+		"": "unknown",
+	} {
+		assert.Equal(t, expected, evictionResource(message), message)
+	}
+}
+
+func TestCheckEvictions(t *testing.T) {
+	statsReporter, err := NewMetricsReporter(noop.NewMeterProvider().Meter("test"))
+	require.NoError(t, err)
+
+	newPod := func(phase coreV1.PodPhase, reason string) *coreV1.Pod {
+		return &coreV1.Pod{
+			ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-pod", Namespace: "loadtest-namespace"},
+			Spec:       coreV1.PodSpec{NodeName: "node-1"},
+			Status: coreV1.PodStatus{
+				Phase:   phase,
+				Reason:  reason,
+				Message: "The node was low on resource: memory. Threshold quantity: 100Mi, available: 50Mi.",
+			},
+		}
+	}
+
+	for _, tt := range []struct {
+		name         string
+		pod          *coreV1.Pod
+		conditions   []metaV1.Condition
+		expectPhase  loadTestV1.LoadTestPhase
+		expectEvents int
+	}{
+		{
+			name:        "running pod",
+			pod:         newPod(coreV1.PodRunning, ""),
+			expectPhase: loadTestV1.LoadTestRunning,
+		},
+		{
+			name:        "failed pod",
+			pod:         newPod(coreV1.PodFailed, "Error"),
+			expectPhase: loadTestV1.LoadTestRunning,
+		},
+		{
+			name:         "evicted pod",
+			pod:          newPod(coreV1.PodFailed, "Evicted"),
+			expectPhase:  loadTestV1.LoadTestErrored,
+			expectEvents: 1,
+		},
+		{
+			name:        "evicted pod garbage collected",
+			pod:         newPod(coreV1.PodSucceeded, ""),
+			conditions:  []metaV1.Condition{{Type: loadTestV1.LoadTestConditionEvicted, Status: metaV1.ConditionTrue}},
+			expectPhase: loadTestV1.LoadTestErrored,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+			require.NoError(t, indexer.Add(tt.pod))
+			recorder := record.NewFakeRecorder(10)
+
+			c := &Controller{
+				cfg:         Config{EvictionCheck: true},
+				podsLister:  coreListersV1.NewPodLister(indexer),
+				recorder:    recorder,
+				statsClient: *statsReporter,
+				logger:      zaptest.NewLogger(t),
+			}
+			loadTest := &loadTestV1.LoadTest{
+				ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-name"},
+				Status: loadTestV1.LoadTestStatus{
+					Phase:      loadTestV1.LoadTestRunning,
+					Namespace:  "loadtest-namespace",
+					Conditions: tt.conditions,
+				},
+			}
+
+			require.NoError(t, c.checkEvictions(context.Background(), loadTest))
+			assert.Equal(t, tt.expectPhase, loadTest.Status.Phase)
+			assert.Len(t, recorder.Events, tt.expectEvents)
+			if tt.expectEvents > 0 {
+				condition := meta.FindStatusCondition(loadTest.Status.Conditions, loadTestV1.LoadTestConditionEvicted)
+				require.NotNil(t, condition)
+				assert.Equal(t, loadTestV1.LoadTestEvictedNodePressure, condition.Reason)
+				assert.Contains(t, condition.Message, "node-1 under memory pressure")
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package controller
+
+import (
+	"fmt"
+	"slices"
+
+	"go.uber.org/zap"
+	batchV1 "k8s.io/api/batch/v1"
+	coreV1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+// checkExistingNamespace moves loadtests asking to run in an existing namespace to errored phase when the
+// namespace is not allowed or does not exist, it returns false if the loadtest was rejected
+func (c *Controller) checkExistingNamespace(loadTest *loadTestV1.LoadTest) bool {
+	if loadTest.Spec.ExistingNamespace == "" {
+		return true
+	}
+
+	err := c.validateExistingNamespace(loadTest.Spec.ExistingNamespace)
+	if err == nil {
+		return true
+	}
+
+	if loadTest.Status.Phase != loadTestV1.LoadTestErrored {
+		c.logger.Info("Rejecting loadtest in existing namespace",
+			zap.String("loadtest", loadTest.GetName()),
+			zap.Error(err),
+		)
+		c.recorder.Event(loadTest, coreV1.EventTypeWarning, reasonExistingNamespaceRejected, err.Error())
+	}
+	setErrored(loadTest, reasonExistingNamespaceRejected, err.Error())
+
+	return false
+}
+
+// validateExistingNamespace returns an error when loadtests can't run in the named existing namespace
+func (c *Controller) validateExistingNamespace(name string) error {
+	if !slices.Contains(c.cfg.ExistingNamespaces, name) {
+		return fmt.Errorf("namespace %q is not allowed for loadtests running in an existing namespace", name)
+	}
+
+	namespace, err := c.namespacesLister.Get(name)
+	if errors.IsNotFound(err) {
+		return fmt.Errorf("namespace %q does not exist", name)
+	}
+	if err != nil {
+		return err
+	}
+	if namespace.Status.Phase == coreV1.NamespaceTerminating {
+		return fmt.Errorf("namespace %q is terminating", name)
+	}
+	return nil
+}
+
+// useExistingNamespace sets the existing namespace in the status of the loadtest once the jobs of other
+// loadtests are gone from it, as backends create jobs with fixed names
+func (c *Controller) useExistingNamespace(loadTest *loadTestV1.LoadTest) error {
+	namespace := loadTest.Spec.ExistingNamespace
+
+	jobs, err := c.jobsLister.Jobs(namespace).List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	for _, job := range jobs {
+		owner := metaV1.GetControllerOf(job)
+		if owner != nil && owner.Kind == "LoadTest" && owner.UID != loadTest.GetUID() {
+			return fmt.Errorf("existing namespace %s is in use by loadtest %s", namespace, owner.Name)
+		}
+	}
+
+	loadTest.Status.Namespace = namespace
+	return nil
+}
+
+// loadTestJobs returns the jobs of the loadtest. A namespace created for a loadtest only holds its jobs,
+// in an existing namespace the jobs controlled by the loadtest are picked.
+func (c *Controller) loadTestJobs(loadTest *loadTestV1.LoadTest) ([]*batchV1.Job, error) {
+	jobs, err := c.jobsLister.Jobs(loadTest.Status.Namespace).List(labels.Everything())
+	if err != nil || loadTest.Spec.ExistingNamespace == "" {
+		return jobs, err
+	}
+
+	var owned []*batchV1.Job
+	for _, job := range jobs {
+		if controlledBy(job, loadTest.GetUID()) {
+			owned = append(owned, job)
+		}
+	}
+	return owned, nil
+}
+
+// loadTestPods returns the pods of the loadtest. A namespace created for a loadtest only holds its pods,
+// in an existing namespace the pods controlled by the loadtest or one of its jobs are picked.
+func (c *Controller) loadTestPods(loadTest *loadTestV1.LoadTest) ([]*coreV1.Pod, error) {
+	pods, err := c.podsLister.Pods(loadTest.Status.Namespace).List(labels.Everything())
+	if err != nil || loadTest.Spec.ExistingNamespace == "" {
+		return pods, err
+	}
+
+	owners, err := c.loadTestOwners(loadTest)
+	if err != nil {
+		return nil, err
+	}
+
+	var owned []*coreV1.Pod
+	for _, pod := range pods {
+		if owner := metaV1.GetControllerOf(pod); owner != nil && owners[owner.UID] {
+			owned = append(owned, pod)
+		}
+	}
+	return owned, nil
+}
+
+// loadTestOwners returns the UIDs of the loadtest and its jobs
+func (c *Controller) loadTestOwners(loadTest *loadTestV1.LoadTest) (map[types.UID]bool, error) {
+	jobs, err := c.loadTestJobs(loadTest)
+	if err != nil {
+		return nil, err
+	}
+
+	owners := map[types.UID]bool{loadTest.GetUID(): true}
+	for _, job := range jobs {
+		owners[job.GetUID()] = true
+	}
+	return owners, nil
+}
+
+// controlledBy tells if the object is controlled by the object with the given UID
+func controlledBy(obj metaV1.Object, uid types.UID) bool {
+	owner := metaV1.GetControllerOf(obj)
+	return owner != nil && owner.UID == uid
+}
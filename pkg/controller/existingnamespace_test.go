@@ -0,0 +1,141 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+	batchV1 "k8s.io/api/batch/v1"
+	coreV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	batchListersV1 "k8s.io/client-go/listers/batch/v1"
+	coreListersV1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+func newExistingNamespaceController(t *testing.T, objects ...interface{}) (*Controller, *record.FakeRecorder) {
+	namespaces := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	jobs := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	pods := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	for _, obj := range objects {
+		switch obj.(type) {
+		case *coreV1.Namespace:
+			require.NoError(t, namespaces.Add(obj))
+		case *batchV1.Job:
+			require.NoError(t, jobs.Add(obj))
+		case *coreV1.Pod:
+			require.NoError(t, pods.Add(obj))
+		}
+	}
+
+	recorder := record.NewFakeRecorder(10)
+	return &Controller{
+		cfg:              Config{ExistingNamespaces: []string{"shared", "terminating", "missing"}},
+		namespacesLister: coreListersV1.NewNamespaceLister(namespaces),
+		jobsLister:       batchListersV1.NewJobLister(jobs),
+		podsLister:       coreListersV1.NewPodLister(pods),
+		recorder:         recorder,
+		logger:           zaptest.NewLogger(t),
+	}, recorder
+}
+
+func ownedBy(kind, name string, uid types.UID) []metaV1.OwnerReference {
+	isController := true
+	return []metaV1.OwnerReference{{Kind: kind, Name: name, UID: uid, Controller: &isController}}
+}
+
+func TestCheckExistingNamespace(t *testing.T) {
+	c, recorder := newExistingNamespaceController(t,
+		&coreV1.Namespace{ObjectMeta: metaV1.ObjectMeta{Name: "shared"}},
+		&coreV1.Namespace{ObjectMeta: metaV1.ObjectMeta{Name: "not-allowed"}},
+		&coreV1.Namespace{ObjectMeta: metaV1.ObjectMeta{Name: "terminating"}, Status: coreV1.NamespaceStatus{Phase: coreV1.NamespaceTerminating}},
+	)
+
+	for _, tt := range []struct {
+		namespace string
+		accepted  bool
+	}{
+		{namespace: "", accepted: true},
+		{namespace: "shared", accepted: true},
+		{namespace: "not-allowed"},
+		{namespace: "terminating"},
+		{namespace: "missing"},
+	} {
+		t.Run(tt.namespace, func(t *testing.T) {
+			loadTest := &loadTestV1.LoadTest{Spec: loadTestV1.LoadTestSpec{ExistingNamespace: tt.namespace}}
+
+			assert.Equal(t, tt.accepted, c.checkExistingNamespace(loadTest))
+			if tt.accepted {
+				assert.Empty(t, loadTest.Status.Phase)
+				assert.Empty(t, recorder.Events)
+				return
+			}
+			assert.Equal(t, loadTestV1.LoadTestErrored, loadTest.Status.Phase)
+			assert.Len(t, recorder.Events, 1)
+			<-recorder.Events
+		})
+	}
+}
+
+func TestUseExistingNamespace(t *testing.T) {
+	job := &batchV1.Job{ObjectMeta: metaV1.ObjectMeta{
+		Name:            "loadtest-master",
+		Namespace:       "shared",
+		OwnerReferences: ownedBy("LoadTest", "other-loadtest", "other-uid"),
+	}}
+	c, _ := newExistingNamespaceController(t, job)
+
+	loadTest := &loadTestV1.LoadTest{
+		ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-name", UID: "loadtest-uid"},
+		Spec:       loadTestV1.LoadTestSpec{ExistingNamespace: "shared"},
+	}
+	err := c.checkOrCreateNamespace(context.Background(), loadTest)
+	assert.ErrorContains(t, err, "in use by loadtest other-loadtest")
+	assert.Empty(t, loadTest.Status.Namespace)
+
+	// jobs of the loadtest itself don't block it
+	job.OwnerReferences = ownedBy("LoadTest", "loadtest-name", "loadtest-uid")
+	require.NoError(t, c.checkOrCreateNamespace(context.Background(), loadTest))
+	assert.Equal(t, "shared", loadTest.Status.Namespace)
+}
+
+func TestLoadTestPods(t *testing.T) {
+	c, _ := newExistingNamespaceController(t,
+		&batchV1.Job{ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-master", Namespace: "shared", UID: "job-uid", OwnerReferences: ownedBy("LoadTest", "loadtest-name", "loadtest-uid")}},
+		&batchV1.Job{ObjectMeta: metaV1.ObjectMeta{Name: "backup", Namespace: "shared", UID: "backup-uid"}},
+		&coreV1.Pod{ObjectMeta: metaV1.ObjectMeta{Name: "master", Namespace: "shared", OwnerReferences: ownedBy("Job", "loadtest-master", "job-uid")}},
+		&coreV1.Pod{ObjectMeta: metaV1.ObjectMeta{Name: "worker", Namespace: "shared", OwnerReferences: ownedBy("LoadTest", "loadtest-name", "loadtest-uid")}},
+		&coreV1.Pod{ObjectMeta: metaV1.ObjectMeta{Name: "backup", Namespace: "shared", OwnerReferences: ownedBy("Job", "backup", "backup-uid")}},
+		&coreV1.Pod{ObjectMeta: metaV1.ObjectMeta{Name: "service", Namespace: "shared"}},
+	)
+
+	loadTest := &loadTestV1.LoadTest{
+		ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-name", UID: "loadtest-uid"},
+		Status:     loadTestV1.LoadTestStatus{Namespace: "shared"},
+	}
+
+	// a namespace created for the loadtest only holds its pods
+	pods, err := c.loadTestPods(loadTest)
+	require.NoError(t, err)
+	assert.Len(t, pods, 4)
+
+	loadTest.Spec.ExistingNamespace = "shared"
+	pods, err = c.loadTestPods(loadTest)
+	require.NoError(t, err)
+	var names []string
+	for _, pod := range pods {
+		names = append(names, pod.GetName())
+	}
+	assert.ElementsMatch(t, []string{"master", "worker"}, names)
+
+	jobs, err := c.loadTestJobs(loadTest)
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	assert.Equal(t, "loadtest-master", jobs[0].GetName())
+}
@@ -0,0 +1,91 @@
+package controller
+
+import (
+	"sync"
+	"time"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+// flappingDetector keeps the recent phase transitions of each LoadTest to detect
+// loadtests oscillating between phases, e.g. running and errored
+type flappingDetector struct {
+	// threshold is the number of oscillations within window considered as flapping, 0 disables detection
+	threshold int
+	window    time.Duration
+	now       func() time.Time
+
+	mu          sync.Mutex
+	transitions map[string][]phaseTransition
+}
+
+// phaseTransition is a phase transition of a LoadTest, oscillation tells whether it went back to a phase seen
+// within the window or out of a finished or errored phase
+type phaseTransition struct {
+	phase       loadTestV1.LoadTestPhase
+	time        time.Time
+	oscillation bool
+}
+
+func newFlappingDetector(threshold int, window time.Duration) *flappingDetector {
+	return &flappingDetector{
+		threshold:   threshold,
+		window:      window,
+		now:         time.Now,
+		transitions: map[string][]phaseTransition{},
+	}
+}
+
+// observe records a phase transition of the given LoadTest key from previous to phase and returns true when the
+// number of oscillations within the window reaches the threshold. Transitions moving forward through the lifecycle,
+// e.g. from creating to finished, are not oscillations. The history of the key is reset once flapping is reported,
+// so every further report requires threshold new oscillations.
+func (d *flappingDetector) observe(key string, previous, phase loadTestV1.LoadTestPhase) bool {
+	if d.threshold <= 0 {
+		return false
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := d.now()
+	history := d.transitions[key]
+
+	// drop transitions that are out of the window
+	i := 0
+	for i < len(history) && now.Sub(history[i].time) > d.window {
+		i++
+	}
+	history = history[i:]
+
+	oscillation := previous == loadTestV1.LoadTestFinished || previous == loadTestV1.LoadTestErrored
+	oscillations := 0
+	for _, transition := range history {
+		if transition.phase == phase {
+			oscillation = true
+		}
+		if transition.oscillation {
+			oscillations++
+		}
+	}
+	history = append(history, phaseTransition{phase: phase, time: now, oscillation: oscillation})
+	if oscillation {
+		oscillations++
+	}
+
+	if oscillations >= d.threshold {
+		delete(d.transitions, key)
+		return true
+	}
+
+	d.transitions[key] = history
+	return false
+}
+
+// forget drops the history of the given LoadTest key
+func (d *flappingDetector) forget(key string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	delete(d.transitions, key)
+}
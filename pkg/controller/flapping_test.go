@@ -0,0 +1,57 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+func TestFlappingDetector(t *testing.T) {
+	now := time.Now()
+	d := newFlappingDetector(3, time.Minute)
+	d.now = func() time.Time { return now }
+
+	assert.False(t, d.observe("loadtest", loadTestV1.LoadTestStarting, loadTestV1.LoadTestRunning))
+	assert.False(t, d.observe("loadtest", loadTestV1.LoadTestRunning, loadTestV1.LoadTestErrored))
+	assert.False(t, d.observe("loadtest", loadTestV1.LoadTestErrored, loadTestV1.LoadTestRunning))
+	assert.False(t, d.observe("other-loadtest", loadTestV1.LoadTestErrored, loadTestV1.LoadTestRunning))
+	assert.False(t, d.observe("loadtest", loadTestV1.LoadTestRunning, loadTestV1.LoadTestErrored))
+	assert.True(t, d.observe("loadtest", loadTestV1.LoadTestErrored, loadTestV1.LoadTestRunning))
+
+	// history is reset once flapping is reported
+	assert.False(t, d.observe("loadtest", loadTestV1.LoadTestRunning, loadTestV1.LoadTestErrored))
+
+	// transitions out of the window are not counted
+	now = now.Add(2 * time.Minute)
+	assert.False(t, d.observe("loadtest", loadTestV1.LoadTestErrored, loadTestV1.LoadTestRunning))
+	assert.False(t, d.observe("loadtest", loadTestV1.LoadTestRunning, loadTestV1.LoadTestErrored))
+	assert.False(t, d.observe("loadtest", loadTestV1.LoadTestErrored, loadTestV1.LoadTestRunning))
+	assert.True(t, d.observe("loadtest", loadTestV1.LoadTestRunning, loadTestV1.LoadTestErrored))
+
+	d.forget("other-loadtest")
+	assert.NotContains(t, d.transitions, "other-loadtest")
+}
+
+func TestFlappingDetectorLifecycle(t *testing.T) {
+	d := newFlappingDetector(1, time.Minute)
+
+	// a loadtest going through its lifecycle within the window is not flapping
+	assert.False(t, d.observe("loadtest", "", loadTestV1.LoadTestCreating))
+	assert.False(t, d.observe("loadtest", loadTestV1.LoadTestCreating, loadTestV1.LoadTestStarting))
+	assert.False(t, d.observe("loadtest", loadTestV1.LoadTestStarting, loadTestV1.LoadTestRunning))
+	assert.False(t, d.observe("loadtest", loadTestV1.LoadTestRunning, loadTestV1.LoadTestFinished))
+
+	// leaving a finished phase is an oscillation
+	assert.True(t, d.observe("loadtest", loadTestV1.LoadTestFinished, loadTestV1.LoadTestRunning))
+}
+
+func TestFlappingDetectorDisabled(t *testing.T) {
+	d := newFlappingDetector(0, time.Minute)
+
+	for i := 0; i < 10; i++ {
+		assert.False(t, d.observe("loadtest", loadTestV1.LoadTestErrored, loadTestV1.LoadTestRunning))
+	}
+}
@@ -0,0 +1,95 @@
+package controller
+
+import (
+	"fmt"
+	"strings"
+
+	coreV1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+// checkGate compares the summary metrics of a finished loadtest against its thresholds and records the
+// result in the Gate condition, so CI pipelines can use loadtests as quality gates. The condition stays
+// Unknown until the summary holds all metrics of the thresholds, once passed or failed it is final.
+func (c *Controller) checkGate(loadTest *loadTestV1.LoadTest) {
+	if len(loadTest.Spec.Thresholds) == 0 {
+		return
+	}
+	if loadTest.Status.Phase != loadTestV1.LoadTestFinished && loadTest.Status.Phase != loadTestV1.LoadTestErrored {
+		return
+	}
+
+	current := meta.FindStatusCondition(loadTest.Status.Conditions, loadTestV1.LoadTestConditionGate)
+	if current != nil && current.Status != metaV1.ConditionUnknown {
+		return
+	}
+
+	condition := evaluateGate(loadTest)
+	meta.SetStatusCondition(&loadTest.Status.Conditions, condition)
+
+	switch condition.Status {
+	case metaV1.ConditionTrue:
+		c.recorder.Event(loadTest, coreV1.EventTypeNormal, condition.Reason, condition.Message)
+	case metaV1.ConditionFalse:
+		c.recorder.Event(loadTest, coreV1.EventTypeWarning, condition.Reason, condition.Message)
+	}
+}
+
+// evaluateGate builds the Gate condition of a finished or errored loadtest
+func evaluateGate(loadTest *loadTestV1.LoadTest) metaV1.Condition {
+	condition := metaV1.Condition{
+		Type:               loadTestV1.LoadTestConditionGate,
+		ObservedGeneration: loadTest.GetGeneration(),
+	}
+
+	if loadTest.Status.Phase == loadTestV1.LoadTestErrored {
+		condition.Status = metaV1.ConditionFalse
+		condition.Reason = loadTestV1.LoadTestGateFailed
+		condition.Message = "Loadtest errored"
+		return condition
+	}
+
+	var failed, missing []string
+	for _, s := range loadTest.Spec.Thresholds {
+		threshold, err := loadTestV1.ParseThreshold(s)
+		if err != nil {
+			failed = append(failed, err.Error())
+			continue
+		}
+
+		raw, ok := loadTest.Status.Summary[threshold.Metric]
+		if !ok {
+			missing = append(missing, threshold.Metric)
+			continue
+		}
+
+		value, err := loadTestV1.ParseMetricValue(raw)
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %s", threshold.Metric, err))
+			continue
+		}
+
+		if !threshold.Evaluate(value) {
+			failed = append(failed, fmt.Sprintf("%s (got %s)", s, raw))
+		}
+	}
+
+	switch {
+	case len(failed) > 0:
+		condition.Status = metaV1.ConditionFalse
+		condition.Reason = loadTestV1.LoadTestGateFailed
+		condition.Message = fmt.Sprintf("Thresholds not satisfied: %s", strings.Join(failed, ", "))
+	case len(missing) > 0:
+		condition.Status = metaV1.ConditionUnknown
+		condition.Reason = loadTestV1.LoadTestGateSummaryPending
+		condition.Message = fmt.Sprintf("Summary is missing metrics: %s", strings.Join(missing, ", "))
+	default:
+		condition.Status = metaV1.ConditionTrue
+		condition.Reason = loadTestV1.LoadTestGatePassed
+		condition.Message = "All thresholds satisfied"
+	}
+	return condition
+}
@@ -0,0 +1,117 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+func TestCheckGate(t *testing.T) {
+	for _, tt := range []struct {
+		name       string
+		phase      loadTestV1.LoadTestPhase
+		thresholds []string
+		summary    map[string]string
+		status     metaV1.ConditionStatus
+		reason     string
+		events     int
+	}{
+		{
+			name:       "no thresholds",
+			phase:      loadTestV1.LoadTestFinished,
+			thresholds: nil,
+			summary:    map[string]string{"p99": "150ms"},
+		},
+		{
+			name:       "running",
+			phase:      loadTestV1.LoadTestRunning,
+			thresholds: []string{"p99 < 200ms"},
+		},
+		{
+			name:       "passed",
+			phase:      loadTestV1.LoadTestFinished,
+			thresholds: []string{"p99 < 200ms", "error_rate < 1%"},
+			summary:    map[string]string{"p99": "0.15s", "error_rate": "0.2%"},
+			status:     metaV1.ConditionTrue,
+			reason:     loadTestV1.LoadTestGatePassed,
+			events:     1,
+		},
+		{
+			name:       "failed",
+			phase:      loadTestV1.LoadTestFinished,
+			thresholds: []string{"p99 < 200ms", "error_rate < 1%"},
+			summary:    map[string]string{"p99": "250ms", "error_rate": "0.2%"},
+			status:     metaV1.ConditionFalse,
+			reason:     loadTestV1.LoadTestGateFailed,
+			events:     1,
+		},
+		{
+			name:       "summary pending",
+			phase:      loadTestV1.LoadTestFinished,
+			thresholds: []string{"p99 < 200ms", "error_rate < 1%"},
+			summary:    map[string]string{"p99": "150ms"},
+			status:     metaV1.ConditionUnknown,
+			reason:     loadTestV1.LoadTestGateSummaryPending,
+		},
+		{
+			name:       "errored",
+			phase:      loadTestV1.LoadTestErrored,
+			thresholds: []string{"p99 < 200ms"},
+			status:     metaV1.ConditionFalse,
+			reason:     loadTestV1.LoadTestGateFailed,
+			events:     1,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			loadTest := &loadTestV1.LoadTest{
+				ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-name"},
+				Spec:       loadTestV1.LoadTestSpec{Thresholds: tt.thresholds},
+				Status: loadTestV1.LoadTestStatus{
+					Phase:   tt.phase,
+					Summary: tt.summary,
+				},
+			}
+
+			recorder := record.NewFakeRecorder(10)
+			c := &Controller{recorder: recorder}
+
+			c.checkGate(loadTest)
+
+			assert.Len(t, recorder.Events, tt.events)
+			if tt.status == "" {
+				assert.Empty(t, loadTest.Status.Conditions)
+				return
+			}
+
+			require.Len(t, loadTest.Status.Conditions, 1)
+			assert.Equal(t, tt.status, loadTest.Status.Conditions[0].Status)
+			assert.Equal(t, tt.reason, loadTest.Status.Conditions[0].Reason)
+		})
+	}
+}
+
+func TestCheckGateFinal(t *testing.T) {
+	loadTest := &loadTestV1.LoadTest{
+		Spec: loadTestV1.LoadTestSpec{Thresholds: []string{"p99 < 200ms"}},
+		Status: loadTestV1.LoadTestStatus{
+			Phase:   loadTestV1.LoadTestFinished,
+			Summary: map[string]string{"p99": "150ms"},
+		},
+	}
+
+	recorder := record.NewFakeRecorder(10)
+	c := &Controller{recorder: recorder}
+
+	c.checkGate(loadTest)
+	loadTest.Status.Summary["p99"] = "250ms"
+	c.checkGate(loadTest)
+
+	assert.Len(t, recorder.Events, 1)
+	require.Len(t, loadTest.Status.Conditions, 1)
+	assert.Equal(t, metaV1.ConditionTrue, loadTest.Status.Conditions[0].Status)
+}
@@ -0,0 +1,85 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+	"github.com/hellofresh/kangal/pkg/report"
+)
+
+// updateGroupIndex lists the loadtest that reached a terminal phase in the report index of its group, the value
+// of its ReportGroupTag tag. The index is kept by the proxy in the report bucket.
+func (c *Controller) updateGroupIndex(ctx context.Context, loadTest *loadTestV1.LoadTest) {
+	if c.cfg.ReportGroupTag == "" || c.cfg.KangalProxyURL == "" {
+		return
+	}
+	if loadTest.Status.Phase != loadTestV1.LoadTestFinished && loadTest.Status.Phase != loadTestV1.LoadTestErrored {
+		return
+	}
+	group, ok := loadTest.Spec.Tags[c.cfg.ReportGroupTag]
+	if !ok {
+		return
+	}
+
+	logger := c.logger.With(zap.String("loadtest", loadTest.GetName()), zap.String("group", group))
+	if errs := validation.IsValidLabelValue(group); group == "" || len(errs) > 0 {
+		logger.Warn("Skipping report index of group which is not a valid label value", zap.Strings("errors", errs))
+		return
+	}
+
+	if err := putGroupIndexEntry(ctx, c.cfg.KangalProxyURL, group, newGroupIndexEntry(loadTest, c.reportURL(loadTest))); err != nil {
+		logger.Error("Failed to update report index of group", zap.Error(err))
+		return
+	}
+	logger.Debug("Updated report index of group")
+}
+
+// newGroupIndexEntry returns the entry of the loadtest in the report index of its group, loadtests without
+// completion time, e.g. errored before their job started, are listed with the current time
+func newGroupIndexEntry(loadTest *loadTestV1.LoadTest, reportURL string) report.GroupEntry {
+	completionTime := time.Now()
+	if loadTest.Status.JobStatus.CompletionTime != nil {
+		completionTime = loadTest.Status.JobStatus.CompletionTime.Time
+	}
+
+	return report.GroupEntry{
+		Name:           loadTest.GetName(),
+		Phase:          loadTest.Status.Phase.String(),
+		ReportURL:      reportURL,
+		CompletionTime: completionTime.UTC(),
+	}
+}
+
+func putGroupIndexEntry(ctx context.Context, proxyURL, group string, entry report.GroupEntry) error {
+	body, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	indexURL := fmt.Sprintf("%s/load-test-group/%s/index", strings.TrimSuffix(proxyURL, "/"), group)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, indexURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("proxy responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zaptest"
+	batchV1 "k8s.io/api/batch/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+	"github.com/hellofresh/kangal/pkg/report"
+)
+
+func TestUpdateGroupIndex(t *testing.T) {
+	completionTime := metaV1.NewTime(time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC))
+
+	for _, tt := range []struct {
+		name     string
+		phase    loadTestV1.LoadTestPhase
+		tags     loadTestV1.LoadTestTags
+		expected []report.GroupEntry
+	}{
+		{
+			name:  "finished",
+			phase: loadTestV1.LoadTestFinished,
+			tags:  loadTestV1.LoadTestTags{"suite": "rc-1.2.3"},
+			expected: []report.GroupEntry{{
+				Name:           "loadtest-name",
+				Phase:          "finished",
+				CompletionTime: completionTime.Time,
+			}},
+		},
+		{
+			name:  "running",
+			phase: loadTestV1.LoadTestRunning,
+			tags:  loadTestV1.LoadTestTags{"suite": "rc-1.2.3"},
+		},
+		{
+			name:  "without group",
+			phase: loadTestV1.LoadTestFinished,
+			tags:  loadTestV1.LoadTestTags{"team": "payments"},
+		},
+		{
+			name:  "invalid group",
+			phase: loadTestV1.LoadTestErrored,
+			tags:  loadTestV1.LoadTestTags{"suite": "rc 1.2.3"},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var entries []report.GroupEntry
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodPut, r.Method)
+				assert.Equal(t, "/load-test-group/rc-1.2.3/index", r.URL.Path)
+
+				var entry report.GroupEntry
+				assert.NoError(t, json.NewDecoder(r.Body).Decode(&entry))
+				entries = append(entries, entry)
+			}))
+			defer server.Close()
+
+			c := &Controller{
+				cfg:    Config{KangalProxyURL: server.URL, ReportGroupTag: "suite"},
+				logger: zaptest.NewLogger(t),
+			}
+			loadTest := &loadTestV1.LoadTest{
+				ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-name"},
+				Spec:       loadTestV1.LoadTestSpec{Tags: tt.tags},
+				Status: loadTestV1.LoadTestStatus{
+					Phase:     tt.phase,
+					JobStatus: batchV1.JobStatus{CompletionTime: &completionTime},
+				},
+			}
+
+			c.updateGroupIndex(context.Background(), loadTest)
+
+			for i := range tt.expected {
+				tt.expected[i].ReportURL = server.URL + "/load-test/loadtest-name/report"
+			}
+			assert.Equal(t, tt.expected, entries)
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/go-chi/render"
+	"go.uber.org/zap"
+
+	cHttp "github.com/hellofresh/kangal/pkg/core/http"
+)
+
+// ReadinessChecker tells if the controller is ready to reconcile loadtests
+type ReadinessChecker interface {
+	Ready() bool
+}
+
+// Ready tells if the informer caches of the controller are synced, loadtests are not reconciled before
+func (c *Controller) Ready() bool {
+	for _, synced := range []func() bool{c.namespacesSynced, c.podsSynced, c.jobsSynced, c.loadtestsSynced} {
+		if synced == nil || !synced() {
+			return false
+		}
+	}
+	return true
+}
+
+// ReadinessHandler answers ok once the controller is ready and service unavailable until then
+func ReadinessHandler(checker ReadinessChecker, logger *zap.Logger) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !checker.Ready() {
+			logger.Debug("Readiness probe failed, informer caches are not synced yet")
+			render.Render(w, r, cHttp.ErrResponse(http.StatusServiceUnavailable, "informer caches are not synced yet"))
+			return
+		}
+
+		render.JSON(w, r, &cHttp.Response{
+			HTTPStatusCode: http.StatusOK,
+			StatusText:     "Kangal Controller is ready",
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package controller
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestControllerReady(t *testing.T) {
+	synced := func() bool { return true }
+	notSynced := func() bool { return false }
+
+	for _, tt := range []struct {
+		name       string
+		controller *Controller
+		expected   bool
+	}{
+		{
+			name:       "caches not set up",
+			controller: &Controller{},
+			expected:   false,
+		},
+		{
+			name: "caches syncing",
+			controller: &Controller{
+				namespacesSynced: synced,
+				podsSynced:       synced,
+				jobsSynced:       notSynced,
+				loadtestsSynced:  synced,
+			},
+			expected: false,
+		},
+		{
+			name: "caches synced",
+			controller: &Controller{
+				namespacesSynced: synced,
+				podsSynced:       synced,
+				jobsSynced:       synced,
+				loadtestsSynced:  synced,
+			},
+			expected: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.controller.Ready())
+		})
+	}
+}
+
+type fakeReadinessChecker bool
+
+func (r fakeReadinessChecker) Ready() bool { return bool(r) }
+
+func TestReadinessHandler(t *testing.T) {
+	for _, tt := range []struct {
+		ready    bool
+		expected int
+	}{
+		{ready: false, expected: http.StatusServiceUnavailable},
+		{ready: true, expected: http.StatusOK},
+	} {
+		w := httptest.NewRecorder()
+		ReadinessHandler(fakeReadinessChecker(tt.ready), zaptest.NewLogger(t))(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+		assert.Equal(t, tt.expected, w.Code)
+	}
+}
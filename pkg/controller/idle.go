@@ -0,0 +1,124 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"go.uber.org/zap"
+	coreV1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	coreInformersV1 "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/kubernetes"
+	coreListersV1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+// errPodInformerNotSynced is returned when the pod informer started for an active loadtest did not sync in time
+var errPodInformerNotSynced = errors.New("pod informer did not sync")
+
+// onDemandPodInformer runs the pod informer of the controller only while loadtests are active, so an idle controller
+// doesn't watch and cache the pods of the whole cluster. Informers can't be restarted, each start builds a new one.
+type onDemandPodInformer struct {
+	kubeClientSet kubernetes.Interface
+	handler       cache.ResourceEventHandler
+	logger        *zap.Logger
+
+	mu       sync.Mutex
+	informer cache.SharedIndexInformer
+	stopCh   chan struct{}
+}
+
+func newOnDemandPodInformer(kubeClientSet kubernetes.Interface, handler cache.ResourceEventHandler, logger *zap.Logger) *onDemandPodInformer {
+	return &onDemandPodInformer{
+		kubeClientSet: kubeClientSet,
+		handler:       handler,
+		logger:        logger,
+	}
+}
+
+// start runs the pod informer unless it is running already and waits for its cache to sync
+func (p *onDemandPodInformer) start(ctx context.Context) error {
+	p.mu.Lock()
+	if p.informer == nil {
+		p.logger.Info("Loadtest active, starting pod informer")
+		// pod events with an unchanged resource version are ignored, there is no need for a resync
+		p.informer = coreInformersV1.NewPodInformer(p.kubeClientSet, "", 0, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+		if _, err := p.informer.AddEventHandler(p.handler); err != nil {
+			p.informer = nil
+			p.mu.Unlock()
+			return err
+		}
+		p.stopCh = make(chan struct{})
+		go p.informer.Run(p.stopCh)
+	}
+	informer := p.informer
+	p.mu.Unlock()
+
+	if !cache.WaitForCacheSync(ctx.Done(), informer.HasSynced) {
+		return errPodInformerNotSynced
+	}
+	return nil
+}
+
+// stop stops the pod informer and drops its cache
+func (p *onDemandPodInformer) stop() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.informer == nil {
+		return
+	}
+	p.logger.Info("No loadtest active, stopping pod informer")
+	close(p.stopCh)
+	p.informer = nil
+}
+
+// lister returns the lister of the running pod informer, or an empty one while it is stopped
+func (p *onDemandPodInformer) lister() coreListersV1.PodLister {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.informer == nil {
+		return coreListersV1.NewPodLister(cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc}))
+	}
+	return coreListersV1.NewPodLister(p.informer.GetIndexer())
+}
+
+// onDemandPodLister is the pod lister of the controller with IdleScaleDown, it lists from the current pod informer
+type onDemandPodLister struct {
+	informer *onDemandPodInformer
+}
+
+// List lists all pods known to the current pod informer
+func (l onDemandPodLister) List(selector labels.Selector) ([]*coreV1.Pod, error) {
+	return l.informer.lister().List(selector)
+}
+
+// Pods returns a lister of the pods in the namespace known to the current pod informer
+func (l onDemandPodLister) Pods(namespace string) coreListersV1.PodNamespaceLister {
+	return l.informer.lister().Pods(namespace)
+}
+
+// activeLoadTest tells whether the loadtest still needs the pod informer, finished and errored loadtests don't
+func activeLoadTest(loadTest *loadTestV1.LoadTest) bool {
+	return loadTest.Status.Phase != loadTestV1.LoadTestFinished && loadTest.Status.Phase != loadTestV1.LoadTestErrored
+}
+
+// checkIdle stops the on demand pod informer when no loadtest is active, it is started again
+// by syncHandler on the next active loadtest
+func (c *Controller) checkIdle() {
+	loadTests, err := c.loadtestsLister.List(labels.Everything())
+	if err != nil {
+		c.logger.Error("Could not list loadtests to check for idleness", zap.Error(err))
+		return
+	}
+	for _, loadTest := range loadTests {
+		if activeLoadTest(loadTest) {
+			return
+		}
+	}
+	c.pods.stop()
+}
@@ -0,0 +1,75 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+	coreV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+	listers "github.com/hellofresh/kangal/pkg/kubernetes/generated/listers/loadtest/v1"
+)
+
+func TestOnDemandPodInformer(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	kubeClient := k8sfake.NewSimpleClientset(&coreV1.Pod{
+		ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-pod", Namespace: "loadtest-namespace"},
+	})
+	pods := newOnDemandPodInformer(kubeClient, cache.ResourceEventHandlerFuncs{}, zaptest.NewLogger(t))
+	lister := onDemandPodLister{informer: pods}
+
+	listed, err := lister.List(labels.Everything())
+	require.NoError(t, err)
+	assert.Empty(t, listed)
+
+	require.NoError(t, pods.start(ctx))
+	// starting a running informer keeps it
+	require.NoError(t, pods.start(ctx))
+	listed, err = lister.Pods("loadtest-namespace").List(labels.Everything())
+	require.NoError(t, err)
+	assert.Len(t, listed, 1)
+
+	pods.stop()
+	listed, err = lister.List(labels.Everything())
+	require.NoError(t, err)
+	assert.Empty(t, listed)
+}
+
+func TestCheckIdle(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	newLoadTest := func(name string, phase loadTestV1.LoadTestPhase) *loadTestV1.LoadTest {
+		return &loadTestV1.LoadTest{
+			ObjectMeta: metaV1.ObjectMeta{Name: name},
+			Status:     loadTestV1.LoadTestStatus{Phase: phase},
+		}
+	}
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	require.NoError(t, indexer.Add(newLoadTest("loadtest-finished", loadTestV1.LoadTestFinished)))
+	require.NoError(t, indexer.Add(newLoadTest("loadtest-running", loadTestV1.LoadTestRunning)))
+
+	c := &Controller{
+		loadtestsLister: listers.NewLoadTestLister(indexer),
+		pods:            newOnDemandPodInformer(k8sfake.NewSimpleClientset(), cache.ResourceEventHandlerFuncs{}, zaptest.NewLogger(t)),
+		logger:          zaptest.NewLogger(t),
+	}
+	require.NoError(t, c.pods.start(ctx))
+
+	c.checkIdle()
+	assert.NotNil(t, c.pods.informer)
+
+	require.NoError(t, indexer.Update(newLoadTest("loadtest-running", loadTestV1.LoadTestErrored)))
+	c.checkIdle()
+	assert.Nil(t, c.pods.informer)
+}
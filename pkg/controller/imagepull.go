@@ -0,0 +1,194 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+	coreV1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+// permanentImagePullMessages are parts of image pull error messages that a retry does not fix
+var permanentImagePullMessages = []string{
+	"not found",
+	"manifest unknown",
+	"unauthorized",
+	"authentication required",
+	"access denied",
+	"denied:",
+}
+
+// imagePullFailure is a container of a loadtest pod failing to pull its image
+type imagePullFailure struct {
+	pod       *coreV1.Pod
+	container string
+	image     string
+	message   string
+	permanent bool
+}
+
+func (f imagePullFailure) String() string {
+	return fmt.Sprintf("container %s of pod %s failed to pull image %s: %s", f.container, f.pod.GetName(), f.image, f.message)
+}
+
+// imagePullFailures returns the containers of the pod waiting on a failed image pull
+func imagePullFailures(pod *coreV1.Pod) []imagePullFailure {
+	var failures []imagePullFailure
+	statuses := append(append([]coreV1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+	for _, status := range statuses {
+		if status.State.Waiting == nil {
+			continue
+		}
+		failing, permanent := classifyImagePull(status.State.Waiting.Reason, status.State.Waiting.Message)
+		if !failing {
+			continue
+		}
+		failures = append(failures, imagePullFailure{
+			pod:       pod,
+			container: status.Name,
+			image:     status.Image,
+			message:   status.State.Waiting.Message,
+			permanent: permanent,
+		})
+	}
+	return failures
+}
+
+// classifyImagePull tells from the waiting reason and message of a container if it failed to pull
+// its image and if the failure is permanent. The cause of a failure is only known from the ErrImagePull
+// message, ImagePullBackOff messages don't carry it and are treated as transient.
+func classifyImagePull(reason, message string) (failing, permanent bool) {
+	switch reason {
+	case "InvalidImageName", "ErrImageNeverPull":
+		return true, true
+	case "ErrImagePull", "ImagePullBackOff":
+		message = strings.ToLower(message)
+		for _, m := range permanentImagePullMessages {
+			if strings.Contains(message, m) {
+				return true, true
+			}
+		}
+		return true, false
+	}
+	return false, false
+}
+
+// ownedByJob tells if the pod is controlled by a Job, which recreates it once deleted
+func ownedByJob(pod *coreV1.Pod) bool {
+	owner := metaV1.GetControllerOf(pod)
+	return owner != nil && owner.Kind == "Job"
+}
+
+// imagePullRetries counts the pods deleted to retry their image pull, per LoadTest key
+type imagePullRetries struct {
+	mu      sync.Mutex
+	retried map[string]map[types.UID]struct{}
+}
+
+func newImagePullRetries() *imagePullRetries {
+	return &imagePullRetries{retried: map[string]map[types.UID]struct{}{}}
+}
+
+// retry records the retry of the given pod of the LoadTest key and returns the number of retries of the
+// LoadTest, ok is false when the pod was already retried, e.g. as its deletion is not observed yet
+func (r *imagePullRetries) retry(key string, pod types.UID) (retries int, ok bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	pods, found := r.retried[key]
+	if !found {
+		pods = map[types.UID]struct{}{}
+		r.retried[key] = pods
+	}
+	if _, found := pods[pod]; found {
+		return len(pods), false
+	}
+	pods[pod] = struct{}{}
+	return len(pods), true
+}
+
+// forget drops the retries of the given LoadTest key
+func (r *imagePullRetries) forget(key string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.retried, key)
+}
+
+// checkImagePulls fails loadtests whose pods can't pull their image. Transient pull failures of pods
+// controlled by a Job are retried by deleting the pod, so the Job recreates it, up to ImagePullRetries
+// times per loadtest, permanent failures error the loadtest right away. It returns false if the loadtest
+// was moved to errored phase.
+func (c *Controller) checkImagePulls(ctx context.Context, key string, loadTest *loadTestV1.LoadTest) (bool, error) {
+	if !c.cfg.ImagePullCheck || loadTest.Status.Namespace == "" {
+		return true, nil
+	}
+	if loadTest.Status.Phase == loadTestV1.LoadTestFinished || loadTest.Status.Phase == loadTestV1.LoadTestErrored {
+		c.imagePullRetries.forget(key)
+		return true, nil
+	}
+
+	pods, err := c.loadTestPods(loadTest)
+	if err != nil {
+		return true, err
+	}
+
+	var retry []imagePullFailure
+	for _, pod := range pods {
+		if pod.GetDeletionTimestamp() != nil {
+			continue
+		}
+		for _, failure := range imagePullFailures(pod) {
+			if failure.permanent {
+				c.failImagePull(key, loadTest, failure.String())
+				return false, nil
+			}
+			if ownedByJob(pod) {
+				retry = append(retry, failure)
+			}
+		}
+	}
+
+	for _, failure := range retry {
+		retries, ok := c.imagePullRetries.retry(key, failure.pod.GetUID())
+		if !ok {
+			continue
+		}
+		if retries > c.cfg.ImagePullRetries {
+			c.failImagePull(key, loadTest, fmt.Sprintf("%s, giving up after %d retries", failure, c.cfg.ImagePullRetries))
+			return false, nil
+		}
+
+		c.logger.Info("Retrying image pull by recreating pod",
+			zap.String("loadtest", loadTest.GetName()),
+			zap.String("pod", failure.pod.GetName()),
+			zap.String("image", failure.image),
+			zap.Int("retry", retries),
+		)
+		err := c.kubeClientSet.CoreV1().Pods(failure.pod.GetNamespace()).Delete(ctx, failure.pod.GetName(), metaV1.DeleteOptions{})
+		if err != nil && !errors.IsNotFound(err) {
+			return true, err
+		}
+		c.recorder.Eventf(loadTest, coreV1.EventTypeNormal, reasonImagePullRetried, "Retry %d/%d: %s", retries, c.cfg.ImagePullRetries, failure)
+	}
+
+	return true, nil
+}
+
+// failImagePull moves the loadtest to errored phase because of an image pull failure
+func (c *Controller) failImagePull(key string, loadTest *loadTestV1.LoadTest, message string) {
+	c.logger.Info("Image pull failed, marking loadtest as errored",
+		zap.String("loadtest", loadTest.GetName()),
+		zap.String("reason", message),
+	)
+	c.recorder.Event(loadTest, coreV1.EventTypeWarning, reasonImagePullFailed, message)
+	setErrored(loadTest, reasonImagePullFailed, message)
+	c.imagePullRetries.forget(key)
+}
@@ -0,0 +1,146 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+	coreV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	coreListersV1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+func TestClassifyImagePull(t *testing.T) {
+	for _, tt := range []struct {
+		reason    string
+		message   string
+		failing   bool
+		permanent bool
+	}{
+		{"ContainerCreating", "", false, false},
+		{"ImagePullBackOff", `Back-off pulling image "grafana/k6:latest"`, true, false},
+		{"ErrImagePull", "rpc error: code = Unknown desc = context deadline exceeded", true, false},
+		{"ErrImagePull", `rpc error: code = NotFound desc = failed to pull and unpack image "grafana/k6:missing": not found`, true, true},
+		{"ErrImagePull", "pull access denied, repository does not exist or may require authorization: server message: insufficient_scope", true, true},
+		{"ErrImagePull", "unexpected status code 401 Unauthorized", true, true},
+		{"InvalidImageName", `couldn't parse image reference "grafana/k6:"`, true, true},
+	} {
+		t.Run(tt.reason+" "+tt.message, func(t *testing.T) {
+			failing, permanent := classifyImagePull(tt.reason, tt.message)
+			assert.Equal(t, tt.failing, failing)
+			assert.Equal(t, tt.permanent, permanent)
+		})
+	}
+}
+
+func TestCheckImagePulls(t *testing.T) {
+	isController := true
+	jobOwner := []metaV1.OwnerReference{{Kind: "Job", Name: "loadtest-job", Controller: &isController}}
+	newPod := func(uid string, owners []metaV1.OwnerReference, reason, message string) *coreV1.Pod {
+		return &coreV1.Pod{
+			ObjectMeta: metaV1.ObjectMeta{Name: uid, Namespace: "loadtest-namespace", UID: types.UID(uid), OwnerReferences: owners},
+			Status: coreV1.PodStatus{
+				ContainerStatuses: []coreV1.ContainerStatus{{
+					Name:  "loadtest",
+					Image: "grafana/k6:latest",
+					State: coreV1.ContainerState{Waiting: &coreV1.ContainerStateWaiting{Reason: reason, Message: message}},
+				}},
+			},
+		}
+	}
+
+	for _, tt := range []struct {
+		name          string
+		pods          []*coreV1.Pod
+		retries       int
+		expectOK      bool
+		expectPhase   loadTestV1.LoadTestPhase
+		expectDeleted []string
+	}{
+		{
+			name:        "pulling",
+			pods:        []*coreV1.Pod{newPod("pod-1", jobOwner, "ContainerCreating", "")},
+			retries:     1,
+			expectOK:    true,
+			expectPhase: loadTestV1.LoadTestStarting,
+		},
+		{
+			name:          "transient failure is retried",
+			pods:          []*coreV1.Pod{newPod("pod-1", jobOwner, "ImagePullBackOff", "Back-off pulling image")},
+			retries:       1,
+			expectOK:      true,
+			expectPhase:   loadTestV1.LoadTestStarting,
+			expectDeleted: []string{"pod-1"},
+		},
+		{
+			name:        "transient failure without retries",
+			pods:        []*coreV1.Pod{newPod("pod-1", jobOwner, "ImagePullBackOff", "Back-off pulling image")},
+			expectPhase: loadTestV1.LoadTestErrored,
+		},
+		{
+			name:        "transient failure of a pod without job is left to the kubelet",
+			pods:        []*coreV1.Pod{newPod("pod-1", nil, "ImagePullBackOff", "Back-off pulling image")},
+			retries:     1,
+			expectOK:    true,
+			expectPhase: loadTestV1.LoadTestStarting,
+		},
+		{
+			name:        "permanent failure",
+			pods:        []*coreV1.Pod{newPod("pod-1", jobOwner, "ErrImagePull", "manifest unknown")},
+			retries:     1,
+			expectPhase: loadTestV1.LoadTestErrored,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+			kubeClient := k8sfake.NewSimpleClientset()
+			for _, pod := range tt.pods {
+				require.NoError(t, indexer.Add(pod))
+				_, err := kubeClient.CoreV1().Pods(pod.Namespace).Create(context.Background(), pod, metaV1.CreateOptions{})
+				require.NoError(t, err)
+			}
+
+			c := &Controller{
+				cfg:              Config{ImagePullCheck: true, ImagePullRetries: tt.retries},
+				kubeClientSet:    kubeClient,
+				podsLister:       coreListersV1.NewPodLister(indexer),
+				recorder:         record.NewFakeRecorder(10),
+				imagePullRetries: newImagePullRetries(),
+				logger:           zaptest.NewLogger(t),
+			}
+			loadTest := &loadTestV1.LoadTest{
+				ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-name"},
+				Status: loadTestV1.LoadTestStatus{
+					Phase:     loadTestV1.LoadTestStarting,
+					Namespace: "loadtest-namespace",
+				},
+			}
+
+			ok, err := c.checkImagePulls(context.Background(), "loadtest-name", loadTest)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectOK, ok)
+			assert.Equal(t, tt.expectPhase, loadTest.Status.Phase)
+
+			pods, err := kubeClient.CoreV1().Pods("loadtest-namespace").List(context.Background(), metaV1.ListOptions{})
+			require.NoError(t, err)
+			assert.Len(t, pods.Items, len(tt.pods)-len(tt.expectDeleted))
+
+			if !ok {
+				return
+			}
+			// the deleted pod is still in the cache and must not be retried twice
+			ok, err = c.checkImagePulls(context.Background(), "loadtest-name", loadTest)
+			require.NoError(t, err)
+			assert.True(t, ok)
+			assert.Equal(t, tt.expectPhase, loadTest.Status.Phase)
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package controller
+
+import (
+	"fmt"
+
+	"go.uber.org/zap"
+	coreV1 "k8s.io/api/core/v1"
+
+	"github.com/hellofresh/kangal/pkg/backends"
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+// checkJobTimeout moves loadtests whose jobs were killed by Kubernetes for running longer than their timeout,
+// the Timeout of the loadtest or DefaultJobTimeout, to errored phase. The backends already error them from the
+// DeadlineExceeded condition of the job, this gives them the JobTimeout reason so they can be told apart from
+// failures of the load test itself.
+func (c *Controller) checkJobTimeout(loadTest *loadTestV1.LoadTest) {
+	if !backends.JobDeadlineExceeded(loadTest.Status.JobStatus) {
+		return
+	}
+	if loadTest.Status.Reason == reasonJobTimeout {
+		loadTest.Status.Phase = loadTestV1.LoadTestErrored
+		return
+	}
+
+	timeout := backends.JobTimeout(c.cfg.DefaultJobTimeout, loadTest.Spec.Timeout)
+	message := fmt.Sprintf("Loadtest jobs were killed after exceeding their timeout of %s", timeout)
+	c.logger.Info("Loadtest jobs exceeded their timeout, marking loadtest as errored",
+		zap.String("loadtest", loadTest.GetName()),
+		zap.Duration("timeout", timeout),
+	)
+	c.recorder.Event(loadTest, coreV1.EventTypeWarning, reasonJobTimeout, message)
+	setErrored(loadTest, reasonJobTimeout, message)
+}
@@ -0,0 +1,99 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zaptest"
+	batchV1 "k8s.io/api/batch/v1"
+	coreV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+func TestCheckJobTimeout(t *testing.T) {
+	deadlineExceeded := batchV1.JobStatus{
+		Conditions: []batchV1.JobCondition{
+			{Type: batchV1.JobFailed, Status: coreV1.ConditionTrue, Reason: "DeadlineExceeded"},
+		},
+	}
+
+	for _, tt := range []struct {
+		name          string
+		phase         loadTestV1.LoadTestPhase
+		reason        string
+		jobStatus     batchV1.JobStatus
+		timeout       time.Duration
+		expectPhase   loadTestV1.LoadTestPhase
+		expectReason  string
+		expectMessage string
+		expectEvents  int
+	}{
+		{
+			name:        "running job",
+			phase:       loadTestV1.LoadTestRunning,
+			jobStatus:   batchV1.JobStatus{Active: 1},
+			expectPhase: loadTestV1.LoadTestRunning,
+		},
+		{
+			name:        "failed job",
+			phase:       loadTestV1.LoadTestErrored,
+			jobStatus:   batchV1.JobStatus{Failed: 1},
+			expectPhase: loadTestV1.LoadTestErrored,
+		},
+		{
+			name:          "deadline exceeded with default timeout",
+			phase:         loadTestV1.LoadTestErrored,
+			jobStatus:     deadlineExceeded,
+			expectPhase:   loadTestV1.LoadTestErrored,
+			expectReason:  reasonJobTimeout,
+			expectMessage: "timeout of 1h0m0s",
+			expectEvents:  1,
+		},
+		{
+			name:          "deadline exceeded with loadtest timeout",
+			phase:         loadTestV1.LoadTestRunning,
+			jobStatus:     deadlineExceeded,
+			timeout:       10 * time.Minute,
+			expectPhase:   loadTestV1.LoadTestErrored,
+			expectReason:  reasonJobTimeout,
+			expectMessage: "timeout of 10m0s",
+			expectEvents:  1,
+		},
+		{
+			name:         "already timed out",
+			phase:        loadTestV1.LoadTestErrored,
+			reason:       reasonJobTimeout,
+			jobStatus:    deadlineExceeded,
+			expectPhase:  loadTestV1.LoadTestErrored,
+			expectReason: reasonJobTimeout,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := record.NewFakeRecorder(10)
+			c := &Controller{
+				cfg:      Config{DefaultJobTimeout: time.Hour},
+				recorder: recorder,
+				logger:   zaptest.NewLogger(t),
+			}
+			loadTest := &loadTestV1.LoadTest{
+				ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-name"},
+				Spec:       loadTestV1.LoadTestSpec{Timeout: tt.timeout},
+				Status: loadTestV1.LoadTestStatus{
+					Phase:     tt.phase,
+					Reason:    tt.reason,
+					JobStatus: tt.jobStatus,
+				},
+			}
+
+			c.checkJobTimeout(loadTest)
+			assert.Equal(t, tt.expectPhase, loadTest.Status.Phase)
+			assert.Equal(t, tt.expectReason, loadTest.Status.Reason)
+			assert.Contains(t, loadTest.Status.Message, tt.expectMessage)
+			assert.Len(t, recorder.Events, tt.expectEvents)
+		})
+	}
+}
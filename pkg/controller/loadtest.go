@@ -2,24 +2,33 @@ package controller
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"maps"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
 
 	"go.uber.org/zap"
 	batchV1 "k8s.io/api/batch/v1"
 	coreV1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	utilRuntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/scheme"
 	typedCoreV1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	batchListersV1 "k8s.io/client-go/listers/batch/v1"
 	coreListersV1 "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/record"
@@ -39,11 +48,105 @@ const (
 	trueString          = "true"
 )
 
+const (
+	// reasonZeroConcurrency is the Event reason for loadtests rejected because they would not generate any load
+	reasonZeroConcurrency = "ZeroConcurrency"
+	// reasonFlapping is the Event reason for loadtests changing phase too often
+	reasonFlapping = "Flapping"
+	// reasonNamespaceOwnerMismatch is the Event reason for loadtests whose namespace is owned by another object
+	reasonNamespaceOwnerMismatch = "NamespaceOwnerMismatch"
+	// reasonCleanUp is the Event reason for loadtests deleted after exceeding their lifetime
+	reasonCleanUp = "CleanUp"
+	// reasonCleanUpFailed is the Event reason for loadtests that could not be deleted after exceeding their lifetime
+	reasonCleanUpFailed = "CleanUpFailed"
+	// reasonInvalidTestFileTemplate is the Event reason for loadtests whose templated test file can't be rendered
+	reasonInvalidTestFileTemplate = "InvalidTestFileTemplate"
+	// reasonResourceBudgetExceeded is the Event reason for loadtests requesting more resources than a single loadtest may
+	reasonResourceBudgetExceeded = "ResourceBudgetExceeded"
+	// reasonImagePullRetried is the Event reason for pods recreated to retry a transient image pull failure
+	reasonImagePullRetried = "ImagePullRetried"
+	// reasonImagePullFailed is the Event reason for loadtests errored because their pods can't pull their image
+	reasonImagePullFailed = "ImagePullFailed"
+	// reasonQuotaExceeded is the Event reason for loadtests errored because their pods exceed the namespace quota
+	reasonQuotaExceeded = "QuotaExceeded"
+	// reasonSpecDrift is the Event reason for loadtests whose spec changed after their jobs were created
+	reasonSpecDrift = "SpecDrift"
+	// reasonPostCompletionStarted is the Event reason for loadtests whose post-completion job was started
+	reasonPostCompletionStarted = "PostCompletionStarted"
+	// reasonPostCompletionFailed is the Event reason for loadtests errored because their post-completion job failed
+	reasonPostCompletionFailed = "PostCompletionFailed"
+	// reasonEvicted is the Event reason for loadtests errored because one of their pods was evicted by node pressure
+	reasonEvicted = "Evicted"
+	// reasonJobTimeout is the Event reason for loadtests errored because their jobs exceeded their timeout
+	reasonJobTimeout = "JobTimeout"
+	// reasonInvalidImageReference is the Event reason for loadtests rejected because their image is not a valid reference
+	reasonInvalidImageReference = "InvalidImageReference"
+	// reasonExistingNamespaceRejected is the Event reason for loadtests rejected because their existing namespace can't be used
+	reasonExistingNamespaceRejected = "ExistingNamespaceRejected"
+	// reasonDependencyWaiting is the Event reason for loadtests waiting for the loadtest they depend on
+	reasonDependencyWaiting = "DependencyWaiting"
+	// reasonDependencyFailed is the Event reason for loadtests errored because of the loadtest they depend on
+	reasonDependencyFailed = "DependencyFailed"
+	// reasonUnsupportedFields is the Event reason for loadtests setting spec fields their backend ignores
+	reasonUnsupportedFields = "UnsupportedFields"
+	// reasonPhaseChanged is the Event reason for loadtests moving to another phase
+	reasonPhaseChanged = "PhaseChanged"
+	// reasonTestFileFetchFailed is the Event reason for loadtests errored because their test file could not be fetched
+	reasonTestFileFetchFailed = "TestFileFetchFailed"
+	// reasonSyncRetriesExhausted is the Event reason for loadtests errored after failing to sync MaxSyncRetries times
+	reasonSyncRetriesExhausted = "SyncRetriesExhausted"
+	// reasonSyncFailed is the status reason of loadtests whose last sync failed and is retried
+	reasonSyncFailed = "SyncFailed"
+
+	// namespaceControllerUIDLabel is the namespace label holding the UID of the loadtest owning it
+	namespaceControllerUIDLabel = "controller-uid"
+)
+
+// triggerSource is the reason a LoadTest was enqueued for reconciliation
+type triggerSource string
+
+const (
+	// triggerAdd is a LoadTest added to the informer, on creation or controller startup
+	triggerAdd triggerSource = "add"
+	// triggerUpdate is a change of a LoadTest
+	triggerUpdate triggerSource = "update"
+	// triggerResync is a periodic resync of the LoadTest informer
+	triggerResync triggerSource = "resync"
+	// triggerJob is a change of a Job owned by a LoadTest
+	triggerJob triggerSource = "job"
+	// triggerPod is a change of a Pod owned by a LoadTest
+	triggerPod triggerSource = "pod"
+	// triggerResume is the reconciliation being resumed
+	triggerResume triggerSource = "resume"
+)
+
 // MetricsReporter used to interface with the metrics configurations
 type MetricsReporter struct {
 	workQueueDepthStat   metric.Int64UpDownCounter
 	reconcileCountStat   metric.Int64UpDownCounter
 	reconcileLatencyStat metric.Int64Histogram
+	flappingCountStat    metric.Int64Counter
+	cleanUpCountStat     metric.Int64Counter
+
+	syncStatusErrorCountStat metric.Int64Counter
+	pausedStat               metric.Int64UpDownCounter
+
+	backendSyncLatencyStat       metric.Int64Histogram
+	backendSyncStatusLatencyStat metric.Int64Histogram
+	namespaceCreateLatencyStat   metric.Int64Histogram
+	namespaceCreateErrorStat     metric.Int64Counter
+	reconcileTriggerCountStat    metric.Int64Counter
+	loadTestsQueuedStat          metric.Int64UpDownCounter
+	admissionDelayedCountStat    metric.Int64Counter
+	evictionCountStat            metric.Int64Counter
+	eventsDroppedCountStat       metric.Int64Counter
+	loadTestsByPhaseStat         metric.Int64UpDownCounter
+	syncErrorCountStat           metric.Int64Counter
+	jobCreationDeferredCountStat metric.Int64Counter
+	loadTestDurationStat         metric.Float64Histogram
+
+	// runningLoadTests counts the running loadtests reported by kangal_loadtests_running
+	runningLoadTests *runningLoadTestsSource
 }
 
 // NewMetricsReporter contains loadtest metrics definition
@@ -73,10 +176,180 @@ func NewMetricsReporter(meter metric.Meter) (*MetricsReporter, error) {
 		return nil, fmt.Errorf("could not register reconcileLatencyStat metric: %w", err)
 	}
 
+	flappingCountStat, err := meter.Int64Counter(
+		"kangal_loadtest_flapping_total",
+		metric.WithDescription("Number of times a loadtest was detected flapping between phases"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not register flappingCountStat metric: %w", err)
+	}
+
+	cleanUpCountStat, err := meter.Int64Counter(
+		"kangal_loadtest_cleanup_total",
+		metric.WithDescription("Number of loadtests deleted after exceeding their lifetime"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not register cleanUpCountStat metric: %w", err)
+	}
+
+	syncStatusErrorCountStat, err := meter.Int64Counter(
+		"kangal_sync_status_errors_total",
+		metric.WithDescription("Number of failed loadtest status syncs"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not register syncStatusErrorCountStat metric: %w", err)
+	}
+
+	pausedStat, err := meter.Int64UpDownCounter(
+		"kangal_reconcile_paused",
+		metric.WithDescription("1 while the reconciliation of loadtests is paused"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not register pausedStat metric: %w", err)
+	}
+
+	backendSyncLatencyStat, err := meter.Int64Histogram(
+		"kangal_backend_sync_duration",
+		metric.WithDescription("Duration of backend Sync calls"),
+		metric.WithUnit("ms"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not register backendSyncLatencyStat metric: %w", err)
+	}
+
+	backendSyncStatusLatencyStat, err := meter.Int64Histogram(
+		"kangal_backend_syncstatus_duration",
+		metric.WithDescription("Duration of backend SyncStatus calls"),
+		metric.WithUnit("ms"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not register backendSyncStatusLatencyStat metric: %w", err)
+	}
+
+	namespaceCreateLatencyStat, err := meter.Int64Histogram(
+		"kangal_namespace_create_latency",
+		metric.WithDescription("Duration of loadtest namespace creation"),
+		metric.WithUnit("ms"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not register namespaceCreateLatencyStat metric: %w", err)
+	}
+
+	namespaceCreateErrorStat, err := meter.Int64Counter(
+		"kangal_namespace_create_errors_total",
+		metric.WithDescription("Number of loadtest namespaces that could not be created by error reason"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not register namespaceCreateErrorStat metric: %w", err)
+	}
+
+	reconcileTriggerCountStat, err := meter.Int64Counter(
+		"kangal_reconcile_trigger_total",
+		metric.WithDescription("Number of loadtests enqueued for reconciliation by trigger source"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not register reconcileTriggerCountStat metric: %w", err)
+	}
+
+	loadTestsQueuedStat, err := meter.Int64UpDownCounter(
+		"kangal_loadtests_queued",
+		metric.WithDescription("Number of loadtests waiting for capacity by backend type"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not register loadTestsQueuedStat metric: %w", err)
+	}
+
+	admissionDelayedCountStat, err := meter.Int64Counter(
+		"kangal_loadtest_admission_delayed_total",
+		metric.WithDescription("Number of loadtests held back for lack of capacity by backend type"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not register admissionDelayedCountStat metric: %w", err)
+	}
+
+	evictionCountStat, err := meter.Int64Counter(
+		"kangal_loadtest_evictions_total",
+		metric.WithDescription("Number of loadtests errored because a pod was evicted by node pressure"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not register evictionCountStat metric: %w", err)
+	}
+
+	eventsDroppedCountStat, err := meter.Int64Counter(
+		"kangal_events_dropped_total",
+		metric.WithDescription("Number of Events dropped because their loadtest exceeded the Event rate limit"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not register eventsDroppedCountStat metric: %w", err)
+	}
+
+	loadTestsByPhaseStat, err := meter.Int64UpDownCounter(
+		"kangal_loadtests_by_phase",
+		metric.WithDescription("Number of loadtests by phase"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not register loadTestsByPhaseStat metric: %w", err)
+	}
+
+	syncErrorCountStat, err := meter.Int64Counter(
+		"kangal_sync_errors_total",
+		metric.WithDescription("Number of failed loadtest syncs, including the ones whose error was not logged again"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not register syncErrorCountStat metric: %w", err)
+	}
+
+	jobCreationDeferredCountStat, err := meter.Int64Counter(
+		"kangal_job_creation_deferred_total",
+		metric.WithDescription("Number of loadtest syncs deferred because the maximum of jobs in their namespace was reached"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not register jobCreationDeferredCountStat metric: %w", err)
+	}
+
+	loadTestDurationStat, err := meter.Float64Histogram(
+		"kangal_loadtest_duration",
+		metric.WithDescription("Duration of loadtests from their creation until they finished or errored"),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not register loadTestDurationStat metric: %w", err)
+	}
+
+	runningLoadTests := &runningLoadTestsSource{}
+	_, err = meter.Int64ObservableGauge(
+		"kangal_loadtests_running",
+		metric.WithDescription("Number of loadtests that have a namespace and are neither finished nor errored"),
+		metric.WithInt64Callback(runningLoadTests.observe),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not register loadTestsRunningStat metric: %w", err)
+	}
+
 	return &MetricsReporter{
-		workQueueDepthStat:   workQueueDepthStat,
-		reconcileCountStat:   reconcileCountStat,
-		reconcileLatencyStat: reconcileLatencyStat,
+		workQueueDepthStat:       workQueueDepthStat,
+		reconcileCountStat:       reconcileCountStat,
+		reconcileLatencyStat:     reconcileLatencyStat,
+		flappingCountStat:        flappingCountStat,
+		cleanUpCountStat:         cleanUpCountStat,
+		syncStatusErrorCountStat: syncStatusErrorCountStat,
+		pausedStat:               pausedStat,
+
+		backendSyncLatencyStat:       backendSyncLatencyStat,
+		backendSyncStatusLatencyStat: backendSyncStatusLatencyStat,
+		namespaceCreateLatencyStat:   namespaceCreateLatencyStat,
+		namespaceCreateErrorStat:     namespaceCreateErrorStat,
+		reconcileTriggerCountStat:    reconcileTriggerCountStat,
+		loadTestsQueuedStat:          loadTestsQueuedStat,
+		admissionDelayedCountStat:    admissionDelayedCountStat,
+		evictionCountStat:            evictionCountStat,
+		eventsDroppedCountStat:       eventsDroppedCountStat,
+		loadTestsByPhaseStat:         loadTestsByPhaseStat,
+		syncErrorCountStat:           syncErrorCountStat,
+		jobCreationDeferredCountStat: jobCreationDeferredCountStat,
+		loadTestDurationStat:         loadTestDurationStat,
+
+		runningLoadTests: runningLoadTests,
 	}, nil
 }
 
@@ -91,6 +364,11 @@ type Controller struct {
 
 	podsLister coreListersV1.PodLister
 	podsSynced cache.InformerSynced
+	// pods runs the pod informer while loadtests are active with IdleScaleDown, nil otherwise
+	pods *onDemandPodInformer
+
+	jobsLister batchListersV1.JobLister
+	jobsSynced cache.InformerSynced
 
 	loadtestsLister listers.LoadTestLister
 	loadtestsSynced cache.InformerSynced
@@ -106,6 +384,36 @@ type Controller struct {
 	recorder record.EventRecorder
 
 	statsClient MetricsReporter
+	// flapping tracks phase transitions to detect loadtests oscillating between phases
+	flapping *flappingDetector
+	// imagePullRetries tracks the pods recreated to retry their image pull
+	imagePullRetries *imagePullRetries
+	// statusThrottle limits status updates that don't change the phase
+	statusThrottle *statusThrottle
+	// admissionQueue tracks the loadtests held back by MaxRunningLoadTests
+	admissionQueue *admissionQueue
+	// reconciles keeps the last reconciliation of each loadtest for the debug endpoint
+	reconciles *reconcileHistory
+	// phases keeps the phase each loadtest is counted in by the loadtests by phase metric
+	phases *phaseCounter
+	// syncErrors deduplicates the logs of repeated sync errors
+	syncErrors *syncErrors
+	// syncRetries tracks the backoff of failed backend syncs when MaxSyncRetries is set
+	syncRetries *syncRetries
+	// statusBatcher coalesces status updates, nil when they are written right away
+	statusBatcher *statusBatcher
+	// eventLimiter is the recorder when Events are rate limited, nil otherwise
+	eventLimiter *rateLimitedRecorder
+	// paused skips the reconciliation of all loadtests, see Pause
+	paused atomic.Bool
+	// suspension is the suspension of the jobs started by Pause, nil when none is running
+	suspendMu  sync.Mutex
+	suspension *suspension
+	// workers tracks the running workers, they stop taking work queue items once stopping is set
+	workers  sync.WaitGroup
+	stopping atomic.Bool
+	// statusViaUpdate writes statuses with Update as the LoadTest CRD has no status subresource
+	statusViaUpdate bool
 
 	registry backends.Registry
 	logger   *zap.Logger
@@ -123,7 +431,6 @@ func NewController(
 	logger *zap.Logger,
 ) *Controller {
 	namespaceInformer := kubeInformerFactory.Core().V1().Namespaces()
-	podInformer := kubeInformerFactory.Core().V1().Pods()
 	jobInformer := kubeInformerFactory.Batch().V1().Jobs()
 
 	loadTestInformer := kangalInformerFactory.Kangal().V1().LoadTests()
@@ -149,32 +456,76 @@ func NewController(
 		namespacesLister: namespaceInformer.Lister(),
 		namespacesSynced: namespaceInformer.Informer().HasSynced,
 
-		podsLister: podInformer.Lister(),
-		podsSynced: podInformer.Informer().HasSynced,
+		jobsLister: jobInformer.Lister(),
+		jobsSynced: jobInformer.Informer().HasSynced,
 
 		loadtestsLister: loadTestInformer.Lister(),
 		loadtestsSynced: loadTestInformer.Informer().HasSynced,
 
-		workQueue:   workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "LoadTest"),
 		recorder:    recorder,
 		statsClient: statsClient,
+		flapping:    newFlappingDetector(cfg.FlappingThreshold, cfg.FlappingWindow),
+
+		imagePullRetries: newImagePullRetries(),
+		statusThrottle:   newStatusThrottle(cfg.StatusUpdateInterval),
+		admissionQueue:   newAdmissionQueue(),
+		reconciles:       newReconcileHistory(),
+		phases:           newPhaseCounter(statsClient.loadTestsByPhaseStat),
+		syncErrors:       newSyncErrors(cfg.SyncErrorLogInterval),
+		syncRetries:      newSyncRetries(),
 
 		registry: registry,
 		logger:   logger,
 	}
+	controller.workQueue = newPriorityQueue("LoadTest", cfg.HighPriorityWeight, controller.loadTestPriority)
+	if statsClient.runningLoadTests != nil {
+		statsClient.runningLoadTests.set(controller.runningLoadTests)
+	}
+	if cfg.EventRateLimit > 0 {
+		controller.eventLimiter = newRateLimitedRecorder(recorder, cfg.EventRateLimit, func(reason string) {
+			statsClient.eventsDroppedCountStat.Add(context.Background(), 1, metric.WithAttributes(attribute.String("reason", reason)))
+		})
+		controller.recorder = controller.eventLimiter
+	}
+	if cfg.StatusBatchInterval > 0 {
+		controller.statusBatcher = newStatusBatcher(cfg.StatusBatchInterval, cfg.SyncHandlerTimeout,
+			controller.writeLoadTestStatus, controller.staleLoadTestStatus)
+	}
+
+	if cfg.Paused {
+		controller.Pause()
+	}
 
 	logger.Debug("Setting up event handlers")
 
 	// Set up an event handler for when a LoadTest resources is added
 	loadTestInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
-		AddFunc: controller.enqueueLoadTest,
-		UpdateFunc: func(_, new interface{}) {
-			controller.enqueueLoadTest(new)
+		AddFunc: func(obj interface{}) {
+			controller.enqueueLoadTest(obj, triggerAdd)
+		},
+		UpdateFunc: func(old, new interface{}) {
+			source := triggerUpdate
+			if new.(*loadTestV1.LoadTest).ResourceVersion == old.(*loadTestV1.LoadTest).ResourceVersion {
+				// periodic resync sends update events for all known LoadTests
+				source = triggerResync
+			}
+			controller.enqueueLoadTest(new, source)
+		},
+		DeleteFunc: func(obj interface{}) {
+			// deleted loadtests are not necessarily reconciled again, stop counting them right away
+			key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+			if err != nil {
+				utilRuntime.HandleError(err)
+				return
+			}
+			controller.phases.forget(context.Background(), key)
 		},
 	})
 
 	jobInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
-		AddFunc: controller.handleObject,
+		AddFunc: func(obj interface{}) {
+			controller.handleObject(obj, triggerJob)
+		},
 		UpdateFunc: func(old, new interface{}) {
 			newJob := new.(*batchV1.Job)
 			oldJob := old.(*batchV1.Job)
@@ -182,12 +533,14 @@ func NewController(
 			if newJob.ResourceVersion == oldJob.ResourceVersion {
 				return
 			}
-			controller.handleObject(new)
+			controller.handleObject(new, triggerJob)
 		},
 	})
 
-	podInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
-		AddFunc: controller.handleObject,
+	podHandler := cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			controller.handleObject(obj, triggerPod)
+		},
 		UpdateFunc: func(old, new interface{}) {
 			newPod := new.(*coreV1.Pod)
 			oldPod := old.(*coreV1.Pod)
@@ -197,43 +550,141 @@ func NewController(
 				// Two different versions of the same Job will always have different RVs.
 				return
 			}
-			controller.handleObject(new)
+			controller.handleObject(new, triggerPod)
 		},
-	})
+	}
+
+	if cfg.IdleScaleDown {
+		// the pod informer is started by syncHandler once a loadtest is active
+		controller.pods = newOnDemandPodInformer(kubeClientSet, podHandler, logger)
+		controller.podsLister = onDemandPodLister{informer: controller.pods}
+		controller.podsSynced = func() bool { return true }
+	} else {
+		podInformer := kubeInformerFactory.Core().V1().Pods()
+		controller.podsLister = podInformer.Lister()
+		controller.podsSynced = podInformer.Informer().HasSynced
+		podInformer.Informer().AddEventHandler(podHandler)
+	}
 
 	return controller
 }
 
 // Run will set up the event handlers for types we are interested in, as well
 // as syncing informer caches and starting workers. It will block until stopCh
-// is closed, at which point it will shutdown the workQueue and wait for
-// workers to finish processing their current work items.
+// is closed, at which point it will shutdown the workQueue, drain it if
+// ShutdownDrainTimeout is set, and stop the workers, waiting up to
+// ShutdownWorkerTimeout for them to finish the loadtests they are syncing.
 func (c *Controller) Run(numThreads int, stopCh <-chan struct{}) error {
 	defer utilRuntime.HandleCrash()
-	defer c.workQueue.ShutDown()
 
 	// Start the informer factories to begin populating the informer caches
 	c.logger.Info("Starting loadtest controller")
 
 	// Wait for the caches to be synced before starting workers
 	c.logger.Debug("Waiting for informer caches to sync")
-	if ok := cache.WaitForCacheSync(stopCh, c.namespacesSynced, c.podsSynced, c.loadtestsSynced); !ok {
+	if ok := cache.WaitForCacheSync(stopCh, c.namespacesSynced, c.podsSynced, c.jobsSynced, c.loadtestsSynced); !ok {
+		c.workQueue.ShutDown()
 		return fmt.Errorf("failed to wait for caches to sync")
 	}
 
+	// workers are kept running after stopCh is closed to drain the work queue
+	workersStopCh := make(chan struct{})
+
+	if c.statusBatcher != nil {
+		c.statusBatcher.start()
+	}
+
 	c.logger.Debug("Starting workers")
 	// Launch numThreads number of threads to process LoadTest resources
 	for i := 0; i < numThreads; i++ {
-		go wait.Until(c.runWorker, time.Second, stopCh)
+		c.workers.Add(1)
+		go func() {
+			defer c.workers.Done()
+			wait.Until(c.runWorker, time.Second, workersStopCh)
+		}()
+	}
+
+	if c.pods != nil {
+		go wait.Until(c.checkIdle, c.cfg.IdleCheckInterval, workersStopCh)
 	}
 
 	c.logger.Debug("Started workers")
 	<-stopCh
 	c.logger.Debug("Shutting down workers")
 
+	c.shutDownWorkQueue(c.cfg.ShutdownDrainTimeout)
+	close(workersStopCh)
+	c.waitForWorkers(c.cfg.ShutdownWorkerTimeout)
+
+	// write the status updates of the drained loadtests
+	if c.statusBatcher != nil {
+		c.statusBatcher.stop()
+	}
+
+	if c.cfg.SuspendJobsOnShutdown {
+		ctx, cancel := context.WithTimeout(context.Background(), c.cfg.SyncHandlerTimeout)
+		defer cancel()
+		c.suspendJobs(ctx, nil)
+	}
+
+	if c.pods != nil {
+		c.pods.stop()
+	}
+
 	return nil
 }
 
+// shutDownWorkQueue stops accepting new items and, if timeout is not 0, waits up to timeout
+// for the queued and in-flight items to be processed
+func (c *Controller) shutDownWorkQueue(timeout time.Duration) {
+	// items added after shut down are dropped, queued ones are still handed out to workers
+	c.workQueue.ShutDown()
+	if timeout == 0 {
+		return
+	}
+
+	c.logger.Info("Draining work queue", zap.Int("items", c.workQueue.Len()), zap.Duration("timeout", timeout))
+
+	drained := make(chan struct{})
+	go func() {
+		defer close(drained)
+		for c.workQueue.Len() > 0 {
+			time.Sleep(100 * time.Millisecond)
+		}
+		// wait for items being processed
+		c.workQueue.ShutDownWithDrain()
+	}()
+
+	select {
+	case <-drained:
+		c.logger.Info("Work queue drained")
+	case <-time.After(timeout):
+		c.logger.Warn("Timed out draining work queue", zap.Int("items", c.workQueue.Len()))
+	}
+}
+
+// waitForWorkers stops the workers from taking further work queue items and, if timeout is not 0, waits up
+// to timeout for them to finish the items they are processing, so that no loadtest is left half synced
+func (c *Controller) waitForWorkers(timeout time.Duration) {
+	c.stopping.Store(true)
+	if timeout == 0 {
+		return
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		c.workers.Wait()
+	}()
+
+	select {
+	case <-done:
+		c.logger.Info("Workers stopped")
+	case <-time.After(timeout):
+		c.logger.Warn("Timed out waiting for workers to stop", zap.Duration("timeout", timeout))
+	}
+}
+
 // runWorker is a long-running function that will continually call the
 // processNextWorkItem function in order to read and process a message on the
 // workQueue.
@@ -251,6 +702,12 @@ func (c *Controller) processNextWorkItem() bool {
 		return false
 	}
 
+	// items left in the queue once the workers are stopping are synced again by the next controller
+	if c.stopping.Load() {
+		c.workQueue.Done(obj)
+		return false
+	}
+
 	// Send the metrics for the current queue depth
 	c.statsClient.workQueueDepthStat.Add(context.Background(), int64(c.workQueue.Len()))
 
@@ -297,12 +754,24 @@ func (c *Controller) processNextWorkItem() bool {
 		if err := c.syncHandler(key); err != nil {
 			// Put the item back on the workQueue to handle any transient errors.
 			c.workQueue.AddRateLimited(key)
-			c.logger.Error("error syncing loadtest, re-queuing", zap.String("loadtest", key), zap.Error(err))
+			c.statsClient.syncErrorCountStat.Add(context.Background(), 1)
+
+			// repeated errors are only logged once per SyncErrorLogInterval
+			log, repeats := c.syncErrors.observe(key, err)
+			if !log {
+				return nil
+			}
+			if repeats > 0 {
+				c.logger.Error("error syncing loadtest repeatedly, re-queuing", zap.String("loadtest", key), zap.Error(err), zap.Int("repeats", repeats))
+			} else {
+				c.logger.Error("error syncing loadtest, re-queuing", zap.String("loadtest", key), zap.Error(err))
+			}
 			return fmt.Errorf("error syncing '%s': %s, requeuing", key, err.Error())
 		}
 		// Finally, if no error occurs we Forget this item so it does not
 		// get queued again until another change happens.
 		c.workQueue.Forget(obj)
+		c.syncErrors.forget(key)
 		c.logger.Debug("Successfully synced", zap.String("loadtest", key))
 		return nil
 	}(obj)
@@ -314,13 +783,43 @@ func (c *Controller) processNextWorkItem() bool {
 	return true
 }
 
+// syncTimeout returns the time limit for the sync of the loadtest, the longest of SyncHandlerTimeout and the time limit
+// recommended by its backend. Loadtests which can't be resolved to a backend are synced within SyncHandlerTimeout.
+func (c *Controller) syncTimeout(key string) time.Duration {
+	timeout := c.cfg.SyncHandlerTimeout
+
+	_, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return timeout
+	}
+	loadTest, err := c.loadtestsLister.Get(name)
+	if err != nil {
+		return timeout
+	}
+	backend, err := c.registry.GetBackend(loadTest.Spec.Type)
+	if err != nil {
+		return timeout
+	}
+
+	if hint, ok := backend.(backends.BackendSyncTimeout); ok && hint.SyncTimeout() > timeout {
+		return hint.SyncTimeout()
+	}
+	return timeout
+}
+
 // syncHandler compares the actual state with the desired, and attempts to
 // converge the two. It then updates the Status block of the LoadTest resource
 // with the current status of the resource.
-func (c *Controller) syncHandler(key string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), c.cfg.SyncHandlerTimeout)
+func (c *Controller) syncHandler(key string) (err error) {
+	reconcileStart := time.Now()
+	ctx, cancel := context.WithTimeout(context.Background(), c.syncTimeout(key))
 	defer cancel()
 
+	ctx, span := tracer.Start(ctx, "syncHandler", trace.WithAttributes(attribute.String("loadtest", key)))
+	defer func() {
+		endSpan(span, err)
+	}()
+
 	logger := c.logger.With(
 		zap.String("loadtest", key),
 	)
@@ -331,12 +830,29 @@ func (c *Controller) syncHandler(key string) error {
 		return nil
 	}
 
+	// leave loadtests untouched while paused, they are enqueued again on Resume
+	if c.Paused() {
+		logger.Debug("Reconciliation paused, skipping loadtest")
+		return nil
+	}
+
 	loadTestFromCache, err := c.loadtestsLister.Get(name)
 	if err != nil {
 		// The LoadTest resource may no longer exist, in which case we stop
 		// processing.
 		if errors.IsNotFound(err) {
 			utilRuntime.HandleError(fmt.Errorf("loadtest '%s' in work queue no longer exists", key))
+			c.flapping.forget(key)
+			c.imagePullRetries.forget(key)
+			c.statusThrottle.forget(key)
+			c.releaseAdmission(ctx, key)
+			c.reconciles.forget(key)
+			c.phases.forget(ctx, key)
+			c.syncErrors.forget(key)
+			c.syncRetries.forget(key)
+			if c.eventLimiter != nil {
+				c.eventLimiter.forget(key)
+			}
 			return nil
 		}
 
@@ -348,59 +864,394 @@ func (c *Controller) syncHandler(key string) error {
 		}
 		return err
 	}
+	// leave loadtests paused by annotation untouched, removing the annotation enqueues them again
+	if loadTestPaused(loadTestFromCache) {
+		logger.Info("Loadtest paused by annotation, skipping loadtest", zap.String("annotation", pausedAnnotation))
+		return nil
+	}
+
 	// copy object before mutate it
 	loadTest := loadTestFromCache.DeepCopy()
 
-	// get report url
-	var reportURL string
-	if c.cfg.KangalProxyURL != "" {
-		reportURL = fmt.Sprintf("%s/load-test/%s/report", c.cfg.KangalProxyURL, loadTest.GetName())
+	defer func() {
+		c.reconciles.record(key, reconcileStart, err)
+		c.phases.record(ctx, key, loadTest.Status.Phase)
+	}()
+
+	// run the pod informer for active loadtests
+	if c.pods != nil && activeLoadTest(loadTest) {
+		if err := c.pods.start(ctx); err != nil {
+			return err
+		}
 	}
 
+	// get report url
+	reportURL := c.reportURL(loadTest)
+	c.recordReportLocation(loadTest, reportURL)
+	c.recordRetentionClass(loadTest, reportURL)
+
 	// get backend
 	backend, err := c.registry.GetBackend(loadTest.Spec.Type)
 	if err != nil {
-		return fmt.Errorf("failed to resolve backend: %w", err)
+		return fmt.Errorf("failed to resolve backend: backend %s not registered; available: %s: %w",
+			loadTest.Spec.Type, strings.Join(c.registry.List(), ", "), err)
 	}
+	span.SetAttributes(attribute.String("type", loadTest.Spec.Type.String()))
 
 	// ensure that status is updated if any of the following fails
 	defer c.updateLoadTestStatus(ctx, key, loadTest, loadTestFromCache)
+	// the error of a failed sync is written with the status above
+	defer func() {
+		if err != nil {
+			setSyncFailure(loadTest, err)
+		}
+	}()
+
+	// reject loadtests that would not generate any load or exceed the resource budget before creating any resources
+	if loadTest.Status.Namespace == "" && (!c.checkConcurrency(backend, loadTest) || !c.checkResourceBudget(backend, loadTest) ||
+		!c.checkImageReferences(loadTest) || !c.checkExistingNamespace(loadTest)) {
+		c.cleanUpStaleLoadTest(ctx, key, loadTest)
+		return nil
+	}
+
+	// warn about spec fields the backend ignores
+	c.checkUnsupportedFields(backend, loadTest)
+
+	// hold back loadtests until the loadtest they depend on is finished
+	if !c.checkDependency(key, loadTest) {
+		if loadTest.Status.Phase == loadTestV1.LoadTestErrored {
+			c.cleanUpStaleLoadTest(ctx, key, loadTest)
+		}
+		return nil
+	}
+
+	// hold back loadtests while the maximum of running loadtests is reached
+	admitted, err := c.admitLoadTest(ctx, key, loadTest)
+	if err != nil || !admitted {
+		return err
+	}
 
 	// check or create namespace
-	err = c.checkOrCreateNamespace(ctx, loadTest)
+	createNamespace := loadTest.Status.Namespace == ""
+	start := time.Now()
+	namespaceCtx, namespaceSpan := tracer.Start(ctx, "checkOrCreateNamespace", loadTestSpanAttributes(loadTest))
+	err = c.checkOrCreateNamespace(namespaceCtx, loadTest)
+	endSpan(namespaceSpan, err)
+	if createNamespace {
+		c.recordSyncPhase(ctx, c.statsClient.namespaceCreateLatencyStat, loadTest, start, err)
+	}
+	if err != nil {
+		return err
+	}
+
+	// resume jobs suspended during maintenance
+	err = c.resumeJobs(ctx, loadTest)
+	if err != nil {
+		return err
+	}
+
+	// share object storage credentials with the test file fetcher
+	err = c.checkOrCreateTestFileCredentials(ctx, loadTest)
+	if err != nil {
+		return err
+	}
+
+	// pods can only pull images with the secrets of their own namespace
+	err = c.checkOrCreateImagePullSecrets(ctx, loadTest)
 	if err != nil {
 		return err
 	}
 
+	// pods can only run with the service accounts of their own namespace
+	err = c.checkOrCreatePodServiceAccount(ctx, loadTest)
+	if err != nil {
+		return err
+	}
+
+	// render templated test files before the backend creates resources from them
+	syncLoadTest := c.templateTestFile(loadTest)
+	if syncLoadTest == nil {
+		c.cleanUpStaleLoadTest(ctx, key, loadTest)
+		return nil
+	}
+
+	// let the backend stamp the jobs it creates with the spec hash checked for spec drift
+	syncLoadTest, err = c.withSpecHash(loadTest, syncLoadTest)
+	if err != nil {
+		return err
+	}
+
+	// hold back the job creation while the namespace runs the maximum of jobs
+	allowed, err := c.checkNamespaceJobs(ctx, key, loadTest)
+	if err != nil || !allowed {
+		return err
+	}
+
+	// wait for the backoff of a failed backend sync, status updates enqueue the loadtest before it elapsed
+	if wait := c.syncRetries.wait(key); wait > 0 {
+		c.workQueue.AddAfter(key, wait)
+		return nil
+	}
+
 	// sync backend resources
-	err = backend.Sync(ctx, *loadTest, reportURL)
+	start = time.Now()
+	syncCtx, syncSpan := tracer.Start(ctx, "backend.Sync", loadTestSpanAttributes(loadTest))
+	err = backend.Sync(syncCtx, *syncLoadTest, reportURL)
+	endSpan(syncSpan, err)
+	c.recordSyncPhase(ctx, c.statsClient.backendSyncLatencyStat, loadTest, start, err)
+	if err != nil {
+		if !c.checkQuotaSyncError(loadTest, err) {
+			c.cleanUpStaleLoadTest(ctx, key, loadTest)
+			return nil
+		}
+		return c.checkSyncRetries(key, loadTest, err)
+	}
+	c.resetSyncRetries(key, loadTest)
+
+	// retry transient image pull failures and fail fast on permanent ones
+	pulling, err := c.checkImagePulls(ctx, key, loadTest)
 	if err != nil {
 		return err
 	}
+	if !pulling {
+		c.cleanUpStaleLoadTest(ctx, key, loadTest)
+		return nil
+	}
+
+	// keep the last known status in case the backend fails to read the current one
+	lastStatus := loadTest.Status.DeepCopy()
 
 	// sync backend status
-	err = backend.SyncStatus(ctx, *loadTest, &loadTest.Status)
+	start = time.Now()
+	syncStatusCtx, syncStatusSpan := tracer.Start(ctx, "backend.SyncStatus", loadTestSpanAttributes(loadTest))
+	err = backend.SyncStatus(syncStatusCtx, *loadTest, &loadTest.Status)
+	endSpan(syncStatusSpan, err)
+	c.recordSyncPhase(ctx, c.statsClient.backendSyncStatusLatencyStat, loadTest, start, err)
+	if err != nil {
+		return c.handleSyncStatusError(ctx, loadTest, lastStatus, err)
+	}
+
+	// error loadtests whose jobs were killed for exceeding their timeout
+	c.checkJobTimeout(loadTest)
+
+	// error loadtests whose pods are rejected by the namespace quota
+	err = c.checkQuota(ctx, loadTest)
 	if err != nil {
 		return err
 	}
 
-	// check and delete stale finished/errored loadtests
-	if c.cfg.CleanUpThreshold != 0 && checkLoadTestLifeTimeExceeded(loadTest, c.cfg.CleanUpThreshold) {
-		logger.Info("Deleting loadtest due to exceeded lifetime",
-			zap.String("phase", loadTest.Status.Phase.String()),
-		)
-		c.deleteLoadTest(ctx, key, loadTest)
+	// error loadtests whose pods were evicted by node pressure
+	err = c.checkEvictions(ctx, loadTest)
+	if err != nil {
+		return err
 	}
 
+	// error loadtests whose test file could not be fetched from its URL
+	err = c.checkTestFileFetch(loadTest)
+	if err != nil {
+		return err
+	}
+
+	// flag running loadtests whose spec changed after their jobs were created
+	err = c.checkSpecDrift(loadTest)
+	if err != nil {
+		return err
+	}
+
+	// gate finished loadtests on their thresholds
+	c.checkGate(loadTest)
+
+	// run the post-completion job of succeeded loadtests before they are finished
+	err = c.checkPostCompletion(ctx, loadTest)
+	if err != nil {
+		return err
+	}
+
+	c.labelLongRunning(ctx, loadTest)
+
+	c.cleanUpStaleLoadTest(ctx, key, loadTest)
+
+	return nil
+}
+
+// recordSyncPhase records the duration of a reconcile phase of the loadtest started at start, when enabled
+func (c *Controller) recordSyncPhase(ctx context.Context, histogram metric.Int64Histogram, loadTest *loadTestV1.LoadTest, start time.Time, err error) {
+	if !c.cfg.SyncPhaseMetrics {
+		return
+	}
+
+	status := trueString
+	if err != nil {
+		status = falseString
+	}
+	histogram.Record(ctx, int64(time.Since(start)/time.Millisecond), metric.WithAttributes(
+		attribute.String("type", loadTest.Spec.Type.String()),
+		attribute.String("success", status),
+	))
+}
+
+// reportURL returns the URL the loadtest uploads its report to, empty when KangalProxyURL is not set
+func (c *Controller) reportURL(loadTest *loadTestV1.LoadTest) string {
+	if c.cfg.KangalProxyURL == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s/load-test/%s/report", c.cfg.KangalProxyURL, loadTest.GetName())
+}
+
+// handleSyncStatusError decides if a SyncStatus failure requeues the loadtest. When SyncStatus errors are
+// tolerated the loadtest keeps its last known status and is synced again on the next informer event, as
+// a failed status read does not mean that the resources created by Sync are not progressing
+func (c *Controller) handleSyncStatusError(ctx context.Context, loadTest *loadTestV1.LoadTest, lastStatus *loadTestV1.LoadTestStatus, err error) error {
+	c.statsClient.syncStatusErrorCountStat.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("type", loadTest.Spec.Type.String()),
+		attribute.Bool("tolerated", c.cfg.TolerateSyncStatusErrors),
+	))
+
+	if !c.cfg.TolerateSyncStatusErrors {
+		return err
+	}
+
+	c.logger.Warn("Failed to sync loadtest status, keeping last known status",
+		zap.String("loadtest", loadTest.GetName()),
+		zap.String("phase", lastStatus.Phase.String()),
+		zap.Error(err),
+	)
+	loadTest.Status = *lastStatus
 	return nil
 }
 
+// cleanUpStaleLoadTest deletes finished/errored loadtests once CleanUpThreshold is exceeded, and active
+// loadtests once MaxTestLifetime is exceeded. The CleanupThreshold of the loadtest spec overrides
+// CleanUpThreshold, a value of 0 never deletes the loadtest
+func (c *Controller) cleanUpStaleLoadTest(ctx context.Context, key string, loadTest *loadTestV1.LoadTest) {
+	logger := c.logger.With(
+		zap.String("loadtest", key),
+	)
+
+	// a zero cleanup threshold keeps finished/errored loadtests, the backstop still applies to them
+	lifetime := c.cfg.CleanUpThreshold
+	if loadTest.Spec.CleanupThreshold != nil {
+		lifetime = *loadTest.Spec.CleanupThreshold
+	}
+
+	// check stale finished/errored loadtests, and active loadtests exceeding the backstop
+	now := time.Now()
+	exceeded := lifetime != 0 && checkLoadTestLifeTimeExceeded(loadTest, lifetime, c.cfg.CleanUpClockSkewTolerance, now)
+	if !exceeded && c.cfg.MaxTestLifetime != 0 &&
+		checkLoadTestMaxLifetimeExceeded(loadTest, c.cfg.MaxTestLifetime, c.cfg.CleanUpClockSkewTolerance, now) {
+		lifetime = c.cfg.MaxTestLifetime
+		exceeded = true
+	}
+	if !exceeded {
+		return
+	}
+
+	logger.Info("Deleting loadtest due to exceeded lifetime",
+		zap.String("phase", loadTest.Status.Phase.String()),
+		zap.Duration("lifetime", lifetime),
+	)
+
+	result := "deleted"
+	if err := c.deleteLoadTest(ctx, key, loadTest); err != nil {
+		result = "failed"
+		c.cleanUpEvent(loadTest, coreV1.EventTypeWarning, reasonCleanUpFailed,
+			fmt.Sprintf("Failed to delete %s loadtest after lifetime of %s: %s", loadTest.Status.Phase, lifetime, err))
+	} else {
+		c.cleanUpEvent(loadTest, coreV1.EventTypeWarning, reasonCleanUp,
+			fmt.Sprintf("Deleted %s loadtest after lifetime of %s", loadTest.Status.Phase, lifetime))
+	}
+	c.statsClient.cleanUpCountStat.Add(ctx, 1, metric.WithAttributes(attribute.String("result", result)))
+}
+
+// cleanUpEvent records a cleanup related Event unless cleanup Events are disabled,
+// so operators can rely on the cleanup metric alone at high loadtest volumes
+func (c *Controller) cleanUpEvent(loadTest *loadTestV1.LoadTest, eventType, reason, message string) {
+	if !c.cfg.CleanUpEvents {
+		return
+	}
+	c.recorder.Event(loadTest, eventType, reason, message)
+}
+
+// checkConcurrency moves the loadtest to errored phase when its backend reports that it
+// has no effective concurrency, it returns false if the loadtest was rejected
+func (c *Controller) checkConcurrency(backend backends.Backend, loadTest *loadTestV1.LoadTest) bool {
+	validator, ok := backend.(backends.BackendValidateConcurrency)
+	if !ok {
+		return true
+	}
+
+	err := validator.ValidateConcurrency(loadTest.Spec)
+	if err == nil {
+		return true
+	}
+
+	if loadTest.Status.Phase != loadTestV1.LoadTestErrored {
+		c.logger.Info("Rejecting loadtest without concurrency",
+			zap.String("loadtest", loadTest.GetName()),
+			zap.Error(err),
+		)
+		c.recorder.Event(loadTest, coreV1.EventTypeWarning, reasonZeroConcurrency, err.Error())
+	}
+	setErrored(loadTest, reasonZeroConcurrency, err.Error())
+
+	return false
+}
+
+// checkResourceBudget moves the loadtest to errored phase when the resources requested by its pods,
+// as estimated by its backend, exceed the resource budget, it returns false if the loadtest was rejected
+func (c *Controller) checkResourceBudget(backend backends.Backend, loadTest *loadTestV1.LoadTest) bool {
+	estimator, ok := backend.(backends.BackendEstimateResources)
+	if !ok {
+		return true
+	}
+
+	err := c.cfg.ResourceBudget.Check(estimator.EstimateResources(loadTest.Spec))
+	if err == nil {
+		return true
+	}
+
+	if loadTest.Status.Phase != loadTestV1.LoadTestErrored {
+		c.logger.Info("Rejecting loadtest exceeding the resource budget",
+			zap.String("loadtest", loadTest.GetName()),
+			zap.Error(err),
+		)
+		c.recorder.Event(loadTest, coreV1.EventTypeWarning, reasonResourceBudgetExceeded, err.Error())
+	}
+	setErrored(loadTest, reasonResourceBudgetExceeded, err.Error())
+
+	return false
+}
+
+// checkImageReferences moves the loadtest to errored phase when its master or worker image is not a valid
+// image reference, so typos are reported before its pods fail to pull, it returns false if the loadtest was rejected
+func (c *Controller) checkImageReferences(loadTest *loadTestV1.LoadTest) bool {
+	if !c.cfg.ImageReferenceCheck {
+		return true
+	}
+
+	err := backends.ValidateImages(loadTest.Spec)
+	if err == nil {
+		return true
+	}
+
+	if loadTest.Status.Phase != loadTestV1.LoadTestErrored {
+		c.logger.Info("Rejecting loadtest with invalid image reference",
+			zap.String("loadtest", loadTest.GetName()),
+			zap.Error(err),
+		)
+		c.recorder.Event(loadTest, coreV1.EventTypeWarning, reasonInvalidImageReference, err.Error())
+	}
+	setErrored(loadTest, reasonInvalidImageReference, err.Error())
+
+	return false
+}
+
 // handleObject will take any resource implementing metaV1.Object and attempt
 // to find the LoadTest resource that 'owns' it. It does this by looking at the
 // objects metadata.ownerReferences field for an appropriate OwnerReference.
 // It then enqueues that LoadTest resource to be processed. If the object does not
 // have an appropriate OwnerReference, it will simply be skipped.
-func (c *Controller) handleObject(obj interface{}) {
+func (c *Controller) handleObject(obj interface{}, source triggerSource) {
 	var object metaV1.Object
 	var ok bool
 	if object, ok = obj.(metaV1.Object); !ok {
@@ -435,92 +1286,444 @@ func (c *Controller) handleObject(obj interface{}) {
 			return
 		}
 
-		c.enqueueLoadTest(foo)
+		c.enqueueLoadTest(foo, source)
 		return
 	}
 }
 
 // enqueueLoadTest takes a LoadTest resource and converts it into a namespace/name
 // string which is then put onto the work queue. This method should *not* be
-// passed resources of any type other than LoadTest.
-func (c *Controller) enqueueLoadTest(obj interface{}) {
+// passed resources of any type other than LoadTest. The source of the trigger
+// is counted when ReconcileTriggerMetric is enabled.
+func (c *Controller) enqueueLoadTest(obj interface{}, source triggerSource) {
 	var key string
 	var err error
 	if key, err = cache.MetaNamespaceKeyFunc(obj); err != nil {
 		utilRuntime.HandleError(err)
 		return
 	}
+	if c.cfg.ReconcileTriggerMetric {
+		c.statsClient.reconcileTriggerCountStat.Add(context.Background(), 1, metric.WithAttributes(attribute.String("source", string(source))))
+	}
 	c.workQueue.Add(key)
 }
 
+// loadTestPriority resolves the work queue priority of a LoadTest key from its annotations,
+// keys of LoadTests that can't be found in the cache get normal priority
+func (c *Controller) loadTestPriority(item interface{}) priority {
+	key, ok := item.(string)
+	if !ok {
+		return priorityNormal
+	}
+
+	_, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return priorityNormal
+	}
+
+	loadTest, err := c.loadtestsLister.Get(name)
+	if err != nil {
+		return priorityNormal
+	}
+
+	if loadTest.GetAnnotations()[priorityAnnotation] == priorityHighValue {
+		return priorityHigh
+	}
+	return priorityNormal
+}
+
 func (c *Controller) updateLoadTestStatus(ctx context.Context, key string, loadTest *loadTestV1.LoadTest, loadTestFromCache *loadTestV1.LoadTest) {
 	logger := c.logger.With(
 		zap.String("loadtest", loadTest.GetName()),
 	)
 
-	if loadTest.Status.Phase != loadTestFromCache.Status.Phase {
+	phaseChanged := loadTest.Status.Phase != loadTestFromCache.Status.Phase
+	// failed syncs are counted from the written status, a throttled count would be lost by the next sync
+	retriesChanged := loadTest.Status.SyncRetries != loadTestFromCache.Status.SyncRetries
+	if phaseChanged || retriesChanged || !reflect.DeepEqual(loadTest.Status.Conditions, loadTestFromCache.Status.Conditions) ||
+		loadTest.Status.ReportLocation != loadTestFromCache.Status.ReportLocation ||
+		loadTest.Status.RetentionClass != loadTestFromCache.Status.RetentionClass ||
+		loadTest.Status.Reason != loadTestFromCache.Status.Reason ||
+		loadTest.Status.Message != loadTestFromCache.Status.Message ||
+		!reflect.DeepEqual(loadTest.Status.Pods, loadTestFromCache.Status.Pods) {
+		// phase changes are written right away, other changes are coalesced and written by a later sync
+		if wait := c.statusThrottle.wait(key); !phaseChanged && !retriesChanged && wait > 0 {
+			logger.Debug("Throttling loadtest status update", zap.Duration("wait", wait))
+			c.workQueue.AddAfter(key, wait)
+			return
+		}
+
+		w := statusWrite{key: key, loadTest: loadTest, phaseChanged: phaseChanged, previousPhase: loadTestFromCache.Status.Phase}
+		if c.statusBatcher != nil && c.statusBatcher.add(w) {
+			logger.Debug("Batching loadtest status update",
+				zap.String("new phase", loadTest.Status.Phase.String()),
+				zap.String("previous phase", loadTestFromCache.Status.Phase.String()),
+			)
+			return
+		}
+
 		logger.Debug("Updating loadtest status",
 			zap.String("new phase", loadTest.Status.Phase.String()),
 			zap.String("previous phase", loadTestFromCache.Status.Phase.String()),
 		)
+		c.writeLoadTestStatus(ctx, w)
+	}
+}
 
-		// UpdateStatus will not allow changes to the Spec of the resource
-		_, err := c.kangalClientSet.KangalV1().LoadTests().UpdateStatus(ctx, loadTest, metaV1.UpdateOptions{})
-		if err != nil {
-			// The LoadTest resource may be conflicted, in which case we stop
-			// processing.
-			if errors.IsConflict(err) {
-				utilRuntime.HandleError(fmt.Errorf("there is a conflict with loadtest '%s' between datastore and cache. it might be because object has been removed or modified in the datastore", key))
-				return
-			}
-			logger.Error("Failed updating loadtest status", zap.Error(err))
+// writeLoadTestStatus writes the status of the loadtest and runs the actions following a phase change
+func (c *Controller) writeLoadTestStatus(ctx context.Context, w statusWrite) {
+	logger := c.logger.With(
+		zap.String("loadtest", w.loadTest.GetName()),
+	)
+
+	ctx, span := tracer.Start(ctx, "updateLoadTestStatus", trace.WithAttributes(
+		attribute.String("phase", w.loadTest.Status.Phase.String()),
+	))
+	defer span.End()
+
+	// UpdateStatus will not allow changes to the Spec of the resource
+	_, err := c.updateStatus(ctx, w.loadTest)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		// The LoadTest resource may be conflicted, in which case we stop
+		// processing.
+		if errors.IsConflict(err) {
+			utilRuntime.HandleError(fmt.Errorf("there is a conflict with loadtest '%s' between datastore and cache. it might be because object has been removed or modified in the datastore", w.key))
 			return
 		}
+		logger.Error("Failed updating loadtest status", zap.Error(err))
+		return
+	}
+
+	logger.Debug("Status updated", zap.Any("status", w.loadTest.Status))
+	c.statusThrottle.updated(w.key)
+
+	if w.phaseChanged {
+		c.phaseChangedEvent(w)
+		c.checkFlapping(ctx, w.key, w.previousPhase, w.loadTest)
+		c.annotateNamespaceOutcome(ctx, w.loadTest)
+		c.mirrorStatus(ctx, w.loadTest)
+		c.updateGroupIndex(ctx, w.loadTest)
+		c.logCompletionSummary(w.loadTest)
+		c.recordLoadTestDuration(ctx, w.loadTest)
+	}
+}
+
+// phaseChangedEvent records the phase change of the written LoadTest status unless phase Events are disabled
+func (c *Controller) phaseChangedEvent(w statusWrite) {
+	if !c.cfg.PhaseEvents {
+		return
+	}
+
+	message := fmt.Sprintf("Phase changed from %s to %s", w.previousPhase, w.loadTest.Status.Phase)
+	if w.previousPhase == "" {
+		message = fmt.Sprintf("Phase set to %s", w.loadTest.Status.Phase)
+	}
+	c.recorder.Event(w.loadTest, coreV1.EventTypeNormal, reasonPhaseChanged, message)
+}
+
+// staleLoadTestStatus tells if the LoadTest changed or was deleted since the status update was computed
+func (c *Controller) staleLoadTestStatus(w statusWrite) bool {
+	current, err := c.loadtestsLister.Get(w.loadTest.GetName())
+	if err != nil {
+		return errors.IsNotFound(err)
+	}
+	return current.GetResourceVersion() != w.loadTest.GetResourceVersion()
+}
+
+// annotateNamespaceOutcome annotates the namespace of a loadtest that reached a terminal phase with
+// the phase and completion time, for tools watching namespaces rather than loadtests. Existing namespaces
+// are not owned by the loadtest and are left untouched.
+func (c *Controller) annotateNamespaceOutcome(ctx context.Context, loadTest *loadTestV1.LoadTest) {
+	if loadTest.Status.Namespace == "" || loadTest.Spec.ExistingNamespace != "" {
+		return
+	}
+	if loadTest.Status.Phase != loadTestV1.LoadTestFinished && loadTest.Status.Phase != loadTestV1.LoadTestErrored {
+		return
+	}
+
+	annotations := map[string]string{}
+	if c.cfg.NamespacePhaseAnnotation != "" {
+		annotations[c.cfg.NamespacePhaseAnnotation] = loadTest.Status.Phase.String()
+	}
+	if c.cfg.NamespaceCompletionTimeAnnotation != "" {
+		completionTime := time.Now()
+		if loadTest.Status.JobStatus.CompletionTime != nil {
+			completionTime = loadTest.Status.JobStatus.CompletionTime.Time
+		}
+		annotations[c.cfg.NamespaceCompletionTimeAnnotation] = completionTime.UTC().Format(time.RFC3339)
+	}
+	if len(annotations) == 0 {
+		return
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": annotations,
+		},
+	})
+	if err != nil {
+		c.logger.Error("Failed to build namespace outcome annotations patch", zap.Error(err))
+		return
+	}
 
-		logger.Debug("Status updated", zap.Any("status", loadTest.Status))
+	_, err = c.kubeClientSet.CoreV1().Namespaces().Patch(ctx, loadTest.Status.Namespace, types.MergePatchType, patch, metaV1.PatchOptions{})
+	if err != nil {
+		c.logger.Error("Failed to annotate namespace with loadtest outcome",
+			zap.String("loadtest", loadTest.GetName()),
+			zap.String("namespace", loadTest.Status.Namespace),
+			zap.Error(err),
+		)
 	}
 }
 
+// labelLongRunning labels loadtests running for longer than LongRunningThreshold so they can be
+// selected by alerting and dashboards, the label is removed once the loadtest stops running
+func (c *Controller) labelLongRunning(ctx context.Context, loadTest *loadTestV1.LoadTest) {
+	if c.cfg.LongRunningThreshold == 0 || c.cfg.LongRunningLabel == "" {
+		return
+	}
+
+	startTime := loadTest.Status.JobStatus.StartTime
+	longRunning := loadTest.Status.Phase == loadTestV1.LoadTestRunning &&
+		startTime != nil && time.Since(startTime.Time) > c.cfg.LongRunningThreshold
+	_, labeled := loadTest.GetLabels()[c.cfg.LongRunningLabel]
+	if longRunning == labeled {
+		return
+	}
+
+	// a null value removes the label with a merge patch
+	var value interface{}
+	if longRunning {
+		value = "true"
+	}
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels": map[string]interface{}{c.cfg.LongRunningLabel: value},
+		},
+	})
+	if err != nil {
+		c.logger.Error("Failed to build long-running label patch", zap.Error(err))
+		return
+	}
+
+	patched, err := c.kangalClientSet.KangalV1().LoadTests().Patch(ctx, loadTest.GetName(), types.MergePatchType, patch, metaV1.PatchOptions{})
+	if err != nil {
+		c.logger.Error("Failed to update long-running label of loadtest",
+			zap.String("loadtest", loadTest.GetName()),
+			zap.Error(err),
+		)
+		return
+	}
+
+	c.logger.Info("Updated long-running label of loadtest",
+		zap.String("loadtest", loadTest.GetName()),
+		zap.Bool("long-running", longRunning),
+	)
+	// keep the status update that follows from conflicting with the patch
+	loadTest.ObjectMeta = patched.ObjectMeta
+}
+
+// checkFlapping records a phase transition of the loadtest and reports it when flapping is detected
+func (c *Controller) checkFlapping(ctx context.Context, key string, previous loadTestV1.LoadTestPhase, loadTest *loadTestV1.LoadTest) {
+	if !c.flapping.observe(key, previous, loadTest.Status.Phase) {
+		return
+	}
+
+	c.logger.Warn("Loadtest is flapping between phases",
+		zap.String("loadtest", loadTest.GetName()),
+		zap.String("phase", loadTest.Status.Phase.String()),
+		zap.Int("oscillations", c.cfg.FlappingThreshold),
+		zap.Duration("window", c.cfg.FlappingWindow),
+	)
+	c.recorder.Eventf(loadTest, coreV1.EventTypeWarning, reasonFlapping,
+		"Loadtest oscillated between phases %d times within %s, last phase is %s",
+		c.cfg.FlappingThreshold, c.cfg.FlappingWindow, loadTest.Status.Phase)
+	// the loadtest name is only in the log and the Event, it would give the metric unbounded cardinality
+	c.statsClient.flappingCountStat.Add(ctx, 1, metric.WithAttributes(attribute.String("type", loadTest.Spec.Type.String())))
+}
+
 // checkOrCreateNamespace checks if a namespace has been created and if not deletes it
 func (c *Controller) checkOrCreateNamespace(ctx context.Context, loadtest *loadTestV1.LoadTest) error {
+	logger := c.logger.With(zap.String("loadtest", loadtest.GetName()))
+
+	// loadtests running in an existing namespace neither create nor own it
+	if loadtest.Spec.ExistingNamespace != "" {
+		if loadtest.Status.Namespace != "" {
+			return nil
+		}
+		return c.useExistingNamespace(loadtest)
+	}
+
 	if loadtest.Status.Namespace != "" {
-		return nil
+		if !c.cfg.VerifyNamespace {
+			return nil
+		}
+		gone, err := c.namespaceGone(loadtest)
+		if err != nil || !gone {
+			return err
+		}
+		logger.Warn("Namespace of loadtest no longer exists, recreating it", zap.String("namespace", loadtest.Status.Namespace))
+		loadtest.Status.Namespace = ""
 	}
 
-	logger := c.logger.With(zap.String("loadtest", loadtest.GetName()))
 	for k, v := range loadtest.Spec.Tags {
 		logger = logger.With(zap.String(k, v))
 	}
 
-	namespaces, err := c.kubeClientSet.CoreV1().Namespaces().List(ctx, metaV1.ListOptions{LabelSelector: "controller=" + loadtest.Name})
+	namespaces, err := c.kubeClientSet.CoreV1().Namespaces().List(ctx, metaV1.ListOptions{
+		LabelSelector: fmt.Sprintf("controller=%s,%s=%s", loadtest.Name, namespaceControllerUIDLabel, loadtest.UID),
+	})
 	if err != nil {
 		return err
 	}
 
-	namespaceName := ""
+	var namespace *coreV1.Namespace
 	if len(namespaces.Items) == 0 {
-		newNamespace, err := newNamespace(loadtest, c.cfg.NamespaceLabels, c.cfg.NamespaceAnnotations)
+		newNamespace, err := newNamespace(loadtest, c.cfg.NamespaceLabels, c.cfg.NamespaceAnnotations, c.cfg.NamespaceLabelTags)
 		if err != nil {
 			return err
 		}
-		namespaceObj, err := c.kubeClientSet.CoreV1().Namespaces().Create(ctx, newNamespace, metaV1.CreateOptions{})
+		if c.cfg.PropagateTagsAsLabels {
+			addTagLabels(newNamespace.Labels, loadtest.Spec.Tags, logger)
+		}
+		namespace, err = c.kubeClientSet.CoreV1().Namespaces().Create(ctx, newNamespace, metaV1.CreateOptions{})
+		if errors.IsAlreadyExists(err) {
+			// namespace was created without the UID label, by an older controller or for another loadtest
+			namespace, err = c.kubeClientSet.CoreV1().Namespaces().Get(ctx, newNamespace.Name, metaV1.GetOptions{})
+		} else if err == nil {
+			logger.Info("Created new namespace", zap.String("namespace", namespace.GetName()))
+		} else {
+			c.recordNamespaceCreateError(ctx, err)
+		}
 		if err != nil {
 			return err
 		}
-		namespaceName = namespaceObj.GetName()
-		logger.Info("Created new namespace", zap.String("namespace", namespaceName))
 	} else {
-		namespaceName = namespaces.Items[0].Name
+		namespace = &namespaces.Items[0]
+	}
+
+	// a namespace deleted out-of-band may still be terminating, wait for it to be gone before recreating it
+	if c.cfg.VerifyNamespace && namespace.Status.Phase == coreV1.NamespaceTerminating {
+		return fmt.Errorf("namespace %s of loadtest %s is terminating", namespace.GetName(), loadtest.GetName())
+	}
+
+	if err := checkNamespaceOwner(namespace, loadtest); err != nil {
+		logger.Error("Refusing to adopt namespace", zap.String("namespace", namespace.GetName()), zap.Error(err))
+		c.recorder.Event(loadtest, coreV1.EventTypeWarning, reasonNamespaceOwnerMismatch, err.Error())
+		return err
+	}
+
+	loadtest.Status.Namespace = namespace.GetName()
+	return nil
+}
+
+// recordNamespaceCreateError counts the namespace that could not be created by the reason of the error,
+// e.g. Forbidden for missing RBAC permissions or quota, errors not returned by the API server are counted as Unknown
+func (c *Controller) recordNamespaceCreateError(ctx context.Context, err error) {
+	reason := errors.ReasonForError(err)
+	if reason == metaV1.StatusReasonUnknown {
+		reason = "Unknown"
+	}
+	c.statsClient.namespaceCreateErrorStat.Add(ctx, 1, metric.WithAttributes(attribute.String("reason", string(reason))))
+}
+
+// namespaceGone tells if the namespace in the status of a loadtest was deleted or is terminating, according
+// to the namespace lister. Loadtests that reached a terminal phase never get their namespace back, so a
+// finished loadtest is not run again.
+func (c *Controller) namespaceGone(loadtest *loadTestV1.LoadTest) (bool, error) {
+	if loadtest.Status.Phase == loadTestV1.LoadTestFinished || loadtest.Status.Phase == loadTestV1.LoadTestErrored {
+		return false, nil
+	}
+
+	namespace, err := c.namespacesLister.Get(loadtest.Status.Namespace)
+	if errors.IsNotFound(err) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return namespace.Status.Phase == coreV1.NamespaceTerminating, nil
+}
+
+// checkOrCreateTestFileCredentials creates the Secret read by the init container fetching the test file
+// from object storage, with the rclone credentials the controller is configured with. The Secret is updated
+// when the credentials changed since it was created, e.g. after they were rotated
+func (c *Controller) checkOrCreateTestFileCredentials(ctx context.Context, loadtest *loadTestV1.LoadTest) error {
+	if len(c.cfg.TestFileCredentials) == 0 || !backends.IsObjectStorageURL(loadtest.Spec.TestFileURL) {
+		return nil
+	}
+
+	data := make(map[string][]byte, len(c.cfg.TestFileCredentials))
+	for key, value := range c.cfg.TestFileCredentials {
+		data[key] = []byte(value)
+	}
+
+	secrets := c.kubeClientSet.CoreV1().Secrets(loadtest.Status.Namespace)
+	existing, err := secrets.Get(ctx, backends.TestFileCredentialsSecretName, metaV1.GetOptions{})
+	if err == nil {
+		if reflect.DeepEqual(existing.Data, data) {
+			return nil
+		}
+
+		existing = existing.DeepCopy()
+		existing.Data = data
+		_, err = secrets.Update(ctx, existing, metaV1.UpdateOptions{})
+		if err != nil {
+			c.logger.Error("Error on updating test file credentials secret", zap.String("loadtest", loadtest.GetName()), zap.Error(err))
+			return err
+		}
+		return nil
+	}
+	if !errors.IsNotFound(err) {
+		return err
+	}
+
+	secret := &coreV1.Secret{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name: backends.TestFileCredentialsSecretName,
+			Labels: map[string]string{
+				"app": "kangal",
+			},
+			OwnerReferences: []metaV1.OwnerReference{
+				*metaV1.NewControllerRef(loadtest, loadTestV1.SchemeGroupVersion.WithKind("LoadTest")),
+			},
+		},
+		Data: data,
+	}
+
+	_, err = secrets.Create(ctx, secret, metaV1.CreateOptions{})
+	if err != nil && !errors.IsAlreadyExists(err) {
+		c.logger.Error("Error on creating test file credentials secret", zap.String("loadtest", loadtest.GetName()), zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// checkNamespaceOwner verifies that the namespace is controlled by the given loadtest and not by
+// another loadtest with the same name or colliding labels
+func checkNamespaceOwner(namespace *coreV1.Namespace, loadtest *loadTestV1.LoadTest) error {
+	ownerRef := metaV1.GetControllerOf(namespace)
+	if ownerRef == nil {
+		return fmt.Errorf("namespace %q has no controller owner, expected loadtest %q with uid %s", namespace.GetName(), loadtest.GetName(), loadtest.GetUID())
+	}
+
+	if ownerRef.Kind != "LoadTest" || ownerRef.UID != loadtest.GetUID() {
+		return fmt.Errorf("namespace %q is owned by %s %q with uid %s, expected loadtest %q with uid %s",
+			namespace.GetName(), ownerRef.Kind, ownerRef.Name, ownerRef.UID, loadtest.GetName(), loadtest.GetUID())
 	}
 
-	loadtest.Status.Namespace = namespaceName
 	return nil
 }
 
 // newNamespace creates a new namespaces object with a random name
-func newNamespace(loadtest *loadTestV1.LoadTest, namespacelabels map[string]string, namespaceAnnotations map[string]string) (*coreV1.Namespace, error) {
-	labels := maps.Clone(namespacelabels)
+func newNamespace(loadtest *loadTestV1.LoadTest, namespacelabels map[string]string, namespaceAnnotations map[string]string, labelTags []string) (*coreV1.Namespace, error) {
+	labels := tagLabels(loadtest.Spec.Tags, labelTags)
+	maps.Copy(labels, namespacelabels)
 	labels["controller"] = loadtest.Name
+	labels[namespaceControllerUIDLabel] = string(loadtest.UID)
 	labels["app"] = "kangal"
 
 	return &coreV1.Namespace{
@@ -535,28 +1738,85 @@ func newNamespace(loadtest *loadTestV1.LoadTest, namespacelabels map[string]stri
 	}, nil
 }
 
+// addTagLabels adds all tags as labels which are not set yet, tags which can't be sanitized to a valid label
+// are skipped with a warning
+func addTagLabels(labels map[string]string, tags loadTestV1.LoadTestTags, logger *zap.Logger) {
+	tagLabels, skipped := backends.TagLabels(tags)
+	for key, value := range tagLabels {
+		if _, ok := labels[key]; !ok {
+			labels[key] = value
+		}
+	}
+	if len(skipped) > 0 {
+		logger.Warn("Skipped loadtest tags which are not valid labels", zap.Strings("tags", skipped))
+	}
+}
+
+// tagLabels returns the tags of the given keys as labels, with their values sanitized to valid label values.
+// Tags whose value has no valid characters are skipped.
+func tagLabels(tags loadTestV1.LoadTestTags, keys []string) map[string]string {
+	labels := map[string]string{}
+	for _, key := range keys {
+		value, ok := tags[key]
+		if !ok {
+			continue
+		}
+		if value = backends.SanitizeLabelValue(value); value != "" {
+			labels[key] = value
+		}
+	}
+	return labels
+}
+
 // checkLoadTestLifeTimeExceeded returns true if the input loadtest has
-// existed for longer than certain threshold, and its status is Finished or Errored
-func checkLoadTestLifeTimeExceeded(loadTest *loadTestV1.LoadTest, deleteThreshold time.Duration) bool {
+// existed for longer than certain threshold at the given time, and its status is Finished or Errored.
+// The timestamps are set by the API server and the job controller, whose clocks may be skewed from the
+// controller clock, so the threshold is extended by skewTolerance.
+func checkLoadTestLifeTimeExceeded(loadTest *loadTestV1.LoadTest, deleteThreshold, skewTolerance time.Duration, now time.Time) bool {
+	// loadtests still creating, starting or running are only deleted by the MaxTestLifetime backstop,
+	// whatever their age
+	if activeLoadTest(loadTest) {
+		return false
+	}
+
+	lifetime := deleteThreshold + skewTolerance
+
 	if loadTest.Status.JobStatus.CompletionTime != nil {
-		if time.Since(loadTest.Status.JobStatus.CompletionTime.Time) > deleteThreshold &&
+		if elapsedSince(loadTest.Status.JobStatus.CompletionTime.Time, now) > lifetime &&
 			(loadTest.Status.Phase == loadTestV1.LoadTestFinished || loadTest.Status.Phase == loadTestV1.LoadTestErrored) {
 			return true
 		}
 	}
 
 	if loadTest.Status.Phase == loadTestV1.LoadTestErrored &&
-		time.Since(loadTest.ObjectMeta.CreationTimestamp.Time) > deleteThreshold {
+		elapsedSince(loadTest.ObjectMeta.CreationTimestamp.Time, now) > lifetime {
 		return true
 	}
 
 	return false
 }
 
-func (c *Controller) deleteLoadTest(ctx context.Context, key string, loadTest *loadTestV1.LoadTest) {
+// checkLoadTestMaxLifetimeExceeded returns true if the input loadtest is still active and has existed
+// for longer than maxLifetime, extended by skewTolerance, at the given time
+func checkLoadTestMaxLifetimeExceeded(loadTest *loadTestV1.LoadTest, maxLifetime, skewTolerance time.Duration, now time.Time) bool {
+	return activeLoadTest(loadTest) && elapsedSince(loadTest.ObjectMeta.CreationTimestamp.Time, now) > maxLifetime+skewTolerance
+}
+
+// elapsedSince returns the time elapsed from t to now, timestamps ahead of now because of clock skew
+// are treated as just passed
+func elapsedSince(t, now time.Time) time.Duration {
+	if elapsed := now.Sub(t); elapsed > 0 {
+		return elapsed
+	}
+	return 0
+}
+
+func (c *Controller) deleteLoadTest(ctx context.Context, key string, loadTest *loadTestV1.LoadTest) error {
+	c.cleanUpBackend(ctx, key, loadTest)
+
 	err := c.kangalClientSet.KangalV1().LoadTests().Delete(ctx, loadTest.Name, metaV1.DeleteOptions{})
 	if err == nil {
-		return
+		return nil
 	}
 
 	// The LoadTest resource may be conflicted, in which case we stop processing.
@@ -565,4 +1825,22 @@ func (c *Controller) deleteLoadTest(ctx context.Context, key string, loadTest *l
 		utilRuntime.HandleError(fmt.Errorf("there is a conflict with loadtest %q between datastore and cache. It might be because object has been removed or modified in the datastore", key))
 	}
 	c.logger.Error("Failed to delete loadtest:", zap.Error(err))
+	return err
+}
+
+// cleanUpBackend lets the backend of the loadtest remove its state which is not garbage collected with the loadtest,
+// failures are logged and don't keep the loadtest from being deleted
+func (c *Controller) cleanUpBackend(ctx context.Context, key string, loadTest *loadTestV1.LoadTest) {
+	backend, err := c.registry.GetBackend(loadTest.Spec.Type)
+	if err != nil {
+		return
+	}
+	cleaner, ok := backend.(backends.BackendCleanup)
+	if !ok {
+		return
+	}
+
+	if err := cleaner.Cleanup(ctx, *loadTest, c.reportURL(loadTest)); err != nil {
+		c.logger.Warn("Failed to clean up loadtest backend state", zap.String("loadtest", key), zap.Error(err))
+	}
 }
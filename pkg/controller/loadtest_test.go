@@ -1,14 +1,35 @@
 package controller
 
 import (
+	"context"
+	"errors"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric/noop"
+	sdkMetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.uber.org/zap/zaptest"
 	batchV1 "k8s.io/api/batch/v1"
+	coreV1 "k8s.io/api/core/v1"
+	k8sAPIErrors "k8s.io/apimachinery/pkg/api/errors"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	coreListersV1 "k8s.io/client-go/listers/core/v1"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
 
+	"github.com/hellofresh/kangal/pkg/backends"
 	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+	fakeClientset "github.com/hellofresh/kangal/pkg/kubernetes/generated/clientset/versioned/fake"
+	listers "github.com/hellofresh/kangal/pkg/kubernetes/generated/listers/loadtest/v1"
 )
 
 func TestShouldDeleteLoadtest(t *testing.T) {
@@ -94,9 +115,1216 @@ func TestShouldDeleteLoadtest(t *testing.T) {
 
 	for _, test := range testPhases {
 		t.Run(test.Name, func(t *testing.T) {
-			timedout := checkLoadTestLifeTimeExceeded(&test.LoadTest, test.Threshold)
+			timedout := checkLoadTestLifeTimeExceeded(&test.LoadTest, test.Threshold, 0, now)
 			assert.Equal(t, test.ExpectedResponse, timedout)
 
 		})
 	}
 }
+
+func TestLoadTestLifeTimeClockSkew(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	threshold := time.Hour
+
+	for _, tt := range []struct {
+		name      string
+		phase     loadTestV1.LoadTestPhase
+		completed time.Duration
+		created   time.Duration
+		tolerance time.Duration
+		exceeded  bool
+	}{
+		{
+			name:      "completion ahead of the controller clock",
+			phase:     loadTestV1.LoadTestFinished,
+			completed: 5 * time.Minute,
+		},
+		{
+			name:    "creation ahead of the controller clock",
+			phase:   loadTestV1.LoadTestErrored,
+			created: 5 * time.Minute,
+		},
+		{
+			name:      "completion behind the controller clock exceeds the threshold",
+			phase:     loadTestV1.LoadTestFinished,
+			completed: -time.Hour - time.Minute,
+			exceeded:  true,
+		},
+		{
+			name:      "skew within the tolerance",
+			phase:     loadTestV1.LoadTestFinished,
+			completed: -time.Hour - time.Minute,
+			tolerance: 2 * time.Minute,
+		},
+		{
+			name:      "skew beyond the tolerance",
+			phase:     loadTestV1.LoadTestFinished,
+			completed: -time.Hour - 3*time.Minute,
+			tolerance: 2 * time.Minute,
+			exceeded:  true,
+		},
+		{
+			name:      "errored without completion within the tolerance",
+			phase:     loadTestV1.LoadTestErrored,
+			created:   -time.Hour - time.Minute,
+			tolerance: 2 * time.Minute,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			loadTest := &loadTestV1.LoadTest{
+				ObjectMeta: metaV1.ObjectMeta{CreationTimestamp: metaV1.NewTime(now.Add(tt.created))},
+				Status:     loadTestV1.LoadTestStatus{Phase: tt.phase},
+			}
+			if tt.completed != 0 {
+				completionTime := metaV1.NewTime(now.Add(tt.completed))
+				loadTest.Status.JobStatus.CompletionTime = &completionTime
+			}
+
+			assert.Equal(t, tt.exceeded, checkLoadTestLifeTimeExceeded(loadTest, threshold, tt.tolerance, now))
+		})
+	}
+}
+
+func TestLoadTestMaxLifetime(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	threshold := time.Hour
+	maxLifetime := 6 * time.Hour
+
+	for _, tt := range []struct {
+		name     string
+		phase    loadTestV1.LoadTestPhase
+		created  time.Duration
+		cleanUp  bool
+		backstop bool
+	}{
+		{
+			name:    "running beyond the cleanup threshold",
+			phase:   loadTestV1.LoadTestRunning,
+			created: -2 * time.Hour,
+		},
+		{
+			name:    "starting beyond the cleanup threshold",
+			phase:   loadTestV1.LoadTestStarting,
+			created: -2 * time.Hour,
+		},
+		{
+			name:     "running beyond the max lifetime",
+			phase:    loadTestV1.LoadTestRunning,
+			created:  -7 * time.Hour,
+			backstop: true,
+		},
+		{
+			name:    "errored beyond the max lifetime",
+			phase:   loadTestV1.LoadTestErrored,
+			created: -7 * time.Hour,
+			cleanUp: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			loadTest := &loadTestV1.LoadTest{
+				ObjectMeta: metaV1.ObjectMeta{CreationTimestamp: metaV1.NewTime(now.Add(tt.created))},
+				Status:     loadTestV1.LoadTestStatus{Phase: tt.phase},
+			}
+			completionTime := metaV1.NewTime(now.Add(tt.created))
+			loadTest.Status.JobStatus.CompletionTime = &completionTime
+
+			assert.Equal(t, tt.cleanUp, checkLoadTestLifeTimeExceeded(loadTest, threshold, 0, now))
+			assert.Equal(t, tt.backstop, checkLoadTestMaxLifetimeExceeded(loadTest, maxLifetime, 0, now))
+		})
+	}
+}
+
+func TestElapsedSince(t *testing.T) {
+	now := time.Now()
+	assert.Equal(t, time.Minute, elapsedSince(now.Add(-time.Minute), now))
+	assert.Zero(t, elapsedSince(now.Add(time.Minute), now))
+}
+
+type concurrencyBackend struct {
+	backends.Backend
+	err error
+}
+
+func (b concurrencyBackend) ValidateConcurrency(loadTestV1.LoadTestSpec) error {
+	return b.err
+}
+
+func TestCheckConcurrency(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		backend  backends.Backend
+		accepted bool
+		phase    loadTestV1.LoadTestPhase
+		events   int
+	}{
+		{
+			name:     "backend without validation",
+			backend:  backends.NewMockBackend(nil),
+			accepted: true,
+		},
+		{
+			name:     "valid concurrency",
+			backend:  concurrencyBackend{},
+			accepted: true,
+		},
+		{
+			name:     "zero concurrency",
+			backend:  concurrencyBackend{err: errors.New("zero threads")},
+			accepted: false,
+			phase:    loadTestV1.LoadTestErrored,
+			events:   1,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := record.NewFakeRecorder(10)
+			c := &Controller{
+				recorder: recorder,
+				logger:   zaptest.NewLogger(t),
+			}
+			loadTest := &loadTestV1.LoadTest{}
+
+			assert.Equal(t, tt.accepted, c.checkConcurrency(tt.backend, loadTest))
+			assert.Equal(t, tt.phase, loadTest.Status.Phase)
+			assert.Len(t, recorder.Events, tt.events)
+		})
+	}
+}
+
+type estimateBackend struct {
+	backends.Backend
+	estimate backends.ResourceEstimate
+}
+
+func (b estimateBackend) EstimateResources(loadTestV1.LoadTestSpec) backends.ResourceEstimate {
+	return b.estimate
+}
+
+func TestCheckResourceBudget(t *testing.T) {
+	budget, err := backends.ParseResourceBudget(3, "2", "")
+	require.NoError(t, err)
+
+	for _, tt := range []struct {
+		name     string
+		backend  backends.Backend
+		accepted bool
+		phase    loadTestV1.LoadTestPhase
+		events   int
+	}{
+		{
+			name:     "backend without estimate",
+			backend:  backends.NewMockBackend(nil),
+			accepted: true,
+		},
+		{
+			name:     "within budget",
+			backend:  estimateBackend{estimate: backends.EstimatePods(3, backends.Resources{CPURequests: "500m"})},
+			accepted: true,
+		},
+		{
+			name:     "too many pods",
+			backend:  estimateBackend{estimate: backends.EstimatePods(4, backends.Resources{})},
+			accepted: false,
+			phase:    loadTestV1.LoadTestErrored,
+			events:   1,
+		},
+		{
+			name:     "too much CPU",
+			backend:  estimateBackend{estimate: backends.EstimatePods(3, backends.Resources{CPULimits: "1"})},
+			accepted: false,
+			phase:    loadTestV1.LoadTestErrored,
+			events:   1,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := record.NewFakeRecorder(10)
+			c := &Controller{
+				cfg:      Config{ResourceBudget: budget},
+				recorder: recorder,
+				logger:   zaptest.NewLogger(t),
+			}
+			loadTest := &loadTestV1.LoadTest{}
+
+			assert.Equal(t, tt.accepted, c.checkResourceBudget(tt.backend, loadTest))
+			assert.Equal(t, tt.phase, loadTest.Status.Phase)
+			assert.Len(t, recorder.Events, tt.events)
+		})
+	}
+}
+
+func TestCheckImageReferences(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		check    bool
+		spec     loadTestV1.LoadTestSpec
+		accepted bool
+		phase    loadTestV1.LoadTestPhase
+		events   int
+	}{
+		{
+			name:     "check disabled",
+			spec:     loadTestV1.LoadTestSpec{MasterConfig: "my image:v1"},
+			accepted: true,
+		},
+		{
+			name:     "default images",
+			check:    true,
+			accepted: true,
+		},
+		{
+			name:     "valid images",
+			check:    true,
+			spec:     loadTestV1.LoadTestSpec{MasterConfig: "grafana/k6:0.49.0", WorkerConfig: "registry.example.com/locust:2"},
+			accepted: true,
+		},
+		{
+			name:     "invalid master image",
+			check:    true,
+			spec:     loadTestV1.LoadTestSpec{MasterConfig: "my image:v1"},
+			accepted: false,
+			phase:    loadTestV1.LoadTestErrored,
+			events:   1,
+		},
+		{
+			name:     "invalid worker image",
+			check:    true,
+			spec:     loadTestV1.LoadTestSpec{MasterConfig: "grafana/k6", WorkerConfig: ":@sha256:"},
+			accepted: false,
+			phase:    loadTestV1.LoadTestErrored,
+			events:   1,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := record.NewFakeRecorder(10)
+			c := &Controller{
+				cfg:      Config{ImageReferenceCheck: tt.check},
+				recorder: recorder,
+				logger:   zaptest.NewLogger(t),
+			}
+			loadTest := &loadTestV1.LoadTest{Spec: tt.spec}
+
+			assert.Equal(t, tt.accepted, c.checkImageReferences(loadTest))
+			assert.Equal(t, tt.phase, loadTest.Status.Phase)
+			assert.Len(t, recorder.Events, tt.events)
+		})
+	}
+}
+
+func TestRecordSyncPhase(t *testing.T) {
+	ctx := context.Background()
+	reader := sdkMetric.NewManualReader()
+	statsReporter, err := NewMetricsReporter(sdkMetric.NewMeterProvider(sdkMetric.WithReader(reader)).Meter("test"))
+	require.NoError(t, err)
+
+	loadTest := &loadTestV1.LoadTest{
+		Spec: loadTestV1.LoadTestSpec{Type: loadTestV1.LoadTestTypeJMeter},
+	}
+	for _, enabled := range []bool{false, true} {
+		c := &Controller{
+			cfg:         Config{SyncPhaseMetrics: enabled},
+			statsClient: *statsReporter,
+		}
+		c.recordSyncPhase(ctx, c.statsClient.backendSyncLatencyStat, loadTest, time.Now(), errors.New("sync failed"))
+	}
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(ctx, &rm))
+	require.Len(t, rm.ScopeMetrics, 1)
+	require.Len(t, rm.ScopeMetrics[0].Metrics, 1)
+
+	m := rm.ScopeMetrics[0].Metrics[0]
+	assert.Equal(t, "kangal_backend_sync_duration", m.Name)
+	histogram, ok := m.Data.(metricdata.Histogram[int64])
+	require.True(t, ok)
+	require.Len(t, histogram.DataPoints, 1)
+	assert.Equal(t, uint64(1), histogram.DataPoints[0].Count)
+
+	backendType, _ := histogram.DataPoints[0].Attributes.Value(attribute.Key("type"))
+	assert.Equal(t, "JMeter", backendType.AsString())
+	success, _ := histogram.DataPoints[0].Attributes.Value(attribute.Key("success"))
+	assert.Equal(t, falseString, success.AsString())
+}
+
+func TestEnqueueLoadTestTrigger(t *testing.T) {
+	ctx := context.Background()
+	reader := sdkMetric.NewManualReader()
+	statsReporter, err := NewMetricsReporter(sdkMetric.NewMeterProvider(sdkMetric.WithReader(reader)).Meter("test"))
+	require.NoError(t, err)
+
+	c := &Controller{
+		cfg:         Config{ReconcileTriggerMetric: true},
+		statsClient: *statsReporter,
+		workQueue: newPriorityQueue("test", 1, func(interface{}) priority {
+			return priorityNormal
+		}),
+		logger: zaptest.NewLogger(t),
+	}
+	defer c.workQueue.ShutDown()
+
+	loadTest := &loadTestV1.LoadTest{ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-name"}}
+	c.enqueueLoadTest(loadTest, triggerAdd)
+	c.enqueueLoadTest(loadTest, triggerPod)
+	c.enqueueLoadTest(loadTest, triggerPod)
+	assert.Equal(t, 1, c.workQueue.Len())
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(ctx, &rm))
+	require.Len(t, rm.ScopeMetrics, 1)
+	require.Len(t, rm.ScopeMetrics[0].Metrics, 1)
+
+	m := rm.ScopeMetrics[0].Metrics[0]
+	assert.Equal(t, "kangal_reconcile_trigger_total", m.Name)
+	sum, ok := m.Data.(metricdata.Sum[int64])
+	require.True(t, ok)
+
+	counts := map[string]int64{}
+	for _, dp := range sum.DataPoints {
+		source, _ := dp.Attributes.Value(attribute.Key("source"))
+		counts[source.AsString()] = dp.Value
+	}
+	assert.Equal(t, map[string]int64{"add": 1, "pod": 2}, counts)
+}
+
+func TestShutDownWorkQueue(t *testing.T) {
+	c := &Controller{
+		workQueue: newPriorityQueue("test", 1, func(interface{}) priority {
+			return priorityNormal
+		}),
+		logger: zaptest.NewLogger(t),
+	}
+	c.workQueue.Add("queued")
+
+	processed := make(chan interface{}, 2)
+	go func() {
+		for {
+			item, shutdown := c.workQueue.Get()
+			if shutdown {
+				close(processed)
+				return
+			}
+			processed <- item
+			c.workQueue.Done(item)
+		}
+	}()
+
+	c.shutDownWorkQueue(time.Second)
+
+	// items added after shut down are dropped
+	c.workQueue.Add("dropped")
+
+	var items []interface{}
+	for item := range processed {
+		items = append(items, item)
+	}
+	assert.Equal(t, []interface{}{"queued"}, items)
+}
+
+func TestWaitForWorkers(t *testing.T) {
+	c := &Controller{logger: zaptest.NewLogger(t)}
+
+	// a worker still syncing a loadtest
+	release := make(chan struct{})
+	c.workers.Add(1)
+	go func() {
+		defer c.workers.Done()
+		<-release
+	}()
+
+	c.waitForWorkers(10 * time.Millisecond)
+	assert.True(t, c.stopping.Load())
+
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		c.waitForWorkers(time.Second)
+	}()
+	close(release)
+
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("waitForWorkers did not return once the workers stopped")
+	}
+}
+
+func TestProcessNextWorkItemStopping(t *testing.T) {
+	c := &Controller{
+		workQueue: newPriorityQueue("test", 1, func(interface{}) priority {
+			return priorityNormal
+		}),
+		logger: zaptest.NewLogger(t),
+	}
+	c.workQueue.Add("queued")
+	c.stopping.Store(true)
+
+	assert.False(t, c.processNextWorkItem())
+	assert.Equal(t, 0, c.workQueue.Len())
+}
+
+func TestCheckOrCreateNamespace(t *testing.T) {
+	loadTest := &loadTestV1.LoadTest{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name: "loadtest-name",
+			UID:  types.UID("loadtest-uid"),
+		},
+	}
+
+	namespace := func(labels map[string]string, ownerUID types.UID) *coreV1.Namespace {
+		owner := loadTest.DeepCopy()
+		owner.UID = ownerUID
+		return &coreV1.Namespace{
+			ObjectMeta: metaV1.ObjectMeta{
+				Name:   "loadtest-name",
+				Labels: labels,
+				OwnerReferences: []metaV1.OwnerReference{
+					*metaV1.NewControllerRef(owner, loadTestV1.SchemeGroupVersion.WithKind("LoadTest")),
+				},
+			},
+		}
+	}
+
+	for _, tt := range []struct {
+		name        string
+		objects     []runtime.Object
+		expectError bool
+	}{
+		{
+			name: "new namespace",
+		},
+		{
+			name:    "labelled namespace owned by loadtest",
+			objects: []runtime.Object{namespace(map[string]string{"controller": "loadtest-name", "controller-uid": "loadtest-uid"}, "loadtest-uid")},
+		},
+		{
+			name:    "namespace without uid label owned by loadtest",
+			objects: []runtime.Object{namespace(map[string]string{"controller": "loadtest-name"}, "loadtest-uid")},
+		},
+		{
+			name:        "namespace owned by another loadtest",
+			objects:     []runtime.Object{namespace(map[string]string{"controller": "loadtest-name"}, "other-uid")},
+			expectError: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Controller{
+				kubeClientSet: k8sfake.NewSimpleClientset(tt.objects...),
+				recorder:      record.NewFakeRecorder(10),
+				logger:        zaptest.NewLogger(t),
+				cfg: Config{
+					NamespaceLabels: map[string]string{},
+				},
+			}
+			lt := loadTest.DeepCopy()
+
+			err := c.checkOrCreateNamespace(context.Background(), lt)
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Empty(t, lt.Status.Namespace)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, "loadtest-name", lt.Status.Namespace)
+		})
+	}
+}
+
+func TestCheckOrCreateNamespaceCreateErrors(t *testing.T) {
+	reader := sdkMetric.NewManualReader()
+	statsReporter, err := NewMetricsReporter(sdkMetric.NewMeterProvider(sdkMetric.WithReader(reader)).Meter("test"))
+	require.NoError(t, err)
+
+	kubeClient := k8sfake.NewSimpleClientset()
+	kubeClient.PrependReactor("create", "namespaces", func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, k8sAPIErrors.NewForbidden(schema.GroupResource{Resource: "namespaces"}, "loadtest-name", errors.New("exceeded quota"))
+	})
+
+	c := &Controller{
+		kubeClientSet: kubeClient,
+		statsClient:   *statsReporter,
+		logger:        zaptest.NewLogger(t),
+		cfg: Config{
+			NamespaceLabels: map[string]string{},
+		},
+	}
+	loadTest := &loadTestV1.LoadTest{ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-name", UID: types.UID("loadtest-uid")}}
+
+	require.Error(t, c.checkOrCreateNamespace(context.Background(), loadTest))
+	require.Error(t, c.checkOrCreateNamespace(context.Background(), loadTest))
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+
+	counts := map[string]int64{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != "kangal_namespace_create_errors_total" {
+				continue
+			}
+			sum, ok := m.Data.(metricdata.Sum[int64])
+			require.True(t, ok)
+			for _, dp := range sum.DataPoints {
+				reason, _ := dp.Attributes.Value(attribute.Key("reason"))
+				counts[reason.AsString()] = dp.Value
+			}
+		}
+	}
+	assert.Equal(t, map[string]int64{"Forbidden": 2}, counts)
+}
+
+func TestAddTagLabels(t *testing.T) {
+	labels := map[string]string{"controller": "loadtest-name", "team": "from-config"}
+	addTagLabels(labels, loadTestV1.LoadTestTags{
+		"team":    "from-tag",
+		"project": "Checkout API",
+		"owner":   "!!!",
+	}, zaptest.NewLogger(t))
+
+	assert.Equal(t, map[string]string{
+		"controller": "loadtest-name",
+		"team":       "from-config",
+		"project":    "Checkout-API",
+	}, labels)
+}
+
+func TestNewNamespaceTagLabels(t *testing.T) {
+	loadTest := &loadTestV1.LoadTest{
+		ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-name", UID: types.UID("loadtest-uid")},
+		Spec: loadTestV1.LoadTestSpec{
+			Tags: loadTestV1.LoadTestTags{
+				"team":        "Payments & Billing",
+				"cost-center": "cc-1234",
+				"department":  "finance",
+				"owner":       "!!!",
+			},
+		},
+	}
+
+	namespace, err := newNamespace(loadTest,
+		map[string]string{"cost-center": "shared"},
+		map[string]string{},
+		[]string{"team", "cost-center", "owner", "missing"},
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Payments-Billing", namespace.Labels["team"])
+	assert.Equal(t, "shared", namespace.Labels["cost-center"])
+	assert.NotContains(t, namespace.Labels, "department")
+	assert.NotContains(t, namespace.Labels, "owner")
+	assert.NotContains(t, namespace.Labels, "missing")
+	assert.Equal(t, "loadtest-name", namespace.Labels["controller"])
+}
+
+func TestCheckOrCreateNamespaceVerify(t *testing.T) {
+	existing := &coreV1.Namespace{ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-name"}}
+	terminating := existing.DeepCopy()
+	terminating.Status.Phase = coreV1.NamespaceTerminating
+
+	for _, tt := range []struct {
+		name              string
+		phase             loadTestV1.LoadTestPhase
+		namespace         *coreV1.Namespace
+		expectError       bool
+		expectedNamespace string
+		expectCreated     bool
+	}{
+		{
+			name:              "namespace exists",
+			phase:             loadTestV1.LoadTestRunning,
+			namespace:         existing,
+			expectedNamespace: "loadtest-name",
+		},
+		{
+			name:              "namespace deleted",
+			phase:             loadTestV1.LoadTestRunning,
+			expectedNamespace: "loadtest-name",
+			expectCreated:     true,
+		},
+		{
+			name:        "namespace terminating",
+			phase:       loadTestV1.LoadTestRunning,
+			namespace:   terminating,
+			expectError: true,
+		},
+		{
+			name:              "finished loadtest with deleted namespace",
+			phase:             loadTestV1.LoadTestFinished,
+			expectedNamespace: "loadtest-name",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			var objects []runtime.Object
+			if tt.namespace != nil {
+				require.NoError(t, indexer.Add(tt.namespace))
+				objects = append(objects, tt.namespace)
+			}
+
+			kubeClient := k8sfake.NewSimpleClientset(objects...)
+			c := &Controller{
+				kubeClientSet:    kubeClient,
+				namespacesLister: coreListersV1.NewNamespaceLister(indexer),
+				recorder:         record.NewFakeRecorder(10),
+				logger:           zaptest.NewLogger(t),
+				cfg: Config{
+					NamespaceLabels: map[string]string{},
+					VerifyNamespace: true,
+				},
+			}
+			lt := &loadTestV1.LoadTest{
+				ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-name", UID: types.UID("loadtest-uid")},
+				Status: loadTestV1.LoadTestStatus{
+					Phase:     tt.phase,
+					Namespace: "loadtest-name",
+				},
+			}
+
+			err := c.checkOrCreateNamespace(context.Background(), lt)
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Empty(t, lt.Status.Namespace)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedNamespace, lt.Status.Namespace)
+
+			_, err = kubeClient.CoreV1().Namespaces().Get(context.Background(), "loadtest-name", metaV1.GetOptions{})
+			assert.Equal(t, tt.expectCreated || tt.namespace != nil, err == nil)
+		})
+	}
+}
+
+func TestCheckOrCreateTestFileCredentials(t *testing.T) {
+	for _, tt := range []struct {
+		name          string
+		testFileURL   string
+		credentials   map[string]string
+		expectCreated bool
+	}{
+		{
+			name:        "http test file",
+			testFileURL: "https://example.com/test.js",
+			credentials: map[string]string{"RCLONE_S3_ACCESS_KEY_ID": "key"},
+		},
+		{
+			name:        "object storage test file without credentials",
+			testFileURL: "s3://bucket/test.js",
+		},
+		{
+			name:          "object storage test file with credentials",
+			testFileURL:   "s3://bucket/test.js",
+			credentials:   map[string]string{"RCLONE_S3_ACCESS_KEY_ID": "key"},
+			expectCreated: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			kubeClient := k8sfake.NewSimpleClientset()
+			c := &Controller{
+				kubeClientSet: kubeClient,
+				logger:        zaptest.NewLogger(t),
+				cfg: Config{
+					TestFileCredentials: tt.credentials,
+				},
+			}
+			lt := &loadTestV1.LoadTest{
+				ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-name"},
+				Spec:       loadTestV1.LoadTestSpec{TestFileURL: tt.testFileURL},
+				Status:     loadTestV1.LoadTestStatus{Namespace: "loadtest-name"},
+			}
+
+			require.NoError(t, c.checkOrCreateTestFileCredentials(context.Background(), lt))
+			// a second sync finds the secret already created
+			require.NoError(t, c.checkOrCreateTestFileCredentials(context.Background(), lt))
+
+			secrets, err := kubeClient.CoreV1().Secrets("loadtest-name").List(context.Background(), metaV1.ListOptions{})
+			require.NoError(t, err)
+			if !tt.expectCreated {
+				assert.Empty(t, secrets.Items)
+				return
+			}
+
+			require.Len(t, secrets.Items, 1)
+			assert.Equal(t, backends.TestFileCredentialsSecretName, secrets.Items[0].Name)
+			assert.Equal(t, []byte("key"), secrets.Items[0].Data["RCLONE_S3_ACCESS_KEY_ID"])
+
+			// rotated credentials replace the ones of the secret
+			c.cfg.TestFileCredentials = map[string]string{"RCLONE_S3_ACCESS_KEY_ID": "rotated"}
+			require.NoError(t, c.checkOrCreateTestFileCredentials(context.Background(), lt))
+
+			secret, err := kubeClient.CoreV1().Secrets("loadtest-name").Get(context.Background(),
+				backends.TestFileCredentialsSecretName, metaV1.GetOptions{})
+			require.NoError(t, err)
+			assert.Equal(t, map[string][]byte{"RCLONE_S3_ACCESS_KEY_ID": []byte("rotated")}, secret.Data)
+		})
+	}
+}
+
+// staticRegistry resolves every loadtest type to the same backend
+type staticRegistry struct {
+	backends.Registry
+	backend backends.Backend
+}
+
+func (r staticRegistry) GetBackend(loadTestV1.LoadTestType) (backends.Backend, error) {
+	return r.backend, nil
+}
+
+type cleanupBackend struct {
+	backends.Backend
+	reportURLs *[]string
+}
+
+func (b cleanupBackend) Cleanup(_ context.Context, _ loadTestV1.LoadTest, reportURL string) error {
+	*b.reportURLs = append(*b.reportURLs, reportURL)
+	return errors.New("report storage unavailable")
+}
+
+func TestCleanUpStaleLoadTest(t *testing.T) {
+	completionTime := metaV1.NewTime(time.Now().Add(-2 * time.Hour))
+
+	for _, tt := range []struct {
+		name          string
+		cleanUpEvents bool
+		events        int
+	}{
+		{
+			name:          "cleanup events enabled",
+			cleanUpEvents: true,
+			events:        1,
+		},
+		{
+			name:          "cleanup events disabled",
+			cleanUpEvents: false,
+			events:        0,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			loadTest := &loadTestV1.LoadTest{
+				ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-name"},
+				Status: loadTestV1.LoadTestStatus{
+					Phase:     loadTestV1.LoadTestFinished,
+					JobStatus: batchV1.JobStatus{CompletionTime: &completionTime},
+				},
+			}
+
+			statsReporter, err := NewMetricsReporter(noop.NewMeterProvider().Meter("test"))
+			require.NoError(t, err)
+
+			recorder := record.NewFakeRecorder(10)
+			kangalClient := fakeClientset.NewSimpleClientset(loadTest)
+			var reportURLs []string
+			c := &Controller{
+				kangalClientSet: kangalClient,
+				recorder:        recorder,
+				statsClient:     *statsReporter,
+				registry:        staticRegistry{backend: cleanupBackend{reportURLs: &reportURLs}},
+				logger:          zaptest.NewLogger(t),
+				cfg: Config{
+					CleanUpThreshold: time.Hour,
+					CleanUpEvents:    tt.cleanUpEvents,
+					KangalProxyURL:   "http://kangal-proxy",
+				},
+			}
+
+			c.cleanUpStaleLoadTest(context.Background(), "loadtest-name", loadTest)
+
+			loadTests, err := kangalClient.KangalV1().LoadTests().List(context.Background(), metaV1.ListOptions{})
+			require.NoError(t, err)
+			assert.Empty(t, loadTests.Items, "a failed backend cleanup does not keep the loadtest")
+			assert.Len(t, recorder.Events, tt.events)
+			assert.Equal(t, []string{"http://kangal-proxy/load-test/loadtest-name/report"}, reportURLs)
+		})
+	}
+}
+
+func TestCleanUpStaleLoadTestThresholdOverride(t *testing.T) {
+	completionTime := metaV1.NewTime(time.Now().Add(-2 * time.Hour))
+	never := time.Duration(0)
+	short := time.Hour
+	long := 3 * time.Hour
+
+	for _, tt := range []struct {
+		name      string
+		threshold *time.Duration
+		deleted   bool
+	}{
+		{
+			name:      "global threshold applies when unset",
+			threshold: nil,
+			deleted:   true,
+		},
+		{
+			name:      "shorter threshold deletes",
+			threshold: &short,
+			deleted:   true,
+		},
+		{
+			name:      "longer threshold keeps",
+			threshold: &long,
+			deleted:   false,
+		},
+		{
+			name:      "zero threshold never deletes",
+			threshold: &never,
+			deleted:   false,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			loadTest := &loadTestV1.LoadTest{
+				ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-name"},
+				Spec:       loadTestV1.LoadTestSpec{CleanupThreshold: tt.threshold},
+				Status: loadTestV1.LoadTestStatus{
+					Phase:     loadTestV1.LoadTestFinished,
+					JobStatus: batchV1.JobStatus{CompletionTime: &completionTime},
+				},
+			}
+
+			statsReporter, err := NewMetricsReporter(noop.NewMeterProvider().Meter("test"))
+			require.NoError(t, err)
+
+			kangalClient := fakeClientset.NewSimpleClientset(loadTest)
+			var reportURLs []string
+			c := &Controller{
+				kangalClientSet: kangalClient,
+				recorder:        record.NewFakeRecorder(10),
+				statsClient:     *statsReporter,
+				registry:        staticRegistry{backend: cleanupBackend{reportURLs: &reportURLs}},
+				logger:          zaptest.NewLogger(t),
+				cfg: Config{
+					CleanUpThreshold: 90 * time.Minute,
+					MaxTestLifetime:  time.Minute,
+					KangalProxyURL:   "http://kangal-proxy",
+				},
+			}
+
+			c.cleanUpStaleLoadTest(context.Background(), "loadtest-name", loadTest)
+
+			loadTests, err := kangalClient.KangalV1().LoadTests().List(context.Background(), metaV1.ListOptions{})
+			require.NoError(t, err)
+			assert.Equal(t, tt.deleted, len(loadTests.Items) == 0)
+		})
+	}
+}
+
+func TestCleanUpStaleLoadTestZeroThresholdBackstop(t *testing.T) {
+	never := time.Duration(0)
+	loadTest := &loadTestV1.LoadTest{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:              "loadtest-name",
+			CreationTimestamp: metaV1.NewTime(time.Now().Add(-2 * time.Hour)),
+		},
+		Spec:   loadTestV1.LoadTestSpec{CleanupThreshold: &never},
+		Status: loadTestV1.LoadTestStatus{Phase: loadTestV1.LoadTestRunning},
+	}
+
+	statsReporter, err := NewMetricsReporter(noop.NewMeterProvider().Meter("test"))
+	require.NoError(t, err)
+
+	kangalClient := fakeClientset.NewSimpleClientset(loadTest)
+	var reportURLs []string
+	c := &Controller{
+		kangalClientSet: kangalClient,
+		recorder:        record.NewFakeRecorder(10),
+		statsClient:     *statsReporter,
+		registry:        staticRegistry{backend: cleanupBackend{reportURLs: &reportURLs}},
+		logger:          zaptest.NewLogger(t),
+		cfg: Config{
+			CleanUpThreshold: time.Hour,
+			MaxTestLifetime:  time.Hour,
+			KangalProxyURL:   "http://kangal-proxy",
+		},
+	}
+
+	c.cleanUpStaleLoadTest(context.Background(), "loadtest-name", loadTest)
+
+	// the zero threshold only keeps finished loadtests, a stuck loadtest exceeding the backstop is deleted
+	loadTests, err := kangalClient.KangalV1().LoadTests().List(context.Background(), metaV1.ListOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, loadTests.Items)
+}
+
+func TestPhaseChangedEvent(t *testing.T) {
+	for _, tt := range []struct {
+		name          string
+		enabled       bool
+		previousPhase loadTestV1.LoadTestPhase
+		expected      []string
+	}{
+		{
+			name:          "phase changed",
+			enabled:       true,
+			previousPhase: loadTestV1.LoadTestStarting,
+			expected:      []string{"Normal PhaseChanged Phase changed from starting to running"},
+		},
+		{
+			name:     "first phase",
+			enabled:  true,
+			expected: []string{"Normal PhaseChanged Phase set to running"},
+		},
+		{
+			name:          "phase events disabled",
+			previousPhase: loadTestV1.LoadTestStarting,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := record.NewFakeRecorder(10)
+			c := &Controller{
+				recorder: recorder,
+				cfg:      Config{PhaseEvents: tt.enabled},
+			}
+
+			c.phaseChangedEvent(statusWrite{
+				key:           "loadtest-name",
+				loadTest:      &loadTestV1.LoadTest{Status: loadTestV1.LoadTestStatus{Phase: loadTestV1.LoadTestRunning}},
+				phaseChanged:  true,
+				previousPhase: tt.previousPhase,
+			})
+
+			close(recorder.Events)
+			var events []string
+			for event := range recorder.Events {
+				events = append(events, event)
+			}
+			assert.Equal(t, tt.expected, events)
+		})
+	}
+}
+
+func TestHandleSyncStatusError(t *testing.T) {
+	syncErr := errors.New("could not list jobs")
+
+	for _, tt := range []struct {
+		name        string
+		tolerate    bool
+		expectError bool
+	}{
+		{
+			name:        "sync status errors requeue",
+			tolerate:    false,
+			expectError: true,
+		},
+		{
+			name:        "sync status errors tolerated",
+			tolerate:    true,
+			expectError: false,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			statsReporter, err := NewMetricsReporter(noop.NewMeterProvider().Meter("test"))
+			require.NoError(t, err)
+
+			c := &Controller{
+				statsClient: *statsReporter,
+				logger:      zaptest.NewLogger(t),
+				cfg:         Config{TolerateSyncStatusErrors: tt.tolerate},
+			}
+			lastStatus := &loadTestV1.LoadTestStatus{Phase: loadTestV1.LoadTestRunning, Namespace: "loadtest-name"}
+			loadTest := &loadTestV1.LoadTest{
+				Status: loadTestV1.LoadTestStatus{Phase: loadTestV1.LoadTestErrored, Namespace: "loadtest-name"},
+			}
+
+			err = c.handleSyncStatusError(context.Background(), loadTest, lastStatus, syncErr)
+			if tt.expectError {
+				assert.ErrorIs(t, err, syncErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, *lastStatus, loadTest.Status)
+		})
+	}
+}
+
+func TestAnnotateNamespaceOutcome(t *testing.T) {
+	completionTime := metaV1.NewTime(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+
+	for _, tt := range []struct {
+		name     string
+		phase    loadTestV1.LoadTestPhase
+		cfg      Config
+		expected map[string]string
+	}{
+		{
+			name:  "running loadtest",
+			phase: loadTestV1.LoadTestRunning,
+			cfg: Config{
+				NamespacePhaseAnnotation:          "kangal.hellofresh.com/phase",
+				NamespaceCompletionTimeAnnotation: "kangal.hellofresh.com/completion-time",
+			},
+		},
+		{
+			name:  "finished loadtest",
+			phase: loadTestV1.LoadTestFinished,
+			cfg: Config{
+				NamespacePhaseAnnotation:          "kangal.hellofresh.com/phase",
+				NamespaceCompletionTimeAnnotation: "kangal.hellofresh.com/completion-time",
+			},
+			expected: map[string]string{
+				"existing":                              "annotation",
+				"kangal.hellofresh.com/phase":           "finished",
+				"kangal.hellofresh.com/completion-time": "2024-01-02T03:04:05Z",
+			},
+		},
+		{
+			name:  "errored loadtest with phase annotation only",
+			phase: loadTestV1.LoadTestErrored,
+			cfg: Config{
+				NamespacePhaseAnnotation: "outcome",
+			},
+			expected: map[string]string{
+				"existing": "annotation",
+				"outcome":  "errored",
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			kubeClient := k8sfake.NewSimpleClientset(&coreV1.Namespace{
+				ObjectMeta: metaV1.ObjectMeta{
+					Name:        "loadtest-name",
+					Annotations: map[string]string{"existing": "annotation"},
+				},
+			})
+			c := &Controller{
+				kubeClientSet: kubeClient,
+				logger:        zaptest.NewLogger(t),
+				cfg:           tt.cfg,
+			}
+			loadTest := &loadTestV1.LoadTest{
+				ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-name"},
+				Status: loadTestV1.LoadTestStatus{
+					Phase:     tt.phase,
+					Namespace: "loadtest-name",
+					JobStatus: batchV1.JobStatus{CompletionTime: &completionTime},
+				},
+			}
+
+			c.annotateNamespaceOutcome(context.Background(), loadTest)
+
+			namespace, err := kubeClient.CoreV1().Namespaces().Get(context.Background(), "loadtest-name", metaV1.GetOptions{})
+			require.NoError(t, err)
+			if tt.expected == nil {
+				assert.Equal(t, map[string]string{"existing": "annotation"}, namespace.Annotations)
+				return
+			}
+			assert.Equal(t, tt.expected, namespace.Annotations)
+		})
+	}
+}
+
+func TestLabelLongRunning(t *testing.T) {
+	const label = "kangal.hellofresh.com/long-running"
+	longAgo := metaV1.NewTime(time.Now().Add(-2 * time.Hour))
+	recently := metaV1.NewTime(time.Now().Add(-time.Minute))
+
+	for _, tt := range []struct {
+		name      string
+		phase     loadTestV1.LoadTestPhase
+		startTime *metaV1.Time
+		labels    map[string]string
+		expected  map[string]string
+	}{
+		{
+			name:      "running within threshold",
+			phase:     loadTestV1.LoadTestRunning,
+			startTime: &recently,
+			labels:    map[string]string{"test-file-hash": "hash"},
+			expected:  map[string]string{"test-file-hash": "hash"},
+		},
+		{
+			name:      "running over threshold",
+			phase:     loadTestV1.LoadTestRunning,
+			startTime: &longAgo,
+			labels:    map[string]string{"test-file-hash": "hash"},
+			expected:  map[string]string{"test-file-hash": "hash", label: "true"},
+		},
+		{
+			name:      "finished",
+			phase:     loadTestV1.LoadTestFinished,
+			startTime: &longAgo,
+			labels:    map[string]string{"test-file-hash": "hash", label: "true"},
+			expected:  map[string]string{"test-file-hash": "hash"},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			loadTest := &loadTestV1.LoadTest{
+				ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-name", Labels: tt.labels},
+				Status: loadTestV1.LoadTestStatus{
+					Phase:     tt.phase,
+					JobStatus: batchV1.JobStatus{StartTime: tt.startTime},
+				},
+			}
+
+			kangalClient := fakeClientset.NewSimpleClientset(loadTest.DeepCopy())
+			c := &Controller{
+				kangalClientSet: kangalClient,
+				logger:          zaptest.NewLogger(t),
+				cfg: Config{
+					LongRunningThreshold: time.Hour,
+					LongRunningLabel:     label,
+				},
+			}
+
+			c.labelLongRunning(context.Background(), loadTest)
+
+			stored, err := kangalClient.KangalV1().LoadTests().Get(context.Background(), "loadtest-name", metaV1.GetOptions{})
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, stored.Labels)
+			assert.Equal(t, tt.expected, loadTest.Labels)
+		})
+	}
+}
+
+type syncTimeoutBackend struct {
+	backends.Backend
+	timeout time.Duration
+}
+
+func (b syncTimeoutBackend) SyncTimeout() time.Duration {
+	return b.timeout
+}
+
+func TestSyncTimeout(t *testing.T) {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	require.NoError(t, indexer.Add(&loadTestV1.LoadTest{ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-name"}}))
+
+	for _, tt := range []struct {
+		name     string
+		key      string
+		backend  backends.Backend
+		expected time.Duration
+	}{
+		{
+			name:     "backend without recommendation",
+			key:      "loadtest-name",
+			backend:  backends.NewMockBackend(nil),
+			expected: time.Minute,
+		},
+		{
+			name:     "longer recommendation",
+			key:      "loadtest-name",
+			backend:  syncTimeoutBackend{timeout: 5 * time.Minute},
+			expected: 5 * time.Minute,
+		},
+		{
+			name:     "shorter recommendation",
+			key:      "loadtest-name",
+			backend:  syncTimeoutBackend{timeout: 10 * time.Second},
+			expected: time.Minute,
+		},
+		{
+			name:     "deleted loadtest",
+			key:      "deleted-loadtest",
+			backend:  syncTimeoutBackend{timeout: 5 * time.Minute},
+			expected: time.Minute,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Controller{
+				cfg:             Config{SyncHandlerTimeout: time.Minute},
+				loadtestsLister: listers.NewLoadTestLister(indexer),
+				registry:        staticRegistry{backend: tt.backend},
+			}
+			assert.Equal(t, tt.expected, c.syncTimeout(tt.key))
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+// recordLoadTestDuration records the duration of the loadtest once it reached a terminal phase, tagged by backend
+// type and phase
+func (c *Controller) recordLoadTestDuration(ctx context.Context, loadTest *loadTestV1.LoadTest) {
+	if loadTest.Status.Phase != loadTestV1.LoadTestFinished && loadTest.Status.Phase != loadTestV1.LoadTestErrored {
+		return
+	}
+
+	c.statsClient.loadTestDurationStat.Record(ctx, loadTestLifetime(loadTest, time.Now()).Seconds(), metric.WithAttributes(
+		attribute.String("type", loadTest.Spec.Type.String()),
+		attribute.String("phase", loadTest.Status.Phase.String()),
+	))
+}
+
+// loadTestLifetime returns the time from the creation of the loadtest until its jobs completed, loadtests without
+// completion time, e.g. errored ones, are measured until now
+func loadTestLifetime(loadTest *loadTestV1.LoadTest, now time.Time) time.Duration {
+	end := now
+	if loadTest.Status.JobStatus.CompletionTime != nil {
+		end = loadTest.Status.JobStatus.CompletionTime.Time
+	}
+	return elapsedSince(loadTest.ObjectMeta.CreationTimestamp.Time, end)
+}
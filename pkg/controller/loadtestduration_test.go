@@ -0,0 +1,97 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdkMetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	batchV1 "k8s.io/api/batch/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+func TestRecordLoadTestDuration(t *testing.T) {
+	created := time.Now().Add(-time.Hour)
+	completed := metaV1.NewTime(created.Add(10 * time.Minute))
+
+	for _, tt := range []struct {
+		name     string
+		phase    loadTestV1.LoadTestPhase
+		recorded bool
+	}{
+		{
+			name:     "finished",
+			phase:    loadTestV1.LoadTestFinished,
+			recorded: true,
+		},
+		{
+			name:     "errored",
+			phase:    loadTestV1.LoadTestErrored,
+			recorded: true,
+		},
+		{
+			name:  "running",
+			phase: loadTestV1.LoadTestRunning,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := sdkMetric.NewManualReader()
+			statsReporter, err := NewMetricsReporter(sdkMetric.NewMeterProvider(sdkMetric.WithReader(reader)).Meter("test"))
+			require.NoError(t, err)
+
+			c := &Controller{statsClient: *statsReporter}
+			c.recordLoadTestDuration(context.Background(), &loadTestV1.LoadTest{
+				ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-name", CreationTimestamp: metaV1.NewTime(created)},
+				Spec:       loadTestV1.LoadTestSpec{Type: loadTestV1.LoadTestTypeK6},
+				Status: loadTestV1.LoadTestStatus{
+					Phase:     tt.phase,
+					JobStatus: batchV1.JobStatus{CompletionTime: &completed},
+				},
+			})
+
+			var rm metricdata.ResourceMetrics
+			require.NoError(t, reader.Collect(context.Background(), &rm))
+
+			var dataPoints []metricdata.HistogramDataPoint[float64]
+			for _, sm := range rm.ScopeMetrics {
+				for _, m := range sm.Metrics {
+					if m.Name != "kangal_loadtest_duration" {
+						continue
+					}
+					histogram, ok := m.Data.(metricdata.Histogram[float64])
+					require.True(t, ok)
+					dataPoints = histogram.DataPoints
+				}
+			}
+
+			if !tt.recorded {
+				assert.Empty(t, dataPoints)
+				return
+			}
+			require.Len(t, dataPoints, 1)
+			assert.Equal(t, uint64(1), dataPoints[0].Count)
+			assert.Equal(t, (10 * time.Minute).Seconds(), dataPoints[0].Sum)
+			phase, _ := dataPoints[0].Attributes.Value("phase")
+			assert.Equal(t, tt.phase.String(), phase.AsString())
+			backend, _ := dataPoints[0].Attributes.Value("type")
+			assert.Equal(t, "K6", backend.AsString())
+		})
+	}
+}
+
+func TestLoadTestLifetime(t *testing.T) {
+	now := time.Now()
+	created := metaV1.NewTime(now.Add(-time.Hour))
+	completed := metaV1.NewTime(now.Add(-15 * time.Minute))
+
+	loadTest := &loadTestV1.LoadTest{ObjectMeta: metaV1.ObjectMeta{CreationTimestamp: created}}
+	assert.Equal(t, time.Hour, loadTestLifetime(loadTest, now))
+
+	loadTest.Status.JobStatus.CompletionTime = &completed
+	assert.Equal(t, 45*time.Minute, loadTestLifetime(loadTest, now))
+}
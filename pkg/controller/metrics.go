@@ -15,8 +15,9 @@ import (
 	mPkg "github.com/hellofresh/kangal/pkg/core/middleware"
 )
 
-// RunMetricsServer starts Prometheus metrics server
-func RunMetricsServer(cfg Config, rr Runner, stopChan chan struct{}) error {
+// RunMetricsServer starts Prometheus metrics server with the health check and backends endpoints,
+// and the pause, debug and config endpoints if enabled
+func RunMetricsServer(cfg Config, rr Runner, checker ReadinessChecker, pauser Pauser, debugger LoadTestDebugger, lister BackendLister, stopChan chan struct{}) error {
 	r := chi.NewRouter()
 	// Define Middleware
 	r.Use(middleware.RequestID)
@@ -27,7 +28,22 @@ func RunMetricsServer(cfg Config, rr Runner, stopChan chan struct{}) error {
 	// Register Routes
 	r.Get("/", cHttp.LivenessHandler("Kangal Controller"))
 	r.Get("/status", cHttp.LivenessHandler("Kangal Controller"))
+	r.Get("/healthz", cHttp.LivenessHandler("Kangal Controller"))
+	r.Get("/readyz", ReadinessHandler(checker, rr.Logger))
 	r.Handle("/metrics", promhttp.Handler())
+	r.Get("/backends", BackendsHandler(lister))
+	if cfg.PauseEndpoint {
+		r.Get("/pause", PauseHandler(pauser, cfg.DebugEndpointToken, rr.Logger))
+		r.Put("/pause", PauseHandler(pauser, cfg.DebugEndpointToken, rr.Logger))
+		r.Delete("/pause", PauseHandler(pauser, cfg.DebugEndpointToken, rr.Logger))
+	}
+	if cfg.DebugEndpoint {
+		r.Get("/debug/loadtest/{name}", DebugHandler(debugger, cfg.DebugEndpointToken, rr.Logger))
+		r.Get("/debug/loadtest/{namespace}/{name}", DebugHandler(debugger, cfg.DebugEndpointToken, rr.Logger))
+	}
+	if cfg.ConfigEndpoint {
+		r.Get("/config", ConfigHandler(cfg, cfg.DebugEndpointToken, rr.Logger))
+	}
 
 	// Run HTTP Server
 	address := fmt.Sprintf(":%d", cfg.HTTPPort)
@@ -35,7 +51,7 @@ func RunMetricsServer(cfg Config, rr Runner, stopChan chan struct{}) error {
 
 	go func() {
 		// Try and run http server, fail on error
-		if err := http.ListenAndServe(address, promhttp.Handler()); err != nil {
+		if err := http.ListenAndServe(address, r); err != nil {
 			rr.Logger.Error("Failed to run HTTP server", zap.Error(err))
 			close(stopChan)
 		}
@@ -0,0 +1,71 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+	coreV1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+// mirrorStatus writes the status of the loadtest to its mirror ConfigMap in StatusMirrorNamespace. The ConfigMap
+// is owned by the loadtest, so it is garbage collected once the loadtest is deleted.
+func (c *Controller) mirrorStatus(ctx context.Context, loadTest *loadTestV1.LoadTest) {
+	if c.cfg.StatusMirrorNamespace == "" {
+		return
+	}
+
+	configMaps := c.kubeClientSet.CoreV1().ConfigMaps(c.cfg.StatusMirrorNamespace)
+	mirror := newStatusMirror(loadTest, c.cfg.StatusMirrorNamespace, c.reportURL(loadTest))
+
+	_, err := configMaps.Update(ctx, mirror, metaV1.UpdateOptions{})
+	if errors.IsNotFound(err) {
+		_, err = configMaps.Create(ctx, mirror, metaV1.CreateOptions{})
+	}
+	if err != nil {
+		c.logger.Error("Failed to mirror loadtest status",
+			zap.String("loadtest", loadTest.GetName()),
+			zap.String("namespace", c.cfg.StatusMirrorNamespace),
+			zap.Error(err),
+		)
+	}
+}
+
+// newStatusMirror builds the ConfigMap mirroring the status of the loadtest, timestamps are formatted
+// in RFC 3339 and left out until they are known
+func newStatusMirror(loadTest *loadTestV1.LoadTest, namespace, reportURL string) *coreV1.ConfigMap {
+	data := map[string]string{
+		"phase":             loadTest.Status.Phase.String(),
+		"namespace":         loadTest.Status.Namespace,
+		"type":              loadTest.Spec.Type.String(),
+		"creationTimestamp": loadTest.GetCreationTimestamp().UTC().Format(time.RFC3339),
+	}
+	if reportURL != "" {
+		data["reportURL"] = reportURL
+	}
+	if startTime := loadTest.Status.JobStatus.StartTime; startTime != nil {
+		data["startTime"] = startTime.UTC().Format(time.RFC3339)
+	}
+	if completionTime := loadTest.Status.JobStatus.CompletionTime; completionTime != nil {
+		data["completionTime"] = completionTime.UTC().Format(time.RFC3339)
+	}
+
+	return &coreV1.ConfigMap{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:      loadTest.GetName(),
+			Namespace: namespace,
+			Labels: map[string]string{
+				"app":        "kangal",
+				"controller": loadTest.GetName(),
+			},
+			OwnerReferences: []metaV1.OwnerReference{
+				*metaV1.NewControllerRef(loadTest, loadTestV1.SchemeGroupVersion.WithKind("LoadTest")),
+			},
+		},
+		Data: data,
+	}
+}
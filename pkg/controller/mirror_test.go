@@ -0,0 +1,62 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+func TestMirrorStatus(t *testing.T) {
+	ctx := context.Background()
+	created := metaV1.NewTime(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+	completed := metaV1.NewTime(created.Add(time.Hour))
+
+	kubeClient := k8sfake.NewSimpleClientset()
+	c := &Controller{
+		cfg: Config{
+			StatusMirrorNamespace: "kangal-status",
+			KangalProxyURL:        "http://kangal-proxy",
+		},
+		kubeClientSet: kubeClient,
+		logger:        zaptest.NewLogger(t),
+	}
+	loadTest := &loadTestV1.LoadTest{
+		ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-name", CreationTimestamp: created},
+		Spec:       loadTestV1.LoadTestSpec{Type: loadTestV1.LoadTestTypeK6},
+		Status: loadTestV1.LoadTestStatus{
+			Phase:     loadTestV1.LoadTestRunning,
+			Namespace: "loadtest-name",
+		},
+	}
+
+	c.mirrorStatus(ctx, loadTest)
+
+	mirror, err := kubeClient.CoreV1().ConfigMaps("kangal-status").Get(ctx, "loadtest-name", metaV1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"phase":             "running",
+		"namespace":         "loadtest-name",
+		"type":              "K6",
+		"creationTimestamp": "2024-01-02T03:04:05Z",
+		"reportURL":         "http://kangal-proxy/load-test/loadtest-name/report",
+	}, mirror.Data)
+	require.Len(t, mirror.OwnerReferences, 1)
+	assert.Equal(t, "LoadTest", mirror.OwnerReferences[0].Kind)
+
+	loadTest.Status.Phase = loadTestV1.LoadTestFinished
+	loadTest.Status.JobStatus.CompletionTime = &completed
+	c.mirrorStatus(ctx, loadTest)
+
+	mirror, err = kubeClient.CoreV1().ConfigMaps("kangal-status").Get(ctx, "loadtest-name", metaV1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "finished", mirror.Data["phase"])
+	assert.Equal(t, "2024-01-02T04:04:05Z", mirror.Data["completionTime"])
+}
@@ -0,0 +1,73 @@
+package controller
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.uber.org/zap"
+	batchV1 "k8s.io/api/batch/v1"
+	coreV1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+// activeJobs returns the number of the given jobs that are neither complete nor failed
+func activeJobs(jobs []*batchV1.Job) int {
+	active := 0
+	for _, job := range jobs {
+		if !jobFinished(job) {
+			active++
+		}
+	}
+	return active
+}
+
+// jobFinished tells if the job has a true Complete or Failed condition
+func jobFinished(job *batchV1.Job) bool {
+	for _, condition := range job.Status.Conditions {
+		if (condition.Type == batchV1.JobComplete || condition.Type == batchV1.JobFailed) && condition.Status == coreV1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+// checkNamespaceJobs defers the backend sync of loadtests that have not created their jobs yet while MaxNamespaceJobs
+// jobs are active in their namespace, e.g. an existing namespace shared by many loadtests, deferred loadtests are
+// synced again after NamespaceJobsRetryInterval. Jobs are counted from the informer cache and a backend may create
+// several jobs at once, so the cap may be exceeded by a few. It returns false if the backend sync was deferred.
+func (c *Controller) checkNamespaceJobs(ctx context.Context, key string, loadTest *loadTestV1.LoadTest) (bool, error) {
+	if c.cfg.MaxNamespaceJobs <= 0 || loadTest.Status.Namespace == "" {
+		return true, nil
+	}
+
+	owned, err := c.loadTestJobs(loadTest)
+	if err != nil {
+		return true, err
+	}
+	if len(owned) > 0 {
+		return true, nil
+	}
+
+	jobs, err := c.jobsLister.Jobs(loadTest.Status.Namespace).List(labels.Everything())
+	if err != nil {
+		return true, err
+	}
+	active := activeJobs(jobs)
+	if active < c.cfg.MaxNamespaceJobs {
+		return true, nil
+	}
+
+	c.logger.Info("Deferring loadtest job creation, maximum of jobs in namespace reached",
+		zap.String("loadtest", loadTest.GetName()),
+		zap.String("namespace", loadTest.Status.Namespace),
+		zap.Int("active", active),
+	)
+	c.statsClient.jobCreationDeferredCountStat.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("type", loadTest.Spec.Type.String()),
+	))
+	c.workQueue.AddAfter(key, c.cfg.NamespaceJobsRetryInterval)
+	return false, nil
+}
@@ -0,0 +1,79 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdkMetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.uber.org/zap/zaptest"
+	batchV1 "k8s.io/api/batch/v1"
+	coreV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	batchListersV1 "k8s.io/client-go/listers/batch/v1"
+	"k8s.io/client-go/tools/cache"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+func TestCheckNamespaceJobs(t *testing.T) {
+	ctx := context.Background()
+	reader := sdkMetric.NewManualReader()
+	statsReporter, err := NewMetricsReporter(sdkMetric.NewMeterProvider(sdkMetric.WithReader(reader)).Meter("test"))
+	require.NoError(t, err)
+
+	newJob := func(name string, owner types.UID, finished bool) *batchV1.Job {
+		job := &batchV1.Job{
+			ObjectMeta: metaV1.ObjectMeta{
+				Name:            name,
+				Namespace:       "shared",
+				OwnerReferences: ownedBy("LoadTest", string(owner), owner),
+			},
+		}
+		if finished {
+			job.Status.Conditions = []batchV1.JobCondition{{Type: batchV1.JobComplete, Status: coreV1.ConditionTrue}}
+		}
+		return job
+	}
+	newLoadTest := func(uid types.UID) *loadTestV1.LoadTest {
+		return &loadTestV1.LoadTest{
+			ObjectMeta: metaV1.ObjectMeta{Name: string(uid), UID: uid},
+			Spec:       loadTestV1.LoadTestSpec{Type: loadTestV1.LoadTestTypeK6, ExistingNamespace: "shared"},
+			Status:     loadTestV1.LoadTestStatus{Phase: loadTestV1.LoadTestCreating, Namespace: "shared"},
+		}
+	}
+
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	require.NoError(t, indexer.Add(newJob("loadtest-a-job", "loadtest-a", false)))
+	require.NoError(t, indexer.Add(newJob("loadtest-b-job", "loadtest-b", true)))
+
+	c := &Controller{
+		cfg:        Config{MaxNamespaceJobs: 1, NamespaceJobsRetryInterval: time.Hour},
+		jobsLister: batchListersV1.NewJobLister(indexer),
+		workQueue: newPriorityQueue("test", 1, func(interface{}) priority {
+			return priorityNormal
+		}),
+		statsClient: *statsReporter,
+		logger:      zaptest.NewLogger(t),
+	}
+	defer c.workQueue.ShutDown()
+
+	allowed, err := c.checkNamespaceJobs(ctx, "loadtest-c", newLoadTest("loadtest-c"))
+	require.NoError(t, err)
+	assert.False(t, allowed, "Expected job creation to be deferred while the maximum is reached")
+	assert.Equal(t, int64(1), collectInt64Sum(t, reader, "kangal_job_creation_deferred_total"))
+
+	// loadtests that already created their jobs keep syncing them
+	allowed, err = c.checkNamespaceJobs(ctx, "loadtest-a", newLoadTest("loadtest-a"))
+	require.NoError(t, err)
+	assert.True(t, allowed)
+
+	require.NoError(t, indexer.Update(newJob("loadtest-a-job", "loadtest-a", true)))
+	allowed, err = c.checkNamespaceJobs(ctx, "loadtest-c", newLoadTest("loadtest-c"))
+	require.NoError(t, err)
+	assert.True(t, allowed, "Expected job creation once the active jobs finished")
+	assert.Equal(t, int64(1), collectInt64Sum(t, reader, "kangal_job_creation_deferred_total"))
+}
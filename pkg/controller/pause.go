@@ -0,0 +1,134 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/go-chi/render"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/labels"
+	utilRuntime "k8s.io/apimachinery/pkg/util/runtime"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+// pausedAnnotation set to "true" on a LoadTest stops its reconciliation, e.g. while its jobs are debugged
+// manually, removing it resumes the reconciliation
+const pausedAnnotation = "kangal.hellofresh.com/paused"
+
+// Pauser switches the reconciliation of loadtests off and on
+type Pauser interface {
+	Pause()
+	Resume()
+	Paused() bool
+}
+
+// PauseStatus is the response of the pause endpoint
+type PauseStatus struct {
+	Paused bool `json:"paused"`
+}
+
+// Pause stops the reconciliation of loadtests, the controller keeps running with warm caches
+// but leaves loadtests and their resources untouched until Resume is called
+func (c *Controller) Pause() {
+	if c.paused.Swap(true) {
+		return
+	}
+	c.statsClient.pausedStat.Add(context.Background(), 1)
+	c.logger.Warn("Reconciliation paused, loadtests are not synced until resumed")
+
+	// loadtests are not known yet when the controller starts paused
+	if c.cfg.SuspendJobsOnPause && c.loadtestsSynced != nil && c.loadtestsSynced() {
+		c.startSuspending()
+	}
+}
+
+// suspension is the suspension of the jobs started by Pause, closing stop ends it after the loadtest in flight
+// and done is closed once it ended
+type suspension struct {
+	stop chan struct{}
+	done chan struct{}
+}
+
+// startSuspending suspends the jobs in the background, so the pause request doesn't wait for all loadtests,
+// the suspension is bounded by SyncHandlerTimeout
+func (c *Controller) startSuspending() {
+	s := &suspension{stop: make(chan struct{}), done: make(chan struct{})}
+	c.suspendMu.Lock()
+	c.suspension = s
+	c.suspendMu.Unlock()
+
+	go func() {
+		defer close(s.done)
+		ctx, cancel := context.WithTimeout(context.Background(), c.cfg.SyncHandlerTimeout)
+		defer cancel()
+		c.suspendJobs(ctx, s.stop)
+	}()
+}
+
+// stopSuspending stops the suspension started by Pause and waits for the loadtest in flight,
+// so the syncs enqueued by Resume see its Suspended condition and resume its jobs
+func (c *Controller) stopSuspending() {
+	c.suspendMu.Lock()
+	s := c.suspension
+	c.suspension = nil
+	c.suspendMu.Unlock()
+
+	if s == nil {
+		return
+	}
+	close(s.stop)
+	<-s.done
+}
+
+// Resume restarts the reconciliation of loadtests and enqueues all of them,
+// so changes that happened while paused are picked up right away and suspended jobs are resumed
+func (c *Controller) Resume() {
+	if !c.paused.Swap(false) {
+		return
+	}
+	c.statsClient.pausedStat.Add(context.Background(), -1)
+	c.logger.Info("Reconciliation resumed")
+	c.stopSuspending()
+
+	loadTests, err := c.loadtestsLister.List(labels.Everything())
+	if err != nil {
+		utilRuntime.HandleError(err)
+		return
+	}
+	for _, loadTest := range loadTests {
+		c.enqueueLoadTest(loadTest, triggerResume)
+	}
+}
+
+// Paused tells if the reconciliation of loadtests is paused
+func (c *Controller) Paused() bool {
+	return c.paused.Load()
+}
+
+// loadTestPaused tells if the reconciliation of the loadtest is paused with the paused annotation
+func loadTestPaused(loadTest *loadTestV1.LoadTest) bool {
+	return loadTest.GetAnnotations()[pausedAnnotation] == "true"
+}
+
+// PauseHandler returns the state of the reconciliation on GET, pauses it on PUT and resumes it on DELETE.
+// PUT and DELETE requests must carry the given token as bearer token.
+func PauseHandler(pauser Pauser, token string, logger *zap.Logger) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && !authorized(r, token) {
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+
+		switch r.Method {
+		case http.MethodPut:
+			logger.Info("Pause requested", zap.String("remote", r.RemoteAddr))
+			pauser.Pause()
+		case http.MethodDelete:
+			logger.Info("Resume requested", zap.String("remote", r.RemoteAddr))
+			pauser.Resume()
+		}
+
+		render.JSON(w, r, &PauseStatus{Paused: pauser.Paused()})
+	}
+}
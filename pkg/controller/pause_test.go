@@ -0,0 +1,170 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/metric/noop"
+	"go.uber.org/zap/zaptest"
+	batchV1 "k8s.io/api/batch/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	batchListersV1 "k8s.io/client-go/listers/batch/v1"
+	"k8s.io/client-go/tools/cache"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+	fakeClientset "github.com/hellofresh/kangal/pkg/kubernetes/generated/clientset/versioned/fake"
+	listers "github.com/hellofresh/kangal/pkg/kubernetes/generated/listers/loadtest/v1"
+)
+
+func TestPauseResume(t *testing.T) {
+	statsReporter, err := NewMetricsReporter(noop.NewMeterProvider().Meter("test"))
+	require.NoError(t, err)
+
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	require.NoError(t, indexer.Add(&loadTestV1.LoadTest{ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-name"}}))
+
+	// the backend is nil, syncing it would panic
+	c := &Controller{
+		loadtestsLister: listers.NewLoadTestLister(indexer),
+		registry:        staticRegistry{},
+		workQueue: newPriorityQueue("test", 1, func(interface{}) priority {
+			return priorityNormal
+		}),
+		statsClient: *statsReporter,
+		logger:      zaptest.NewLogger(t),
+	}
+	defer c.workQueue.ShutDown()
+
+	c.Pause()
+	assert.True(t, c.Paused())
+
+	// paused loadtests are skipped before they are looked up
+	require.NoError(t, c.syncHandler("loadtest-name"))
+
+	c.Resume()
+	assert.False(t, c.Paused())
+	assert.Equal(t, 1, c.workQueue.Len(), "Expected loadtests to be enqueued on resume")
+}
+
+func TestPauseSuspendJobs(t *testing.T) {
+	statsReporter, err := NewMetricsReporter(noop.NewMeterProvider().Meter("test"))
+	require.NoError(t, err)
+
+	job := &batchV1.Job{ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-job", Namespace: "loadtest-namespace"}}
+	running := &loadTestV1.LoadTest{
+		ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-running"},
+		Status:     loadTestV1.LoadTestStatus{Phase: loadTestV1.LoadTestRunning, Namespace: "loadtest-namespace"},
+	}
+	jobIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	require.NoError(t, jobIndexer.Add(job))
+	loadTestIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	require.NoError(t, loadTestIndexer.Add(running))
+	kubeClient := k8sfake.NewSimpleClientset(job)
+
+	c := &Controller{
+		cfg:             Config{SuspendJobsOnPause: true, SyncHandlerTimeout: time.Minute},
+		kubeClientSet:   kubeClient,
+		kangalClientSet: fakeClientset.NewSimpleClientset(running),
+		jobsLister:      batchListersV1.NewJobLister(jobIndexer),
+		loadtestsLister: listers.NewLoadTestLister(loadTestIndexer),
+		loadtestsSynced: func() bool { return true },
+		registry:        staticRegistry{},
+		workQueue: newPriorityQueue("test", 1, func(interface{}) priority {
+			return priorityNormal
+		}),
+		statsClient: *statsReporter,
+		logger:      zaptest.NewLogger(t),
+	}
+	defer c.workQueue.ShutDown()
+
+	c.Pause()
+	assert.Eventually(t, func() bool {
+		suspended, err := kubeClient.BatchV1().Jobs("loadtest-namespace").Get(context.Background(), "loadtest-job", metaV1.GetOptions{})
+		return err == nil && suspended.Spec.Suspend != nil && *suspended.Spec.Suspend
+	}, time.Second, 10*time.Millisecond, "Expected the jobs to be suspended in the background")
+
+	c.Resume()
+	assert.Nil(t, c.suspension, "Expected resume to stop the suspension")
+}
+
+func TestSyncHandlerPausedAnnotation(t *testing.T) {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	require.NoError(t, indexer.Add(&loadTestV1.LoadTest{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:        "loadtest-name",
+			Annotations: map[string]string{pausedAnnotation: "true"},
+		},
+		Status: loadTestV1.LoadTestStatus{Phase: loadTestV1.LoadTestRunning},
+	}))
+
+	// the backend is nil, syncing it would panic
+	c := &Controller{
+		loadtestsLister: listers.NewLoadTestLister(indexer),
+		registry:        staticRegistry{},
+		logger:          zaptest.NewLogger(t),
+	}
+
+	require.NoError(t, c.syncHandler("loadtest-name"))
+}
+
+func TestLoadTestPaused(t *testing.T) {
+	for _, tt := range []struct {
+		annotations map[string]string
+		expected    bool
+	}{
+		{annotations: nil, expected: false},
+		{annotations: map[string]string{pausedAnnotation: "false"}, expected: false},
+		{annotations: map[string]string{pausedAnnotation: "true"}, expected: true},
+	} {
+		loadTest := &loadTestV1.LoadTest{ObjectMeta: metaV1.ObjectMeta{Annotations: tt.annotations}}
+		assert.Equal(t, tt.expected, loadTestPaused(loadTest))
+	}
+}
+
+type fakePauser struct {
+	paused bool
+}
+
+func (p *fakePauser) Pause()       { p.paused = true }
+func (p *fakePauser) Resume()      { p.paused = false }
+func (p *fakePauser) Paused() bool { return p.paused }
+
+func TestPauseHandler(t *testing.T) {
+	pauser := &fakePauser{}
+	handler := PauseHandler(pauser, "secret", zaptest.NewLogger(t))
+
+	for _, tt := range []struct {
+		method string
+		token  string
+		code   int
+		paused bool
+		body   string
+	}{
+		{method: http.MethodGet, code: http.StatusOK, paused: false, body: `{"paused":false}`},
+		{method: http.MethodPut, code: http.StatusUnauthorized, paused: false},
+		{method: http.MethodPut, token: "wrong", code: http.StatusUnauthorized, paused: false},
+		{method: http.MethodPut, token: "secret", code: http.StatusOK, paused: true, body: `{"paused":true}`},
+		{method: http.MethodGet, code: http.StatusOK, paused: true, body: `{"paused":true}`},
+		{method: http.MethodDelete, code: http.StatusUnauthorized, paused: true},
+		{method: http.MethodDelete, token: "secret", code: http.StatusOK, paused: false, body: `{"paused":false}`},
+	} {
+		r := httptest.NewRequest(tt.method, "/pause", nil)
+		if tt.token != "" {
+			r.Header.Set("Authorization", "Bearer "+tt.token)
+		}
+		w := httptest.NewRecorder()
+		handler(w, r)
+
+		assert.Equal(t, tt.code, w.Code)
+		assert.Equal(t, tt.paused, pauser.paused)
+		if tt.body != "" {
+			assert.JSONEq(t, tt.body, w.Body.String())
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package controller
+
+import (
+	"context"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+// phaseCounter keeps the phase each LoadTest is counted in by the loadtests by phase metric, so a LoadTest
+// is moved between phases when its phase changes and dropped once it is deleted, whatever its last phase was
+type phaseCounter struct {
+	stat metric.Int64UpDownCounter
+
+	mu     sync.Mutex
+	phases map[string]loadTestV1.LoadTestPhase
+}
+
+func newPhaseCounter(stat metric.Int64UpDownCounter) *phaseCounter {
+	return &phaseCounter{
+		stat:   stat,
+		phases: map[string]loadTestV1.LoadTestPhase{},
+	}
+}
+
+// record counts the given LoadTest key in phase, loadtests without phase yet are not counted
+func (p *phaseCounter) record(ctx context.Context, key string, phase loadTestV1.LoadTestPhase) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	previous, ok := p.phases[key]
+	if ok && previous == phase {
+		return
+	}
+	if ok {
+		p.stat.Add(ctx, -1, metric.WithAttributes(attribute.String("phase", string(previous))))
+		delete(p.phases, key)
+	}
+	if phase == "" {
+		return
+	}
+	p.stat.Add(ctx, 1, metric.WithAttributes(attribute.String("phase", string(phase))))
+	p.phases[key] = phase
+}
+
+// forget stops counting the given LoadTest key
+func (p *phaseCounter) forget(ctx context.Context, key string) {
+	p.record(ctx, key, "")
+}
@@ -0,0 +1,59 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdkMetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+func TestPhaseCounter(t *testing.T) {
+	ctx := context.Background()
+	reader := sdkMetric.NewManualReader()
+	statsReporter, err := NewMetricsReporter(sdkMetric.NewMeterProvider(sdkMetric.WithReader(reader)).Meter("test"))
+	require.NoError(t, err)
+
+	p := newPhaseCounter(statsReporter.loadTestsByPhaseStat)
+
+	p.record(ctx, "default/loadtest-new", "")
+	p.record(ctx, "default/loadtest-a", loadTestV1.LoadTestCreating)
+	p.record(ctx, "default/loadtest-b", loadTestV1.LoadTestCreating)
+	p.record(ctx, "default/loadtest-b", loadTestV1.LoadTestCreating)
+	assert.Equal(t, map[string]int64{"creating": 2}, collectLoadTestsByPhase(t, reader))
+
+	p.record(ctx, "default/loadtest-a", loadTestV1.LoadTestRunning)
+	assert.Equal(t, map[string]int64{"creating": 1, "running": 1}, collectLoadTestsByPhase(t, reader))
+
+	// a loadtest deleted while running is not counted anymore
+	p.forget(ctx, "default/loadtest-a")
+	p.forget(ctx, "default/loadtest-unknown")
+	assert.Equal(t, map[string]int64{"creating": 1, "running": 0}, collectLoadTestsByPhase(t, reader))
+}
+
+// collectLoadTestsByPhase returns the values of the loadtests by phase metric by phase
+func collectLoadTestsByPhase(t *testing.T, reader sdkMetric.Reader) map[string]int64 {
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+
+	values := map[string]int64{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != "kangal_loadtests_by_phase" {
+				continue
+			}
+			sum, ok := m.Data.(metricdata.Sum[int64])
+			require.True(t, ok)
+
+			for _, dp := range sum.DataPoints {
+				phase, _ := dp.Attributes.Value("phase")
+				values[phase.AsString()] = dp.Value
+			}
+		}
+	}
+	return values
+}
@@ -0,0 +1,127 @@
+package controller
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+	batchV1 "k8s.io/api/batch/v1"
+	coreV1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/hellofresh/kangal/pkg/backends"
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+// postCompletionJobName is the name of the job run after a loadtest succeeded
+const postCompletionJobName = "loadtest-post-completion"
+
+// newPostCompletionJob returns the post-completion job of the loadtest. Its container gets the report URL of the
+// loadtest to download the results from.
+func newPostCompletionJob(loadTest *loadTestV1.LoadTest, reportURL string) *batchV1.Job {
+	ownerRef := metaV1.NewControllerRef(loadTest, loadTestV1.SchemeGroupVersion.WithKind("LoadTest"))
+	backoffLimit := int32(0)
+
+	return &batchV1.Job{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:            postCompletionJobName,
+			Namespace:       loadTest.Status.Namespace,
+			OwnerReferences: []metaV1.OwnerReference{*ownerRef},
+		},
+		Spec: batchV1.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: coreV1.PodTemplateSpec{
+				Spec: coreV1.PodSpec{
+					RestartPolicy: coreV1.RestartPolicyNever,
+					Containers: []coreV1.Container{{
+						Name:    "post-completion",
+						Image:   loadTest.Spec.PostCompletion.Image,
+						Command: loadTest.Spec.PostCompletion.Command,
+						Env: []coreV1.EnvVar{
+							{Name: "LOADTEST_NAME", Value: loadTest.GetName()},
+							{Name: "REPORT_URL", Value: reportURL},
+						},
+					}},
+				},
+			},
+		},
+	}
+}
+
+// checkPostCompletion runs the post-completion job of loadtests that define one once they finished. The
+// loadtest is kept running until the job completed and is moved to errored phase when the job fails, the
+// state of the job is recorded in the PostCompletion condition.
+func (c *Controller) checkPostCompletion(ctx context.Context, loadTest *loadTestV1.LoadTest) error {
+	if loadTest.Spec.PostCompletion == nil || loadTest.Status.Namespace == "" {
+		return nil
+	}
+
+	job, err := c.jobsLister.Jobs(loadTest.Status.Namespace).Get(postCompletionJobName)
+	if errors.IsNotFound(err) {
+		if loadTest.Status.Phase != loadTestV1.LoadTestFinished {
+			return nil
+		}
+		return c.startPostCompletion(ctx, loadTest)
+	}
+	if err != nil {
+		return err
+	}
+
+	// the job status is not known to the backends, which may consider the job as part of the loadtest
+	phase, _ := backends.PhaseFromJobConditions(job.Status)
+	switch phase {
+	case loadTestV1.LoadTestFinished:
+		loadTest.Status.Phase = loadTestV1.LoadTestFinished
+		c.setPostCompletionCondition(loadTest, metaV1.ConditionTrue, loadTestV1.LoadTestPostCompletionSucceeded,
+			"Post-completion job completed")
+	case loadTestV1.LoadTestErrored:
+		if !meta.IsStatusConditionFalse(loadTest.Status.Conditions, loadTestV1.LoadTestConditionPostCompletion) {
+			c.recorder.Event(loadTest, coreV1.EventTypeWarning, reasonPostCompletionFailed, "Post-completion job failed")
+		}
+		setErrored(loadTest, reasonPostCompletionFailed, "Post-completion job failed")
+		c.setPostCompletionCondition(loadTest, metaV1.ConditionFalse, loadTestV1.LoadTestPostCompletionFailed,
+			"Post-completion job failed")
+	default:
+		loadTest.Status.Phase = loadTestV1.LoadTestRunning
+	}
+	return nil
+}
+
+// startPostCompletion creates the post-completion job of the finished loadtest and keeps the loadtest running. The
+// job is scheduled like the loadtest pods, with the configured node selector, tolerations and pod annotations.
+func (c *Controller) startPostCompletion(ctx context.Context, loadTest *loadTestV1.LoadTest) error {
+	job := newPostCompletionJob(loadTest, c.reportURL(loadTest))
+	job.Spec.Template.Annotations = backends.MergePodAnnotations(c.cfg.PodAnnotations, nil, loadTest.Spec.PodAnnotations)
+	job.Spec.Template.Spec.NodeSelector = c.cfg.NodeSelectors
+	job.Spec.Template.Spec.Tolerations = c.cfg.Tolerations.KubeToleration()
+	job.Spec.Template.Spec.ImagePullSecrets = backends.ImagePullSecrets(c.cfg.ImagePullSecrets, loadTest.Spec.ImagePullSecrets)
+	job.Spec.Template.Spec.SecurityContext = backends.PodSecurityContext(c.cfg.SeccompProfile)
+	_, err := c.kubeClientSet.BatchV1().Jobs(job.GetNamespace()).Create(ctx, job, metaV1.CreateOptions{})
+	if err != nil && !errors.IsAlreadyExists(err) {
+		return err
+	}
+
+	if err == nil {
+		c.logger.Info("Started post-completion job",
+			zap.String("loadtest", loadTest.GetName()),
+			zap.String("image", job.Spec.Template.Spec.Containers[0].Image),
+		)
+		c.recorder.Event(loadTest, coreV1.EventTypeNormal, reasonPostCompletionStarted, "Started post-completion job")
+	}
+
+	loadTest.Status.Phase = loadTestV1.LoadTestRunning
+	c.setPostCompletionCondition(loadTest, metaV1.ConditionUnknown, loadTestV1.LoadTestPostCompletionRunning,
+		"Post-completion job is running")
+	return nil
+}
+
+func (c *Controller) setPostCompletionCondition(loadTest *loadTestV1.LoadTest, status metaV1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(&loadTest.Status.Conditions, metaV1.Condition{
+		Type:               loadTestV1.LoadTestConditionPostCompletion,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: loadTest.GetGeneration(),
+	})
+}
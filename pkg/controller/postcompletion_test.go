@@ -0,0 +1,134 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+	batchV1 "k8s.io/api/batch/v1"
+	coreV1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	batchListersV1 "k8s.io/client-go/listers/batch/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+func TestCheckPostCompletion(t *testing.T) {
+	newJob := func(conditionType batchV1.JobConditionType) *batchV1.Job {
+		job := &batchV1.Job{ObjectMeta: metaV1.ObjectMeta{Name: postCompletionJobName, Namespace: "loadtest-namespace"}}
+		if conditionType != "" {
+			job.Status.Conditions = []batchV1.JobCondition{{Type: conditionType, Status: coreV1.ConditionTrue}}
+		}
+		return job
+	}
+
+	for _, tt := range []struct {
+		name            string
+		phase           loadTestV1.LoadTestPhase
+		job             *batchV1.Job
+		expectPhase     loadTestV1.LoadTestPhase
+		expectCondition metaV1.ConditionStatus
+		expectCreated   bool
+		expectEvents    int
+	}{
+		{
+			name:        "loadtest still running",
+			phase:       loadTestV1.LoadTestRunning,
+			expectPhase: loadTestV1.LoadTestRunning,
+		},
+		{
+			name:        "loadtest errored",
+			phase:       loadTestV1.LoadTestErrored,
+			expectPhase: loadTestV1.LoadTestErrored,
+		},
+		{
+			name:            "loadtest finished starts the job",
+			phase:           loadTestV1.LoadTestFinished,
+			expectPhase:     loadTestV1.LoadTestRunning,
+			expectCondition: metaV1.ConditionUnknown,
+			expectCreated:   true,
+			expectEvents:    1,
+		},
+		{
+			name:        "job running",
+			phase:       loadTestV1.LoadTestFinished,
+			job:         newJob(""),
+			expectPhase: loadTestV1.LoadTestRunning,
+		},
+		{
+			name:            "job completed",
+			phase:           loadTestV1.LoadTestFinished,
+			job:             newJob(batchV1.JobComplete),
+			expectPhase:     loadTestV1.LoadTestFinished,
+			expectCondition: metaV1.ConditionTrue,
+		},
+		{
+			name:            "job failed",
+			phase:           loadTestV1.LoadTestFinished,
+			job:             newJob(batchV1.JobFailed),
+			expectPhase:     loadTestV1.LoadTestErrored,
+			expectCondition: metaV1.ConditionFalse,
+			expectEvents:    1,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+			kubeClient := k8sfake.NewSimpleClientset()
+			if tt.job != nil {
+				require.NoError(t, indexer.Add(tt.job))
+			}
+			recorder := record.NewFakeRecorder(10)
+
+			c := &Controller{
+				cfg: Config{
+					KangalProxyURL: "http://kangal-proxy",
+					NodeSelectors:  map[string]string{"pool": "loadtest"},
+					PodAnnotations: map[string]string{"team": "platform"},
+				},
+				kubeClientSet: kubeClient,
+				jobsLister:    batchListersV1.NewJobLister(indexer),
+				recorder:      recorder,
+				logger:        zaptest.NewLogger(t),
+			}
+			loadTest := &loadTestV1.LoadTest{
+				ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-name"},
+				Spec: loadTestV1.LoadTestSpec{
+					PostCompletion: &loadTestV1.PostCompletionJob{Image: "summarizer:latest", Command: []string{"summarize"}},
+				},
+				Status: loadTestV1.LoadTestStatus{Phase: tt.phase, Namespace: "loadtest-namespace"},
+			}
+
+			require.NoError(t, c.checkPostCompletion(context.Background(), loadTest))
+			assert.Equal(t, tt.expectPhase, loadTest.Status.Phase)
+			assert.Len(t, recorder.Events, tt.expectEvents)
+
+			condition := meta.FindStatusCondition(loadTest.Status.Conditions, loadTestV1.LoadTestConditionPostCompletion)
+			if tt.expectCondition == "" {
+				assert.Nil(t, condition)
+			} else {
+				require.NotNil(t, condition)
+				assert.Equal(t, tt.expectCondition, condition.Status)
+			}
+
+			job, err := kubeClient.BatchV1().Jobs("loadtest-namespace").Get(context.Background(), postCompletionJobName, metaV1.GetOptions{})
+			if !tt.expectCreated {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			container := job.Spec.Template.Spec.Containers[0]
+			assert.Equal(t, "summarizer:latest", container.Image)
+			assert.Equal(t, []string{"summarize"}, container.Command)
+			assert.Contains(t, container.Env, coreV1.EnvVar{Name: "REPORT_URL", Value: "http://kangal-proxy/load-test/loadtest-name/report"})
+			// the job runs on the nodes of the loadtest pods
+			assert.Equal(t, map[string]string{"pool": "loadtest"}, job.Spec.Template.Spec.NodeSelector)
+			assert.Equal(t, map[string]string{"team": "platform"}, job.Spec.Template.Annotations)
+		})
+	}
+}
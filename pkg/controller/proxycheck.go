@@ -0,0 +1,54 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// checkProxy requests the status endpoint of the Kangal proxy at proxyURL, it fails when the proxy
+// does not respond successfully within the timeout of ctx
+func checkProxy(ctx context.Context, client *http.Client, proxyURL string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(proxyURL, "/")+"/status", nil)
+	if err != nil {
+		return fmt.Errorf("invalid kangal proxy URL %q: %w", proxyURL, err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("kangal proxy %s is unreachable: %w", proxyURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("kangal proxy %s responded with status %d", proxyURL, resp.StatusCode)
+	}
+	return nil
+}
+
+// verifyProxy checks that the Kangal proxy loadtests upload their reports to is reachable when enabled, so
+// a misconfigured KangalProxyURL is noticed at startup rather than by missing reports. An unreachable proxy
+// is logged, or returned as error when ProxyCheckRequired is set.
+func verifyProxy(cfg Config, client *http.Client, logger *zap.Logger) error {
+	if !cfg.ProxyCheck || cfg.KangalProxyURL == "" {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.ProxyCheckTimeout)
+	defer cancel()
+
+	err := checkProxy(ctx, client, cfg.KangalProxyURL)
+	if err == nil {
+		logger.Info("Kangal proxy is reachable", zap.String("url", cfg.KangalProxyURL))
+		return nil
+	}
+	if cfg.ProxyCheckRequired {
+		return err
+	}
+
+	logger.Warn("Kangal proxy is unreachable, loadtest reports will not be uploaded", zap.Error(err))
+	return nil
+}
@@ -0,0 +1,74 @@
+package controller
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestVerifyProxy(t *testing.T) {
+	healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/status", r.URL.Path)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer healthy.Close()
+
+	unhealthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer unhealthy.Close()
+
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer slow.Close()
+
+	for _, tt := range []struct {
+		name        string
+		cfg         Config
+		expectError bool
+	}{
+		{
+			name: "disabled",
+			cfg:  Config{KangalProxyURL: unhealthy.URL, ProxyCheckRequired: true},
+		},
+		{
+			name: "reachable",
+			cfg:  Config{KangalProxyURL: healthy.URL + "/", ProxyCheck: true, ProxyCheckRequired: true},
+		},
+		{
+			name: "unreachable is logged",
+			cfg:  Config{KangalProxyURL: unhealthy.URL, ProxyCheck: true},
+		},
+		{
+			name:        "unreachable is required",
+			cfg:         Config{KangalProxyURL: unhealthy.URL, ProxyCheck: true, ProxyCheckRequired: true},
+			expectError: true,
+		},
+		{
+			name:        "timeout",
+			cfg:         Config{KangalProxyURL: slow.URL, ProxyCheck: true, ProxyCheckRequired: true, ProxyCheckTimeout: 10 * time.Millisecond},
+			expectError: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.cfg.ProxyCheckTimeout == 0 {
+				tt.cfg.ProxyCheckTimeout = time.Second
+			}
+
+			err := verifyProxy(tt.cfg, http.DefaultClient, zaptest.NewLogger(t))
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
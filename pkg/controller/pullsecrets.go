@@ -0,0 +1,80 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+	coreV1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/hellofresh/kangal/pkg/backends"
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+// checkOrCreateImagePullSecrets copies the image pull secrets of the loadtest pods from ImagePullSecretsNamespace
+// to the loadtest namespace, pods can only use the secrets of their own namespace. Only the secrets configured in
+// ImagePullSecrets are copied, and only when they are docker registry secrets, so that loadtests can't read
+// arbitrary secrets of the controller namespace. Other secrets of the loadtest must exist in its namespace. Without
+// ImagePullSecretsNamespace all the secrets are expected to exist in the loadtest namespace already
+func (c *Controller) checkOrCreateImagePullSecrets(ctx context.Context, loadtest *loadTestV1.LoadTest) error {
+	source := c.cfg.ImagePullSecretsNamespace
+	if source == "" || source == loadtest.Status.Namespace {
+		return nil
+	}
+
+	for _, name := range backends.ImagePullSecretNames(c.cfg.ImagePullSecrets, loadtest.Spec.ImagePullSecrets) {
+		_, err := c.kubeClientSet.CoreV1().Secrets(loadtest.Status.Namespace).Get(ctx, name, metaV1.GetOptions{})
+		if err == nil {
+			continue
+		}
+		if !errors.IsNotFound(err) {
+			return err
+		}
+		if !isConfiguredImagePullSecret(c.cfg.ImagePullSecrets, name) {
+			return fmt.Errorf("image pull secret %s must exist in namespace %s", name, loadtest.Status.Namespace)
+		}
+
+		secret, err := c.kubeClientSet.CoreV1().Secrets(source).Get(ctx, name, metaV1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("could not read image pull secret %s/%s: %w", source, name, err)
+		}
+		if secret.Type != coreV1.SecretTypeDockerConfigJson && secret.Type != coreV1.SecretTypeDockercfg {
+			return fmt.Errorf("image pull secret %s/%s has type %q, expected %q or %q", source, name, secret.Type,
+				coreV1.SecretTypeDockerConfigJson, coreV1.SecretTypeDockercfg)
+		}
+
+		copied := &coreV1.Secret{
+			ObjectMeta: metaV1.ObjectMeta{
+				Name: name,
+				Labels: map[string]string{
+					"app": "kangal",
+				},
+				OwnerReferences: []metaV1.OwnerReference{
+					*metaV1.NewControllerRef(loadtest, loadTestV1.SchemeGroupVersion.WithKind("LoadTest")),
+				},
+			},
+			Type: secret.Type,
+			Data: secret.Data,
+		}
+
+		_, err = c.kubeClientSet.CoreV1().Secrets(loadtest.Status.Namespace).Create(ctx, copied, metaV1.CreateOptions{})
+		if err != nil && !errors.IsAlreadyExists(err) {
+			c.logger.Error("Error on copying image pull secret", zap.String("loadtest", loadtest.GetName()),
+				zap.String("secret", name), zap.Error(err))
+			return err
+		}
+	}
+
+	return nil
+}
+
+func isConfiguredImagePullSecret(configured []string, name string) bool {
+	for _, configuredName := range configured {
+		if configuredName == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,114 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+	coreV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+func TestCheckOrCreateImagePullSecrets(t *testing.T) {
+	opaqueSecret := &coreV1.Secret{
+		ObjectMeta: metaV1.ObjectMeta{Name: "credentials", Namespace: "kangal"},
+		Type:       coreV1.SecretTypeOpaque,
+		Data:       map[string][]byte{"password": []byte("secret")},
+	}
+	registrySecret := func(name string) *coreV1.Secret {
+		return &coreV1.Secret{
+			ObjectMeta: metaV1.ObjectMeta{Name: name, Namespace: "kangal"},
+			Type:       coreV1.SecretTypeDockerConfigJson,
+			Data:       map[string][]byte{coreV1.DockerConfigJsonKey: []byte(`{"auths":{}}`)},
+		}
+	}
+
+	for _, tt := range []struct {
+		name            string
+		sourceNamespace string
+		configured      []string
+		loadTest        []string
+		expected        []string
+		expectError     bool
+	}{
+		{
+			name:       "no source namespace",
+			configured: []string{"registry"},
+		},
+		{
+			name:            "configured secrets",
+			sourceNamespace: "kangal",
+			configured:      []string{"registry"},
+			expected:        []string{"registry"},
+		},
+		{
+			name:            "loadtest secrets",
+			sourceNamespace: "kangal",
+			configured:      []string{"registry", "team-registry"},
+			loadTest:        []string{"team-registry"},
+			expected:        []string{"team-registry"},
+		},
+		{
+			name:            "loadtest secrets not configured",
+			sourceNamespace: "kangal",
+			configured:      []string{"registry"},
+			loadTest:        []string{"team-registry"},
+			expectError:     true,
+		},
+		{
+			name:            "not a registry secret",
+			sourceNamespace: "kangal",
+			configured:      []string{"credentials"},
+			expectError:     true,
+		},
+		{
+			name:            "missing secret",
+			sourceNamespace: "kangal",
+			configured:      []string{"unknown"},
+			expectError:     true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			kubeClient := k8sfake.NewSimpleClientset(registrySecret("registry"), registrySecret("team-registry"),
+				opaqueSecret)
+			c := &Controller{
+				kubeClientSet: kubeClient,
+				logger:        zaptest.NewLogger(t),
+				cfg: Config{
+					ImagePullSecrets:          tt.configured,
+					ImagePullSecretsNamespace: tt.sourceNamespace,
+				},
+			}
+			lt := &loadTestV1.LoadTest{
+				ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-name"},
+				Spec:       loadTestV1.LoadTestSpec{ImagePullSecrets: tt.loadTest},
+				Status:     loadTestV1.LoadTestStatus{Namespace: "loadtest-name"},
+			}
+
+			err := c.checkOrCreateImagePullSecrets(context.Background(), lt)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			// a second sync finds the secrets already copied
+			require.NoError(t, c.checkOrCreateImagePullSecrets(context.Background(), lt))
+
+			secrets, err := kubeClient.CoreV1().Secrets("loadtest-name").List(context.Background(), metaV1.ListOptions{})
+			require.NoError(t, err)
+
+			var names []string
+			for _, secret := range secrets.Items {
+				names = append(names, secret.Name)
+				assert.Equal(t, coreV1.SecretTypeDockerConfigJson, secret.Type)
+				assert.Equal(t, "loadtest-name", metaV1.GetControllerOf(&secret).Name)
+			}
+			assert.Equal(t, tt.expected, names)
+		})
+	}
+}
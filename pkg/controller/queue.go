@@ -0,0 +1,278 @@
+package controller
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/client-go/util/workqueue"
+)
+
+// priority is the scheduling class of an item in the priorityQueue
+type priority int
+
+const (
+	priorityNormal priority = iota
+	priorityHigh
+)
+
+const (
+	// priorityAnnotation can be set on a LoadTest to change its scheduling class,
+	// only priorityHighValue is recognised, any other value means normal priority
+	priorityAnnotation = "kangal.hellofresh.com/priority"
+	priorityHighValue  = "high"
+)
+
+// priorityQueue is a rate limited work queue made of two lanes, high and normal priority.
+// Items are routed to a lane by priorityFunc when they are added. Get serves up to
+// highWeight items from the high lane for every item of the normal lane, so normal
+// priority items can be delayed by urgent ones but never starve.
+type priorityQueue struct {
+	lanes        [2]workqueue.Interface
+	outs         [2]chan interface{}
+	delayed      workqueue.DelayingInterface
+	rateLimiter  workqueue.RateLimiter
+	priorityFunc func(item interface{}) priority
+	highWeight   int
+
+	mu sync.Mutex
+	// served is the number of consecutive items served from the high lane
+	served int
+	// items keeps the lane of the items from the time they are added until they are done in their lane and not
+	// added again, all adds of an item go to the same lane meanwhile so it is never processed by two workers at once
+	items map[interface{}]*queueItem
+}
+
+// queueItem is the state of an item in the priorityQueue. An item is only added to its lane when it is neither
+// queued nor processing, so the lane never holds it twice and the state is only changed under the queue lock
+type queueItem struct {
+	lane priority
+	// queued is true from the time the item is added until Get serves it, including while it is moved out of its
+	// lane, and when it is added again while being processed
+	queued bool
+	// processing is true from the time Get serves the item until it is done
+	processing bool
+}
+
+// newPriorityQueue creates a priorityQueue and starts moving items out of its lanes
+func newPriorityQueue(name string, highWeight int, priorityFunc func(item interface{}) priority) *priorityQueue {
+	if highWeight < 1 {
+		highWeight = 1
+	}
+
+	q := &priorityQueue{
+		delayed: workqueue.NewNamedDelayingQueue(name + "-delayed"),
+		// both lanes share the same rate limiter, so back-off is kept when an item changes lanes
+		rateLimiter:  workqueue.DefaultControllerRateLimiter(),
+		priorityFunc: priorityFunc,
+		highWeight:   highWeight,
+		items:        map[interface{}]*queueItem{},
+	}
+	q.lanes[priorityNormal] = workqueue.NewNamed(name)
+	q.lanes[priorityHigh] = workqueue.NewNamed(name + "-high")
+
+	for _, p := range []priority{priorityNormal, priorityHigh} {
+		q.outs[p] = make(chan interface{}, 1)
+		go q.pump(p, q.outs[p])
+	}
+	go q.release()
+
+	return q
+}
+
+// pump moves items from the given lane to its out channel until the lane is shut down
+func (q *priorityQueue) pump(p priority, out chan<- interface{}) {
+	defer close(out)
+	for {
+		item, shutdown := q.lanes[p].Get()
+		if shutdown {
+			return
+		}
+		out <- item
+	}
+}
+
+// release adds the items whose delay elapsed until the queue is shut down
+func (q *priorityQueue) release() {
+	for {
+		item, shutdown := q.delayed.Get()
+		if shutdown {
+			return
+		}
+		q.Add(item)
+		q.delayed.Done(item)
+	}
+}
+
+// Add adds an item to the lane given by its priority, items already in the queue stay in their lane
+func (q *priorityQueue) Add(item interface{}) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.lanes[priorityNormal].ShuttingDown() {
+		return
+	}
+
+	i, ok := q.items[item]
+	if !ok {
+		i = &queueItem{lane: q.priorityFunc(item)}
+		q.items[item] = i
+	}
+	if i.queued {
+		return
+	}
+	i.queued = true
+	// an item being processed is added back to its lane once it is done
+	if !i.processing {
+		q.lanes[i.lane].Add(item)
+	}
+}
+
+// AddAfter adds an item to the lane given by its priority after the indicated duration has passed
+func (q *priorityQueue) AddAfter(item interface{}, duration time.Duration) {
+	if duration <= 0 {
+		q.Add(item)
+		return
+	}
+	// the lane is chosen once the item is added, so it follows the item if it is added meanwhile
+	q.delayed.AddAfter(item, duration)
+}
+
+// AddRateLimited adds an item to the lane given by its priority after the rate limiter says it's ok
+func (q *priorityQueue) AddRateLimited(item interface{}) {
+	q.AddAfter(item, q.rateLimiter.When(item))
+}
+
+// Forget indicates that an item is finished being retried
+func (q *priorityQueue) Forget(item interface{}) {
+	q.rateLimiter.Forget(item)
+}
+
+// NumRequeues returns back how many times the item was requeued
+func (q *priorityQueue) NumRequeues(item interface{}) int {
+	return q.rateLimiter.NumRequeues(item)
+}
+
+// Len returns the number of items waiting in both lanes, including the items moved out of their lane
+// and not served by Get yet
+func (q *priorityQueue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	n := 0
+	for _, i := range q.items {
+		if i.queued {
+			n++
+		}
+	}
+	return n
+}
+
+// Get blocks until it can return an item to be processed, following the weighted policy
+// described in priorityQueue. It returns shutdown true once both lanes are shut down and drained.
+func (q *priorityQueue) Get() (interface{}, bool) {
+	for {
+		q.mu.Lock()
+		first, second := priorityHigh, priorityNormal
+		if q.served >= q.highWeight {
+			first, second = priorityNormal, priorityHigh
+		}
+		firstCh, secondCh := q.outs[first], q.outs[second]
+		q.mu.Unlock()
+
+		if firstCh == nil && secondCh == nil {
+			return nil, true
+		}
+
+		lane := first
+		item, ok, received := tryReceive(firstCh)
+		if !received {
+			lane = second
+			item, ok, received = tryReceive(secondCh)
+		}
+		if !received {
+			select {
+			case item, ok = <-firstCh:
+				lane = first
+			case item, ok = <-secondCh:
+				lane = second
+			}
+		}
+
+		q.mu.Lock()
+		if !ok {
+			// lane was shut down and has no more items
+			q.outs[lane] = nil
+			q.mu.Unlock()
+			continue
+		}
+		if lane == priorityHigh {
+			q.served++
+		} else {
+			q.served = 0
+		}
+		if i, ok := q.items[item]; ok {
+			i.queued = false
+			i.processing = true
+		}
+		q.mu.Unlock()
+
+		return item, false
+	}
+}
+
+// tryReceive reads from a channel without blocking, received is false if nothing was read
+func tryReceive(ch <-chan interface{}) (item interface{}, ok bool, received bool) {
+	select {
+	case item, ok = <-ch:
+		return item, ok, true
+	default:
+		return nil, false, false
+	}
+}
+
+// Done marks item as done processing in the lane it was served from, and adds it back to that lane
+// if it was added while being processed
+func (q *priorityQueue) Done(item interface{}) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	i, ok := q.items[item]
+	if !ok || !i.processing {
+		return
+	}
+	i.processing = false
+	q.lanes[i.lane].Done(item)
+
+	if !i.queued {
+		delete(q.items, item)
+		return
+	}
+	q.lanes[i.lane].Add(item)
+}
+
+// ShutDown shuts down both lanes
+func (q *priorityQueue) ShutDown() {
+	q.delayed.ShutDown()
+	q.lanes[priorityHigh].ShutDown()
+	q.lanes[priorityNormal].ShutDown()
+}
+
+// ShutDownWithDrain shuts down both lanes and waits for the items being processed to be done
+func (q *priorityQueue) ShutDownWithDrain() {
+	q.delayed.ShutDown()
+
+	var wg sync.WaitGroup
+	for _, lane := range q.lanes {
+		wg.Add(1)
+		go func(lane workqueue.Interface) {
+			defer wg.Done()
+			lane.ShutDownWithDrain()
+		}(lane)
+	}
+	wg.Wait()
+}
+
+// ShuttingDown returns true if the queue is shutting down
+func (q *priorityQueue) ShuttingDown() bool {
+	return q.lanes[priorityNormal].ShuttingDown()
+}
@@ -0,0 +1,202 @@
+package controller
+
+import (
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPriorityQueue(t *testing.T) {
+	priorityOf := func(item interface{}) priority {
+		if strings.HasPrefix(item.(string), "high") {
+			return priorityHigh
+		}
+		return priorityNormal
+	}
+
+	q := newPriorityQueue("test", 2, priorityOf)
+	defer q.ShutDown()
+
+	items := []string{"normal-1", "normal-2", "high-1", "high-2", "high-3", "high-4", "high-5"}
+	remaining := map[priority]int{}
+	for _, item := range items {
+		q.Add(item)
+		remaining[priorityOf(item)]++
+	}
+
+	var got []string
+	for range items {
+		// wait for every lane with pending items to have one ready to be served
+		require.Eventually(t, func() bool {
+			for p, n := range remaining {
+				if n > 0 && len(q.outs[p]) == 0 {
+					return false
+				}
+			}
+			return true
+		}, time.Second, time.Millisecond)
+
+		item, shutdown := q.Get()
+		require.False(t, shutdown)
+		q.Done(item)
+
+		remaining[priorityOf(item)]--
+		got = append(got, item.(string))
+	}
+
+	// normal priority items are interleaved after every two high priority ones
+	assert.Equal(t, []string{"high-1", "high-2", "normal-1", "high-3", "high-4", "normal-2", "high-5"}, got)
+}
+
+func TestPriorityQueueShutDown(t *testing.T) {
+	q := newPriorityQueue("test", 1, func(interface{}) priority {
+		return priorityNormal
+	})
+
+	q.Add("item")
+	q.ShutDown()
+
+	item, shutdown := q.Get()
+	require.False(t, shutdown)
+	assert.Equal(t, "item", item)
+	q.Done(item)
+
+	_, shutdown = q.Get()
+	assert.True(t, shutdown)
+}
+
+func TestPriorityQueuePriorityChange(t *testing.T) {
+	var high atomic.Bool
+	q := newPriorityQueue("test", 1, func(interface{}) priority {
+		if high.Load() {
+			return priorityHigh
+		}
+		return priorityNormal
+	})
+	defer q.ShutDown()
+
+	q.Add("item")
+	item, shutdown := q.Get()
+	require.False(t, shutdown)
+
+	// the item is added again with another priority while it is processed
+	high.Store(true)
+	q.Add("item")
+	q.Add("item")
+	assert.Equal(t, 1, q.Len())
+
+	served := make(chan interface{}, 1)
+	go func() {
+		item, _ := q.Get()
+		served <- item
+	}()
+
+	select {
+	case <-served:
+		t.Fatal("item was served again while it is processed")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	q.Done(item)
+	select {
+	case item := <-served:
+		assert.Equal(t, "item", item)
+	case <-time.After(time.Second):
+		t.Fatal("item was not served again once it was done")
+	}
+	assert.Zero(t, q.Len())
+}
+
+func TestPriorityQueueConcurrentPriorityChange(t *testing.T) {
+	var high atomic.Bool
+	q := newPriorityQueue("test", 2, func(interface{}) priority {
+		if high.Load() {
+			return priorityHigh
+		}
+		return priorityNormal
+	})
+
+	var processing, overlaps atomic.Int32
+	var workers sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for {
+				item, shutdown := q.Get()
+				if shutdown {
+					return
+				}
+				if !processing.CompareAndSwap(0, 1) {
+					overlaps.Add(1)
+				}
+				time.Sleep(time.Millisecond)
+				processing.Store(0)
+				q.Done(item)
+			}
+		}()
+	}
+
+	for i := 0; i < 200; i++ {
+		high.Store(i%2 == 0)
+		if i%3 == 0 {
+			q.AddAfter("item", time.Duration(i%5)*time.Millisecond)
+		} else {
+			q.Add("item")
+		}
+		time.Sleep(100 * time.Microsecond)
+	}
+
+	q.ShutDownWithDrain()
+	workers.Wait()
+
+	assert.Zero(t, overlaps.Load(), "item was processed by two workers at once")
+}
+
+func TestPriorityQueueAddWhileServed(t *testing.T) {
+	q := newPriorityQueue("test", 1, func(interface{}) priority {
+		return priorityNormal
+	})
+	defer q.ShutDown()
+
+	var served atomic.Int32
+	go func() {
+		for {
+			item, shutdown := q.Get()
+			if shutdown {
+				return
+			}
+			served.Add(1)
+			q.Done(item)
+		}
+	}()
+
+	// adds race with the item being moved out of its lane and served
+	var adders sync.WaitGroup
+	for a := 0; a < 4; a++ {
+		adders.Add(1)
+		go func() {
+			defer adders.Done()
+			for i := 0; i < 10000; i++ {
+				q.Add("item")
+			}
+		}()
+	}
+	adders.Wait()
+
+	require.Eventually(t, func() bool {
+		return q.Len() == 0
+	}, time.Second, time.Millisecond)
+
+	// the item is not stuck in its lane and is served again when added
+	before := served.Load()
+	q.Add("item")
+	require.Eventually(t, func() bool {
+		return served.Load() > before
+	}, time.Second, time.Millisecond)
+}
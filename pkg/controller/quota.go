@@ -0,0 +1,167 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	batchV1 "k8s.io/api/batch/v1"
+	coreV1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+// quotaExceededRegexp matches the error of the quota admission plugin rejecting a pod, e.g.
+// "exceeded quota: compute, requested: limits.cpu=2, used: limits.cpu=3, limited: limits.cpu=4"
+var quotaExceededRegexp = regexp.MustCompile(`exceeded quota: (\S+), requested: (\S+), used: (\S+), limited: (\S+)`)
+
+// quotaExceeded returns a message stating the requested and available resources when the given error
+// message is a pod rejected by a ResourceQuota, ok is false otherwise
+func quotaExceeded(message string) (string, bool) {
+	match := quotaExceededRegexp.FindStringSubmatch(message)
+	if match == nil {
+		return "", false
+	}
+
+	used := parseQuotaResources(match[3])
+	limited := parseQuotaResources(match[4])
+
+	var available []string
+	for _, pair := range strings.Split(match[2], ",") {
+		name, _, _ := strings.Cut(pair, "=")
+		limit, ok := limited[name]
+		if !ok {
+			continue
+		}
+		if usage, ok := used[name]; ok {
+			limit.Sub(usage)
+		}
+		available = append(available, fmt.Sprintf("%s=%s", name, limit.String()))
+	}
+
+	return fmt.Sprintf("namespace quota %s exceeded: requested %s, available %s", match[1], match[2], strings.Join(available, ",")), true
+}
+
+// parseQuotaResources parses a comma-separated list of resource=quantity pairs, invalid pairs are skipped
+func parseQuotaResources(s string) map[string]resource.Quantity {
+	resources := map[string]resource.Quantity{}
+	for _, pair := range strings.Split(s, ",") {
+		name, value, found := strings.Cut(pair, "=")
+		if !found {
+			continue
+		}
+		if quantity, err := resource.ParseQuantity(value); err == nil {
+			resources[name] = quantity
+		}
+	}
+	return resources
+}
+
+// checkQuota moves loadtests that are not running yet to errored phase when their pods can't be created
+// because they exceed a ResourceQuota of the loadtest namespace, instead of leaving them stuck in starting phase.
+// Only Jobs still missing pods QuotaCheckGracePeriod after they were created are checked, so a pod rejected once,
+// e.g. while the pods of a previous loadtest were terminating, doesn't error the loadtest and the Events are not
+// listed on every sync. Such a Job is rejected by the quota when its last FailedCreate Event is a quota error.
+func (c *Controller) checkQuota(ctx context.Context, loadTest *loadTestV1.LoadTest) error {
+	if !c.cfg.QuotaCheck || loadTest.Status.Namespace == "" {
+		return nil
+	}
+	if loadTest.Status.Phase != loadTestV1.LoadTestCreating && loadTest.Status.Phase != loadTestV1.LoadTestStarting {
+		return nil
+	}
+
+	jobs, err := c.loadTestJobs(loadTest)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, job := range jobs {
+		if !missingPods(job) || now.Sub(job.GetCreationTimestamp().Time) < c.cfg.QuotaCheckGracePeriod {
+			continue
+		}
+
+		events, err := c.kubeClientSet.CoreV1().Events(job.GetNamespace()).List(ctx, metaV1.ListOptions{
+			FieldSelector: fields.AndSelectors(
+				fields.OneTermEqualSelector("involvedObject.uid", string(job.GetUID())),
+				fields.OneTermEqualSelector("reason", "FailedCreate"),
+			).String(),
+		})
+		if err != nil {
+			return err
+		}
+
+		var last *coreV1.Event
+		for i, event := range events.Items {
+			if event.Reason != "FailedCreate" || event.InvolvedObject.UID != job.GetUID() {
+				continue
+			}
+			if last == nil || eventTime(event).After(eventTime(*last)) {
+				last = &events.Items[i]
+			}
+		}
+		if last == nil {
+			continue
+		}
+		if message, ok := quotaExceeded(last.Message); ok {
+			c.failQuota(loadTest, message)
+			return nil
+		}
+	}
+	return nil
+}
+
+// missingPods tells if the Job has fewer pods than it runs in parallel, suspended Jobs have no pods on purpose
+func missingPods(job *batchV1.Job) bool {
+	if job.Spec.Suspend != nil && *job.Spec.Suspend {
+		return false
+	}
+	parallelism := int32(1)
+	if job.Spec.Parallelism != nil {
+		parallelism = *job.Spec.Parallelism
+	}
+	return job.Status.Active+job.Status.Succeeded+job.Status.Failed < parallelism
+}
+
+// eventTime returns when the Event last occurred
+func eventTime(event coreV1.Event) time.Time {
+	if !event.LastTimestamp.IsZero() {
+		return event.LastTimestamp.Time
+	}
+	if !event.EventTime.IsZero() {
+		return event.EventTime.Time
+	}
+	return event.GetCreationTimestamp().Time
+}
+
+// checkQuotaSyncError moves the loadtest to errored phase when the backend failed to create its resources
+// because they exceed a ResourceQuota, it returns false if the loadtest was moved to errored phase
+func (c *Controller) checkQuotaSyncError(loadTest *loadTestV1.LoadTest, err error) bool {
+	if !c.cfg.QuotaCheck {
+		return true
+	}
+	message, ok := quotaExceeded(err.Error())
+	if !ok {
+		return true
+	}
+	c.failQuota(loadTest, message)
+	return false
+}
+
+// failQuota moves the loadtest to errored phase because it exceeds the quota of its namespace
+func (c *Controller) failQuota(loadTest *loadTestV1.LoadTest, message string) {
+	if loadTest.Status.Phase != loadTestV1.LoadTestErrored {
+		c.logger.Info("Loadtest exceeds namespace quota, marking loadtest as errored",
+			zap.String("loadtest", loadTest.GetName()),
+			zap.String("reason", message),
+		)
+		c.recorder.Event(loadTest, coreV1.EventTypeWarning, reasonQuotaExceeded, message)
+	}
+	setErrored(loadTest, reasonQuotaExceeded, message)
+}
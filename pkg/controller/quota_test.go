@@ -0,0 +1,169 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+	batchV1 "k8s.io/api/batch/v1"
+	coreV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	batchListersV1 "k8s.io/client-go/listers/batch/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+const quotaErrorMessage = `Error creating: pods "loadtest-job-abcde" is forbidden: exceeded quota: compute, ` +
+	`requested: limits.cpu=2,limits.memory=1Gi, used: limits.cpu=3,limits.memory=2Gi, limited: limits.cpu=4,limits.memory=4Gi`
+
+func TestQuotaExceeded(t *testing.T) {
+	message, ok := quotaExceeded(quotaErrorMessage)
+	require.True(t, ok)
+	assert.Equal(t, "namespace quota compute exceeded: requested limits.cpu=2,limits.memory=1Gi, available limits.cpu=1,limits.memory=2Gi", message)
+
+	_, ok = quotaExceeded(`pods "loadtest-job-abcde" is forbidden: error looking up service account`)
+	assert.False(t, ok)
+}
+
+func TestCheckQuota(t *testing.T) {
+	created := metaV1.NewTime(time.Now().Add(-2 * time.Minute))
+	newJob := func(status batchV1.JobStatus) *batchV1.Job {
+		return &batchV1.Job{
+			ObjectMeta: metaV1.ObjectMeta{
+				Name:              "loadtest-job",
+				Namespace:         "loadtest-namespace",
+				UID:               "job-uid",
+				CreationTimestamp: created,
+			},
+			Status: status,
+		}
+	}
+	event := func(name, message string, last time.Time) runtime.Object {
+		return &coreV1.Event{
+			ObjectMeta:     metaV1.ObjectMeta{Name: name, Namespace: "loadtest-namespace"},
+			InvolvedObject: coreV1.ObjectReference{Kind: "Job", Name: "loadtest-job", UID: "job-uid"},
+			Reason:         "FailedCreate",
+			Message:        message,
+			LastTimestamp:  metaV1.NewTime(last),
+		}
+	}
+	now := time.Now()
+
+	for _, tt := range []struct {
+		name        string
+		phase       loadTestV1.LoadTestPhase
+		job         *batchV1.Job
+		gracePeriod time.Duration
+		events      []runtime.Object
+		expectPhase loadTestV1.LoadTestPhase
+	}{
+		{
+			name:        "no events",
+			phase:       loadTestV1.LoadTestStarting,
+			job:         newJob(batchV1.JobStatus{}),
+			expectPhase: loadTestV1.LoadTestStarting,
+		},
+		{
+			name:        "other failure",
+			phase:       loadTestV1.LoadTestStarting,
+			job:         newJob(batchV1.JobStatus{}),
+			events:      []runtime.Object{event("service-account", "Error creating: pods is forbidden: error looking up service account", now)},
+			expectPhase: loadTestV1.LoadTestStarting,
+		},
+		{
+			name:        "quota exceeded",
+			phase:       loadTestV1.LoadTestStarting,
+			job:         newJob(batchV1.JobStatus{}),
+			events:      []runtime.Object{event("quota", quotaErrorMessage, now)},
+			expectPhase: loadTestV1.LoadTestErrored,
+		},
+		{
+			name:        "quota exceeded within grace period",
+			phase:       loadTestV1.LoadTestStarting,
+			job:         newJob(batchV1.JobStatus{}),
+			gracePeriod: 5 * time.Minute,
+			events:      []runtime.Object{event("quota", quotaErrorMessage, now)},
+			expectPhase: loadTestV1.LoadTestStarting,
+		},
+		{
+			name:        "pods created on retry",
+			phase:       loadTestV1.LoadTestStarting,
+			job:         newJob(batchV1.JobStatus{Active: 1}),
+			events:      []runtime.Object{event("quota", quotaErrorMessage, now)},
+			expectPhase: loadTestV1.LoadTestStarting,
+		},
+		{
+			name:  "quota rejection followed by another failure",
+			phase: loadTestV1.LoadTestStarting,
+			job:   newJob(batchV1.JobStatus{}),
+			events: []runtime.Object{
+				event("quota", quotaErrorMessage, now.Add(-time.Minute)),
+				event("service-account", "Error creating: pods is forbidden: error looking up service account", now),
+			},
+			expectPhase: loadTestV1.LoadTestStarting,
+		},
+		{
+			name:        "running loadtest",
+			phase:       loadTestV1.LoadTestRunning,
+			job:         newJob(batchV1.JobStatus{}),
+			events:      []runtime.Object{event("quota", quotaErrorMessage, now)},
+			expectPhase: loadTestV1.LoadTestRunning,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+			require.NoError(t, indexer.Add(tt.job))
+
+			c := &Controller{
+				cfg:           Config{QuotaCheck: true, QuotaCheckGracePeriod: tt.gracePeriod},
+				kubeClientSet: k8sfake.NewSimpleClientset(tt.events...),
+				jobsLister:    batchListersV1.NewJobLister(indexer),
+				recorder:      record.NewFakeRecorder(10),
+				logger:        zaptest.NewLogger(t),
+			}
+			loadTest := &loadTestV1.LoadTest{
+				ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-name"},
+				Status: loadTestV1.LoadTestStatus{
+					Phase:     tt.phase,
+					Namespace: "loadtest-namespace",
+				},
+			}
+
+			require.NoError(t, c.checkQuota(context.Background(), loadTest))
+			assert.Equal(t, tt.expectPhase, loadTest.Status.Phase)
+		})
+	}
+}
+
+func TestMissingPods(t *testing.T) {
+	suspend := true
+	parallelism := int32(2)
+
+	assert.True(t, missingPods(&batchV1.Job{}))
+	assert.False(t, missingPods(&batchV1.Job{Status: batchV1.JobStatus{Active: 1}}))
+	assert.False(t, missingPods(&batchV1.Job{Spec: batchV1.JobSpec{Suspend: &suspend}}))
+	assert.True(t, missingPods(&batchV1.Job{Spec: batchV1.JobSpec{Parallelism: &parallelism}, Status: batchV1.JobStatus{Active: 1}}))
+}
+
+func TestCheckQuotaSyncError(t *testing.T) {
+	c := &Controller{
+		cfg:      Config{QuotaCheck: true},
+		recorder: record.NewFakeRecorder(10),
+		logger:   zaptest.NewLogger(t),
+	}
+	loadTest := &loadTestV1.LoadTest{}
+
+	assert.True(t, c.checkQuotaSyncError(loadTest, errors.New("connection refused")))
+	assert.Empty(t, loadTest.Status.Phase)
+
+	assert.False(t, c.checkQuotaSyncError(loadTest, errors.New(quotaErrorMessage)))
+	assert.Equal(t, loadTestV1.LoadTestErrored, loadTest.Status.Phase)
+}
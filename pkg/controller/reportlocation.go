@@ -0,0 +1,63 @@
+package controller
+
+import (
+	"net/url"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+// sanitizeStorageURL returns the storage URL without the credentials, query and fragment it may carry,
+// e.g. the access key of a user info or the signature of a presigned URL
+func sanitizeStorageURL(storage string) (*url.URL, error) {
+	u, err := url.Parse(storage)
+	if err != nil {
+		return nil, err
+	}
+	u.User = nil
+	u.RawQuery = ""
+	u.ForceQuery = false
+	u.Fragment = ""
+	u.RawFragment = ""
+	return u, nil
+}
+
+// reportLocation returns the location of the report of the named loadtest under the storage URL, reports
+// are stored with the loadtest name as object key
+func reportLocation(storage, name string) (string, error) {
+	u, err := sanitizeStorageURL(storage)
+	if err != nil {
+		return "", err
+	}
+	return u.JoinPath(name).String(), nil
+}
+
+// recordReportLocation records the storage location of the report in the status of loadtests uploading one
+func (c *Controller) recordReportLocation(loadTest *loadTestV1.LoadTest, reportURL string) {
+	if c.cfg.ReportStorageLocation == "" || reportURL == "" || loadTest.Status.ReportLocation != "" {
+		return
+	}
+
+	// the storage location is validated on startup
+	location, err := reportLocation(c.cfg.ReportStorageLocation, loadTest.GetName())
+	if err != nil {
+		return
+	}
+	loadTest.Status.ReportLocation = location
+}
+
+// recordRetentionClass records the retention class the report is tagged with in the status of loadtests uploading one,
+// loadtests without a valid retention class get the configured default one, standard if none is configured
+func (c *Controller) recordRetentionClass(loadTest *loadTestV1.LoadTest, reportURL string) {
+	if reportURL == "" || loadTest.Status.RetentionClass != "" {
+		return
+	}
+
+	class := loadTest.Spec.RetentionClass
+	if !class.IsValid() {
+		class = loadTestV1.RetentionClass(c.cfg.DefaultRetentionClass)
+	}
+	if class == "" {
+		class = loadTestV1.RetentionStandard
+	}
+	loadTest.Status.RetentionClass = class
+}
@@ -0,0 +1,118 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"go.uber.org/zap"
+	coreV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+)
+
+// checkScheduling cross-references the node selectors and tolerations of loadtest pods with the labels and
+// taints of the given nodes, it returns a warning for each combination that looks misconfigured
+func checkScheduling(nodes []coreV1.Node, nodeSelector map[string]string, tolerations []coreV1.Toleration) []string {
+	var warnings []string
+
+	selector := labels.SelectorFromSet(nodeSelector)
+	var selected []coreV1.Node
+	for _, node := range nodes {
+		if selector.Matches(labels.Set(node.GetLabels())) {
+			selected = append(selected, node)
+		}
+	}
+
+	if len(selected) == 0 {
+		warnings = append(warnings, fmt.Sprintf("no node matches the node selector %q", selector.String()))
+	} else {
+		var untolerated []string
+		for _, node := range selected {
+			taints := untoleratedTaints(node, tolerations)
+			if len(taints) == 0 {
+				untolerated = nil
+				break
+			}
+			untolerated = append(untolerated, fmt.Sprintf("node %s has taints %s", node.GetName(), strings.Join(taints, ", ")))
+		}
+		if len(untolerated) > 0 {
+			warnings = append(warnings, fmt.Sprintf("no node matching the node selector %q is tolerated: %s",
+				selector.String(), strings.Join(untolerated, "; ")))
+		}
+	}
+
+	for i := range tolerations {
+		if !toleratesAnyTaint(nodes, &tolerations[i]) {
+			warnings = append(warnings, fmt.Sprintf("toleration %s matches no node taint", formatToleration(tolerations[i])))
+		}
+	}
+
+	return warnings
+}
+
+// untoleratedTaints returns the taints of the node that keep pods with the given tolerations off it
+func untoleratedTaints(node coreV1.Node, tolerations []coreV1.Toleration) []string {
+	var untolerated []string
+	for i := range node.Spec.Taints {
+		taint := &node.Spec.Taints[i]
+		if taint.Effect == coreV1.TaintEffectPreferNoSchedule {
+			continue
+		}
+		tolerated := false
+		for j := range tolerations {
+			if tolerations[j].ToleratesTaint(taint) {
+				tolerated = true
+				break
+			}
+		}
+		if !tolerated {
+			untolerated = append(untolerated, taint.ToString())
+		}
+	}
+	sort.Strings(untolerated)
+	return untolerated
+}
+
+func toleratesAnyTaint(nodes []coreV1.Node, toleration *coreV1.Toleration) bool {
+	for _, node := range nodes {
+		for i := range node.Spec.Taints {
+			if toleration.ToleratesTaint(&node.Spec.Taints[i]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func formatToleration(t coreV1.Toleration) string {
+	return fmt.Sprintf("%s:%s:%s:%s", t.Key, t.Value, t.Operator, t.Effect)
+}
+
+// verifyScheduling checks at startup that loadtest pods with the configured node selectors and tolerations can
+// be scheduled on the nodes of the cluster when enabled, so a misconfiguration is noticed before the first
+// loadtest is stuck pending. Problems are logged, they don't prevent the controller from starting.
+func verifyScheduling(cfg Config, client kubernetes.Interface, logger *zap.Logger) {
+	if !cfg.SchedulingCheck {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.KubeClientTimeout)
+	defer cancel()
+
+	nodes, err := client.CoreV1().Nodes().List(ctx, metaV1.ListOptions{})
+	if err != nil {
+		logger.Warn("Could not list nodes to check the scheduling of loadtest pods", zap.Error(err))
+		return
+	}
+
+	warnings := checkScheduling(nodes.Items, cfg.NodeSelectors, cfg.Tolerations.KubeToleration())
+	for _, warning := range warnings {
+		logger.Warn("Loadtest pod scheduling is likely misconfigured", zap.String("reason", warning))
+	}
+	if len(warnings) == 0 {
+		logger.Info("Loadtest pods can be scheduled", zap.Int("nodes", len(nodes.Items)))
+	}
+}
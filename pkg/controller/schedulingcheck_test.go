@@ -0,0 +1,66 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	coreV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/hellofresh/kangal/pkg/kubernetes"
+)
+
+func TestCheckScheduling(t *testing.T) {
+	loadGenNode := coreV1.Node{
+		ObjectMeta: metaV1.ObjectMeta{Name: "loadgen-1", Labels: map[string]string{"pool": "loadgen"}},
+		Spec: coreV1.NodeSpec{Taints: []coreV1.Taint{
+			{Key: "workload", Value: "loadtest", Effect: coreV1.TaintEffectNoSchedule},
+		}},
+	}
+	defaultNode := coreV1.Node{
+		ObjectMeta: metaV1.ObjectMeta{Name: "default-1", Labels: map[string]string{"pool": "default"}},
+	}
+
+	for _, tt := range []struct {
+		name           string
+		nodeSelector   map[string]string
+		tolerations    kubernetes.Tolerations
+		expectWarnings []string
+	}{
+		{
+			name: "no node selector nor tolerations",
+		},
+		{
+			name:         "tolerated load generator pool",
+			nodeSelector: map[string]string{"pool": "loadgen"},
+			tolerations:  kubernetes.Tolerations{{Key: "workload", Value: "loadtest", Operator: "Equal", Effect: "NoSchedule"}},
+		},
+		{
+			name:           "node selector matches no node",
+			nodeSelector:   map[string]string{"pool": "loadgne"},
+			tolerations:    kubernetes.Tolerations{{Key: "workload", Value: "loadtest", Operator: "Equal", Effect: "NoSchedule"}},
+			expectWarnings: []string{`no node matches the node selector "pool=loadgne"`},
+		},
+		{
+			name:         "missing toleration",
+			nodeSelector: map[string]string{"pool": "loadgen"},
+			expectWarnings: []string{
+				`no node matching the node selector "pool=loadgen" is tolerated: node loadgen-1 has taints workload=loadtest:NoSchedule`,
+			},
+		},
+		{
+			name:         "toleration matching no taint",
+			nodeSelector: map[string]string{"pool": "loadgen"},
+			tolerations: kubernetes.Tolerations{
+				{Key: "workload", Value: "loadtest", Operator: "Equal", Effect: "NoSchedule"},
+				{Key: "workload", Value: "lodtest", Operator: "Equal", Effect: "NoSchedule"},
+			},
+			expectWarnings: []string{"toleration workload:lodtest:Equal:NoSchedule matches no node taint"},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			warnings := checkScheduling([]coreV1.Node{loadGenNode, defaultNode}, tt.nodeSelector, tt.tolerations.KubeToleration())
+			assert.Equal(t, tt.expectWarnings, warnings)
+		})
+	}
+}
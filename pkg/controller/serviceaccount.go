@@ -0,0 +1,52 @@
+package controller
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+	coreV1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+// checkOrCreatePodServiceAccount creates PodServiceAccount in the namespace created for the loadtest when it is
+// missing there. Service accounts set by the loadtest and existing namespaces are left alone, the service
+// account is expected to exist there already
+func (c *Controller) checkOrCreatePodServiceAccount(ctx context.Context, loadtest *loadTestV1.LoadTest) error {
+	name := c.cfg.PodServiceAccount
+	if name == "" || loadtest.Spec.ServiceAccountName != "" || loadtest.Spec.ExistingNamespace != "" {
+		return nil
+	}
+
+	_, err := c.kubeClientSet.CoreV1().ServiceAccounts(loadtest.Status.Namespace).Get(ctx, name, metaV1.GetOptions{})
+	if err == nil {
+		return nil
+	}
+	if !errors.IsNotFound(err) {
+		return err
+	}
+
+	serviceAccount := &coreV1.ServiceAccount{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name: name,
+			Labels: map[string]string{
+				"app": "kangal",
+			},
+			Annotations: c.cfg.PodServiceAccountAnnotations,
+			OwnerReferences: []metaV1.OwnerReference{
+				*metaV1.NewControllerRef(loadtest, loadTestV1.SchemeGroupVersion.WithKind("LoadTest")),
+			},
+		},
+	}
+
+	_, err = c.kubeClientSet.CoreV1().ServiceAccounts(loadtest.Status.Namespace).Create(ctx, serviceAccount, metaV1.CreateOptions{})
+	if err != nil && !errors.IsAlreadyExists(err) {
+		c.logger.Error("Error on creating pod service account", zap.String("loadtest", loadtest.GetName()),
+			zap.String("serviceAccount", name), zap.Error(err))
+		return err
+	}
+
+	return nil
+}
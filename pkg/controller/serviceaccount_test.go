@@ -0,0 +1,66 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+func TestCheckOrCreatePodServiceAccount(t *testing.T) {
+	for _, tt := range []struct {
+		name              string
+		configured        string
+		loadTest          string
+		existingNamespace string
+		expected          []string
+	}{
+		{name: "none"},
+		{name: "configured", configured: "kangal-load", expected: []string{"kangal-load"}},
+		{name: "loadtest service account", configured: "kangal-load", loadTest: "team-load"},
+		{name: "existing namespace", configured: "kangal-load", existingNamespace: "loadtest-name"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			kubeClient := k8sfake.NewSimpleClientset()
+			c := &Controller{
+				kubeClientSet: kubeClient,
+				logger:        zaptest.NewLogger(t),
+				cfg: Config{
+					PodServiceAccount: tt.configured,
+					PodServiceAccountAnnotations: map[string]string{
+						"eks.amazonaws.com/role-arn": "arn:aws:iam::123456789012:role/kangal",
+					},
+				},
+			}
+			lt := &loadTestV1.LoadTest{
+				ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-name"},
+				Spec: loadTestV1.LoadTestSpec{
+					ServiceAccountName: tt.loadTest,
+					ExistingNamespace:  tt.existingNamespace,
+				},
+				Status: loadTestV1.LoadTestStatus{Namespace: "loadtest-name"},
+			}
+
+			require.NoError(t, c.checkOrCreatePodServiceAccount(context.Background(), lt))
+			// a second sync finds the service account already created
+			require.NoError(t, c.checkOrCreatePodServiceAccount(context.Background(), lt))
+
+			serviceAccounts, err := kubeClient.CoreV1().ServiceAccounts("loadtest-name").List(context.Background(), metaV1.ListOptions{})
+			require.NoError(t, err)
+
+			var names []string
+			for _, serviceAccount := range serviceAccounts.Items {
+				names = append(names, serviceAccount.Name)
+				assert.Equal(t, "arn:aws:iam::123456789012:role/kangal", serviceAccount.Annotations["eks.amazonaws.com/role-arn"])
+				assert.Equal(t, "loadtest-name", metaV1.GetControllerOf(&serviceAccount).Name)
+			}
+			assert.Equal(t, tt.expected, names)
+		})
+	}
+}
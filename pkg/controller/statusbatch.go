@@ -0,0 +1,132 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+// statusBatchSize is the capacity of the channel of pending status writes, syncs block once it is full
+const statusBatchSize = 256
+
+// statusWrite is a status update of a LoadTest waiting to be written
+type statusWrite struct {
+	key          string
+	loadTest     *loadTestV1.LoadTest
+	phaseChanged bool
+	// previousPhase is the phase of the LoadTest before the phase change
+	previousPhase loadTestV1.LoadTestPhase
+}
+
+// statusBatcher coalesces the status updates of LoadTests and writes them once per interval, to reduce
+// the write pressure on the API server when many loadtests change phase at once. Only the latest pending
+// update of each LoadTest is written.
+type statusBatcher struct {
+	interval time.Duration
+	timeout  time.Duration
+	// write writes a single status update
+	write func(ctx context.Context, w statusWrite)
+	// stale tells if the LoadTest changed since the status update was computed, stale updates would
+	// conflict and are dropped, the change of the LoadTest enqueues it again
+	stale func(w statusWrite) bool
+
+	writes chan statusWrite
+	stopCh chan struct{}
+	doneCh chan struct{}
+}
+
+func newStatusBatcher(interval, timeout time.Duration, write func(context.Context, statusWrite), stale func(statusWrite) bool) *statusBatcher {
+	return &statusBatcher{
+		interval: interval,
+		timeout:  timeout,
+		write:    write,
+		stale:    stale,
+		writes:   make(chan statusWrite, statusBatchSize),
+		stopCh:   make(chan struct{}),
+		doneCh:   make(chan struct{}),
+	}
+}
+
+// add queues the status update for the next flush, it returns false when the batcher is stopped and the
+// update has to be written right away
+func (b *statusBatcher) add(w statusWrite) bool {
+	select {
+	case <-b.stopCh:
+		return false
+	default:
+	}
+
+	select {
+	case b.writes <- w:
+		return true
+	case <-b.stopCh:
+		return false
+	}
+}
+
+// start runs the flush goroutine
+func (b *statusBatcher) start() {
+	go b.run()
+}
+
+// stop stops accepting status updates and waits for the pending ones to be written
+func (b *statusBatcher) stop() {
+	close(b.stopCh)
+	<-b.doneCh
+}
+
+func (b *statusBatcher) run() {
+	defer close(b.doneCh)
+
+	ticker := time.NewTicker(b.interval)
+	defer ticker.Stop()
+
+	pending := map[string]statusWrite{}
+	for {
+		select {
+		case w := <-b.writes:
+			coalesceStatusWrite(pending, w)
+		case <-ticker.C:
+			b.flush(pending)
+		case <-b.stopCh:
+			for {
+				select {
+				case w := <-b.writes:
+					coalesceStatusWrite(pending, w)
+				default:
+					b.flush(pending)
+					return
+				}
+			}
+		}
+	}
+}
+
+// coalesceStatusWrite replaces the pending status update of the LoadTest, a phase change of the replaced
+// update is kept so the actions following a phase change are not skipped
+func coalesceStatusWrite(pending map[string]statusWrite, w statusWrite) {
+	if previous, ok := pending[w.key]; ok && previous.phaseChanged {
+		w.phaseChanged = true
+		w.previousPhase = previous.previousPhase
+	}
+	pending[w.key] = w
+}
+
+// flush writes and clears the pending status updates
+func (b *statusBatcher) flush(pending map[string]statusWrite) {
+	if len(pending) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), b.timeout)
+	defer cancel()
+
+	for key, w := range pending {
+		delete(pending, key)
+		if b.stale(w) {
+			continue
+		}
+		b.write(ctx, w)
+	}
+}
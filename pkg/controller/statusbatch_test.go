@@ -0,0 +1,81 @@
+package controller
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+func TestStatusBatcher(t *testing.T) {
+	newWrite := func(key string, phase loadTestV1.LoadTestPhase, phaseChanged bool) statusWrite {
+		return statusWrite{
+			key: key,
+			loadTest: &loadTestV1.LoadTest{
+				ObjectMeta: metaV1.ObjectMeta{Name: key},
+				Status:     loadTestV1.LoadTestStatus{Phase: phase},
+			},
+			phaseChanged: phaseChanged,
+		}
+	}
+
+	var mu sync.Mutex
+	var written []statusWrite
+	b := newStatusBatcher(time.Hour, time.Second,
+		func(_ context.Context, w statusWrite) {
+			mu.Lock()
+			defer mu.Unlock()
+			written = append(written, w)
+		},
+		func(w statusWrite) bool {
+			return w.key == "stale"
+		},
+	)
+	b.start()
+
+	changed := newWrite("loadtest", loadTestV1.LoadTestRunning, true)
+	changed.previousPhase = loadTestV1.LoadTestStarting
+	require.True(t, b.add(changed))
+	require.True(t, b.add(newWrite("loadtest", loadTestV1.LoadTestRunning, false)))
+	require.True(t, b.add(newWrite("stale", loadTestV1.LoadTestFinished, true)))
+
+	// stopping writes the pending updates
+	b.stop()
+	assert.False(t, b.add(newWrite("loadtest", loadTestV1.LoadTestFinished, true)), "Expected updates to be rejected once stopped")
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, written, 1, "Expected a single write per loadtest and stale writes to be dropped")
+	assert.Equal(t, "loadtest", written[0].key)
+	assert.True(t, written[0].phaseChanged, "Expected the phase change of the replaced update to be kept")
+	assert.Equal(t, loadTestV1.LoadTestStarting, written[0].previousPhase)
+}
+
+func TestStatusBatcherFlushesPeriodically(t *testing.T) {
+	written := make(chan statusWrite, 1)
+	b := newStatusBatcher(10*time.Millisecond, time.Second,
+		func(_ context.Context, w statusWrite) {
+			written <- w
+		},
+		func(statusWrite) bool {
+			return false
+		},
+	)
+	b.start()
+	defer b.stop()
+
+	require.True(t, b.add(statusWrite{key: "loadtest", loadTest: &loadTestV1.LoadTest{}}))
+
+	select {
+	case w := <-written:
+		assert.Equal(t, "loadtest", w.key)
+	case <-time.After(time.Second):
+		t.Fatal("Expected the status update to be written")
+	}
+}
@@ -0,0 +1,32 @@
+package controller
+
+import (
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+// setErrored moves the loadtest to errored phase, with the reason and message of the status telling why,
+// so the failure is shown by kubectl get loadtest without digging into the controller logs
+func setErrored(loadTest *loadTestV1.LoadTest, reason, message string) {
+	loadTest.Status.Phase = loadTestV1.LoadTestErrored
+	loadTest.Status.Reason = reason
+	loadTest.Status.Message = message
+}
+
+// setSyncFailure records the error of a failed sync that is retried in the status of the loadtest,
+// errored loadtests keep the reason they errored with
+func setSyncFailure(loadTest *loadTestV1.LoadTest, err error) {
+	if loadTest.Status.Phase == loadTestV1.LoadTestErrored {
+		return
+	}
+	loadTest.Status.Reason = reasonSyncFailed
+	loadTest.Status.Message = err.Error()
+}
+
+// clearSyncFailure drops the error of a failed sync from the status once the loadtest synced successfully
+func clearSyncFailure(loadTest *loadTestV1.LoadTest) {
+	if loadTest.Status.Reason != reasonSyncFailed {
+		return
+	}
+	loadTest.Status.Reason = ""
+	loadTest.Status.Message = ""
+}
@@ -0,0 +1,49 @@
+package controller
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+func TestSyncFailure(t *testing.T) {
+	loadTest := &loadTestV1.LoadTest{
+		Status: loadTestV1.LoadTestStatus{Phase: loadTestV1.LoadTestCreating},
+	}
+
+	setSyncFailure(loadTest, errors.New("could not create namespace"))
+	assert.Equal(t, reasonSyncFailed, loadTest.Status.Reason)
+	assert.Equal(t, "could not create namespace", loadTest.Status.Message)
+
+	clearSyncFailure(loadTest)
+	assert.Empty(t, loadTest.Status.Reason)
+	assert.Empty(t, loadTest.Status.Message)
+}
+
+func TestSyncFailureKeepsErroredReason(t *testing.T) {
+	loadTest := &loadTestV1.LoadTest{}
+	setErrored(loadTest, reasonQuotaExceeded, "exceeded quota: pods")
+
+	setSyncFailure(loadTest, errors.New("could not update job"))
+	clearSyncFailure(loadTest)
+
+	assert.Equal(t, loadTestV1.LoadTestErrored, loadTest.Status.Phase)
+	assert.Equal(t, reasonQuotaExceeded, loadTest.Status.Reason)
+	assert.Equal(t, "exceeded quota: pods", loadTest.Status.Message)
+}
+
+func TestSyncHandlerSyncFailure(t *testing.T) {
+	syncErr := errors.New("could not reach the backend API")
+	s := newSyncHandlerTest(t, Config{}, syncErrorBackend{err: syncErr})
+
+	stored, err := s.sync(t)
+	require.ErrorIs(t, err, syncErr)
+
+	assert.Equal(t, loadTestV1.LoadTestCreating, stored.Status.Phase)
+	assert.Equal(t, reasonSyncFailed, stored.Status.Reason)
+	assert.Equal(t, syncErr.Error(), stored.Status.Message)
+}
@@ -0,0 +1,57 @@
+package controller
+
+import (
+	"context"
+	"errors"
+
+	"go.uber.org/zap"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/discovery"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+// statusSubresource is the name of the status subresource of the LoadTest CRD
+const statusSubresource = "loadtests/status"
+
+// verifyStatusSubresource checks at startup that the LoadTest CRD has the status subresource, without it
+// UpdateStatus fails and loadtests are stuck in their initial phase. It returns true when statuses must be written
+// with a plain Update instead, which requires StatusSubresourceFallback. Failing discovery requests are logged,
+// they don't prevent the controller from starting.
+func verifyStatusSubresource(cfg Config, client discovery.DiscoveryInterface, logger *zap.Logger) (bool, error) {
+	groupVersion := loadTestV1.SchemeGroupVersion.String()
+	resources, err := client.ServerResourcesForGroupVersion(groupVersion)
+	if err != nil {
+		logger.Warn("Could not discover the LoadTest resources to check the status subresource",
+			zap.String("groupVersion", groupVersion),
+			zap.Error(err),
+		)
+		return false, nil
+	}
+
+	for _, resource := range resources.APIResources {
+		if resource.Name == statusSubresource {
+			return false, nil
+		}
+	}
+
+	if !cfg.StatusSubresourceFallback {
+		return false, errors.New("the LoadTest CRD has no status subresource so loadtest statuses can't be written, " +
+			"install the CRD from charts/kangal/crds/loadtest.yaml or set STATUS_SUBRESOURCE_FALLBACK=true to write " +
+			"statuses with plain updates")
+	}
+	logger.Warn("The LoadTest CRD has no status subresource, loadtest statuses are written with plain updates. "+
+		"Install the CRD from charts/kangal/crds/loadtest.yaml to fix it",
+		zap.String("groupVersion", groupVersion),
+	)
+	return true, nil
+}
+
+// updateStatus writes the status of the loadtest, with a plain Update when the CRD has no status subresource
+func (c *Controller) updateStatus(ctx context.Context, loadTest *loadTestV1.LoadTest) (*loadTestV1.LoadTest, error) {
+	if c.statusViaUpdate {
+		// without the status subresource the status is part of the resource and Update writes it
+		return c.kangalClientSet.KangalV1().LoadTests().Update(ctx, loadTest, metaV1.UpdateOptions{})
+	}
+	return c.kangalClientSet.KangalV1().LoadTests().UpdateStatus(ctx, loadTest, metaV1.UpdateOptions{})
+}
@@ -0,0 +1,107 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	fakeDiscovery "k8s.io/client-go/discovery/fake"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+	fakeClientset "github.com/hellofresh/kangal/pkg/kubernetes/generated/clientset/versioned/fake"
+)
+
+func TestVerifyStatusSubresource(t *testing.T) {
+	for _, tt := range []struct {
+		name            string
+		resources       []metaV1.APIResource
+		discovered      bool
+		fallback        bool
+		statusViaUpdate bool
+		expectError     bool
+	}{
+		{
+			name:       "status subresource",
+			resources:  []metaV1.APIResource{{Name: "loadtests"}, {Name: "loadtests/status"}},
+			discovered: true,
+		},
+		{
+			name:        "missing status subresource",
+			resources:   []metaV1.APIResource{{Name: "loadtests"}},
+			discovered:  true,
+			expectError: true,
+		},
+		{
+			name:            "missing status subresource with fallback",
+			resources:       []metaV1.APIResource{{Name: "loadtests"}},
+			discovered:      true,
+			fallback:        true,
+			statusViaUpdate: true,
+		},
+		{
+			name:       "discovery failure",
+			discovered: false,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			client := fakeClientset.NewSimpleClientset().Discovery().(*fakeDiscovery.FakeDiscovery)
+			if tt.discovered {
+				client.Resources = []*metaV1.APIResourceList{{
+					GroupVersion: loadTestV1.SchemeGroupVersion.String(),
+					APIResources: tt.resources,
+				}}
+			}
+
+			statusViaUpdate, err := verifyStatusSubresource(Config{StatusSubresourceFallback: tt.fallback}, client, zaptest.NewLogger(t))
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.statusViaUpdate, statusViaUpdate)
+		})
+	}
+}
+
+func TestUpdateStatus(t *testing.T) {
+	for _, tt := range []struct {
+		name            string
+		statusViaUpdate bool
+		verb            string
+		subresource     string
+	}{
+		{
+			name:        "status subresource",
+			verb:        "update",
+			subresource: "status",
+		},
+		{
+			name:            "plain update",
+			statusViaUpdate: true,
+			verb:            "update",
+			subresource:     "",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			loadTest := &loadTestV1.LoadTest{ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-name"}}
+			kangalClient := fakeClientset.NewSimpleClientset(loadTest)
+			c := &Controller{
+				kangalClientSet: kangalClient,
+				statusViaUpdate: tt.statusViaUpdate,
+			}
+
+			updated := loadTest.DeepCopy()
+			updated.Status.Phase = loadTestV1.LoadTestRunning
+			_, err := c.updateStatus(context.Background(), updated)
+			require.NoError(t, err)
+
+			actions := kangalClient.Actions()
+			require.Len(t, actions, 1)
+			assert.Equal(t, tt.verb, actions[0].GetVerb())
+			assert.Equal(t, tt.subresource, actions[0].GetSubresource())
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	utilRuntime "k8s.io/apimachinery/pkg/util/runtime"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+// suspendJobs suspends the jobs of all active loadtests during maintenance, so they don't spawn new pods
+// while the controller doesn't manage them, and sets their Suspended condition. The jobs are resumed by the
+// next sync of the loadtest once the controller manages loadtests again. It stops before the next loadtest
+// once ctx is done or stop is closed, a nil stop never stops it.
+func (c *Controller) suspendJobs(ctx context.Context, stop <-chan struct{}) {
+	loadTests, err := c.loadtestsLister.List(labels.Everything())
+	if err != nil {
+		utilRuntime.HandleError(err)
+		return
+	}
+
+	for _, loadTest := range loadTests {
+		select {
+		case <-ctx.Done():
+			c.logger.Warn("Stopped suspending loadtest jobs", zap.Error(ctx.Err()))
+			return
+		case <-stop:
+			return
+		default:
+		}
+
+		if loadTest.Status.Namespace == "" {
+			continue
+		}
+		if loadTest.Status.Phase == loadTestV1.LoadTestFinished || loadTest.Status.Phase == loadTestV1.LoadTestErrored {
+			continue
+		}
+
+		logger := c.logger.With(zap.String("loadtest", loadTest.GetName()))
+		if err := c.setJobsSuspended(ctx, loadTest, true); err != nil {
+			logger.Error("Failed to suspend loadtest jobs", zap.Error(err))
+			continue
+		}
+
+		suspended := loadTest.DeepCopy()
+		meta.SetStatusCondition(&suspended.Status.Conditions, metaV1.Condition{
+			Type:               loadTestV1.LoadTestConditionSuspended,
+			Status:             metaV1.ConditionTrue,
+			Reason:             loadTestV1.LoadTestSuspendedMaintenance,
+			Message:            "Loadtest jobs are suspended while the controller is paused or shut down",
+			ObservedGeneration: loadTest.GetGeneration(),
+		})
+		_, err := c.updateStatus(ctx, suspended)
+		if err != nil {
+			logger.Error("Failed to set loadtest suspended condition", zap.Error(err))
+			continue
+		}
+		logger.Info("Suspended loadtest jobs")
+	}
+}
+
+// resumeJobs resumes the jobs of a loadtest suspended by suspendJobs and clears its Suspended condition
+func (c *Controller) resumeJobs(ctx context.Context, loadTest *loadTestV1.LoadTest) error {
+	if !meta.IsStatusConditionTrue(loadTest.Status.Conditions, loadTestV1.LoadTestConditionSuspended) {
+		return nil
+	}
+
+	if loadTest.Status.Namespace != "" {
+		if err := c.setJobsSuspended(ctx, loadTest, false); err != nil {
+			return err
+		}
+	}
+
+	meta.SetStatusCondition(&loadTest.Status.Conditions, metaV1.Condition{
+		Type:               loadTestV1.LoadTestConditionSuspended,
+		Status:             metaV1.ConditionFalse,
+		Reason:             loadTestV1.LoadTestResumed,
+		Message:            "Loadtest jobs were resumed",
+		ObservedGeneration: loadTest.GetGeneration(),
+	})
+	c.logger.Info("Resumed loadtest jobs", zap.String("loadtest", loadTest.GetName()))
+	return nil
+}
+
+// setJobsSuspended sets the suspend field of all jobs of the loadtest
+func (c *Controller) setJobsSuspended(ctx context.Context, loadTest *loadTestV1.LoadTest, suspend bool) error {
+	jobs, err := c.loadTestJobs(loadTest)
+	if err != nil {
+		return err
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{
+			"suspend": suspend,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, job := range jobs {
+		if job.Spec.Suspend != nil && *job.Spec.Suspend == suspend {
+			continue
+		}
+		_, err := c.kubeClientSet.BatchV1().Jobs(job.GetNamespace()).Patch(ctx, job.GetName(), types.MergePatchType, patch, metaV1.PatchOptions{})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+	batchV1 "k8s.io/api/batch/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	batchListersV1 "k8s.io/client-go/listers/batch/v1"
+	"k8s.io/client-go/tools/cache"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+	fakeClientset "github.com/hellofresh/kangal/pkg/kubernetes/generated/clientset/versioned/fake"
+	listers "github.com/hellofresh/kangal/pkg/kubernetes/generated/listers/loadtest/v1"
+)
+
+func TestSuspendResumeJobs(t *testing.T) {
+	job := &batchV1.Job{ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-job", Namespace: "loadtest-namespace"}}
+	running := &loadTestV1.LoadTest{
+		ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-running"},
+		Status:     loadTestV1.LoadTestStatus{Phase: loadTestV1.LoadTestRunning, Namespace: "loadtest-namespace"},
+	}
+	finished := &loadTestV1.LoadTest{
+		ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-finished"},
+		Status:     loadTestV1.LoadTestStatus{Phase: loadTestV1.LoadTestFinished, Namespace: "finished-namespace"},
+	}
+
+	jobIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	require.NoError(t, jobIndexer.Add(job))
+	loadTestIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	require.NoError(t, loadTestIndexer.Add(running))
+	require.NoError(t, loadTestIndexer.Add(finished))
+
+	kubeClient := k8sfake.NewSimpleClientset(job)
+	kangalClient := fakeClientset.NewSimpleClientset(running, finished)
+
+	c := &Controller{
+		kubeClientSet:   kubeClient,
+		kangalClientSet: kangalClient,
+		jobsLister:      batchListersV1.NewJobLister(jobIndexer),
+		loadtestsLister: listers.NewLoadTestLister(loadTestIndexer),
+		logger:          zaptest.NewLogger(t),
+	}
+	ctx := context.Background()
+
+	c.suspendJobs(ctx, nil)
+
+	suspendedJob, err := kubeClient.BatchV1().Jobs("loadtest-namespace").Get(ctx, "loadtest-job", metaV1.GetOptions{})
+	require.NoError(t, err)
+	require.NotNil(t, suspendedJob.Spec.Suspend)
+	assert.True(t, *suspendedJob.Spec.Suspend)
+
+	suspended, err := kangalClient.KangalV1().LoadTests().Get(ctx, "loadtest-running", metaV1.GetOptions{})
+	require.NoError(t, err)
+	assert.True(t, meta.IsStatusConditionTrue(suspended.Status.Conditions, loadTestV1.LoadTestConditionSuspended))
+
+	untouched, err := kangalClient.KangalV1().LoadTests().Get(ctx, "loadtest-finished", metaV1.GetOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, untouched.Status.Conditions, "Expected finished loadtests not to be suspended")
+
+	require.NoError(t, jobIndexer.Update(suspendedJob))
+	require.NoError(t, c.resumeJobs(ctx, suspended))
+
+	resumedJob, err := kubeClient.BatchV1().Jobs("loadtest-namespace").Get(ctx, "loadtest-job", metaV1.GetOptions{})
+	require.NoError(t, err)
+	require.NotNil(t, resumedJob.Spec.Suspend)
+	assert.False(t, *resumedJob.Spec.Suspend)
+
+	condition := meta.FindStatusCondition(suspended.Status.Conditions, loadTestV1.LoadTestConditionSuspended)
+	require.NotNil(t, condition)
+	assert.Equal(t, metaV1.ConditionFalse, condition.Status)
+	assert.Equal(t, loadTestV1.LoadTestResumed, condition.Reason)
+
+	// loadtests that were not suspended are left alone
+	require.NoError(t, c.resumeJobs(ctx, suspended))
+}
+
+func TestSuspendJobsStopped(t *testing.T) {
+	running := &loadTestV1.LoadTest{
+		ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-running"},
+		Status:     loadTestV1.LoadTestStatus{Phase: loadTestV1.LoadTestRunning, Namespace: "loadtest-namespace"},
+	}
+	loadTestIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	require.NoError(t, loadTestIndexer.Add(running))
+	kangalClient := fakeClientset.NewSimpleClientset(running)
+
+	c := &Controller{
+		kangalClientSet: kangalClient,
+		loadtestsLister: listers.NewLoadTestLister(loadTestIndexer),
+		logger:          zaptest.NewLogger(t),
+	}
+
+	stop := make(chan struct{})
+	close(stop)
+	c.suspendJobs(context.Background(), stop)
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	c.suspendJobs(canceled, nil)
+
+	assert.Empty(t, kangalClient.Actions(), "Expected no loadtest to be suspended once stopped")
+}
@@ -0,0 +1,67 @@
+package controller
+
+import (
+	"sync"
+	"time"
+)
+
+// syncErrors deduplicates the logs of repeated sync errors of each LoadTest: an error is logged when it first
+// occurs and then once per interval with the number of times it repeated, a different error is logged right away
+type syncErrors struct {
+	// interval between the logs of a repeated error, 0 logs every error
+	interval time.Duration
+	now      func() time.Time
+
+	mu     sync.Mutex
+	errors map[string]*syncError
+}
+
+type syncError struct {
+	message string
+	logged  time.Time
+	repeats int
+}
+
+func newSyncErrors(interval time.Duration) *syncErrors {
+	return &syncErrors{
+		interval: interval,
+		now:      time.Now,
+		errors:   map[string]*syncError{},
+	}
+}
+
+// observe records a sync error of the given LoadTest key and returns whether it must be logged, with the number of
+// times it repeated since it was logged last
+func (s *syncErrors) observe(key string, err error) (log bool, repeats int) {
+	if s.interval <= 0 {
+		return true, 0
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	last, ok := s.errors[key]
+	if !ok || last.message != err.Error() {
+		s.errors[key] = &syncError{message: err.Error(), logged: now}
+		return true, 0
+	}
+
+	last.repeats++
+	if now.Sub(last.logged) < s.interval {
+		return false, 0
+	}
+
+	repeats = last.repeats
+	last.repeats = 0
+	last.logged = now
+	return true, repeats
+}
+
+// forget removes the sync error of the given LoadTest key, e.g. once it synced successfully
+func (s *syncErrors) forget(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.errors, key)
+}
@@ -0,0 +1,54 @@
+package controller
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyncErrors(t *testing.T) {
+	now := time.Now()
+	s := newSyncErrors(time.Minute)
+	s.now = func() time.Time { return now }
+
+	errTimeout := errors.New("timeout")
+
+	log, repeats := s.observe("default/loadtest", errTimeout)
+	assert.True(t, log, "first occurrence is logged")
+	assert.Equal(t, 0, repeats)
+
+	now = now.Add(10 * time.Second)
+	log, _ = s.observe("default/loadtest", errTimeout)
+	assert.False(t, log, "repeat within the interval is not logged")
+	log, _ = s.observe("default/loadtest", errTimeout)
+	assert.False(t, log)
+
+	now = now.Add(time.Minute)
+	log, repeats = s.observe("default/loadtest", errTimeout)
+	assert.True(t, log, "repeat after the interval is logged")
+	assert.Equal(t, 3, repeats)
+
+	log, repeats = s.observe("default/loadtest", errors.New("conflict"))
+	assert.True(t, log, "a different error is logged right away")
+	assert.Equal(t, 0, repeats)
+
+	log, _ = s.observe("default/other-loadtest", errTimeout)
+	assert.True(t, log, "errors are deduplicated per loadtest")
+
+	s.forget("default/loadtest")
+	log, repeats = s.observe("default/loadtest", errors.New("conflict"))
+	assert.True(t, log, "error after a successful sync is logged")
+	assert.Equal(t, 0, repeats)
+}
+
+func TestSyncErrorsDisabled(t *testing.T) {
+	s := newSyncErrors(0)
+
+	for i := 0; i < 3; i++ {
+		log, repeats := s.observe("default/loadtest", errors.New("timeout"))
+		assert.True(t, log)
+		assert.Equal(t, 0, repeats)
+	}
+}
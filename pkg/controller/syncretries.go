@@ -0,0 +1,119 @@
+package controller
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	coreV1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+// syncRetries tracks when the backend sync of each LoadTest may be retried after it failed, so status updates
+// enqueueing the LoadTest don't retry it before its backoff elapsed
+type syncRetries struct {
+	now func() time.Time
+
+	mu   sync.Mutex
+	next map[string]time.Time
+}
+
+func newSyncRetries() *syncRetries {
+	return &syncRetries{
+		now:  time.Now,
+		next: map[string]time.Time{},
+	}
+}
+
+// wait returns how long the backend sync of the given LoadTest key has to wait for its next retry
+func (r *syncRetries) wait(key string) time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	next, ok := r.next[key]
+	if !ok {
+		return 0
+	}
+	return next.Sub(r.now())
+}
+
+// failed schedules the next retry of the backend sync of the given LoadTest key after backoff
+func (r *syncRetries) failed(key string, backoff time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.next[key] = r.now().Add(backoff)
+}
+
+// forget drops the next retry of the given LoadTest key, e.g. once it synced successfully
+func (r *syncRetries) forget(key string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.next, key)
+}
+
+// syncRetryBackoff returns the backoff before the given retry, starting at base and doubling on each retry up to
+// maxBackoff
+func syncRetryBackoff(retries int32, base, maxBackoff time.Duration) time.Duration {
+	backoff := base
+	for i := int32(1); i < retries && backoff < maxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > maxBackoff {
+		return maxBackoff
+	}
+	return backoff
+}
+
+// checkSyncRetries counts the consecutive failed backend syncs of the loadtest when MaxSyncRetries is set: the
+// loadtest is enqueued again after an exponential backoff until it failed MaxSyncRetries times, then it is moved to
+// errored phase with the last error. It returns syncErr to requeue the loadtest with the rate limiter of the work
+// queue when MaxSyncRetries is not set, nil otherwise.
+func (c *Controller) checkSyncRetries(key string, loadTest *loadTestV1.LoadTest, syncErr error) error {
+	if c.cfg.MaxSyncRetries <= 0 {
+		return syncErr
+	}
+
+	loadTest.Status.SyncRetries++
+	logger := c.logger.With(
+		zap.String("loadtest", key),
+		zap.Int32("retries", loadTest.Status.SyncRetries),
+		zap.Error(syncErr),
+	)
+
+	if loadTest.Status.SyncRetries < c.cfg.MaxSyncRetries {
+		backoff := syncRetryBackoff(loadTest.Status.SyncRetries, c.cfg.SyncRetryBackoff, c.cfg.SyncRetryMaxBackoff)
+		logger.Info("Failed to sync backend resources, retrying loadtest", zap.Duration("backoff", backoff))
+		setSyncFailure(loadTest, syncErr)
+		c.syncRetries.failed(key, backoff)
+		c.workQueue.AddAfter(key, backoff)
+		return nil
+	}
+
+	c.syncRetries.forget(key)
+	message := fmt.Sprintf("Failed to sync backend resources %d times: %s", loadTest.Status.SyncRetries, syncErr)
+	logger.Info("Backend sync retries exhausted, marking loadtest as errored")
+	c.recorder.Event(loadTest, coreV1.EventTypeWarning, reasonSyncRetriesExhausted, message)
+
+	meta.SetStatusCondition(&loadTest.Status.Conditions, metaV1.Condition{
+		Type:               loadTestV1.LoadTestConditionBackendSync,
+		Status:             metaV1.ConditionFalse,
+		Reason:             loadTestV1.LoadTestBackendSyncRetriesExhausted,
+		Message:            message,
+		ObservedGeneration: loadTest.GetGeneration(),
+	})
+	setErrored(loadTest, reasonSyncRetriesExhausted, message)
+	return nil
+}
+
+// resetSyncRetries resets the count of failed backend syncs of the loadtest once it synced successfully
+func (c *Controller) resetSyncRetries(key string, loadTest *loadTestV1.LoadTest) {
+	c.syncRetries.forget(key)
+	loadTest.Status.SyncRetries = 0
+	clearSyncFailure(loadTest)
+}
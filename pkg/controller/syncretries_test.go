@@ -0,0 +1,235 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/metric/noop"
+	"go.uber.org/zap/zaptest"
+	coreV1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/hellofresh/kangal/pkg/backends"
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+	fakeClientset "github.com/hellofresh/kangal/pkg/kubernetes/generated/clientset/versioned/fake"
+	listers "github.com/hellofresh/kangal/pkg/kubernetes/generated/listers/loadtest/v1"
+)
+
+func TestSyncRetryBackoff(t *testing.T) {
+	for _, tt := range []struct {
+		retries  int32
+		expected time.Duration
+	}{
+		{retries: 1, expected: 5 * time.Second},
+		{retries: 2, expected: 10 * time.Second},
+		{retries: 4, expected: 40 * time.Second},
+		{retries: 10, expected: time.Minute},
+	} {
+		assert.Equal(t, tt.expected, syncRetryBackoff(tt.retries, 5*time.Second, time.Minute))
+	}
+}
+
+func TestSyncRetriesWait(t *testing.T) {
+	now := time.Now()
+	retries := newSyncRetries()
+	retries.now = func() time.Time { return now }
+
+	assert.Zero(t, retries.wait("loadtest-name"))
+
+	retries.failed("loadtest-name", 10*time.Second)
+	assert.Equal(t, 10*time.Second, retries.wait("loadtest-name"))
+
+	now = now.Add(15 * time.Second)
+	assert.True(t, retries.wait("loadtest-name") <= 0)
+
+	retries.forget("loadtest-name")
+	assert.Zero(t, retries.wait("loadtest-name"))
+}
+
+func TestCheckSyncRetries(t *testing.T) {
+	syncErr := errors.New("could not reach the backend API")
+
+	for _, tt := range []struct {
+		name          string
+		maxRetries    int32
+		retries       int32
+		expectError   bool
+		expectRetries int32
+		expectPhase   loadTestV1.LoadTestPhase
+		expectReason  string
+		expectEvents  int
+	}{
+		{
+			name:        "retries disabled",
+			expectError: true,
+			expectPhase: loadTestV1.LoadTestCreating,
+		},
+		{
+			name:          "retried with backoff",
+			maxRetries:    3,
+			retries:       1,
+			expectRetries: 2,
+			expectPhase:   loadTestV1.LoadTestCreating,
+			expectReason:  reasonSyncFailed,
+		},
+		{
+			name:          "retries exhausted",
+			maxRetries:    3,
+			retries:       2,
+			expectRetries: 3,
+			expectPhase:   loadTestV1.LoadTestErrored,
+			expectReason:  reasonSyncRetriesExhausted,
+			expectEvents:  1,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			loadTest := &loadTestV1.LoadTest{
+				ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-name"},
+				Status: loadTestV1.LoadTestStatus{
+					Phase:       loadTestV1.LoadTestCreating,
+					SyncRetries: tt.retries,
+				},
+			}
+
+			recorder := record.NewFakeRecorder(10)
+			c := &Controller{
+				recorder:    recorder,
+				logger:      zaptest.NewLogger(t),
+				syncRetries: newSyncRetries(),
+				workQueue: newPriorityQueue("test", 1, func(interface{}) priority {
+					return priorityNormal
+				}),
+				cfg: Config{
+					MaxSyncRetries:      tt.maxRetries,
+					SyncRetryBackoff:    time.Minute,
+					SyncRetryMaxBackoff: time.Hour,
+				},
+			}
+			defer c.workQueue.ShutDown()
+
+			err := c.checkSyncRetries("loadtest-name", loadTest, syncErr)
+			if tt.expectError {
+				assert.ErrorIs(t, err, syncErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, tt.expectRetries, loadTest.Status.SyncRetries)
+			assert.Equal(t, tt.expectPhase, loadTest.Status.Phase)
+			assert.Equal(t, tt.expectReason, loadTest.Status.Reason)
+			assert.Len(t, recorder.Events, tt.expectEvents)
+
+			if tt.expectPhase == loadTestV1.LoadTestErrored {
+				condition := meta.FindStatusCondition(loadTest.Status.Conditions, loadTestV1.LoadTestConditionBackendSync)
+				require.NotNil(t, condition)
+				assert.Equal(t, loadTestV1.LoadTestBackendSyncRetriesExhausted, condition.Reason)
+				assert.Contains(t, condition.Message, syncErr.Error())
+				assert.Zero(t, c.syncRetries.wait("loadtest-name"))
+			} else if tt.maxRetries > 0 {
+				assert.True(t, c.syncRetries.wait("loadtest-name") > 0)
+			}
+		})
+	}
+}
+
+type syncErrorBackend struct {
+	backends.Backend
+	err error
+}
+
+func (b syncErrorBackend) Sync(context.Context, loadTestV1.LoadTest, string) error {
+	return b.err
+}
+
+// syncHandlerTest runs syncHandler against fake clientsets, with the lister cache updated from the written
+// LoadTest between syncs like the informer would
+type syncHandlerTest struct {
+	controller   *Controller
+	kangalClient *fakeClientset.Clientset
+	indexer      cache.Indexer
+}
+
+func newSyncHandlerTest(t *testing.T, cfg Config, backend backends.Backend) *syncHandlerTest {
+	t.Helper()
+
+	loadTest := &loadTestV1.LoadTest{
+		ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-name"},
+		Status: loadTestV1.LoadTestStatus{
+			Phase:     loadTestV1.LoadTestCreating,
+			Namespace: "loadtest-namespace",
+		},
+	}
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	require.NoError(t, indexer.Add(loadTest.DeepCopy()))
+
+	statsReporter, err := NewMetricsReporter(noop.NewMeterProvider().Meter("test"))
+	require.NoError(t, err)
+
+	cfg.SyncHandlerTimeout = time.Minute
+	kangalClient := fakeClientset.NewSimpleClientset(loadTest)
+	c := &Controller{
+		cfg:             cfg,
+		kubeClientSet:   k8sfake.NewSimpleClientset(&coreV1.Namespace{ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-namespace"}}),
+		kangalClientSet: kangalClient,
+		loadtestsLister: listers.NewLoadTestLister(indexer),
+		recorder:        record.NewFakeRecorder(10),
+		statsClient:     *statsReporter,
+		flapping:        newFlappingDetector(0, 0),
+		statusThrottle:  newStatusThrottle(cfg.StatusUpdateInterval),
+		reconciles:      newReconcileHistory(),
+		phases:          newPhaseCounter(statsReporter.loadTestsByPhaseStat),
+		syncErrors:      newSyncErrors(cfg.SyncErrorLogInterval),
+		syncRetries:     newSyncRetries(),
+		registry:        staticRegistry{backend: backend},
+		logger:          zaptest.NewLogger(t),
+		workQueue: newPriorityQueue("test", 1, func(interface{}) priority {
+			return priorityNormal
+		}),
+	}
+	t.Cleanup(c.workQueue.ShutDown)
+
+	return &syncHandlerTest{controller: c, kangalClient: kangalClient, indexer: indexer}
+}
+
+// sync runs syncHandler once and returns the written LoadTest
+func (s *syncHandlerTest) sync(t *testing.T) (*loadTestV1.LoadTest, error) {
+	t.Helper()
+
+	syncErr := s.controller.syncHandler("loadtest-name")
+
+	stored, err := s.kangalClient.KangalV1().LoadTests().Get(context.Background(), "loadtest-name", metaV1.GetOptions{})
+	require.NoError(t, err)
+	require.NoError(t, s.indexer.Update(stored.DeepCopy()))
+	return stored, syncErr
+}
+
+func TestSyncHandlerSyncRetries(t *testing.T) {
+	syncErr := errors.New("could not reach the backend API")
+	s := newSyncHandlerTest(t, Config{
+		MaxSyncRetries:      3,
+		SyncRetryBackoff:    time.Nanosecond,
+		SyncRetryMaxBackoff: time.Nanosecond,
+		// the count is written right away, even while other status updates are throttled
+		StatusUpdateInterval: time.Hour,
+	}, syncErrorBackend{err: syncErr})
+
+	var stored *loadTestV1.LoadTest
+	for retries := int32(1); retries <= 3; retries++ {
+		var err error
+		stored, err = s.sync(t)
+		require.NoError(t, err)
+		assert.Equal(t, retries, stored.Status.SyncRetries)
+	}
+
+	assert.Equal(t, loadTestV1.LoadTestErrored, stored.Status.Phase)
+	assert.Equal(t, reasonSyncRetriesExhausted, stored.Status.Reason)
+	assert.Contains(t, stored.Status.Message, syncErr.Error())
+}
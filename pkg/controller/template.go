@@ -0,0 +1,75 @@
+package controller
+
+import (
+	"bytes"
+	"fmt"
+	"text/template"
+	"time"
+
+	"go.uber.org/zap"
+	coreV1 "k8s.io/api/core/v1"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+// testFileTemplateData is the restricted set of variables available to templated test files
+type testFileTemplateData struct {
+	// Name of the loadtest
+	Name string
+	// Namespace the loadtest runs in
+	Namespace string
+	// Tags of the loadtest
+	Tags map[string]string
+	// RunID identifies the run of the loadtest, it is the UID of the LoadTest resource
+	RunID string
+	// Time is the time the test file is rendered at, in UTC
+	Time time.Time
+}
+
+// renderTestFile executes the test file of the loadtest as a Go template, referencing
+// variables that don't exist fails the rendering
+func renderTestFile(loadTest *loadTestV1.LoadTest, now time.Time) ([]byte, error) {
+	tmpl, err := template.New("testFile").Option("missingkey=error").Parse(string(loadTest.Spec.TestFile))
+	if err != nil {
+		return nil, fmt.Errorf("invalid test file template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	err = tmpl.Execute(&buf, testFileTemplateData{
+		Name:      loadTest.GetName(),
+		Namespace: loadTest.Status.Namespace,
+		Tags:      loadTest.Spec.Tags,
+		RunID:     string(loadTest.GetUID()),
+		Time:      now.UTC(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to render test file template: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// templateTestFile returns the loadtest to sync the backend with, with the test file rendered when templating
+// is enabled. Loadtests whose template can't be rendered are moved to errored phase and nil is returned.
+func (c *Controller) templateTestFile(loadTest *loadTestV1.LoadTest) *loadTestV1.LoadTest {
+	if !loadTest.Spec.TemplateTestFile || len(loadTest.Spec.TestFile) == 0 {
+		return loadTest
+	}
+
+	testFile, err := renderTestFile(loadTest, time.Now())
+	if err != nil {
+		if loadTest.Status.Phase != loadTestV1.LoadTestErrored {
+			c.logger.Info("Rejecting loadtest with invalid test file template",
+				zap.String("loadtest", loadTest.GetName()),
+				zap.Error(err),
+			)
+			c.recorder.Event(loadTest, coreV1.EventTypeWarning, reasonInvalidTestFileTemplate, err.Error())
+		}
+		setErrored(loadTest, reasonInvalidTestFileTemplate, err.Error())
+		return nil
+	}
+
+	// the rendered test file is only handed to the backend, the spec keeps the template
+	rendered := loadTest.DeepCopy()
+	rendered.Spec.TestFile = testFile
+	return rendered
+}
@@ -0,0 +1,94 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+func TestRenderTestFile(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	for _, tt := range []struct {
+		name        string
+		testFile    string
+		expected    string
+		expectError bool
+	}{
+		{
+			name:     "variables",
+			testFile: `{"name": "{{.Name}}", "ns": "{{.Namespace}}", "run": "{{.RunID}}", "env": "{{.Tags.env}}", "at": "{{.Time.Format "2006-01-02"}}"}`,
+			expected: `{"name": "loadtest-name", "ns": "loadtest-namespace", "run": "loadtest-uid", "env": "staging", "at": "2024-01-02"}`,
+		},
+		{
+			name:     "no template actions",
+			testFile: `call = "helloworld.Greeter.SayHello"`,
+			expected: `call = "helloworld.Greeter.SayHello"`,
+		},
+		{
+			name:        "invalid template",
+			testFile:    `{{.Name`,
+			expectError: true,
+		},
+		{
+			name:        "unknown variable",
+			testFile:    `{{.Secret}}`,
+			expectError: true,
+		},
+		{
+			name:        "unknown tag",
+			testFile:    `{{.Tags.team}}`,
+			expectError: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			loadTest := &loadTestV1.LoadTest{
+				ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-name", UID: types.UID("loadtest-uid")},
+				Spec: loadTestV1.LoadTestSpec{
+					Tags:     loadTestV1.LoadTestTags{"env": "staging"},
+					TestFile: []byte(tt.testFile),
+				},
+				Status: loadTestV1.LoadTestStatus{Namespace: "loadtest-namespace"},
+			}
+
+			rendered, err := renderTestFile(loadTest, now)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, string(rendered))
+		})
+	}
+}
+
+func TestTemplateTestFile(t *testing.T) {
+	recorder := record.NewFakeRecorder(10)
+	c := &Controller{recorder: recorder, logger: zaptest.NewLogger(t)}
+
+	// templating is opt-in, literal template actions are kept otherwise
+	loadTest := &loadTestV1.LoadTest{
+		ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-name"},
+		Spec:       loadTestV1.LoadTestSpec{TestFile: []byte("{{.Name}}")},
+	}
+	assert.Same(t, loadTest, c.templateTestFile(loadTest))
+
+	loadTest.Spec.TemplateTestFile = true
+	rendered := c.templateTestFile(loadTest)
+	require.NotNil(t, rendered)
+	assert.Equal(t, "loadtest-name", string(rendered.Spec.TestFile))
+	assert.Equal(t, "{{.Name}}", string(loadTest.Spec.TestFile), "Expected spec to keep the template")
+
+	loadTest.Spec.TestFile = []byte("{{.Name")
+	assert.Nil(t, c.templateTestFile(loadTest))
+	assert.Equal(t, loadTestV1.LoadTestErrored, loadTest.Status.Phase)
+	assert.Len(t, recorder.Events, 1)
+}
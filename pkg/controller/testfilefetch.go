@@ -0,0 +1,82 @@
+package controller
+
+import (
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+	coreV1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/hellofresh/kangal/pkg/backends"
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+// failedTestFileFetch returns the termination of the first test file fetcher init container that exited
+// with an error, either in its current or in its last state when it is restarted, nil if there is none
+func failedTestFileFetch(pods []*coreV1.Pod) (*coreV1.Pod, *coreV1.ContainerStateTerminated) {
+	for _, pod := range pods {
+		for _, status := range pod.Status.InitContainerStatuses {
+			if status.Name != backends.TestFileFetcherContainerName {
+				continue
+			}
+			for _, terminated := range []*coreV1.ContainerStateTerminated{status.State.Terminated, status.LastTerminationState.Terminated} {
+				if terminated != nil && terminated.ExitCode != 0 {
+					return pod, terminated
+				}
+			}
+		}
+	}
+	return nil, nil
+}
+
+// checkTestFileFetch moves loadtests fetching their test file from TestFileURL to errored phase when the
+// test file fetcher of one of their pods failed, e.g. because the file is missing, access is denied or the
+// file exceeds TestFileMaxSize. Retrying the pod would fail the same way, so the loadtest is errored right
+// away, with the output of the fetcher in the TestFileFetch condition.
+func (c *Controller) checkTestFileFetch(loadTest *loadTestV1.LoadTest) error {
+	if loadTest.Spec.TestFileURL == "" || loadTest.Status.Namespace == "" {
+		return nil
+	}
+	if meta.IsStatusConditionFalse(loadTest.Status.Conditions, loadTestV1.LoadTestConditionTestFileFetch) {
+		loadTest.Status.Phase = loadTestV1.LoadTestErrored
+		return nil
+	}
+	// loadtests errored by their backend because of the failed pod are checked as well, to explain why
+	if loadTest.Status.Phase == loadTestV1.LoadTestFinished {
+		return nil
+	}
+
+	pods, err := c.loadTestPods(loadTest)
+	if err != nil {
+		return err
+	}
+	pod, terminated := failedTestFileFetch(pods)
+	if pod == nil {
+		return nil
+	}
+
+	output := strings.TrimSpace(terminated.Message)
+	if output == "" {
+		output = terminated.Reason
+	}
+	message := fmt.Sprintf("Failed to fetch test file %s in pod %s, exit code %d: %s",
+		loadTest.Spec.TestFileURL, pod.GetName(), terminated.ExitCode, output)
+	c.logger.Info("Loadtest test file could not be fetched, marking loadtest as errored",
+		zap.String("loadtest", loadTest.GetName()),
+		zap.String("pod", pod.GetName()),
+		zap.Int32("exitCode", terminated.ExitCode),
+	)
+	c.recorder.Event(loadTest, coreV1.EventTypeWarning, reasonTestFileFetchFailed, message)
+
+	meta.SetStatusCondition(&loadTest.Status.Conditions, metaV1.Condition{
+		Type:               loadTestV1.LoadTestConditionTestFileFetch,
+		Status:             metaV1.ConditionFalse,
+		Reason:             loadTestV1.LoadTestTestFileFetchFailed,
+		Message:            message,
+		ObservedGeneration: loadTest.GetGeneration(),
+	})
+	setErrored(loadTest, reasonTestFileFetchFailed, message)
+	return nil
+}
@@ -0,0 +1,123 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+	coreV1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	coreListersV1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/hellofresh/kangal/pkg/backends"
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+func TestCheckTestFileFetch(t *testing.T) {
+	newPod := func(state, lastState *coreV1.ContainerStateTerminated) *coreV1.Pod {
+		return &coreV1.Pod{
+			ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-pod", Namespace: "loadtest-namespace"},
+			Status: coreV1.PodStatus{
+				InitContainerStatuses: []coreV1.ContainerStatus{
+					{
+						Name:                 backends.TestFileFetcherContainerName,
+						State:                coreV1.ContainerState{Terminated: state},
+						LastTerminationState: coreV1.ContainerState{Terminated: lastState},
+					},
+				},
+			},
+		}
+	}
+	notFound := &coreV1.ContainerStateTerminated{ExitCode: 22, Message: "curl: (22) The requested URL returned error: 404\n"}
+
+	for _, tt := range []struct {
+		name         string
+		testFileURL  string
+		phase        loadTestV1.LoadTestPhase
+		pod          *coreV1.Pod
+		conditions   []metaV1.Condition
+		expectPhase  loadTestV1.LoadTestPhase
+		expectEvents int
+	}{
+		{
+			name:        "inline test file",
+			phase:       loadTestV1.LoadTestStarting,
+			pod:         newPod(notFound, nil),
+			expectPhase: loadTestV1.LoadTestStarting,
+		},
+		{
+			name:        "test file fetched",
+			testFileURL: "https://example.com/config.json",
+			phase:       loadTestV1.LoadTestRunning,
+			pod:         newPod(&coreV1.ContainerStateTerminated{ExitCode: 0}, nil),
+			expectPhase: loadTestV1.LoadTestRunning,
+		},
+		{
+			name:         "test file fetch failed",
+			testFileURL:  "https://example.com/config.json",
+			phase:        loadTestV1.LoadTestStarting,
+			pod:          newPod(notFound, nil),
+			expectPhase:  loadTestV1.LoadTestErrored,
+			expectEvents: 1,
+		},
+		{
+			name:         "test file fetch restarted",
+			testFileURL:  "https://example.com/config.json",
+			phase:        loadTestV1.LoadTestStarting,
+			pod:          newPod(nil, notFound),
+			expectPhase:  loadTestV1.LoadTestErrored,
+			expectEvents: 1,
+		},
+		{
+			name:         "errored by the backend",
+			testFileURL:  "https://example.com/config.json",
+			phase:        loadTestV1.LoadTestErrored,
+			pod:          newPod(notFound, nil),
+			expectPhase:  loadTestV1.LoadTestErrored,
+			expectEvents: 1,
+		},
+		{
+			name:        "failed pod garbage collected",
+			testFileURL: "https://example.com/config.json",
+			phase:       loadTestV1.LoadTestRunning,
+			pod:         newPod(nil, nil),
+			conditions:  []metaV1.Condition{{Type: loadTestV1.LoadTestConditionTestFileFetch, Status: metaV1.ConditionFalse}},
+			expectPhase: loadTestV1.LoadTestErrored,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+			require.NoError(t, indexer.Add(tt.pod))
+			recorder := record.NewFakeRecorder(10)
+
+			c := &Controller{
+				podsLister: coreListersV1.NewPodLister(indexer),
+				recorder:   recorder,
+				logger:     zaptest.NewLogger(t),
+			}
+			loadTest := &loadTestV1.LoadTest{
+				ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-name"},
+				Spec:       loadTestV1.LoadTestSpec{TestFileURL: tt.testFileURL},
+				Status: loadTestV1.LoadTestStatus{
+					Phase:      tt.phase,
+					Namespace:  "loadtest-namespace",
+					Conditions: tt.conditions,
+				},
+			}
+
+			require.NoError(t, c.checkTestFileFetch(loadTest))
+			assert.Equal(t, tt.expectPhase, loadTest.Status.Phase)
+			assert.Len(t, recorder.Events, tt.expectEvents)
+			if tt.expectEvents > 0 {
+				condition := meta.FindStatusCondition(loadTest.Status.Conditions, loadTestV1.LoadTestConditionTestFileFetch)
+				require.NotNil(t, condition)
+				assert.Equal(t, loadTestV1.LoadTestTestFileFetchFailed, condition.Reason)
+				assert.Contains(t, condition.Message, "exit code 22: curl: (22) The requested URL returned error: 404")
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package controller
+
+import (
+	"sync"
+	"time"
+)
+
+// statusThrottle keeps the time of the last status update of each LoadTest, to write status updates that
+// don't change the phase at most once per interval
+type statusThrottle struct {
+	// interval is the minimum time between two status updates of a LoadTest, 0 disables throttling
+	interval time.Duration
+	now      func() time.Time
+
+	mu          sync.Mutex
+	lastUpdates map[string]time.Time
+}
+
+func newStatusThrottle(interval time.Duration) *statusThrottle {
+	return &statusThrottle{
+		interval:    interval,
+		now:         time.Now,
+		lastUpdates: map[string]time.Time{},
+	}
+}
+
+// wait returns the time a status update of the given LoadTest key has to wait for, 0 when it can be written now
+func (t *statusThrottle) wait(key string) time.Duration {
+	if t.interval <= 0 {
+		return 0
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	lastUpdate, ok := t.lastUpdates[key]
+	if !ok {
+		return 0
+	}
+	if wait := t.interval - t.now().Sub(lastUpdate); wait > 0 {
+		return wait
+	}
+	return 0
+}
+
+// updated records a status update of the given LoadTest key
+func (t *statusThrottle) updated(key string) {
+	if t.interval <= 0 {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.lastUpdates[key] = t.now()
+}
+
+// forget drops the last status update of the given LoadTest key
+func (t *statusThrottle) forget(key string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.lastUpdates, key)
+}
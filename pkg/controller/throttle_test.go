@@ -0,0 +1,79 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+	fakeClientset "github.com/hellofresh/kangal/pkg/kubernetes/generated/clientset/versioned/fake"
+)
+
+func TestStatusThrottle(t *testing.T) {
+	now := time.Now()
+	throttle := newStatusThrottle(10 * time.Second)
+	throttle.now = func() time.Time { return now }
+
+	assert.Zero(t, throttle.wait("loadtest"))
+	throttle.updated("loadtest")
+
+	now = now.Add(4 * time.Second)
+	assert.Equal(t, 6*time.Second, throttle.wait("loadtest"))
+	assert.Zero(t, throttle.wait("other-loadtest"))
+
+	now = now.Add(6 * time.Second)
+	assert.Zero(t, throttle.wait("loadtest"))
+
+	throttle.updated("loadtest")
+	throttle.forget("loadtest")
+	assert.Zero(t, throttle.wait("loadtest"))
+}
+
+func TestStatusThrottleDisabled(t *testing.T) {
+	throttle := newStatusThrottle(0)
+	throttle.updated("loadtest")
+	assert.Zero(t, throttle.wait("loadtest"))
+}
+
+func TestUpdateLoadTestStatusPods(t *testing.T) {
+	loadTestFromCache := &loadTestV1.LoadTest{
+		ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-name"},
+		Status:     loadTestV1.LoadTestStatus{Phase: loadTestV1.LoadTestRunning},
+	}
+	kangalClient := fakeClientset.NewSimpleClientset(loadTestFromCache.DeepCopy())
+	c := &Controller{
+		kangalClientSet: kangalClient,
+		statusThrottle:  newStatusThrottle(time.Hour),
+		logger:          zaptest.NewLogger(t),
+		workQueue: newPriorityQueue("test", 1, func(interface{}) priority {
+			return priorityNormal
+		}),
+	}
+	defer c.workQueue.ShutDown()
+
+	stored := func() loadTestV1.LoadTestPodsStatus {
+		loadTest, err := kangalClient.KangalV1().LoadTests().Get(context.Background(), "loadtest-name", metaV1.GetOptions{})
+		require.NoError(t, err)
+		return loadTest.Status.Pods
+	}
+
+	// pod counter changes are written
+	loadTest := loadTestFromCache.DeepCopy()
+	loadTest.Status.Pods.Active = 2
+	c.updateLoadTestStatus(context.Background(), "loadtest-name", loadTest, loadTestFromCache)
+	assert.Equal(t, int32(2), stored().Active)
+
+	// further changes within the interval are throttled
+	loadTestFromCache = loadTest
+	loadTest = loadTestFromCache.DeepCopy()
+	loadTest.Status.Pods.Active = 1
+	loadTest.Status.Pods.Succeeded = 1
+	c.updateLoadTestStatus(context.Background(), "loadtest-name", loadTest, loadTestFromCache)
+	assert.Equal(t, int32(2), stored().Active)
+	assert.Zero(t, stored().Succeeded)
+}
@@ -0,0 +1,31 @@
+package controller
+
+import (
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+// tracer traces the reconciles of loadtests, spans are dropped unless a tracer provider is set globally
+var tracer = otel.Tracer("github.com/hellofresh/kangal/pkg/controller")
+
+// endSpan ends the span, marking it as failed when err is not nil
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// loadTestSpanAttributes tags the child spans of a reconcile with the loadtest and its backend type,
+// so they can be searched without walking up to the syncHandler span
+func loadTestSpanAttributes(loadTest *loadTestV1.LoadTest) trace.SpanStartOption {
+	return trace.WithAttributes(
+		attribute.String("loadtest", loadTest.GetName()),
+		attribute.String("type", loadTest.Spec.Type.String()),
+	)
+}
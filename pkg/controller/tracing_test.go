@@ -0,0 +1,58 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkTrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+func TestEndSpan(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tracer := sdkTrace.NewTracerProvider(sdkTrace.WithSpanProcessor(recorder)).Tracer("test")
+
+	ctx, parent := tracer.Start(context.Background(), "syncHandler")
+	_, child := tracer.Start(ctx, "backend.Sync")
+	endSpan(child, errors.New("sync failed"))
+	endSpan(parent, nil)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+
+	assert.Equal(t, "backend.Sync", spans[0].Name())
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	assert.Equal(t, "sync failed", spans[0].Status().Description)
+	assert.Len(t, spans[0].Events(), 1, "Expected the error to be recorded")
+	assert.Equal(t, spans[1].SpanContext().SpanID(), spans[0].Parent().SpanID())
+
+	assert.Equal(t, "syncHandler", spans[1].Name())
+	assert.Equal(t, codes.Unset, spans[1].Status().Code)
+}
+
+func TestLoadTestSpanAttributes(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tracer := sdkTrace.NewTracerProvider(sdkTrace.WithSpanProcessor(recorder)).Tracer("test")
+
+	loadTest := &loadTestV1.LoadTest{
+		ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-name"},
+		Spec:       loadTestV1.LoadTestSpec{Type: loadTestV1.LoadTestTypeGhz},
+	}
+	_, span := tracer.Start(context.Background(), "backend.Sync", loadTestSpanAttributes(loadTest))
+	span.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	assert.ElementsMatch(t, []attribute.KeyValue{
+		attribute.String("loadtest", "loadtest-name"),
+		attribute.String("type", "Ghz"),
+	}, spans[0].Attributes())
+}
@@ -0,0 +1,56 @@
+package controller
+
+import (
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+	coreV1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/hellofresh/kangal/pkg/backends"
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+// checkUnsupportedFields flags loadtests setting spec fields their backend doesn't declare in its capabilities, so
+// users don't assume a setting took effect while the backend silently ignores it. The UnsupportedFields condition
+// lists the ignored fields, the Warning Event is only emitted when they change. Loadtests of backends not declaring
+// their capabilities are not checked.
+func (c *Controller) checkUnsupportedFields(backend backends.Backend, loadTest *loadTestV1.LoadTest) {
+	if !c.cfg.UnsupportedFieldsWarning {
+		return
+	}
+	describer, ok := backend.(backends.BackendCapabilities)
+	if !ok {
+		return
+	}
+
+	fields := describer.Capabilities().UnsupportedFields(loadTest.Spec)
+	current := meta.FindStatusCondition(loadTest.Status.Conditions, loadTestV1.LoadTestConditionUnsupportedFields)
+	if len(fields) == 0 {
+		// the fields may be removed from the spec
+		if current != nil {
+			meta.RemoveStatusCondition(&loadTest.Status.Conditions, loadTestV1.LoadTestConditionUnsupportedFields)
+		}
+		return
+	}
+
+	message := fmt.Sprintf("Backend %s ignores the fields: %s", loadTest.Spec.Type, strings.Join(fields, ", "))
+	if current != nil && current.Message == message {
+		return
+	}
+
+	c.logger.Warn("Loadtest sets fields its backend ignores",
+		zap.String("loadtest", loadTest.GetName()),
+		zap.Strings("fields", fields),
+	)
+	c.recorder.Event(loadTest, coreV1.EventTypeWarning, reasonUnsupportedFields, message)
+	meta.SetStatusCondition(&loadTest.Status.Conditions, metaV1.Condition{
+		Type:               loadTestV1.LoadTestConditionUnsupportedFields,
+		Status:             metaV1.ConditionTrue,
+		Reason:             loadTestV1.LoadTestFieldsIgnored,
+		Message:            message,
+		ObservedGeneration: loadTest.GetGeneration(),
+	})
+}
@@ -0,0 +1,94 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zaptest"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/hellofresh/kangal/pkg/backends"
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+func TestCheckUnsupportedFields(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	backend := backends.NewMockBackend(ctrl)
+	backend.EXPECT().Capabilities().Return(backends.Capabilities{
+		RequiredFields: []string{"distributedPods", "testFile"},
+		OptionalFields: []string{"testFileURL", "masterImage"},
+	}).AnyTimes()
+
+	recorder := record.NewFakeRecorder(10)
+	c := &Controller{
+		cfg:      Config{UnsupportedFieldsWarning: true},
+		recorder: recorder,
+		logger:   zaptest.NewLogger(t),
+	}
+
+	loadTest := &loadTestV1.LoadTest{
+		ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-name"},
+		Spec: loadTestV1.LoadTestSpec{
+			Type:      loadTestV1.LoadTestTypeGhz,
+			TestFile:  []byte("{}"),
+			Rate:      "50/1s",
+			Resources: &loadTestV1.LoadTestResources{CPULimits: "1"},
+		},
+	}
+
+	c.checkUnsupportedFields(backend, loadTest)
+	condition := meta.FindStatusCondition(loadTest.Status.Conditions, loadTestV1.LoadTestConditionUnsupportedFields)
+	if assert.NotNil(t, condition) {
+		assert.Equal(t, metaV1.ConditionTrue, condition.Status)
+		assert.Equal(t, loadTestV1.LoadTestFieldsIgnored, condition.Reason)
+		assert.Equal(t, "Backend Ghz ignores the fields: rate, cpuLimits", condition.Message)
+	}
+	assert.Len(t, recorder.Events, 1)
+
+	// the event is not emitted again for the same fields
+	c.checkUnsupportedFields(backend, loadTest)
+	assert.Len(t, recorder.Events, 1)
+
+	loadTest.Spec.Rate = ""
+	loadTest.Spec.Resources = nil
+	c.checkUnsupportedFields(backend, loadTest)
+	assert.Nil(t, meta.FindStatusCondition(loadTest.Status.Conditions, loadTestV1.LoadTestConditionUnsupportedFields))
+	assert.Len(t, recorder.Events, 1)
+
+	c.cfg.UnsupportedFieldsWarning = false
+	loadTest.Spec.Rate = "50/1s"
+	c.checkUnsupportedFields(backend, loadTest)
+	assert.Empty(t, loadTest.Status.Conditions)
+}
+
+// basicBackend implements none of the optional backend interfaces
+type basicBackend struct {
+	backends.Backend
+}
+
+func TestCheckUnsupportedFieldsWithoutCapabilities(t *testing.T) {
+	recorder := record.NewFakeRecorder(10)
+	c := &Controller{
+		cfg:      Config{UnsupportedFieldsWarning: true},
+		recorder: recorder,
+		logger:   zaptest.NewLogger(t),
+	}
+
+	loadTest := &loadTestV1.LoadTest{
+		ObjectMeta: metaV1.ObjectMeta{Name: "loadtest-name"},
+		Spec: loadTestV1.LoadTestSpec{
+			Type: loadTestV1.LoadTestTypeGhz,
+			Rate: "50/1s",
+		},
+	}
+
+	// backends not declaring their capabilities are not checked
+	c.checkUnsupportedFields(basicBackend{}, loadTest)
+	assert.Empty(t, loadTest.Status.Conditions)
+	assert.Empty(t, recorder.Events)
+}
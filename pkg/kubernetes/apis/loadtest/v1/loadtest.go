@@ -26,8 +26,13 @@ func BuildLoadTestObject(spec LoadTestSpec) (*LoadTest, error) {
 
 	name := "loadtest-" + generatedName
 
+	testFileHash := getHashFromBytes(spec.TestFile)
+	if spec.TestFileURL != "" {
+		testFileHash = getHashFromBytes([]byte(spec.TestFileURL))
+	}
+
 	labels := map[string]string{
-		"test-file-hash": getHashFromBytes(spec.TestFile),
+		"test-file-hash": testFileHash,
 	}
 
 	for tagName, tagValue := range spec.Tags {
@@ -107,6 +112,8 @@ func LoadTestPhaseFromString(phase string) (LoadTestPhase, error) {
 		return LoadTestFinished, nil
 	case LoadTestErrored:
 		return LoadTestErrored, nil
+	case LoadTestWaiting:
+		return LoadTestWaiting, nil
 	}
 
 	return "", ErrUnknownLoadTestPhase
@@ -0,0 +1,24 @@
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+const (
+	// LoadTestConditionSpecDrift is the condition type telling if the running loadtest doesn't reflect its current spec
+	LoadTestConditionSpecDrift = "SpecDrift"
+	// LoadTestSpecChanged is the SpecDrift condition reason when the spec changed after the loadtest jobs were created
+	LoadTestSpecChanged = "SpecChanged"
+	// LoadTestSpecInSync is the SpecDrift condition reason when the loadtest jobs were created from the current spec
+	LoadTestSpecInSync = "SpecInSync"
+)
+
+// SpecHash returns a hash of the loadtest spec, to detect changes of the spec after its resources were created
+func SpecHash(spec LoadTestSpec) (string, error) {
+	b, err := json.Marshal(spec)
+	if err != nil {
+		return "", fmt.Errorf("could not hash loadtest spec: %w", err)
+	}
+	return getHashFromBytes(b), nil
+}
@@ -0,0 +1,24 @@
+package v1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpecHash(t *testing.T) {
+	spec := LoadTestSpec{Type: LoadTestTypeK6, TestFile: []byte("export default function() {}")}
+
+	hash, err := SpecHash(spec)
+	require.NoError(t, err)
+
+	same, err := SpecHash(*spec.DeepCopy())
+	require.NoError(t, err)
+	assert.Equal(t, hash, same)
+
+	spec.TargetURL = "http://example.com"
+	changed, err := SpecHash(spec)
+	require.NoError(t, err)
+	assert.NotEqual(t, hash, changed)
+}
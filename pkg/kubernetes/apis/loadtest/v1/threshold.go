@@ -0,0 +1,138 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// LoadTestConditionGate is the condition type holding the result of the loadtest thresholds
+	LoadTestConditionGate = "Gate"
+	// LoadTestGatePassed is the Gate condition reason when all thresholds are satisfied
+	LoadTestGatePassed = "GatePassed"
+	// LoadTestGateFailed is the Gate condition reason when a threshold is not satisfied or the loadtest errored
+	LoadTestGateFailed = "GateFailed"
+	// LoadTestGateSummaryPending is the Gate condition reason when the summary lacks metrics of the thresholds
+	LoadTestGateSummaryPending = "SummaryPending"
+)
+
+// Possible threshold errors
+var (
+	ErrThresholdFormat = errors.New("threshold must be in the form '<metric> <operator> <value>'")
+	ErrThresholdValue  = errors.New("threshold value must be a duration, a percentage or a number")
+)
+
+var thresholdRegexp = regexp.MustCompile(`^\s*([A-Za-z0-9_.\-]+)\s*(<=|>=|<|>)\s*(\S+)\s*$`)
+
+// Threshold is a condition a summary metric of a finished loadtest must satisfy, e.g. "p99 < 200ms"
+type Threshold struct {
+	Metric   string
+	Operator string
+	Value    float64
+}
+
+// ParseThreshold parses a threshold in the form "<metric> <operator> <value>",
+// supported operators are <, <=, > and >=
+func ParseThreshold(s string) (Threshold, error) {
+	matches := thresholdRegexp.FindStringSubmatch(s)
+	if matches == nil {
+		return Threshold{}, fmt.Errorf("%w: %q", ErrThresholdFormat, s)
+	}
+
+	value, err := ParseMetricValue(matches[3])
+	if err != nil {
+		return Threshold{}, err
+	}
+
+	return Threshold{
+		Metric:   matches[1],
+		Operator: matches[2],
+		Value:    value,
+	}, nil
+}
+
+// ThresholdsFromString builds a list of thresholds from a comma separated string
+func ThresholdsFromString(thresholdsStr string) ([]string, error) {
+	var thresholds []string
+	for _, threshold := range strings.Split(thresholdsStr, ",") {
+		threshold = strings.TrimSpace(threshold)
+		if threshold == "" {
+			continue
+		}
+		if _, err := ParseThreshold(threshold); err != nil {
+			return nil, err
+		}
+		thresholds = append(thresholds, threshold)
+	}
+	return thresholds, nil
+}
+
+// ParseMetricValue parses a threshold or summary metric value. Durations are converted
+// to milliseconds, percentages to the percentage number, so "1%" and "1" are equal
+func ParseMetricValue(s string) (float64, error) {
+	s = strings.TrimSpace(s)
+
+	if strings.HasSuffix(s, "%") {
+		v, err := strconv.ParseFloat(strings.TrimSuffix(s, "%"), 64)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %q", ErrThresholdValue, s)
+		}
+		return v, nil
+	}
+
+	if v, err := strconv.ParseFloat(s, 64); err == nil {
+		return v, nil
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q", ErrThresholdValue, s)
+	}
+	return float64(d) / float64(time.Millisecond), nil
+}
+
+// Evaluate tells if the given metric value satisfies the threshold
+func (t Threshold) Evaluate(value float64) bool {
+	switch t.Operator {
+	case "<":
+		return value < t.Value
+	case "<=":
+		return value <= t.Value
+	case ">":
+		return value > t.Value
+	case ">=":
+		return value >= t.Value
+	}
+	return false
+}
+
+// String returns string representation of Threshold
+func (t Threshold) String() string {
+	return fmt.Sprintf("%s %s %s", t.Metric, t.Operator, strconv.FormatFloat(t.Value, 'f', -1, 64))
+}
+
+// GateResult returns "passed", "failed" or "pending" depending on the Gate condition of the loadtest,
+// or an empty string when the loadtest has no thresholds
+func GateResult(loadTest LoadTest) string {
+	if len(loadTest.Spec.Thresholds) == 0 {
+		return ""
+	}
+
+	condition := meta.FindStatusCondition(loadTest.Status.Conditions, LoadTestConditionGate)
+	switch {
+	case condition == nil:
+		return "pending"
+	case condition.Status == metaV1.ConditionTrue:
+		return "passed"
+	case condition.Status == metaV1.ConditionFalse:
+		return "failed"
+	}
+	return "pending"
+}
@@ -0,0 +1,106 @@
+package v1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestParseThreshold(t *testing.T) {
+	for _, tt := range []struct {
+		name      string
+		threshold string
+		want      Threshold
+		err       error
+	}{
+		{
+			name:      "duration",
+			threshold: "p99 < 200ms",
+			want:      Threshold{Metric: "p99", Operator: "<", Value: 200},
+		},
+		{
+			name:      "duration in seconds",
+			threshold: "p95<=1.5s",
+			want:      Threshold{Metric: "p95", Operator: "<=", Value: 1500},
+		},
+		{
+			name:      "percentage",
+			threshold: "error_rate < 1%",
+			want:      Threshold{Metric: "error_rate", Operator: "<", Value: 1},
+		},
+		{
+			name:      "number",
+			threshold: " rps >= 500 ",
+			want:      Threshold{Metric: "rps", Operator: ">=", Value: 500},
+		},
+		{
+			name:      "missing operator",
+			threshold: "p99 200ms",
+			err:       ErrThresholdFormat,
+		},
+		{
+			name:      "unsupported operator",
+			threshold: "p99 == 200ms",
+			err:       ErrThresholdFormat,
+		},
+		{
+			name:      "invalid value",
+			threshold: "p99 < fast",
+			err:       ErrThresholdValue,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseThreshold(tt.threshold)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestThresholdsFromString(t *testing.T) {
+	thresholds, err := ThresholdsFromString("p99 < 200ms, error_rate < 1%,")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"p99 < 200ms", "error_rate < 1%"}, thresholds)
+
+	thresholds, err = ThresholdsFromString("")
+	require.NoError(t, err)
+	assert.Empty(t, thresholds)
+
+	_, err = ThresholdsFromString("p99 < 200ms, p95")
+	assert.ErrorIs(t, err, ErrThresholdFormat)
+}
+
+func TestThresholdEvaluate(t *testing.T) {
+	threshold := Threshold{Metric: "p99", Operator: "<", Value: 200}
+	assert.True(t, threshold.Evaluate(199))
+	assert.False(t, threshold.Evaluate(200))
+
+	threshold.Operator = "<="
+	assert.True(t, threshold.Evaluate(200))
+
+	threshold.Operator = ">"
+	assert.False(t, threshold.Evaluate(200))
+
+	threshold.Operator = ">="
+	assert.True(t, threshold.Evaluate(200))
+}
+
+func TestGateResult(t *testing.T) {
+	loadTest := LoadTest{}
+	assert.Equal(t, "", GateResult(loadTest))
+
+	loadTest.Spec.Thresholds = []string{"p99 < 200ms"}
+	assert.Equal(t, "pending", GateResult(loadTest))
+
+	loadTest.Status.Conditions = []metaV1.Condition{{Type: LoadTestConditionGate, Status: metaV1.ConditionFalse}}
+	assert.Equal(t, "failed", GateResult(loadTest))
+
+	loadTest.Status.Conditions[0].Status = metaV1.ConditionTrue
+	assert.Equal(t, "passed", GateResult(loadTest))
+}
@@ -40,6 +40,10 @@ type LoadTest struct {
 type LoadTestPodsStatus struct {
 	Current *int32 `json:"current"`
 	Desired *int32 `json:"desired"`
+	// Active, Succeeded and Failed are the pod counters of the loadtest jobs
+	Active    int32 `json:"active"`
+	Succeeded int32 `json:"succeeded"`
+	Failed    int32 `json:"failed"`
 }
 
 // LoadTestSpec is the spec for a LoadTest resource
@@ -50,11 +54,130 @@ type LoadTestSpec struct {
 	WorkerConfig    ImageDetails      `json:"workerConfig"`
 	DistributedPods *int32            `json:"distributedPods"`
 	Tags            LoadTestTags      `json:"tags"`
-	TestFile        []byte            `json:"testFile"`
+	TestFile        []byte            `json:"testFile,omitempty"`
+	TestFileURL     string            `json:"testFileURL,omitempty"`
 	TestData        []byte            `json:"testData,omitempty"`
 	EnvVars         map[string]string `json:"envVars,omitempty"`
 	TargetURL       string            `json:"targetURL,omitempty"`
 	Duration        time.Duration     `json:"duration,omitempty"`
+	Thresholds      []string          `json:"thresholds,omitempty"`
+	// Rate is the request rate of backends attacking at a constant rate, e.g. 50/1s for Vegeta or 2000 for wrk2
+	Rate string `json:"rate,omitempty"`
+	// VUs is the number of virtual users of backends simulating users, e.g. k6, 0 keeps the test file options
+	VUs int32 `json:"vus,omitempty"`
+	// Connections is the number of HTTP connections kept open by backends opening a fixed number of connections,
+	// e.g. wrk2, 0 uses the backend default
+	Connections int32 `json:"connections,omitempty"`
+	// Threads is the number of threads of backends running a fixed number of threads, e.g. wrk2, 0 uses the
+	// backend default
+	Threads int32 `json:"threads,omitempty"`
+	// Args are extra command line arguments of the load generator, e.g. --insecure for ghz
+	Args []string `json:"args,omitempty"`
+	// ReportFormat is the format of the report of backends supporting several ones, e.g. json for ghz
+	ReportFormat string `json:"reportFormat,omitempty"`
+	// TemplateTestFile renders TestFile as a Go template before it is handed to the backend
+	TemplateTestFile bool `json:"templateTestFile,omitempty"`
+	// PodAnnotations are added to the loadtest pods, the backend and controller-global pod annotations override them
+	PodAnnotations map[string]string `json:"podAnnotations,omitempty"`
+	// ImagePullSecrets are the names of the secrets used to pull the images of the loadtest pods, they replace
+	// the controller-global image pull secrets
+	ImagePullSecrets []string `json:"imagePullSecrets,omitempty"`
+	// ServiceAccountName is the service account the loadtest pods run with, it replaces the controller-global
+	// pod service account and must exist in the loadtest namespace
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+	// InitContainers are run before the load generator in the loadtest pods, e.g. to seed data or download fixtures,
+	// sharing the test file volume with it
+	InitContainers []InitContainer `json:"initContainers,omitempty"`
+	// Sidecars are run next to the load generator in the loadtest pods, e.g. to scrape its metrics, sharing the
+	// test file volume with it
+	Sidecars []Sidecar `json:"sidecars,omitempty"`
+	// EnvFrom sets the keys of existing Secrets or ConfigMaps of the loadtest namespace as environment variables of
+	// the load generator, so sensitive values are not copied into the loadtest
+	EnvFrom []EnvFromSource `json:"envFrom,omitempty"`
+	// PostCompletion is run after the loadtest succeeded, the loadtest is only finished once it completed
+	PostCompletion *PostCompletionJob `json:"postCompletion,omitempty"`
+	// ExistingNamespace runs the loadtest in the given existing namespace instead of creating one for it,
+	// the namespace must be allowed by the controller and is neither owned nor deleted by the loadtest
+	ExistingNamespace string `json:"existingNamespace,omitempty"`
+	// DependsOn is the name of the loadtest that must be finished before this loadtest is started
+	DependsOn string `json:"dependsOn,omitempty"`
+	// Resources overrides the resource requests and limits of the loadtest pods set by the backend
+	Resources *LoadTestResources `json:"resources,omitempty"`
+	// RetentionClass decides how long the report of the loadtest is kept by the lifecycle rules of the storage
+	RetentionClass RetentionClass `json:"retentionClass,omitempty"`
+	// CleanupThreshold overrides the cleanup threshold of the controller for the loadtest, 0 never deletes it once
+	// finished, the max lifetime of the controller still applies
+	CleanupThreshold *time.Duration `json:"cleanupThreshold,omitempty"`
+	// Timeout is the time limit of the loadtest jobs, Kubernetes kills them and the loadtest errors once exceeded,
+	// 0 uses the controller default
+	Timeout time.Duration `json:"timeout,omitempty"`
+}
+
+// LoadTestResources are the resource requests and limits of the loadtest pods, unset fields keep the backend defaults
+type LoadTestResources struct {
+	CPULimits      string `json:"cpuLimits,omitempty"`
+	CPURequests    string `json:"cpuRequests,omitempty"`
+	MemoryLimits   string `json:"memoryLimits,omitempty"`
+	MemoryRequests string `json:"memoryRequests,omitempty"`
+}
+
+// PostCompletionJob is a container run after a loadtest succeeded, e.g. to post-process its results into a summary
+type PostCompletionJob struct {
+	Image   string   `json:"image"`
+	Command []string `json:"command,omitempty"`
+}
+
+// InitContainer is a container run before the load generator of a loadtest, the name defaults to its position
+type InitContainer struct {
+	Name    string            `json:"name,omitempty"`
+	Image   string            `json:"image"`
+	Command []string          `json:"command,omitempty"`
+	Env     map[string]string `json:"env,omitempty"`
+}
+
+// Sidecar is a container run next to the load generator of a loadtest, the name defaults to its position
+type Sidecar struct {
+	Name    string            `json:"name,omitempty"`
+	Image   string            `json:"image"`
+	Command []string          `json:"command,omitempty"`
+	Env     map[string]string `json:"env,omitempty"`
+	// Native runs the sidecar as a native sidecar, an init container with restartPolicy Always stopped by Kubernetes
+	// once the load generator exited, on clusters supporting it. Other sidecars must exit on their own
+	Native bool `json:"native,omitempty"`
+}
+
+// EnvFromSource references a Secret or a ConfigMap of the loadtest namespace whose keys are set as environment
+// variables of the load generator, exactly one of SecretName and ConfigMapName must be set
+type EnvFromSource struct {
+	SecretName    string `json:"secretName,omitempty"`
+	ConfigMapName string `json:"configMapName,omitempty"`
+	// Prefix is prepended to the names of the variables
+	Prefix string `json:"prefix,omitempty"`
+}
+
+// RetentionClass is the class of the retention of a loadtest report
+type RetentionClass string
+
+const (
+	// RetentionShort is for reports of short-lived tests, e.g. CI smoke tests
+	RetentionShort RetentionClass = "short"
+	// RetentionStandard is the retention of reports of loadtests without a retention class
+	RetentionStandard RetentionClass = "standard"
+	// RetentionLong is for reports kept for comparisons, e.g. release baselines
+	RetentionLong RetentionClass = "long"
+)
+
+// RetentionClasses are the valid retention classes
+var RetentionClasses = []RetentionClass{RetentionShort, RetentionStandard, RetentionLong}
+
+// IsValid returns true if the retention class is one of RetentionClasses
+func (c RetentionClass) IsValid() bool {
+	for _, class := range RetentionClasses {
+		if c == class {
+			return true
+		}
+	}
+	return false
 }
 
 // LoadTestTags is a list of tags of a LoadTest resource.
@@ -69,6 +192,19 @@ type LoadTestStatus struct {
 	Namespace string             `json:"namespace"`
 	JobStatus batchv1.JobStatus  `json:"jobStatus"`
 	Pods      LoadTestPodsStatus `json:"pods"`
+	// Summary holds the summary metrics of a finished loadtest, e.g. p99 or error_rate
+	Summary    map[string]string  `json:"summary,omitempty"`
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// ReportLocation is the object storage location of the report of the loadtest, without credentials
+	ReportLocation string `json:"reportLocation,omitempty"`
+	// RetentionClass is the retention class the report of the loadtest is tagged with on upload
+	RetentionClass RetentionClass `json:"retentionClass,omitempty"`
+	// SyncRetries is the number of consecutive failed syncs of the backend resources of the loadtest
+	SyncRetries int32 `json:"syncRetries,omitempty"`
+	// Reason is the CamelCase reason the loadtest errored or its last sync failed, Message is the human readable
+	// error. Both are empty while the loadtest syncs successfully
+	Reason  string `json:"reason,omitempty"`
+	Message string `json:"message,omitempty"`
 }
 
 // LoadTestPhase defines the phases that a loadtest can be in
@@ -96,6 +232,57 @@ const (
 	// LoadTestErrored is set in case of resource creating failed because of
 	// incorrect data provided by user
 	LoadTestErrored LoadTestPhase = "errored"
+	// LoadTestWaiting is when the loadtest waits for the loadtest it depends on
+	// to finish, before any resources have been created
+	LoadTestWaiting LoadTestPhase = "waiting"
+)
+
+const (
+	// LoadTestConditionSuspended is the condition type telling if the jobs of the loadtest are suspended
+	LoadTestConditionSuspended = "Suspended"
+	// LoadTestSuspendedMaintenance is the Suspended condition reason when the jobs were suspended during maintenance
+	LoadTestSuspendedMaintenance = "Maintenance"
+	// LoadTestResumed is the Suspended condition reason when the suspended jobs were resumed
+	LoadTestResumed = "Resumed"
+)
+
+const (
+	// LoadTestConditionPostCompletion is the condition type holding the state of the post-completion job
+	LoadTestConditionPostCompletion = "PostCompletion"
+	// LoadTestPostCompletionRunning is the PostCompletion condition reason while the post-completion job runs
+	LoadTestPostCompletionRunning = "Running"
+	// LoadTestPostCompletionSucceeded is the PostCompletion condition reason once the post-completion job completed
+	LoadTestPostCompletionSucceeded = "Succeeded"
+	// LoadTestPostCompletionFailed is the PostCompletion condition reason when the post-completion job failed
+	LoadTestPostCompletionFailed = "Failed"
+)
+
+const (
+	// LoadTestConditionEvicted is the condition type telling if a pod of the loadtest was evicted
+	LoadTestConditionEvicted = "Evicted"
+	// LoadTestEvictedNodePressure is the Evicted condition reason when a pod was evicted because of node pressure
+	LoadTestEvictedNodePressure = "NodePressure"
+)
+
+const (
+	// LoadTestConditionUnsupportedFields is the condition type listing the spec fields the loadtest backend ignores
+	LoadTestConditionUnsupportedFields = "UnsupportedFields"
+	// LoadTestFieldsIgnored is the UnsupportedFields condition reason when the backend ignores some spec fields
+	LoadTestFieldsIgnored = "FieldsIgnored"
+)
+
+const (
+	// LoadTestConditionTestFileFetch is the condition type telling if the test file of the loadtest was fetched from its URL
+	LoadTestConditionTestFileFetch = "TestFileFetch"
+	// LoadTestTestFileFetchFailed is the TestFileFetch condition reason when the test file fetcher of a pod failed
+	LoadTestTestFileFetchFailed = "FetchFailed"
+)
+
+const (
+	// LoadTestConditionBackendSync is the condition type telling if the backend resources of the loadtest could be synced
+	LoadTestConditionBackendSync = "BackendSync"
+	// LoadTestBackendSyncRetriesExhausted is the BackendSync condition reason when the backend sync failed MaxSyncRetries times
+	LoadTestBackendSyncRetriesExhausted = "RetriesExhausted"
 )
 
 // LoadTestType needs to be specified to know what tool to use when running a loadtest
@@ -117,6 +304,12 @@ const (
 	LoadTestTypeGhz LoadTestType = "Ghz"
 	// LoadTestTypeK6 tells controller to use k6 provider
 	LoadTestTypeK6 LoadTestType = "K6"
+	// LoadTestTypeVegeta tells controller to use Vegeta provider
+	LoadTestTypeVegeta LoadTestType = "Vegeta"
+	// LoadTestTypeGatling tells controller to use Gatling provider
+	LoadTestTypeGatling LoadTestType = "Gatling"
+	// LoadTestTypeWrk2 tells controller to use wrk2 provider
+	LoadTestTypeWrk2 LoadTestType = "Wrk2"
 )
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -245,13 +245,17 @@ func (c *Client) CountExistingLoadtests() (map[apisLoadTestV1.LoadTestPhase]int6
 		apisLoadTestV1.LoadTestCreating: 0,
 		apisLoadTestV1.LoadTestErrored:  0,
 		apisLoadTestV1.LoadTestStarting: 0,
+		apisLoadTestV1.LoadTestWaiting:  0,
 	}
 
 	var typeCount = map[apisLoadTestV1.LoadTestType]int64{
-		apisLoadTestV1.LoadTestTypeK6:     0,
-		apisLoadTestV1.LoadTestTypeJMeter: 0,
-		apisLoadTestV1.LoadTestTypeLocust: 0,
-		apisLoadTestV1.LoadTestTypeGhz:    0,
+		apisLoadTestV1.LoadTestTypeK6:      0,
+		apisLoadTestV1.LoadTestTypeJMeter:  0,
+		apisLoadTestV1.LoadTestTypeLocust:  0,
+		apisLoadTestV1.LoadTestTypeGhz:     0,
+		apisLoadTestV1.LoadTestTypeVegeta:  0,
+		apisLoadTestV1.LoadTestTypeGatling: 0,
+		apisLoadTestV1.LoadTestTypeWrk2:    0,
 	}
 
 	for _, loadTest := range tt.Items {
@@ -19,6 +19,23 @@ type Toleration struct {
 // Tolerations is an alias to Toleration slice
 type Tolerations []Toleration
 
+// LoadGeneratorTolerations is the preset of tolerations for the taint commonly set on
+// dedicated load generator node pools, "workload=loadtest" with NoSchedule and NoExecute effects
+var LoadGeneratorTolerations = Tolerations{
+	{
+		Key:      "workload",
+		Value:    "loadtest",
+		Operator: string(kubeCoreV1.TolerationOpEqual),
+		Effect:   string(kubeCoreV1.TaintEffectNoSchedule),
+	},
+	{
+		Key:      "workload",
+		Value:    "loadtest",
+		Operator: string(kubeCoreV1.TolerationOpEqual),
+		Effect:   string(kubeCoreV1.TaintEffectNoExecute),
+	},
+}
+
 // ParseToleration parses a pattern of key:value:Operation:Effect to Toleration
 func ParseToleration(toleration string) (Toleration, error) {
 	tolerationParts := strings.Split(toleration, ":")
@@ -53,6 +70,27 @@ func ParseTolerations(tolerations []string) (Tolerations, error) {
 	return parsedTolerations, nil
 }
 
+// Merge returns tolerations followed by the given ones that are not already present
+func (tolerations Tolerations) Merge(other Tolerations) Tolerations {
+	merged := make(Tolerations, 0, len(tolerations)+len(other))
+	merged = append(merged, tolerations...)
+	for _, toleration := range other {
+		if !merged.contains(toleration) {
+			merged = append(merged, toleration)
+		}
+	}
+	return merged
+}
+
+func (tolerations Tolerations) contains(toleration Toleration) bool {
+	for _, t := range tolerations {
+		if t == toleration {
+			return true
+		}
+	}
+	return false
+}
+
 // Validate validates the Toleration properties to be compatible with Kubernetes Tolerations
 func (t Toleration) Validate() error {
 	op := kubeCoreV1.TolerationOperator(t.Operator)
@@ -67,3 +67,18 @@ func TestParseToleration(t *testing.T) {
 		})
 	}
 }
+
+func TestTolerationsMerge(t *testing.T) {
+	custom := Tolerations{
+		{Key: "key1", Value: "value1", Operator: "Equal", Effect: "NoSchedule"},
+		LoadGeneratorTolerations[0],
+	}
+
+	merged := LoadGeneratorTolerations.Merge(custom)
+
+	assert.Equal(t, Tolerations{
+		LoadGeneratorTolerations[0],
+		LoadGeneratorTolerations[1],
+		{Key: "key1", Value: "value1", Operator: "Equal", Effect: "NoSchedule"},
+	}, merged)
+}
@@ -13,6 +13,7 @@ type Config struct {
 	Logger              observability.LoggerConfig
 	OpenAPI             OpenAPIConfig
 	Report              report.Config
+	Dedup               DedupConfig
 	MaxLoadTestsRun     int
 	MaxListLimit        int64 `envconfig:"MAX_LIST_LIMIT" required:"true" default:"50"`
 	MasterURL           string
@@ -22,6 +23,14 @@ type Config struct {
 	KubeClientTimeout time.Duration `envconfig:"KUBE_CLIENT_TIMEOUT" default:"5s"`
 }
 
+// DedupConfig is the configuration of the check rejecting load tests that duplicate a recently created one
+type DedupConfig struct {
+	// Window is the time after creation during which a non-terminal load test blocks its duplicates, 0 disables the check
+	Window time.Duration `envconfig:"DEDUP_WINDOW" default:"0s"`
+	// Tag is the tag holding the identity of load tests, the test file hash is used when it is empty
+	Tag string `envconfig:"DEDUP_TAG"`
+}
+
 // OpenAPIConfig is the OpenAPI specification-specific parameters
 type OpenAPIConfig struct {
 	SpecPath          string `envconfig:"OPEN_API_SPEC_PATH" default:"/etc/kangal"`
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"time"
 
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/metric"
@@ -21,6 +22,7 @@ import (
 	mPkg "github.com/hellofresh/kangal/pkg/core/middleware"
 	kube "github.com/hellofresh/kangal/pkg/kubernetes"
 	apisLoadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+	"github.com/hellofresh/kangal/pkg/report"
 )
 
 const (
@@ -34,11 +36,13 @@ type Proxy struct {
 	registry            backends.Registry
 	kubeClient          *kube.Client
 	allowedCustomImages bool
+	dedup               DedupConfig
 }
 
 // MetricsReporter used to interface with the metrics configurations
 type MetricsReporter struct {
 	countRunningLoadtests metric.Int64ObservableUpDownCounter
+	reportStorageBytes    metric.Int64ObservableGauge
 }
 
 // NewMetricsReporter contains loadtest metrics definition
@@ -65,20 +69,37 @@ func NewMetricsReporter(meter metric.Meter, kubeClient *kube.Client) (*MetricsRe
 		return nil, fmt.Errorf("could not register countRunningLoadtests metric: %w", err)
 	}
 
+	reportStorageBytes, err := meter.Int64ObservableGauge(
+		"kangal_report_storage_bytes",
+		metric.WithDescription("Size of the reports of each team, when report storage is capped per team"),
+		metric.WithUnit("By"),
+		metric.WithInt64Callback(func(ctx context.Context, io metric.Int64Observer) error {
+			for team, size := range report.TeamUsage() {
+				io.Observe(size, metric.WithAttributes(attribute.String("team", team)))
+			}
+			return nil
+		}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not register reportStorageBytes metric: %w", err)
+	}
+
 	return &MetricsReporter{
 			countRunningLoadtests: countRunningLoadtests,
+			reportStorageBytes:    reportStorageBytes,
 		},
 		nil
 }
 
 // NewProxy returns new Proxy handlers
-func NewProxy(maxLoadTestsRun int, registry backends.Registry, kubeClient *kube.Client, maxListLimit int64, allowedCustomImages bool) *Proxy {
+func NewProxy(maxLoadTestsRun int, registry backends.Registry, kubeClient *kube.Client, maxListLimit int64, allowedCustomImages bool, dedup DedupConfig) *Proxy {
 	return &Proxy{
 		maxLoadTestsRun:     maxLoadTestsRun,
 		registry:            registry,
 		kubeClient:          kubeClient,
 		maxListLimit:        maxListLimit,
 		allowedCustomImages: allowedCustomImages,
+		dedup:               dedup,
 	}
 }
 
@@ -99,6 +120,7 @@ type LoadTestStatus struct {
 	Tags            apisLoadTestV1.LoadTestTags `json:"tags"`
 	HasEnvVars      bool                        `json:"hasEnvVars"`
 	HasTestData     bool                        `json:"hasTestData"`
+	Gate            string                      `json:"gate,omitempty"` // result of the loadtest thresholds
 }
 
 // List lists all the load tests.
@@ -134,6 +156,7 @@ func (p *Proxy) List(w http.ResponseWriter, r *http.Request) {
 			Tags:            lt.Spec.Tags,
 			HasEnvVars:      len(lt.Spec.EnvVars) != 0,
 			HasTestData:     len(lt.Spec.TestData) != 0,
+			Gate:            apisLoadTestV1.GateResult(lt),
 		}
 	}
 
@@ -145,6 +168,47 @@ func (p *Proxy) List(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// findDuplicateLoadTest returns a load test with the same identity as the given one, created within the
+// dedup window and still in a non-terminal phase. The identity is the value of the dedup tag, or the
+// test file hash when no dedup tag is configured.
+func (p *Proxy) findDuplicateLoadTest(ctx context.Context, loadTest *apisLoadTestV1.LoadTest) (*apisLoadTestV1.LoadTest, error) {
+	if p.dedup.Window <= 0 {
+		return nil, nil
+	}
+
+	var (
+		loadTests *apisLoadTestV1.LoadTestList
+		err       error
+	)
+	if p.dedup.Tag != "" {
+		value, ok := loadTest.Spec.Tags[p.dedup.Tag]
+		if !ok {
+			return nil, nil
+		}
+		loadTests, err = p.kubeClient.ListLoadTest(ctx, kube.ListOptions{Tags: map[string]string{p.dedup.Tag: value}})
+	} else {
+		loadTests, err = p.kubeClient.GetLoadTestsByLabel(ctx, loadTest)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return recentActiveLoadTest(loadTests.Items, p.dedup.Window, time.Now()), nil
+}
+
+// recentActiveLoadTest returns the first load test created within window before now that is not finished or errored
+func recentActiveLoadTest(loadTests []apisLoadTestV1.LoadTest, window time.Duration, now time.Time) *apisLoadTestV1.LoadTest {
+	for i, lt := range loadTests {
+		if lt.Status.Phase == apisLoadTestV1.LoadTestFinished || lt.Status.Phase == apisLoadTestV1.LoadTestErrored {
+			continue
+		}
+		if now.Sub(lt.CreationTimestamp.Time) < window {
+			return &loadTests[i]
+		}
+	}
+	return nil
+}
+
 // Create creates loadtest CR on POST request
 func (p *Proxy) Create(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -164,6 +228,18 @@ func (p *Proxy) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if len(ltSpec.TestFile) == 0 && ltSpec.TestFileURL == "" && backends.RequiresTestFile(backend) {
+		render.Render(w, r, cHttp.ErrResponse(http.StatusBadRequest,
+			fmt.Sprintf("%s for loadtest type %s", ErrRequireTestFile, ltSpec.Type)))
+		return
+	}
+
+	if len(ltSpec.Thresholds) > 0 && !backends.SupportsThresholds(backend) {
+		render.Render(w, r, cHttp.ErrResponse(http.StatusBadRequest,
+			fmt.Sprintf("%s for loadtest type %s", ErrThresholdsUnsupported, ltSpec.Type)))
+		return
+	}
+
 	err = backend.TransformLoadTestSpec(&ltSpec)
 	if err != nil {
 		logger.Error("could not transform LoadTest spec", zap.Error(err))
@@ -178,6 +254,20 @@ func (p *Proxy) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Reject duplicates of a load test created recently and still in progress
+	duplicate, err := p.findDuplicateLoadTest(ctx, loadTest)
+	if err != nil {
+		logger.Error("Could not list load tests with the same identity", zap.Error(err))
+		render.Render(w, r, cHttp.ErrResponse(http.StatusInternalServerError, "Could not list load tests with the same identity"))
+		return
+	}
+	if duplicate != nil {
+		render.Render(w, r, cHttp.ErrResponse(http.StatusConflict, fmt.Sprintf(
+			"Load test %s with the same identity was created less than %s ago and is %s, aborting.",
+			duplicate.Name, p.dedup.Window, duplicate.Status.Phase)))
+		return
+	}
+
 	// Find the old load test with the same data
 	labeledLoadTests, err := p.kubeClient.GetLoadTestsByLabel(ctx, loadTest)
 	if err != nil {
@@ -237,9 +327,15 @@ func (p *Proxy) Create(w http.ResponseWriter, r *http.Request) {
 		Tags:            loadTest.Spec.Tags,
 		HasEnvVars:      len(loadTest.Spec.EnvVars) != 0,
 		HasTestData:     len(loadTest.Spec.TestData) != 0,
+		Gate:            apisLoadTestV1.GateResult(*loadTest),
 	})
 }
 
+// Backends returns the registered backends with the load test fields each of them supports
+func (p *Proxy) Backends(w http.ResponseWriter, r *http.Request) {
+	render.JSON(w, r, p.registry.Capabilities())
+}
+
 // Delete deletes load test CR
 func (p *Proxy) Delete(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -287,6 +383,7 @@ func (p *Proxy) Get(w http.ResponseWriter, r *http.Request) {
 		Tags:            result.Spec.Tags,
 		HasEnvVars:      len(result.Spec.EnvVars) != 0,
 		HasTestData:     len(result.Spec.TestData) != 0,
+		Gate:            apisLoadTestV1.GateResult(*result),
 	})
 }
 
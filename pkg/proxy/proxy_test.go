@@ -2,6 +2,7 @@ package proxy
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"io"
 	"net/http"
@@ -208,7 +209,7 @@ func TestProxy_List(t *testing.T) {
 			})
 			c := kube.NewClient(loadTestClientSet.KangalV1().LoadTests(), kubeClientSet, logger)
 
-			testProxyHandler := NewProxy(1, nil, c, 50, false)
+			testProxyHandler := NewProxy(1, nil, c, 50, false, DedupConfig{})
 
 			req := httptest.NewRequest("POST", "http://example.com/foo?"+tc.urlParams, nil)
 			req = req.WithContext(ctx)
@@ -312,7 +313,7 @@ func TestProxyCreate(t *testing.T) {
 				backends.WithKangalClientSet(loadtestClientSet),
 			)
 
-			testProxyHandler := NewProxy(1, b, c, 50, false)
+			testProxyHandler := NewProxy(1, b, c, 50, false, DedupConfig{})
 			handler := testProxyHandler.Create
 
 			requestWrap := createRequestWrapper(t, tt.requestFiles, strconv.Itoa(tt.distributedPods), string(tt.loadTestType), tt.tagsString, false, "", "")
@@ -416,7 +417,7 @@ func TestNewProxyRecreate(t *testing.T) {
 
 			w := httptest.NewRecorder()
 
-			testProxyHandler := NewProxy(1, b, c, 50, false)
+			testProxyHandler := NewProxy(1, b, c, 50, false, DedupConfig{})
 			testProxyHandler.Create(w, req)
 
 			resp := w.Result()
@@ -516,7 +517,7 @@ func TestProxyCreateWithErrors(t *testing.T) {
 			req.Header.Set("Content-Type", requestWrap.contentType)
 			w := httptest.NewRecorder()
 
-			testProxyHandler := NewProxy(1, b, c, 50, false)
+			testProxyHandler := NewProxy(1, b, c, 50, false, DedupConfig{})
 			testProxyHandler.Create(w, req)
 
 			resp := w.Result()
@@ -590,7 +591,7 @@ func TestProxyGet(t *testing.T) {
 
 			w := httptest.NewRecorder()
 
-			testProxyHandler := NewProxy(1, b, c, 50, false)
+			testProxyHandler := NewProxy(1, b, c, 50, false, DedupConfig{})
 			testProxyHandler.Get(w, req)
 
 			resp := w.Result()
@@ -640,7 +641,7 @@ func TestProxyDelete(t *testing.T) {
 
 			w := httptest.NewRecorder()
 
-			testProxyHandler := NewProxy(1, nil, c, 50, false)
+			testProxyHandler := NewProxy(1, nil, c, 50, false, DedupConfig{})
 			testProxyHandler.Delete(w, req)
 
 			resp := w.Result()
@@ -740,7 +741,7 @@ func TestProxyGetLogs(t *testing.T) {
 
 			w := httptest.NewRecorder()
 
-			testProxyHandler := NewProxy(1, b, c, 50, false)
+			testProxyHandler := NewProxy(1, b, c, 50, false, DedupConfig{})
 			testProxyHandler.GetLogs(w, req.WithContext(ctx))
 
 			resp := w.Result()
@@ -764,3 +765,129 @@ func buildMocFormReq(t *testing.T, requestFiles map[string]string, distributedPo
 	req.Header.Set("Content-Type", request.contentType)
 	return req
 }
+
+func TestRecentActiveLoadTest(t *testing.T) {
+	now := time.Now()
+	loadTest := func(name string, phase apisLoadTestV1.LoadTestPhase, age time.Duration) apisLoadTestV1.LoadTest {
+		return apisLoadTestV1.LoadTest{
+			ObjectMeta: metaV1.ObjectMeta{
+				Name:              name,
+				CreationTimestamp: metaV1.NewTime(now.Add(-age)),
+			},
+			Status: apisLoadTestV1.LoadTestStatus{Phase: phase},
+		}
+	}
+
+	for _, tt := range []struct {
+		name      string
+		loadTests []apisLoadTestV1.LoadTest
+		expected  string
+	}{
+		{
+			name: "no load tests",
+		},
+		{
+			name:      "recent running load test",
+			loadTests: []apisLoadTestV1.LoadTest{loadTest("loadtest-running", apisLoadTestV1.LoadTestRunning, time.Minute)},
+			expected:  "loadtest-running",
+		},
+		{
+			name:      "old running load test",
+			loadTests: []apisLoadTestV1.LoadTest{loadTest("loadtest-old", apisLoadTestV1.LoadTestRunning, time.Hour)},
+		},
+		{
+			name: "recent terminal load tests",
+			loadTests: []apisLoadTestV1.LoadTest{
+				loadTest("loadtest-finished", apisLoadTestV1.LoadTestFinished, time.Minute),
+				loadTest("loadtest-errored", apisLoadTestV1.LoadTestErrored, time.Minute),
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := recentActiveLoadTest(tt.loadTests, 10*time.Minute, now)
+			if tt.expected == "" {
+				assert.Nil(t, actual)
+				return
+			}
+			require.NotNil(t, actual)
+			assert.Equal(t, tt.expected, actual.Name)
+		})
+	}
+}
+
+func TestProxyCreateDuplicate(t *testing.T) {
+	var (
+		kubeClientSet     = fake.NewSimpleClientset()
+		loadtestClientSet = fakeClientset.NewSimpleClientset()
+		logger            = zaptest.NewLogger(t)
+	)
+	ctx := mPkg.SetLogger(context.Background(), logger)
+	c := kube.NewClient(loadtestClientSet.KangalV1().LoadTests(), kubeClientSet, logger)
+	b := backends.New(
+		backends.WithLogger(logger),
+		backends.WithKubeClientSet(kubeClientSet),
+		backends.WithKangalClientSet(loadtestClientSet),
+	)
+
+	loadtestClientSet.Fake.PrependReactor("list", "loadtests", func(action k8sTesting.Action) (handled bool, ret runtime.Object, err error) {
+		assert.Equal(t, "test-tag-pipeline=123", action.(k8sTesting.ListAction).GetListRestrictions().Labels.String())
+		return true, &apisLoadTestV1.LoadTestList{
+			Items: []apisLoadTestV1.LoadTest{
+				{
+					ObjectMeta: metaV1.ObjectMeta{
+						Name:              "loadtest-duplicate",
+						Labels:            map[string]string{"test-tag-pipeline": "123"},
+						CreationTimestamp: metaV1.NewTime(time.Now().Add(-time.Minute)),
+					},
+					Status: apisLoadTestV1.LoadTestStatus{Phase: apisLoadTestV1.LoadTestRunning},
+				},
+			},
+		}, nil
+	})
+
+	requestFiles := map[string]string{
+		"testFile": "testdata/valid/loadtest.jmx",
+	}
+	requestWrap := createRequestWrapper(t, requestFiles, "2", "JMeter", "pipeline:123", false, "", "")
+
+	req := httptest.NewRequest("POST", "http://example.com/load-test", requestWrap.body)
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", requestWrap.contentType)
+
+	w := httptest.NewRecorder()
+
+	testProxyHandler := NewProxy(1, b, c, 50, false, DedupConfig{Window: 10 * time.Minute, Tag: "pipeline"})
+	testProxyHandler.Create(w, req)
+
+	resp := w.Result()
+	respBody, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	assert.Equal(t, `{"error":"Load test loadtest-duplicate with the same identity was created less than 10m0s ago and is running, aborting."}`+"\n", string(respBody))
+}
+
+func TestProxyBackends(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	b := backends.New(backends.WithLogger(logger))
+
+	testProxyHandler := NewProxy(1, b, nil, 50, false, DedupConfig{})
+
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/backends", nil)
+	w := httptest.NewRecorder()
+	testProxyHandler.Backends(w, req)
+
+	resp := w.Result()
+	defer resp.Body.Close()
+
+	var capabilities []backends.Capabilities
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&capabilities))
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, capabilities, backends.Capabilities{
+		Type:            apisLoadTestV1.LoadTestTypeJMeter,
+		Distributed:     true,
+		TestFileFormats: []string{"jmx"},
+		TestDataFormats: []string{"csv"},
+		RequiredFields:  []string{"distributedPods", "testFile"},
+		OptionalFields:  []string{"testFileURL", "testData", "envVars", "masterImage", "workerImage"},
+	})
+}
@@ -20,19 +20,38 @@ import (
 )
 
 const (
-	backendType     = "type"
-	overwrite       = "overwrite"
-	masterImage     = "masterImage"
-	workerImage     = "workerImage"
-	distributedPods = "distributedPods"
-	tags            = "tags"
-	testFile        = "testFile"
-	testData        = "testData"
-	envVars         = "envVars"
-	targetURL       = "targetURL"
-	duration        = "duration"
-	loadTestID      = "id"
-	workerPodID     = "worker"
+	backendType      = "type"
+	overwrite        = "overwrite"
+	masterImage      = "masterImage"
+	workerImage      = "workerImage"
+	distributedPods  = "distributedPods"
+	tags             = "tags"
+	testFile         = "testFile"
+	testFileURL      = "testFileURL"
+	testData         = "testData"
+	envVars          = "envVars"
+	targetURL        = "targetURL"
+	duration         = "duration"
+	rate             = "rate"
+	vus              = "vus"
+	connections      = "connections"
+	threads          = "threads"
+	extraArgs        = "args"
+	reportFormat     = "reportFormat"
+	dependsOn        = "dependsOn"
+	cpuLimits        = "cpuLimits"
+	cpuRequests      = "cpuRequests"
+	memoryLimits     = "memoryLimits"
+	memoryRequests   = "memoryRequests"
+	thresholds       = "thresholds"
+	templateFile     = "templateTestFile"
+	retentionClass   = "retentionClass"
+	cleanupThreshold = "cleanupThreshold"
+	timeout          = "timeout"
+	imagePullSecrets = "imagePullSecrets"
+	serviceAccount   = "serviceAccountName"
+	loadTestID       = "id"
+	workerPodID      = "worker"
 )
 
 var (
@@ -46,18 +65,42 @@ var (
 	ErrWrongImageFormat = errors.New("invalid image format")
 	// ErrEmptyType is the error returned when there's no loadtest type provided
 	ErrEmptyType = errors.New("loadtest type is empty")
+	// ErrWrongTestFileURLScheme is the error returned when the testFileURL can not be fetched by the controller
+	ErrWrongTestFileURLScheme = errors.New("testFileURL scheme must be one of http, https, s3 or gs")
+	// ErrEmptyObjectKey is the error returned when an object storage testFileURL has no object key
+	ErrEmptyObjectKey = errors.New("testFileURL must have the form s3://bucket/key or gs://bucket/key")
+	// ErrWrongVUs is the error returned when the number of virtual users is not a positive integer
+	ErrWrongVUs = errors.New("vus must be a positive integer")
+	// ErrWrongConnections is the error returned when the number of connections is not a positive integer
+	ErrWrongConnections = errors.New("connections must be a positive integer")
+	// ErrWrongThreads is the error returned when the number of threads is not a positive integer
+	ErrWrongThreads = errors.New("threads must be a positive integer")
+	// ErrNegativeCleanupThreshold is the error returned when the cleanupThreshold is negative
+	ErrNegativeCleanupThreshold = errors.New("cleanupThreshold must not be negative")
+	// ErrNegativeTimeout is the error returned when the timeout is negative
+	ErrNegativeTimeout = errors.New("timeout must not be negative")
+	// ErrWrongRetentionClass is the error returned when the retentionClass is not one of the retention classes
+	ErrWrongRetentionClass = errors.New("retentionClass must be one of short, standard or long")
+	// ErrRequireTestFile is the error returned when neither testFile nor testFileURL is set for a backend requiring them
+	ErrRequireTestFile = errors.New("testFile or testFileURL is required")
+	// ErrThresholdsUnsupported is the error returned when thresholds are set for a backend not producing a summary
+	ErrThresholdsUnsupported = errors.New("thresholds are not supported, the backend does not produce a summary")
 
 	testFileFormats = map[string]bool{
-		"jmx":  true,
-		"py":   true,
-		"json": true,
-		"toml": true,
-		"js":   true,
-		"tar":  true,
+		"jmx":   true,
+		"py":    true,
+		"json":  true,
+		"toml":  true,
+		"js":    true,
+		"tar":   true,
+		"txt":   true,
+		"scala": true,
+		"java":  true,
 	}
 	testDataFileFormats = map[string]bool{
 		"csv":      true,
 		"protoset": true,
+		"tar":      true,
 	}
 
 	dockerImageRegexp = regexp.MustCompile("^.*:.*$|^$")
@@ -132,7 +175,18 @@ func fromHTTPRequestToLoadTestSpec(r *http.Request, logger *zap.Logger, allowedC
 		return apisLoadTestV1.LoadTestSpec{}, fmt.Errorf("error getting %s from request: %w", tags, err)
 	}
 
+	tfURL, err := getTestFileURL(r)
+	if err != nil {
+		logger.Debug("Bad value", zap.String("field", testFileURL), zap.Error(err))
+		return apisLoadTestV1.LoadTestSpec{}, fmt.Errorf("error getting %s from request: %w", testFileURL, err)
+	}
+
 	tf, err := getTestFile(r)
+	if err == http.ErrMissingFile {
+		// testFile is optional when the test file is fetched from testFileURL or the backend doesn't need one,
+		// checked once the backend is known
+		err = nil
+	}
 	if err != nil {
 		logger.Debug("Could not get file from request", zap.String("file", testFile), zap.Error(err))
 		return apisLoadTestV1.LoadTestSpec{}, fmt.Errorf("error getting %s from request: %w", testFile, err)
@@ -162,6 +216,56 @@ func fromHTTPRequestToLoadTestSpec(r *http.Request, logger *zap.Logger, allowedC
 		return apisLoadTestV1.LoadTestSpec{}, fmt.Errorf("error getting %s from request: %w", duration, err)
 	}
 
+	vu, err := getVUs(r)
+	if err != nil {
+		logger.Debug("Bad value", zap.String("field", vus), zap.Error(err))
+		return apisLoadTestV1.LoadTestSpec{}, fmt.Errorf("error getting %s from request: %w", vus, err)
+	}
+
+	conns, err := getPositiveInt32(r, connections, ErrWrongConnections)
+	if err != nil {
+		logger.Debug("Bad value", zap.String("field", connections), zap.Error(err))
+		return apisLoadTestV1.LoadTestSpec{}, fmt.Errorf("error getting %s from request: %w", connections, err)
+	}
+
+	thr, err := getPositiveInt32(r, threads, ErrWrongThreads)
+	if err != nil {
+		logger.Debug("Bad value", zap.String("field", threads), zap.Error(err))
+		return apisLoadTestV1.LoadTestSpec{}, fmt.Errorf("error getting %s from request: %w", threads, err)
+	}
+
+	tt, err := getTemplateTestFile(r)
+	if err != nil {
+		logger.Debug("Bad value: ", zap.String("field", templateFile), zap.Error(err))
+		return apisLoadTestV1.LoadTestSpec{}, fmt.Errorf("bad %s value: should be boolean", templateFile)
+	}
+
+	th, err := getThresholds(r)
+	if err != nil {
+		logger.Debug("Bad value", zap.String("field", thresholds), zap.Error(err))
+		return apisLoadTestV1.LoadTestSpec{}, fmt.Errorf("error getting %s from request: %w", thresholds, err)
+	}
+
+	rc, err := getRetentionClass(r)
+	if err != nil {
+		logger.Debug("Bad value", zap.String("field", retentionClass), zap.Error(err))
+		return apisLoadTestV1.LoadTestSpec{}, fmt.Errorf("error getting %s from request: %w", retentionClass, err)
+	}
+
+	ea := getArgs(r)
+
+	ct, err := getCleanupThreshold(r)
+	if err != nil {
+		logger.Debug("Bad value", zap.String("field", cleanupThreshold), zap.Error(err))
+		return apisLoadTestV1.LoadTestSpec{}, fmt.Errorf("error getting %s from request: %w", cleanupThreshold, err)
+	}
+
+	to, err := getTimeout(r)
+	if err != nil {
+		logger.Debug("Bad value", zap.String("field", timeout), zap.Error(err))
+		return apisLoadTestV1.LoadTestSpec{}, fmt.Errorf("error getting %s from request: %w", timeout, err)
+	}
+
 	mi := apisLoadTestV1.ImageDetails("")
 	wi := apisLoadTestV1.ImageDetails("")
 	if allowedCustomImages {
@@ -178,17 +282,33 @@ func fromHTTPRequestToLoadTestSpec(r *http.Request, logger *zap.Logger, allowedC
 	}
 
 	return apisLoadTestV1.LoadTestSpec{
-		Type:            lt,
-		Overwrite:       o,
-		MasterConfig:    mi,
-		WorkerConfig:    wi,
-		DistributedPods: &dp,
-		Tags:            tagList,
-		TestFile:        tf,
-		TestData:        td,
-		EnvVars:         ev,
-		TargetURL:       turl,
-		Duration:        dur,
+		Type:               lt,
+		Overwrite:          o,
+		MasterConfig:       mi,
+		WorkerConfig:       wi,
+		DistributedPods:    &dp,
+		Tags:               tagList,
+		TestFile:           tf,
+		TestFileURL:        tfURL,
+		TestData:           td,
+		EnvVars:            ev,
+		TargetURL:          turl,
+		Duration:           dur,
+		Rate:               r.FormValue(rate),
+		VUs:                vu,
+		Connections:        conns,
+		Threads:            thr,
+		DependsOn:          r.FormValue(dependsOn),
+		Resources:          getResources(r),
+		Thresholds:         th,
+		TemplateTestFile:   tt,
+		RetentionClass:     rc,
+		CleanupThreshold:   ct,
+		Timeout:            to,
+		Args:               ea,
+		ReportFormat:       r.FormValue(reportFormat),
+		ImagePullSecrets:   getRepeatedValues(r, imagePullSecrets),
+		ServiceAccountName: r.FormValue(serviceAccount),
 	}, nil
 }
 
@@ -316,6 +436,16 @@ func getOverwrite(r *http.Request) (bool, error) {
 	return overwrite, nil
 }
 
+func getTemplateTestFile(r *http.Request) (bool, error) {
+	t := r.FormValue(templateFile)
+
+	if t == "" {
+		return false, nil
+	}
+
+	return strconv.ParseBool(t)
+}
+
 func getLoadTestType(r *http.Request) (apisLoadTestV1.LoadTestType, error) {
 	ltType := r.FormValue(backendType)
 	if ltType == "" {
@@ -353,6 +483,34 @@ func getTargetURL(r *http.Request) (string, error) {
 	return targetURL, nil
 }
 
+func getTestFileURL(r *http.Request) (string, error) {
+	testFileURL := r.FormValue(testFileURL)
+
+	if testFileURL == "" {
+		return "", nil
+	}
+
+	u, err := url.Parse(testFileURL)
+	if err != nil {
+		return "", err
+	}
+
+	if u.Scheme == "" || u.Host == "" {
+		return "", ErrWrongURLFormat
+	}
+
+	switch u.Scheme {
+	case "http", "https":
+	case "s3", "gs":
+		if strings.Trim(u.Path, "/") == "" {
+			return "", ErrEmptyObjectKey
+		}
+	default:
+		return "", ErrWrongTestFileURLScheme
+	}
+	return testFileURL, nil
+}
+
 func getDuration(r *http.Request) (time.Duration, error) {
 	val := r.FormValue(duration)
 
@@ -363,6 +521,106 @@ func getDuration(r *http.Request) (time.Duration, error) {
 	return time.ParseDuration(val)
 }
 
+// getVUs returns the number of virtual users, 0 when not set
+func getVUs(r *http.Request) (int32, error) {
+	return getPositiveInt32(r, vus, ErrWrongVUs)
+}
+
+// getPositiveInt32 returns the positive integer of the field, 0 when not set, or errWrong when it is not one
+func getPositiveInt32(r *http.Request, field string, errWrong error) (int32, error) {
+	val := r.FormValue(field)
+
+	if val == "" {
+		return 0, nil
+	}
+
+	n, err := strconv.ParseInt(val, 10, 32)
+	if err != nil || n <= 0 {
+		return 0, errWrong
+	}
+	return int32(n), nil
+}
+
+// getResources returns the resources overriding the backend resources, nil if none is set
+func getResources(r *http.Request) *apisLoadTestV1.LoadTestResources {
+	resources := apisLoadTestV1.LoadTestResources{
+		CPULimits:      r.FormValue(cpuLimits),
+		CPURequests:    r.FormValue(cpuRequests),
+		MemoryLimits:   r.FormValue(memoryLimits),
+		MemoryRequests: r.FormValue(memoryRequests),
+	}
+	if resources == (apisLoadTestV1.LoadTestResources{}) {
+		return nil
+	}
+	return &resources
+}
+
+func getThresholds(r *http.Request) ([]string, error) {
+	return apisLoadTestV1.ThresholdsFromString(r.FormValue(thresholds))
+}
+
+// getRetentionClass returns the retention class of the report, empty if none is set
+func getRetentionClass(r *http.Request) (apisLoadTestV1.RetentionClass, error) {
+	class := apisLoadTestV1.RetentionClass(r.FormValue(retentionClass))
+	if class != "" && !class.IsValid() {
+		return "", ErrWrongRetentionClass
+	}
+	return class, nil
+}
+
+// getArgs returns the extra args of the load generator, given as repeated args fields
+func getArgs(r *http.Request) []string {
+	return getRepeatedValues(r, extraArgs)
+}
+
+// getRepeatedValues returns the non empty values of a field given several times
+func getRepeatedValues(r *http.Request, field string) []string {
+	// FormValue parses the form, which holds all values of the field
+	r.FormValue(field)
+
+	var values []string
+	for _, value := range r.Form[field] {
+		if value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// getCleanupThreshold returns the cleanup threshold overriding the one of the controller, nil if none is set
+func getCleanupThreshold(r *http.Request) (*time.Duration, error) {
+	val := r.FormValue(cleanupThreshold)
+	if val == "" {
+		return nil, nil
+	}
+
+	threshold, err := time.ParseDuration(val)
+	if err != nil {
+		return nil, err
+	}
+	if threshold < 0 {
+		return nil, ErrNegativeCleanupThreshold
+	}
+	return &threshold, nil
+}
+
+// getTimeout returns the timeout of the loadtest jobs, 0 if none is set
+func getTimeout(r *http.Request) (time.Duration, error) {
+	val := r.FormValue(timeout)
+	if val == "" {
+		return 0, nil
+	}
+
+	to, err := time.ParseDuration(val)
+	if err != nil {
+		return 0, err
+	}
+	if to < 0 {
+		return 0, ErrNegativeTimeout
+	}
+	return to, nil
+}
+
 func getImage(r *http.Request, role string) (apisLoadTestV1.ImageDetails, error) {
 	imageStr := r.FormValue(role)
 	return apisLoadTestV1.ImageDetails(imageStr), nil
@@ -3,6 +3,7 @@ package proxy
 import (
 	"bytes"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"testing"
 	"time"
@@ -482,6 +483,281 @@ func TestGetDuration(t *testing.T) {
 	}
 }
 
+func TestGetVUs(t *testing.T) {
+	for value, expected := range map[string]int32{"": 0, "50": 50} {
+		req := httptest.NewRequest(http.MethodPost, "/load-test", nil)
+		req.Form = url.Values{"vus": []string{value}}
+
+		actual, err := getVUs(req)
+		assert.NoError(t, err, value)
+		assert.Equal(t, expected, actual, value)
+	}
+
+	for _, value := range []string{"0", "-1", "ten", "3000000000"} {
+		req := httptest.NewRequest(http.MethodPost, "/load-test", nil)
+		req.Form = url.Values{"vus": []string{value}}
+
+		_, err := getVUs(req)
+		assert.ErrorIs(t, err, ErrWrongVUs, value)
+	}
+}
+
+func TestGetPositiveInt32(t *testing.T) {
+	for value, expected := range map[string]int32{"": 0, "100": 100} {
+		req := httptest.NewRequest(http.MethodPost, "/load-test", nil)
+		req.Form = url.Values{"connections": []string{value}}
+
+		actual, err := getPositiveInt32(req, connections, ErrWrongConnections)
+		assert.NoError(t, err, value)
+		assert.Equal(t, expected, actual, value)
+	}
+
+	for _, value := range []string{"0", "-1", "ten"} {
+		req := httptest.NewRequest(http.MethodPost, "/load-test", nil)
+		req.Form = url.Values{"threads": []string{value}}
+
+		_, err := getPositiveInt32(req, threads, ErrWrongThreads)
+		assert.ErrorIs(t, err, ErrWrongThreads, value)
+	}
+}
+
+func TestGetThresholds(t *testing.T) {
+	scenarios := []struct {
+		thresholds  string
+		expected    []string
+		expectError bool
+	}{
+		{
+			thresholds:  "p99 < 200ms, error_rate < 1%",
+			expected:    []string{"p99 < 200ms", "error_rate < 1%"},
+			expectError: false,
+		},
+		{
+			thresholds:  "p99 is fast",
+			expected:    nil,
+			expectError: true,
+		},
+		{
+			thresholds:  "",
+			expected:    nil,
+			expectError: false,
+		},
+	}
+
+	for _, scenario := range scenarios {
+		req, err := http.NewRequest("POST", "/load-test", new(bytes.Buffer))
+		if err != nil {
+			t.Error(err)
+			t.FailNow()
+		}
+
+		req.Form = url.Values{"thresholds": []string{scenario.thresholds}}
+		req.ParseForm()
+
+		actual, err := getThresholds(req)
+
+		if scenario.expectError {
+			assert.Error(t, err)
+		} else {
+			assert.NoError(t, err)
+		}
+
+		assert.Equal(t, scenario.expected, actual)
+	}
+}
+
+func TestGetResources(t *testing.T) {
+	scenarios := []struct {
+		form     url.Values
+		expected *apisLoadTestV1.LoadTestResources
+	}{
+		{
+			form:     url.Values{},
+			expected: nil,
+		},
+		{
+			form:     url.Values{"cpuLimits": []string{"2"}, "memoryRequests": []string{"1Gi"}},
+			expected: &apisLoadTestV1.LoadTestResources{CPULimits: "2", MemoryRequests: "1Gi"},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		req, err := http.NewRequest("POST", "/load-test", new(bytes.Buffer))
+		if err != nil {
+			t.Error(err)
+			t.FailNow()
+		}
+
+		req.Form = scenario.form
+		req.ParseForm()
+
+		assert.Equal(t, scenario.expected, getResources(req))
+	}
+}
+
+func TestGetRetentionClass(t *testing.T) {
+	scenarios := []struct {
+		retentionClass string
+		expected       apisLoadTestV1.RetentionClass
+		expectError    bool
+	}{
+		{
+			retentionClass: "",
+			expected:       "",
+		},
+		{
+			retentionClass: "long",
+			expected:       apisLoadTestV1.RetentionLong,
+		},
+		{
+			retentionClass: "forever",
+			expected:       "",
+			expectError:    true,
+		},
+	}
+
+	for _, scenario := range scenarios {
+		req, err := http.NewRequest("POST", "/load-test", new(bytes.Buffer))
+		require.NoError(t, err)
+
+		req.Form = url.Values{"retentionClass": []string{scenario.retentionClass}}
+		req.ParseForm()
+
+		actual, err := getRetentionClass(req)
+
+		if scenario.expectError {
+			assert.ErrorIs(t, err, ErrWrongRetentionClass)
+		} else {
+			assert.NoError(t, err)
+		}
+
+		assert.Equal(t, scenario.expected, actual)
+	}
+}
+
+func TestGetArgs(t *testing.T) {
+	for _, scenario := range []struct {
+		args     []string
+		expected []string
+	}{
+		{
+			args:     nil,
+			expected: nil,
+		},
+		{
+			args:     []string{""},
+			expected: nil,
+		},
+		{
+			args:     []string{"--insecure", "--connections=5"},
+			expected: []string{"--insecure", "--connections=5"},
+		},
+	} {
+		req, err := http.NewRequest("POST", "/load-test", new(bytes.Buffer))
+		require.NoError(t, err)
+
+		req.Form = url.Values{"args": scenario.args}
+
+		assert.Equal(t, scenario.expected, getArgs(req))
+	}
+}
+
+func TestGetImagePullSecrets(t *testing.T) {
+	req, err := http.NewRequest("POST", "/load-test", new(bytes.Buffer))
+	require.NoError(t, err)
+
+	req.Form = url.Values{"imagePullSecrets": []string{"registry-a", "", "registry-b"}}
+
+	assert.Equal(t, []string{"registry-a", "registry-b"}, getRepeatedValues(req, imagePullSecrets))
+}
+
+func TestGetCleanupThreshold(t *testing.T) {
+	day := 24 * time.Hour
+	never := time.Duration(0)
+
+	for _, scenario := range []struct {
+		cleanupThreshold string
+		expected         *time.Duration
+		expectError      bool
+	}{
+		{
+			cleanupThreshold: "",
+			expected:         nil,
+		},
+		{
+			cleanupThreshold: "24h",
+			expected:         &day,
+		},
+		{
+			cleanupThreshold: "0s",
+			expected:         &never,
+		},
+		{
+			cleanupThreshold: "-1h",
+			expectError:      true,
+		},
+		{
+			cleanupThreshold: "forever",
+			expectError:      true,
+		},
+	} {
+		req, err := http.NewRequest("POST", "/load-test", new(bytes.Buffer))
+		require.NoError(t, err)
+
+		req.Form = url.Values{"cleanupThreshold": []string{scenario.cleanupThreshold}}
+
+		actual, err := getCleanupThreshold(req)
+
+		if scenario.expectError {
+			assert.Error(t, err)
+		} else {
+			assert.NoError(t, err)
+		}
+
+		assert.Equal(t, scenario.expected, actual)
+	}
+}
+
+func TestGetTimeout(t *testing.T) {
+	for _, scenario := range []struct {
+		timeout     string
+		expected    time.Duration
+		expectError bool
+	}{
+		{
+			timeout:  "",
+			expected: 0,
+		},
+		{
+			timeout:  "30m",
+			expected: 30 * time.Minute,
+		},
+		{
+			timeout:     "-1h",
+			expectError: true,
+		},
+		{
+			timeout:     "forever",
+			expectError: true,
+		},
+	} {
+		req, err := http.NewRequest("POST", "/load-test", new(bytes.Buffer))
+		require.NoError(t, err)
+
+		req.Form = url.Values{"timeout": []string{scenario.timeout}}
+
+		actual, err := getTimeout(req)
+
+		if scenario.expectError {
+			assert.Error(t, err)
+		} else {
+			assert.NoError(t, err)
+		}
+
+		assert.Equal(t, scenario.expected, actual)
+	}
+}
+
 func TestGetTargetURL(t *testing.T) {
 	for _, ti := range []struct {
 		tag         string
@@ -532,6 +808,79 @@ func TestGetTargetURL(t *testing.T) {
 	}
 }
 
+func TestGetTestFileURL(t *testing.T) {
+	for _, ti := range []struct {
+		tag         string
+		testFileURL string
+		expected    string
+		expectError bool
+	}{
+		{
+			tag:         "empty testFileURL",
+			testFileURL: "",
+			expected:    "",
+			expectError: false,
+		},
+		{
+			tag:         "valid URL as testFileURL",
+			testFileURL: "https://raw.example.com/tests/config.json",
+			expected:    "https://raw.example.com/tests/config.json",
+			expectError: false,
+		},
+		{
+			tag:         "invalid URL without scheme",
+			testFileURL: "raw.example.com/tests/config.json",
+			expected:    "",
+			expectError: true,
+		},
+		{
+			tag:         "s3 URL as testFileURL",
+			testFileURL: "s3://bucket/tests/config.json",
+			expected:    "s3://bucket/tests/config.json",
+			expectError: false,
+		},
+		{
+			tag:         "gs URL as testFileURL",
+			testFileURL: "gs://bucket/config.json",
+			expected:    "gs://bucket/config.json",
+			expectError: false,
+		},
+		{
+			tag:         "object storage URL without key",
+			testFileURL: "s3://bucket/",
+			expected:    "",
+			expectError: true,
+		},
+		{
+			tag:         "unsupported scheme",
+			testFileURL: "ftp://example.com/config.json",
+			expected:    "",
+			expectError: true,
+		},
+	} {
+		t.Run(ti.tag, func(t *testing.T) {
+			req, err := http.NewRequest("POST", "/load-test", new(bytes.Buffer))
+			if err != nil {
+				t.Error(err)
+				t.FailNow()
+			}
+
+			req.Form = url.Values{"testFileURL": []string{ti.testFileURL}}
+			req.ParseForm()
+
+			actual, err := getTestFileURL(req)
+
+			if ti.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, ti.expected, actual)
+		})
+	}
+}
+
 func TestGetImage(t *testing.T) {
 	for _, ti := range []struct {
 		tag              string
@@ -35,7 +35,7 @@ func RunServer(cfg Config, rr Runner) error {
 		backends.WithLogger(rr.Logger),
 	)
 
-	proxyHandler := NewProxy(cfg.MaxLoadTestsRun, registry, rr.KubeClient, cfg.MaxListLimit, cfg.AllowedCustomImages)
+	proxyHandler := NewProxy(cfg.MaxLoadTestsRun, registry, rr.KubeClient, cfg.MaxListLimit, cfg.AllowedCustomImages, cfg.Dedup)
 
 	// Start instrumented server
 	r := chi.NewRouter()
@@ -77,6 +77,12 @@ func RunServer(cfg Config, rr Runner) error {
 		otelhttp.NewHandler(http.HandlerFunc(proxyHandler.Delete), loadtestRouteWithID),
 	)
 
+	backendsRoute := "/backends"
+	r.Method(http.MethodGet,
+		backendsRoute,
+		otelhttp.NewHandler(http.HandlerFunc(proxyHandler.Backends), backendsRoute),
+	)
+
 	// ---------------------------------------------------------------------- //
 	// LoadTest API Documentation
 	// ---------------------------------------------------------------------- //
@@ -95,6 +101,9 @@ func RunServer(cfg Config, rr Runner) error {
 	})
 	r.Get("/load-test/{id}/report/*", report.ShowHandler())
 	r.Put("/load-test/{id}/report", report.PersistHandler(rr.KubeClient, rr.Logger))
+	r.Delete("/load-test/{id}/report", report.DeleteHandler(rr.KubeClient, rr.Logger))
+	r.Get("/load-test-group/{group}", report.GroupIndexShowHandler(rr.Logger))
+	r.Put("/load-test-group/{group}/index", report.GroupIndexPersistHandler(rr.Logger))
 
 	address := fmt.Sprintf(":%d", cfg.HTTPPort)
 	rr.Logger.Info("Running HTTP server...", zap.String("address", address))
@@ -0,0 +1,147 @@
+package report
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"sync"
+	"time"
+)
+
+// errReportTooLarge is returned by report fetches for objects larger than the cache, they are streamed instead
+var errReportTooLarge = errors.New("report is too large to be cached")
+
+// reportObject is a report fetched from object storage
+type reportObject struct {
+	key         string
+	contentType string
+	modTime     time.Time
+	// notFound tells that there is no object for the report, it may be stored as a directory
+	notFound bool
+
+	// content is set for cached reports, object for streamed ones
+	content []byte
+	object  io.ReadSeeker
+}
+
+// reader returns a new reader of the report content
+func (o *reportObject) reader() io.ReadSeeker {
+	if o.object != nil {
+		return o.object
+	}
+	return bytes.NewReader(o.content)
+}
+
+// reportCache keeps report objects in memory for a short time, concurrent requests for a report
+// that is not cached yet share a single fetch
+type reportCache struct {
+	ttl     time.Duration
+	maxSize int64
+	now     func() time.Time
+
+	mu      sync.Mutex
+	size    int64
+	entries map[string]*reportCacheEntry
+}
+
+type reportCacheEntry struct {
+	// ready is closed once the fetch of the entry is done
+	ready   chan struct{}
+	report  *reportObject
+	err     error
+	expires time.Time
+}
+
+func newReportCache(ttl time.Duration, maxSize int64) *reportCache {
+	return &reportCache{
+		ttl:     ttl,
+		maxSize: maxSize,
+		now:     time.Now,
+		entries: map[string]*reportCacheEntry{},
+	}
+}
+
+// get returns the cached report of the given key or fetches it. Failed fetches are shared with the
+// requests waiting for them but are not cached.
+func (c *reportCache) get(key string, fetch func() (*reportObject, error)) (*reportObject, error) {
+	c.mu.Lock()
+	if e, ok := c.entries[key]; ok {
+		select {
+		case <-e.ready:
+			if c.now().Before(e.expires) {
+				c.mu.Unlock()
+				return e.report, nil
+			}
+			c.remove(key)
+		default:
+			c.mu.Unlock()
+			<-e.ready
+			return e.report, e.err
+		}
+	}
+
+	e := &reportCacheEntry{ready: make(chan struct{})}
+	c.entries[key] = e
+	c.mu.Unlock()
+
+	e.report, e.err = fetch()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	defer close(e.ready)
+
+	if e.err != nil {
+		delete(c.entries, key)
+		return e.report, e.err
+	}
+
+	e.expires = c.now().Add(c.ttl)
+	c.size += int64(len(e.report.content))
+	c.evict()
+	return e.report, nil
+}
+
+// invalidate drops the entry of the given key, e.g. once its report was deleted
+func (c *reportCache) invalidate(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.remove(key)
+}
+
+// remove drops the entry of the given key, c.mu must be held
+func (c *reportCache) remove(key string) {
+	if e, ok := c.entries[key]; ok {
+		if e.report != nil {
+			c.size -= int64(len(e.report.content))
+		}
+		delete(c.entries, key)
+	}
+}
+
+// evict drops expired entries and then the entries closest to expiry until the cache fits maxSize, c.mu must be held
+func (c *reportCache) evict() {
+	now := c.now()
+	for key, e := range c.entries {
+		if !e.expires.IsZero() && !now.Before(e.expires) {
+			c.remove(key)
+		}
+	}
+
+	for c.size > c.maxSize {
+		oldest := ""
+		for key, e := range c.entries {
+			if e.expires.IsZero() {
+				// fetch in flight
+				continue
+			}
+			if oldest == "" || e.expires.Before(c.entries[oldest].expires) {
+				oldest = key
+			}
+		}
+		if oldest == "" {
+			return
+		}
+		c.remove(oldest)
+	}
+}
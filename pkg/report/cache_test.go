@@ -0,0 +1,107 @@
+package report
+
+import (
+	"errors"
+	"io"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReportCacheSharesConcurrentFetches(t *testing.T) {
+	c := newReportCache(time.Minute, 1024)
+
+	var fetches int32
+	release := make(chan struct{})
+	fetch := func() (*reportObject, error) {
+		atomic.AddInt32(&fetches, 1)
+		<-release
+		return &reportObject{key: "loadtest-name", content: []byte("report")}, nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			obj, err := c.get("loadtest-name", fetch)
+			assert.NoError(t, err)
+			content, err := io.ReadAll(obj.reader())
+			assert.NoError(t, err)
+			assert.Equal(t, "report", string(content))
+		}()
+	}
+
+	// wait for the first fetch to start before releasing it
+	for atomic.LoadInt32(&fetches) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&fetches))
+}
+
+func TestReportCacheExpires(t *testing.T) {
+	now := time.Now()
+	c := newReportCache(time.Minute, 1024)
+	c.now = func() time.Time { return now }
+
+	var fetches int
+	fetch := func() (*reportObject, error) {
+		fetches++
+		return &reportObject{content: []byte("report")}, nil
+	}
+
+	_, err := c.get("loadtest-name", fetch)
+	require.NoError(t, err)
+	_, err = c.get("loadtest-name", fetch)
+	require.NoError(t, err)
+	assert.Equal(t, 1, fetches)
+
+	now = now.Add(2 * time.Minute)
+	_, err = c.get("loadtest-name", fetch)
+	require.NoError(t, err)
+	assert.Equal(t, 2, fetches)
+	assert.Equal(t, int64(len("report")), c.size)
+}
+
+func TestReportCacheDoesNotCacheErrors(t *testing.T) {
+	c := newReportCache(time.Minute, 1024)
+
+	fetchErr := errors.New("storage unavailable")
+	_, err := c.get("loadtest-name", func() (*reportObject, error) {
+		return nil, fetchErr
+	})
+	assert.Equal(t, fetchErr, err)
+
+	obj, err := c.get("loadtest-name", func() (*reportObject, error) {
+		return &reportObject{content: []byte("report")}, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []byte("report"), obj.content)
+}
+
+func TestReportCacheEvictsToMaxSize(t *testing.T) {
+	now := time.Now()
+	c := newReportCache(time.Minute, 10)
+	c.now = func() time.Time { return now }
+
+	for _, key := range []string{"first", "second", "third"} {
+		_, err := c.get(key, func() (*reportObject, error) {
+			return &reportObject{content: []byte("12345")}, nil
+		})
+		require.NoError(t, err)
+		now = now.Add(time.Second)
+	}
+
+	assert.Equal(t, int64(10), c.size)
+	assert.NotContains(t, c.entries, "first")
+	assert.Contains(t, c.entries, "second")
+	assert.Contains(t, c.entries, "third")
+}
@@ -0,0 +1,264 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+)
+
+// Report storage cap policies, enforced per team
+const (
+	// CapPolicyNone does not cap the report storage of teams, reports are not tracked
+	CapPolicyNone = "none"
+	// CapPolicyDeleteOldest deletes the oldest reports of a team once a new report exceeds its cap
+	CapPolicyDeleteOldest = "delete-oldest"
+	// CapPolicyReject refuses new reports of a team that exceeds its cap
+	CapPolicyReject = "reject"
+)
+
+// usageIndexPrefix is the prefix of the objects indexing the reports of each team
+const usageIndexPrefix = ".kangal/usage/"
+
+var (
+	// ErrUnknownCapPolicy is returned for report storage cap policies that don't exist
+	ErrUnknownCapPolicy = errors.New("unknown report storage cap policy")
+	// ErrTeamCapExceeded is returned when the reports of a team exceed its report storage cap
+	ErrTeamCapExceeded = errors.New("report storage cap of the team is exceeded")
+)
+
+// ReportEntry is a persisted report accounted to a team
+type ReportEntry struct {
+	Name string    `json:"name"`
+	Size int64     `json:"size"`
+	Time time.Time `json:"time"`
+}
+
+// CapPolicy enforces the report storage cap of teams
+type CapPolicy interface {
+	// Admit is called before a report of a team with the given usage in bytes is persisted,
+	// an error rejects the report
+	Admit(usage int64) error
+	// Evict returns the reports to delete after a report of a team was persisted
+	Evict(reports []ReportEntry) []ReportEntry
+}
+
+// NewCapPolicy returns the report storage cap policy of the given name capping teams to limit bytes,
+// the none policy returns nil
+func NewCapPolicy(name string, limit int64) (CapPolicy, error) {
+	switch name {
+	case CapPolicyNone, "":
+		return nil, nil
+	case CapPolicyDeleteOldest:
+		return deleteOldestPolicy{limit: limit}, nil
+	case CapPolicyReject:
+		return rejectPolicy{limit: limit}, nil
+	}
+	return nil, fmt.Errorf("%w: %q", ErrUnknownCapPolicy, name)
+}
+
+type deleteOldestPolicy struct {
+	limit int64
+}
+
+// Admit accepts all reports, the cap is enforced by Evict
+func (deleteOldestPolicy) Admit(int64) error {
+	return nil
+}
+
+// Evict returns the oldest reports until the others fit the cap, the newest report is always kept
+func (p deleteOldestPolicy) Evict(reports []ReportEntry) []ReportEntry {
+	sorted := append([]ReportEntry{}, reports...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Time.Before(sorted[j].Time)
+	})
+
+	size := usageSize(sorted)
+	var evicted []ReportEntry
+	for len(sorted) > 1 && size > p.limit {
+		evicted = append(evicted, sorted[0])
+		size -= sorted[0].Size
+		sorted = sorted[1:]
+	}
+	return evicted
+}
+
+type rejectPolicy struct {
+	limit int64
+}
+
+// Admit rejects reports of teams whose usage reached the cap
+func (p rejectPolicy) Admit(usage int64) error {
+	if usage >= p.limit {
+		return fmt.Errorf("%w: %d bytes used, cap is %d bytes", ErrTeamCapExceeded, usage, p.limit)
+	}
+	return nil
+}
+
+// Evict keeps all reports, the cap is enforced by Admit
+func (rejectPolicy) Evict([]ReportEntry) []ReportEntry {
+	return nil
+}
+
+func usageSize(reports []ReportEntry) int64 {
+	var size int64
+	for _, r := range reports {
+		size += r.Size
+	}
+	return size
+}
+
+// teamUsage is the index of the reports of a team, stored as a JSON object in the report bucket
+type teamUsage struct {
+	Reports []ReportEntry `json:"reports"`
+}
+
+// add accounts the given report to the team, replacing a previous report with the same name
+func (u *teamUsage) add(entry ReportEntry) {
+	u.remove(entry.Name)
+	u.Reports = append(u.Reports, entry)
+}
+
+// remove drops the report of the given name from the team
+func (u *teamUsage) remove(name string) {
+	reports := u.Reports[:0]
+	for _, r := range u.Reports {
+		if r.Name != name {
+			reports = append(reports, r)
+		}
+	}
+	u.Reports = reports
+}
+
+// teamUsageBytes holds the last known report storage usage of each team, reported as metric
+var teamUsageBytes = struct {
+	sync.Mutex
+	teams map[string]int64
+}{teams: map[string]int64{}}
+
+// TeamUsage returns the last known report storage usage in bytes of each team
+func TeamUsage() map[string]int64 {
+	teamUsageBytes.Lock()
+	defer teamUsageBytes.Unlock()
+
+	usage := make(map[string]int64, len(teamUsageBytes.teams))
+	for team, size := range teamUsageBytes.teams {
+		usage[team] = size
+	}
+	return usage
+}
+
+func setTeamUsage(team string, size int64) {
+	teamUsageBytes.Lock()
+	defer teamUsageBytes.Unlock()
+
+	teamUsageBytes.teams[team] = size
+}
+
+// loadTeamUsage reads the report index of the team, a missing index is an empty one
+func loadTeamUsage(ctx context.Context, team string) (*teamUsage, error) {
+	obj, err := minioClient.GetObject(ctx, bucketName, usageIndexPrefix+team, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer obj.Close()
+
+	content, err := io.ReadAll(obj)
+	if objErr := minio.ToErrorResponse(err); objErr.StatusCode == http.StatusNotFound {
+		return &teamUsage{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	usage := &teamUsage{}
+	if err := json.Unmarshal(content, usage); err != nil {
+		return nil, fmt.Errorf("invalid report index of team %q: %w", team, err)
+	}
+	setTeamUsage(team, usageSize(usage.Reports))
+	return usage, nil
+}
+
+// saveTeamUsage writes the report index of the team
+func saveTeamUsage(ctx context.Context, team string, usage *teamUsage) error {
+	content, err := json.Marshal(usage)
+	if err != nil {
+		return err
+	}
+
+	_, err = minioClient.PutObject(ctx, bucketName, usageIndexPrefix+team, bytes.NewReader(content), int64(len(content)), minio.PutObjectOptions{
+		ContentType: "application/json",
+	})
+	if err != nil {
+		return err
+	}
+	setTeamUsage(team, usageSize(usage.Reports))
+	return nil
+}
+
+// admitReport checks the report storage cap of the team before one of its reports is persisted
+func admitReport(ctx context.Context, team string) error {
+	if capPolicy == nil || team == "" {
+		return nil
+	}
+
+	usage, err := loadTeamUsage(ctx, team)
+	if err != nil {
+		return err
+	}
+	return capPolicy.Admit(usageSize(usage.Reports))
+}
+
+// untrackReport removes the deleted report from the storage usage of the team
+func untrackReport(ctx context.Context, team, loadTestName string) error {
+	if capPolicy == nil || team == "" {
+		return nil
+	}
+
+	usage, err := loadTeamUsage(ctx, team)
+	if err != nil {
+		return err
+	}
+	usage.remove(loadTestName)
+
+	return saveTeamUsage(ctx, team, usage)
+}
+
+// trackReport accounts the persisted report to the team and deletes the reports evicted by the cap policy.
+// Concurrent uploads of the same team may overwrite each other's index update, the index is then corrected
+// by the next upload of the report.
+func trackReport(ctx context.Context, team, loadTestName string) ([]ReportEntry, error) {
+	if capPolicy == nil || team == "" {
+		return nil, nil
+	}
+
+	stat, err := minioClient.StatObject(ctx, bucketName, loadTestName, minio.StatObjectOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	usage, err := loadTeamUsage(ctx, team)
+	if err != nil {
+		return nil, err
+	}
+	usage.add(ReportEntry{Name: loadTestName, Size: stat.Size, Time: stat.LastModified})
+
+	evicted := capPolicy.Evict(usage.Reports)
+	for _, r := range evicted {
+		err := minioClient.RemoveObject(ctx, bucketName, r.Name, minio.RemoveObjectOptions{})
+		if err != nil && minio.ToErrorResponse(err).StatusCode != http.StatusNotFound {
+			return nil, fmt.Errorf("could not delete report %q: %w", r.Name, err)
+		}
+		usage.remove(r.Name)
+	}
+
+	return evicted, saveTeamUsage(ctx, team, usage)
+}
@@ -0,0 +1,64 @@
+package report
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCapPolicy(t *testing.T) {
+	policy, err := NewCapPolicy(CapPolicyNone, 100)
+	require.NoError(t, err)
+	assert.Nil(t, policy)
+
+	policy, err = NewCapPolicy(CapPolicyDeleteOldest, 100)
+	require.NoError(t, err)
+	assert.NotNil(t, policy)
+
+	policy, err = NewCapPolicy(CapPolicyReject, 100)
+	require.NoError(t, err)
+	assert.NotNil(t, policy)
+
+	_, err = NewCapPolicy("delete-newest", 100)
+	assert.ErrorIs(t, err, ErrUnknownCapPolicy)
+}
+
+func TestDeleteOldestPolicy(t *testing.T) {
+	now := time.Now()
+	reports := []ReportEntry{
+		{Name: "second", Size: 40, Time: now.Add(-time.Hour)},
+		{Name: "newest", Size: 40, Time: now},
+		{Name: "oldest", Size: 40, Time: now.Add(-2 * time.Hour)},
+	}
+
+	policy := deleteOldestPolicy{limit: 100}
+	assert.NoError(t, policy.Admit(1000))
+	assert.Equal(t, []ReportEntry{reports[2]}, policy.Evict(reports))
+
+	// the newest report is kept even when it exceeds the cap alone
+	policy = deleteOldestPolicy{limit: 10}
+	assert.Equal(t, []ReportEntry{reports[2], reports[0]}, policy.Evict(reports))
+
+	policy = deleteOldestPolicy{limit: 120}
+	assert.Empty(t, policy.Evict(reports))
+}
+
+func TestRejectPolicy(t *testing.T) {
+	policy := rejectPolicy{limit: 100}
+	assert.NoError(t, policy.Admit(99))
+	assert.ErrorIs(t, policy.Admit(100), ErrTeamCapExceeded)
+	assert.Empty(t, policy.Evict([]ReportEntry{{Name: "report", Size: 1000}}))
+}
+
+func TestTeamUsage(t *testing.T) {
+	usage := &teamUsage{}
+	usage.add(ReportEntry{Name: "first", Size: 10})
+	usage.add(ReportEntry{Name: "second", Size: 20})
+	usage.add(ReportEntry{Name: "first", Size: 30})
+	assert.Equal(t, int64(50), usageSize(usage.Reports))
+
+	usage.remove("second")
+	assert.Equal(t, []ReportEntry{{Name: "first", Size: 30}}, usage.Reports)
+}
@@ -1,5 +1,7 @@
 package report
 
+import "time"
+
 // Config is the report package related basic config
 type Config struct {
 	// S3 compatible configuration access keys and endpoints needed to store load test reports
@@ -10,4 +12,22 @@ type Config struct {
 	AWSBucketName       string `envconfig:"AWS_BUCKET_NAME" default:""`
 	AWSUseHTTPS         bool   `envconfig:"AWS_USE_HTTPS" default:"false"`
 	AWSPresignedExpires string `envconfig:"AWS_PRESIGNED_EXPIRES" default:""`
+
+	// CacheTTL is the time reports are kept in memory to serve repeated requests, 0 disables the cache
+	CacheTTL time.Duration `envconfig:"REPORT_CACHE_TTL" default:"0s"`
+	// CacheMaxSize is the total size in bytes of the cached reports, larger reports are never cached
+	CacheMaxSize int64 `envconfig:"REPORT_CACHE_MAX_SIZE" default:"67108864"`
+
+	// TeamTag is the loadtest tag holding the team a report is accounted to
+	TeamTag string `envconfig:"REPORT_TEAM_TAG" default:"team"`
+	// TeamCap is the total size in bytes of the reports of a team, enforced with TeamCapPolicy
+	TeamCap int64 `envconfig:"REPORT_TEAM_CAP" default:"0"`
+	// TeamCapPolicy is one of none, delete-oldest or reject, none does not track reports per team
+	TeamCapPolicy string `envconfig:"REPORT_TEAM_CAP_POLICY" default:"none"`
+
+	// RetentionTag is the object tag reports are tagged with on upload, with the value RetentionClasses maps
+	// the retention class of the loadtest to, empty disables the tagging
+	RetentionTag string `envconfig:"REPORT_RETENTION_TAG" default:""`
+	// RetentionClasses maps the retention classes short, standard and long to the value of RetentionTag
+	RetentionClasses map[string]string `envconfig:"REPORT_RETENTION_CLASSES" default:"short:short,standard:standard,long:long"`
 }
@@ -0,0 +1,196 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/minio/minio-go/v7"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	khttp "github.com/hellofresh/kangal/pkg/core/http"
+)
+
+// groupIndexPrefix is the prefix of the objects indexing the reports of each group of loadtests
+const groupIndexPrefix = ".kangal/groups/"
+
+// GroupEntry is a loadtest of a group, listed with its report in the index of the group
+type GroupEntry struct {
+	Name           string    `json:"name"`
+	Phase          string    `json:"phase"`
+	ReportURL      string    `json:"reportURL,omitempty"`
+	CompletionTime time.Time `json:"completionTime"`
+}
+
+// groupIndex is the index of the loadtests of a group, stored as a JSON object in the report bucket
+type groupIndex struct {
+	LoadTests []GroupEntry `json:"loadTests"`
+}
+
+// add lists the given loadtest in the group, replacing a previous entry with the same name,
+// the loadtests are kept sorted by name
+func (g *groupIndex) add(entry GroupEntry) {
+	loadTests := g.LoadTests[:0]
+	for _, e := range g.LoadTests {
+		if e.Name != entry.Name {
+			loadTests = append(loadTests, e)
+		}
+	}
+	g.LoadTests = append(loadTests, entry)
+	sort.Slice(g.LoadTests, func(i, j int) bool {
+		return g.LoadTests[i].Name < g.LoadTests[j].Name
+	})
+}
+
+// validGroup returns an error when the group can't be used in the key of its index object,
+// groups are loadtest tag values and must be valid label values
+func validGroup(group string) error {
+	if group == "" {
+		return errors.New("group is empty")
+	}
+	if errs := validation.IsValidLabelValue(group); len(errs) > 0 {
+		return fmt.Errorf("invalid group %q: %s", group, strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+// loadGroupIndex reads the index of the group, a missing index is an empty one
+func loadGroupIndex(ctx context.Context, group string) (*groupIndex, error) {
+	obj, err := minioClient.GetObject(ctx, bucketName, groupIndexPrefix+group, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer obj.Close()
+
+	content, err := io.ReadAll(obj)
+	if objErr := minio.ToErrorResponse(err); objErr.StatusCode == http.StatusNotFound {
+		return &groupIndex{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	index := &groupIndex{}
+	if err := json.Unmarshal(content, index); err != nil {
+		return nil, fmt.Errorf("invalid report index of group %q: %w", group, err)
+	}
+	return index, nil
+}
+
+// saveGroupIndex writes the index of the group
+func saveGroupIndex(ctx context.Context, group string, index *groupIndex) error {
+	content, err := json.Marshal(index)
+	if err != nil {
+		return err
+	}
+
+	_, err = minioClient.PutObject(ctx, bucketName, groupIndexPrefix+group, bytes.NewReader(content), int64(len(content)), minio.PutObjectOptions{
+		ContentType: "application/json",
+	})
+	return err
+}
+
+// groupIndexTemplate renders the index of a group as HTML page linking the report of each loadtest
+var groupIndexTemplate = template.Must(template.New("group").Parse(`<!DOCTYPE html>
+<html>
+<head><title>Load tests of {{.Group}}</title></head>
+<body>
+<h1>Load tests of {{.Group}}</h1>
+<table>
+<tr><th>Load test</th><th>Phase</th><th>Completed</th></tr>
+{{- range .LoadTests}}
+<tr><td>{{if .ReportURL}}<a href="{{.ReportURL}}/">{{.Name}}</a>{{else}}{{.Name}}{{end}}</td><td>{{.Phase}}</td><td>{{.CompletionTime.UTC.Format "2006-01-02T15:04:05Z07:00"}}</td></tr>
+{{- end}}
+</table>
+</body>
+</html>
+`))
+
+func renderGroupIndex(w io.Writer, group string, index *groupIndex) error {
+	return groupIndexTemplate.Execute(w, struct {
+		Group     string
+		LoadTests []GroupEntry
+	}{Group: group, LoadTests: index.LoadTests})
+}
+
+// GroupIndexPersistHandler lists the loadtest of the request body in the index of its group. Concurrent updates of
+// the same group may overwrite each other, the controller updates the index of a loadtest once it is finished.
+func GroupIndexPersistHandler(logger *zap.Logger) func(w http.ResponseWriter, r *http.Request) {
+	if minioClient == nil {
+		panic("client was not initialized, please initialize object storage client")
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		group := chi.URLParam(r, "group")
+		if err := validGroup(group); err != nil {
+			render.Render(w, r, khttp.ErrResponse(http.StatusBadRequest, err.Error()))
+			return
+		}
+
+		var entry GroupEntry
+		if err := json.NewDecoder(r.Body).Decode(&entry); err != nil || entry.Name == "" {
+			render.Render(w, r, khttp.ErrResponse(http.StatusBadRequest, "body must be a load test with a name"))
+			return
+		}
+
+		index, err := loadGroupIndex(r.Context(), group)
+		if err == nil {
+			index.add(entry)
+			err = saveGroupIndex(r.Context(), group, index)
+		}
+		if err != nil {
+			logger.Error("Failed to update report index of group", zap.Error(err),
+				zap.String("group", group), zap.String("loadtest", entry.Name))
+			render.Render(w, r, khttp.ErrResponse(http.StatusInternalServerError, err.Error()))
+			return
+		}
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, "Group index updated")
+	}
+}
+
+// GroupIndexShowHandler serves the index of a group as HTML page linking the reports of its loadtests
+func GroupIndexShowHandler(logger *zap.Logger) func(w http.ResponseWriter, r *http.Request) {
+	if minioClient == nil {
+		panic("client was not initialized, please initialize object storage client")
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		group := chi.URLParam(r, "group")
+		if err := validGroup(group); err != nil {
+			render.Render(w, r, khttp.ErrResponse(http.StatusBadRequest, err.Error()))
+			return
+		}
+
+		index, err := loadGroupIndex(r.Context(), group)
+		if err != nil {
+			logger.Error("Failed to read report index of group", zap.Error(err), zap.String("group", group))
+			render.Render(w, r, khttp.ErrResponse(http.StatusInternalServerError, err.Error()))
+			return
+		}
+		if len(index.LoadTests) == 0 {
+			render.Render(w, r, khttp.ErrResponse(http.StatusNotFound, fmt.Sprintf("no load test of group %q finished yet", group)))
+			return
+		}
+
+		var page bytes.Buffer
+		if err := renderGroupIndex(&page, group, index); err != nil {
+			render.Render(w, r, khttp.ErrResponse(http.StatusInternalServerError, err.Error()))
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write(page.Bytes())
+	}
+}
@@ -0,0 +1,43 @@
+package report
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGroupIndexAdd(t *testing.T) {
+	index := &groupIndex{}
+	index.add(GroupEntry{Name: "smoke", Phase: "errored"})
+	index.add(GroupEntry{Name: "baseline", Phase: "finished"})
+	index.add(GroupEntry{Name: "smoke", Phase: "finished"})
+
+	assert.Equal(t, []GroupEntry{
+		{Name: "baseline", Phase: "finished"},
+		{Name: "smoke", Phase: "finished"},
+	}, index.LoadTests)
+}
+
+func TestValidGroup(t *testing.T) {
+	assert.NoError(t, validGroup("rc-1.2.3"))
+	assert.Error(t, validGroup(""))
+	assert.Error(t, validGroup("../usage"))
+}
+
+func TestRenderGroupIndex(t *testing.T) {
+	completionTime := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+	index := &groupIndex{LoadTests: []GroupEntry{
+		{Name: "baseline", Phase: "finished", ReportURL: "http://kangal-proxy/load-test/baseline/report", CompletionTime: completionTime},
+		{Name: "smoke", Phase: "errored", CompletionTime: completionTime},
+	}}
+
+	var page bytes.Buffer
+	require.NoError(t, renderGroupIndex(&page, "rc-1.2.3", index))
+
+	assert.Contains(t, page.String(), "<h1>Load tests of rc-1.2.3</h1>")
+	assert.Contains(t, page.String(), `<a href="http://kangal-proxy/load-test/baseline/report/">baseline</a>`)
+	assert.Contains(t, page.String(), "<td>smoke</td><td>errored</td><td>2021-03-04T05:06:07Z</td>")
+}
@@ -2,8 +2,11 @@ package report
 
 import (
 	"archive/tar"
+	"context"
+	"errors"
 	"fmt"
 	"io"
+	"mime"
 	"net/http"
 	"os"
 	"strings"
@@ -55,28 +58,22 @@ func ShowHandler() func(w http.ResponseWriter, r *http.Request) {
 		}
 
 		// first, try to handle uncompressed tar archive
-		obj, err := minioClient.GetObject(ctx, bucketName, loadTestName, minio.GetObjectOptions{})
+		obj, err := getReport(ctx, loadTestName)
 		if nil != err {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
 
-		objStat, err := obj.Stat()
 		// not found error can be a directory
-		if objErr, _ := err.(minio.ErrorResponse); http.StatusNotFound == objErr.StatusCode {
+		if obj.notFound {
 			http.FileServer(fs).ServeHTTP(w, r)
 			return
 		}
-		// unknown error
-		if nil != err {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
 
 		// serve uncompressed tar archive content
-		if objStat.ContentType == "application/x-tar" {
+		if obj.contentType == "application/x-tar" {
 			prefix := fmt.Sprintf("%s/%s", tmpDir, loadTestName)
-			err = untar(prefix, obj, afero.NewOsFs())
+			err = untar(prefix, obj.reader(), afero.NewOsFs())
 			if nil != err {
 				http.Error(w, err.Error(), http.StatusInternalServerError)
 				return
@@ -86,9 +83,82 @@ func ShowHandler() func(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
-		// serve existing static file
-		http.ServeContent(w, r, objStat.Key, objStat.LastModified, obj)
+		// serve existing static file, with the content type it was uploaded with, e.g. for JSON or CSV reports
+		if contentType := servedContentType(obj.contentType); contentType != "" {
+			w.Header().Set("Content-Type", contentType)
+		}
+		http.ServeContent(w, r, obj.key, obj.modTime, obj.reader())
+	}
+}
+
+// servedContentType returns the content type a report is served with, empty when the report was stored
+// without a specific content type and it must be detected from its content instead
+func servedContentType(contentType string) string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return ""
+	}
+
+	switch mediaType {
+	case "application/octet-stream", "binary/octet-stream":
+		return ""
+	}
+	return contentType
+}
+
+// getReport returns the report object of the given loadtest, from the report cache when it is enabled
+func getReport(ctx context.Context, loadTestName string) (*reportObject, error) {
+	if cache == nil {
+		return fetchReport(ctx, loadTestName, -1)
+	}
+
+	// the fetch is shared with concurrent requests, so it must not be canceled with the first one
+	obj, err := cache.get(loadTestName, func() (*reportObject, error) {
+		return fetchReport(context.WithoutCancel(ctx), loadTestName, cache.maxSize)
+	})
+	if errors.Is(err, errReportTooLarge) {
+		return fetchReport(ctx, loadTestName, -1)
 	}
+	return obj, err
+}
+
+// fetchReport gets the report object of the given loadtest from object storage. The content of reports up to
+// maxSize bytes is read into memory, larger reports fail with errReportTooLarge. A negative maxSize
+// streams the content from object storage instead.
+func fetchReport(ctx context.Context, loadTestName string, maxSize int64) (*reportObject, error) {
+	obj, err := minioClient.GetObject(ctx, bucketName, loadTestName, minio.GetObjectOptions{})
+	if nil != err {
+		return nil, err
+	}
+
+	objStat, err := obj.Stat()
+	if objErr, _ := err.(minio.ErrorResponse); http.StatusNotFound == objErr.StatusCode {
+		return &reportObject{notFound: true}, nil
+	}
+	if nil != err {
+		return nil, err
+	}
+
+	result := &reportObject{
+		key:         objStat.Key,
+		contentType: objStat.ContentType,
+		modTime:     objStat.LastModified,
+	}
+
+	if maxSize < 0 {
+		result.object = obj
+		return result, nil
+	}
+
+	defer obj.Close()
+	if objStat.Size > maxSize {
+		return nil, errReportTooLarge
+	}
+	result.content, err = io.ReadAll(obj)
+	if nil != err {
+		return nil, err
+	}
+	return result, nil
 }
 
 func untar(prefix string, obj io.Reader, afs afero.Fs) error {
@@ -140,7 +210,7 @@ func PersistHandler(kubeClient *kk8s.Client, logger *zap.Logger) func(w http.Res
 	return func(w http.ResponseWriter, r *http.Request) {
 		loadTestName := chi.URLParam(r, "id")
 
-		_, err := kubeClient.GetLoadTest(r.Context(), loadTestName)
+		loadTest, err := kubeClient.GetLoadTest(r.Context(), loadTestName)
 		if k8sAPIErrors.IsNotFound(err) {
 			render.Render(w, r, khttp.ErrResponse(http.StatusNotFound, err.Error()))
 			return
@@ -150,6 +220,19 @@ func PersistHandler(kubeClient *kk8s.Client, logger *zap.Logger) func(w http.Res
 			return
 		}
 
+		team := loadTest.Spec.Tags[teamTag]
+		err = admitReport(r.Context(), team)
+		if errors.Is(err, ErrTeamCapExceeded) {
+			logger.Warn("Rejecting report of team exceeding its report storage cap",
+				zap.String("loadtest", loadTestName), zap.String("team", team), zap.Error(err))
+			render.Render(w, r, khttp.ErrResponse(http.StatusInsufficientStorage, err.Error()))
+			return
+		}
+		if err != nil {
+			render.Render(w, r, khttp.ErrResponse(http.StatusInternalServerError, err.Error()))
+			return
+		}
+
 		url, err := newPreSignedPutURL(r.Context(), loadTestName)
 		if nil != err {
 			render.Render(w, r, khttp.ErrResponse(http.StatusInternalServerError, err.Error()))
@@ -179,7 +262,57 @@ func PersistHandler(kubeClient *kk8s.Client, logger *zap.Logger) func(w http.Res
 			return
 		}
 
+		// the report is persisted, failing to tag it or to account it to its team must not fail the upload
+		if err := tagReport(r.Context(), loadTest); err != nil {
+			logger.Error("Failed to tag report with its retention class", zap.Error(err), zap.String("loadtest", loadTestName))
+		}
+		evicted, err := trackReport(r.Context(), team, loadTestName)
+		if err != nil {
+			logger.Error("Failed to track report storage of team", zap.Error(err),
+				zap.String("loadtest", loadTestName), zap.String("team", team))
+		}
+		for _, e := range evicted {
+			logger.Info("Deleted report of team exceeding its report storage cap",
+				zap.String("loadtest", e.Name), zap.String("team", team), zap.Int64("size", e.Size))
+		}
+
 		render.Status(r, proxyResp.StatusCode)
 		render.JSON(w, r, "Report persisted")
 	}
 }
+
+// DeleteHandler deletes the report of the loadtest from the storage and from the storage usage of its team,
+// deleting a report that doesn't exist succeeds
+func DeleteHandler(kubeClient *kk8s.Client, logger *zap.Logger) func(w http.ResponseWriter, r *http.Request) {
+	if minioClient == nil {
+		panic("client was not initialized, please initialize object storage client")
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		loadTestName := chi.URLParam(r, "id")
+
+		err := minioClient.RemoveObject(r.Context(), bucketName, loadTestName, minio.RemoveObjectOptions{})
+		if err != nil && minio.ToErrorResponse(err).StatusCode != http.StatusNotFound {
+			logger.Error("Failed to delete report", zap.Error(err), zap.String("loadtest", loadTestName))
+			render.Render(w, r, khttp.ErrResponse(http.StatusInternalServerError, err.Error()))
+			return
+		}
+		if cache != nil {
+			cache.invalidate(loadTestName)
+		}
+
+		// the report is deleted, failing to remove it from the usage of its team must not fail the deletion
+		if capPolicy != nil {
+			loadTest, err := kubeClient.GetLoadTest(r.Context(), loadTestName)
+			if err == nil {
+				err = untrackReport(r.Context(), loadTest.Spec.Tags[teamTag], loadTestName)
+			}
+			if err != nil && !k8sAPIErrors.IsNotFound(err) {
+				logger.Error("Failed to untrack report storage of team", zap.Error(err), zap.String("loadtest", loadTestName))
+			}
+		}
+
+		logger.Info("Deleted report", zap.String("loadtest", loadTestName))
+		render.NoContent(w, r)
+	}
+}
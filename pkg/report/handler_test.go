@@ -70,6 +70,20 @@ func TestShowHandler(t *testing.T) {
 	}
 }
 
+func TestServedContentType(t *testing.T) {
+	for contentType, expected := range map[string]string{
+		"":                          "",
+		"application/octet-stream":  "",
+		"binary/octet-stream":       "",
+		"text/html; charset=utf-8":  "text/html; charset=utf-8",
+		"application/json":          "application/json",
+		"text/csv; charset=utf-8":   "text/csv; charset=utf-8",
+		"not a valid content type/": "",
+	} {
+		assert.Equal(t, expected, servedContentType(contentType), contentType)
+	}
+}
+
 type roundTripFunc func(req *http.Request) *http.Response
 
 func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
@@ -17,6 +17,10 @@ var (
 	bucketName  string
 	fs          http.FileSystem
 	expires     time.Duration
+	cache       *reportCache
+	capPolicy   CapPolicy
+	teamTag     string
+	retention   *retentionTagging
 )
 
 // InitObjectStorageClient inits new minio backend client to work with S3 compatible storages
@@ -76,5 +80,20 @@ func InitObjectStorageClient(cfg Config) error {
 	if nil != err {
 		return err
 	}
+	// Init report cache, shared by concurrent requests for the same report
+	if cfg.CacheTTL > 0 {
+		cache = newReportCache(cfg.CacheTTL, cfg.CacheMaxSize)
+	}
+	// Init report storage cap of teams
+	capPolicy, err = NewCapPolicy(cfg.TeamCapPolicy, cfg.TeamCap)
+	if err != nil {
+		return err
+	}
+	teamTag = cfg.TeamTag
+	// Init retention tagging of reports
+	retention, err = newRetentionTagging(cfg.RetentionTag, cfg.RetentionClasses)
+	if err != nil {
+		return err
+	}
 	return nil
 }
@@ -0,0 +1,71 @@
+package report
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/tags"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+// retentionTagging maps the retention classes of loadtests to the object tag the lifecycle rules of the storage
+// key off, e.g. to expire the reports tagged with retention=7d after 7 days
+type retentionTagging struct {
+	key    string
+	values map[loadTestV1.RetentionClass]string
+}
+
+// newRetentionTagging returns the tagging of reports with the given tag key and tag values of the retention classes,
+// nil if the tag key is empty
+func newRetentionTagging(key string, classes map[string]string) (*retentionTagging, error) {
+	if key == "" {
+		return nil, nil
+	}
+
+	values := make(map[loadTestV1.RetentionClass]string, len(classes))
+	for class, value := range classes {
+		if !loadTestV1.RetentionClass(class).IsValid() {
+			return nil, fmt.Errorf("unknown retention class %q, must be one of short, standard or long", class)
+		}
+		values[loadTestV1.RetentionClass(class)] = value
+	}
+	return &retentionTagging{key: key, values: values}, nil
+}
+
+// objectTags returns the object tags of the report of the loadtest, nil if its retention class has no tag value.
+// The class recorded in the status by the controller wins over the one of the spec.
+func (t *retentionTagging) objectTags(loadTest *loadTestV1.LoadTest) map[string]string {
+	class := loadTest.Status.RetentionClass
+	if class == "" {
+		class = loadTest.Spec.RetentionClass
+	}
+	if !class.IsValid() {
+		class = loadTestV1.RetentionStandard
+	}
+
+	value, ok := t.values[class]
+	if !ok || value == "" {
+		return nil
+	}
+	return map[string]string{t.key: value}
+}
+
+// tagReport tags the persisted report of the loadtest with its retention class
+func tagReport(ctx context.Context, loadTest *loadTestV1.LoadTest) error {
+	if retention == nil {
+		return nil
+	}
+
+	objectTags := retention.objectTags(loadTest)
+	if objectTags == nil {
+		return nil
+	}
+
+	t, err := tags.NewTags(objectTags, true)
+	if err != nil {
+		return fmt.Errorf("invalid retention tag: %w", err)
+	}
+	return minioClient.PutObjectTagging(ctx, bucketName, loadTest.GetName(), t, minio.PutObjectTaggingOptions{})
+}
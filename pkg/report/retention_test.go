@@ -0,0 +1,64 @@
+package report
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+func TestNewRetentionTagging(t *testing.T) {
+	tagging, err := newRetentionTagging("", map[string]string{"short": "7d"})
+	assert.NoError(t, err)
+	assert.Nil(t, tagging)
+
+	_, err = newRetentionTagging("retention", map[string]string{"forever": "never"})
+	assert.Error(t, err)
+
+	tagging, err = newRetentionTagging("retention", map[string]string{"short": "7d", "long": "180d"})
+	require.NoError(t, err)
+	assert.Equal(t, "7d", tagging.values[loadTestV1.RetentionShort])
+}
+
+func TestRetentionObjectTags(t *testing.T) {
+	tagging, err := newRetentionTagging("retention", map[string]string{"short": "7d", "standard": "30d"})
+	require.NoError(t, err)
+
+	for _, tt := range []struct {
+		name     string
+		spec     loadTestV1.RetentionClass
+		status   loadTestV1.RetentionClass
+		expected map[string]string
+	}{
+		{
+			name:     "default",
+			expected: map[string]string{"retention": "30d"},
+		},
+		{
+			name:     "from spec",
+			spec:     loadTestV1.RetentionShort,
+			expected: map[string]string{"retention": "7d"},
+		},
+		{
+			name:     "status wins over spec",
+			spec:     loadTestV1.RetentionStandard,
+			status:   loadTestV1.RetentionShort,
+			expected: map[string]string{"retention": "7d"},
+		},
+		{
+			name:     "class without tag value",
+			spec:     loadTestV1.RetentionLong,
+			expected: nil,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			loadTest := &loadTestV1.LoadTest{
+				Spec:   loadTestV1.LoadTestSpec{RetentionClass: tt.spec},
+				Status: loadTestV1.LoadTestStatus{RetentionClass: tt.status},
+			}
+			assert.Equal(t, tt.expected, tagging.objectTags(loadTest))
+		})
+	}
+}
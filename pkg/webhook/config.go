@@ -0,0 +1,17 @@
+package webhook
+
+import (
+	"github.com/hellofresh/kangal/pkg/core/observability"
+)
+
+// Config is the possible Webhook configurations
+type Config struct {
+	HTTPPort int `envconfig:"WEBHOOK_HTTP_PORT" default:"8443"`
+	Logger   observability.LoggerConfig
+
+	// TLSCertFile is the path of the certificate served to the Kubernetes API server, it must be signed
+	// by the CA bundle of the ValidatingWebhookConfiguration
+	TLSCertFile string `envconfig:"WEBHOOK_TLS_CERT_FILE" required:"true"`
+	// TLSKeyFile is the path of the private key of TLSCertFile
+	TLSKeyFile string `envconfig:"WEBHOOK_TLS_KEY_FILE" required:"true"`
+}
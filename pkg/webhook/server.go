@@ -0,0 +1,119 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"go.uber.org/zap"
+	admissionV1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/hellofresh/kangal/pkg/backends"
+	cHttp "github.com/hellofresh/kangal/pkg/core/http"
+	mPkg "github.com/hellofresh/kangal/pkg/core/middleware"
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+// ValidateRoute is the path the ValidatingWebhookConfiguration sends LoadTest admission reviews to
+const ValidateRoute = "/validate-loadtest"
+
+// Runner encapsulates all Kangal Webhook server dependencies
+type Runner struct {
+	Logger *zap.Logger
+}
+
+// RunServer runs Kangal admission webhook server
+func RunServer(cfg Config, rr Runner) error {
+	registry := backends.New(
+		backends.WithLogger(rr.Logger),
+	)
+
+	r := chi.NewRouter()
+
+	r.Use(middleware.RequestID)
+	r.Use(mPkg.NewLogger(rr.Logger).Handler)
+	r.Use(mPkg.Recovery)
+
+	r.Get("/status", cHttp.LivenessHandler("Kangal Webhook"))
+	r.Post(ValidateRoute, ValidateHandler(registry, rr.Logger))
+
+	address := fmt.Sprintf(":%d", cfg.HTTPPort)
+	rr.Logger.Info("Running HTTPS server...", zap.String("address", address))
+
+	// Try and run https server, fail on error
+	err := http.ListenAndServeTLS(address, cfg.TLSCertFile, cfg.TLSKeyFile, r)
+	if err != nil {
+		return fmt.Errorf("failed to run HTTPS server: %w", err)
+	}
+	return nil
+}
+
+// ValidateHandler answers LoadTest admission reviews, denying load tests that fail Validate.
+// Updates that don't change the spec are always allowed, so load tests created before the webhook
+// was installed can still be synced by the controller
+func ValidateHandler(registry backends.Registry, logger *zap.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var review admissionV1.AdmissionReview
+		if err := json.NewDecoder(r.Body).Decode(&review); err != nil {
+			http.Error(w, fmt.Sprintf("could not decode admission review: %s", err), http.StatusBadRequest)
+			return
+		}
+		if review.Request == nil {
+			http.Error(w, "admission review has no request", http.StatusBadRequest)
+			return
+		}
+
+		response := &admissionV1.AdmissionResponse{
+			UID:     review.Request.UID,
+			Allowed: true,
+		}
+		if err := admit(registry, review.Request); err != nil {
+			logger.Info("Denied load test",
+				zap.String("loadtest", review.Request.Name),
+				zap.String("operation", string(review.Request.Operation)),
+				zap.Error(err),
+			)
+			response.Allowed = false
+			response.Result = &metaV1.Status{
+				Status:  metaV1.StatusFailure,
+				Message: err.Error(),
+				Reason:  metaV1.StatusReasonInvalid,
+				Code:    http.StatusUnprocessableEntity,
+			}
+		}
+
+		review.Response = response
+		review.Request = nil
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(review); err != nil {
+			logger.Error("Could not write admission review", zap.Error(err))
+		}
+	}
+}
+
+// admit validates the load test of a create or update admission request
+func admit(registry backends.Registry, req *admissionV1.AdmissionRequest) error {
+	if req.Operation != admissionV1.Create && req.Operation != admissionV1.Update {
+		return nil
+	}
+
+	var loadTest loadTestV1.LoadTest
+	if err := json.Unmarshal(req.Object.Raw, &loadTest); err != nil {
+		return fmt.Errorf("could not decode load test: %w", err)
+	}
+
+	if req.Operation == admissionV1.Update {
+		var oldLoadTest loadTestV1.LoadTest
+		if err := json.Unmarshal(req.OldObject.Raw, &oldLoadTest); err == nil &&
+			equality.Semantic.DeepEqual(oldLoadTest.Spec, loadTest.Spec) {
+			return nil
+		}
+	}
+
+	return Validate(registry, loadTest)
+}
@@ -0,0 +1,101 @@
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	admissionV1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+func rawLoadTest(t *testing.T, loadTest loadTestV1.LoadTest) runtime.RawExtension {
+	t.Helper()
+	raw, err := json.Marshal(loadTest)
+	require.NoError(t, err)
+	return runtime.RawExtension{Raw: raw}
+}
+
+func TestValidateHandler(t *testing.T) {
+	invalid := validLoadTest()
+	invalid.Spec.TestFile = nil
+
+	for _, tt := range []struct {
+		name      string
+		operation admissionV1.Operation
+		object    loadTestV1.LoadTest
+		oldObject *loadTestV1.LoadTest
+		allowed   bool
+	}{
+		{
+			name:      "create valid",
+			operation: admissionV1.Create,
+			object:    validLoadTest(),
+			allowed:   true,
+		},
+		{
+			name:      "create invalid",
+			operation: admissionV1.Create,
+			object:    invalid,
+			allowed:   false,
+		},
+		{
+			name:      "update to invalid spec",
+			operation: admissionV1.Update,
+			object:    invalid,
+			oldObject: func() *loadTestV1.LoadTest { lt := validLoadTest(); return &lt }(),
+			allowed:   false,
+		},
+		{
+			name:      "update without spec change",
+			operation: admissionV1.Update,
+			object:    invalid,
+			oldObject: &invalid,
+			allowed:   true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &admissionV1.AdmissionRequest{
+				UID:       types.UID("review-uid"),
+				Operation: tt.operation,
+				Object:    rawLoadTest(t, tt.object),
+			}
+			if tt.oldObject != nil {
+				req.OldObject = rawLoadTest(t, *tt.oldObject)
+			}
+			body, err := json.Marshal(admissionV1.AdmissionReview{Request: req})
+			require.NoError(t, err)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, ValidateRoute, bytes.NewReader(body))
+			ValidateHandler(testRegistry{backend: validateBackend{}}, zap.NewNop())(w, r)
+
+			require.Equal(t, http.StatusOK, w.Code)
+
+			var review admissionV1.AdmissionReview
+			require.NoError(t, json.NewDecoder(w.Body).Decode(&review))
+			require.NotNil(t, review.Response)
+			assert.Equal(t, types.UID("review-uid"), review.Response.UID)
+			assert.Equal(t, tt.allowed, review.Response.Allowed)
+			if !tt.allowed {
+				assert.Equal(t, ErrRequireTestFile.Error(), review.Response.Result.Message)
+			}
+		})
+	}
+}
+
+func TestValidateHandlerBadRequest(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, ValidateRoute, bytes.NewReader([]byte(`{}`)))
+	ValidateHandler(testRegistry{backend: validateBackend{}}, zap.NewNop())(w, r)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
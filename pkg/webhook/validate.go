@@ -0,0 +1,71 @@
+package webhook
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hellofresh/kangal/pkg/backends"
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+var (
+	// ErrRequireMinOneDistributedPod the DistributedPods field is required to be 1 or more
+	ErrRequireMinOneDistributedPod = errors.New("LoadTest must specify 1 or more DistributedPods")
+	// ErrRequireTestFile the TestFile field is required to not be empty, unless TestFileURL is set or the backend
+	// doesn't require a test file
+	ErrRequireTestFile = errors.New("LoadTest TestFile or TestFileURL is required")
+	// ErrThresholdsUnsupported the Thresholds field is only allowed for backends filling in the summary of load tests
+	ErrThresholdsUnsupported = errors.New("LoadTest Thresholds are not supported by the backend, it does not produce a summary")
+	// ErrNegativeDuration the Duration field must not be negative
+	ErrNegativeDuration = errors.New("LoadTest Duration must not be negative")
+	// ErrNegativeVUs the VUs field must not be negative
+	ErrNegativeVUs = errors.New("LoadTest VUs must not be negative")
+	// ErrNegativeCleanupThreshold the CleanupThreshold field must not be negative
+	ErrNegativeCleanupThreshold = errors.New("LoadTest CleanupThreshold must not be negative")
+	// ErrNegativeTimeout the Timeout field must not be negative
+	ErrNegativeTimeout = errors.New("LoadTest Timeout must not be negative")
+)
+
+// Validate checks the fields common to all backends of the load test, then lets its backend
+// reject it when the backend implements backends.BackendValidate
+func Validate(registry backends.Registry, loadTest loadTestV1.LoadTest) error {
+	backend, err := registry.GetBackend(loadTest.Spec.Type)
+	if err != nil {
+		return fmt.Errorf("%w: %q", err, loadTest.Spec.Type)
+	}
+
+	spec := loadTest.Spec
+	if spec.DistributedPods == nil || *spec.DistributedPods < 1 {
+		return ErrRequireMinOneDistributedPod
+	}
+
+	if len(spec.TestFile) == 0 && spec.TestFileURL == "" && backends.RequiresTestFile(backend) {
+		return ErrRequireTestFile
+	}
+
+	if len(spec.Thresholds) > 0 && !backends.SupportsThresholds(backend) {
+		return ErrThresholdsUnsupported
+	}
+
+	if spec.Duration < 0 {
+		return ErrNegativeDuration
+	}
+
+	if spec.VUs < 0 {
+		return ErrNegativeVUs
+	}
+
+	if spec.CleanupThreshold != nil && *spec.CleanupThreshold < 0 {
+		return ErrNegativeCleanupThreshold
+	}
+
+	if spec.Timeout < 0 {
+		return ErrNegativeTimeout
+	}
+
+	if validator, ok := backend.(backends.BackendValidate); ok {
+		return validator.Validate(loadTest)
+	}
+
+	return nil
+}
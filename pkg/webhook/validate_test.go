@@ -0,0 +1,187 @@
+package webhook
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hellofresh/kangal/pkg/backends"
+	loadTestV1 "github.com/hellofresh/kangal/pkg/kubernetes/apis/loadtest/v1"
+)
+
+var errBackendInvalid = errors.New("backend invalid")
+
+// testRegistry resolves only the load test type of its backend
+type testRegistry struct {
+	backends.Registry
+	backend backends.Backend
+}
+
+func (r testRegistry) GetBackend(loadTestType loadTestV1.LoadTestType) (backends.Backend, error) {
+	if loadTestType != r.backend.Type() {
+		return nil, backends.ErrNoBackendRegistered
+	}
+	return r.backend, nil
+}
+
+// validateBackend rejects load tests with the "invalid" tag
+type validateBackend struct {
+	backends.Backend
+}
+
+func (validateBackend) Type() loadTestV1.LoadTestType {
+	return loadTestV1.LoadTestTypeGhz
+}
+
+func (validateBackend) Validate(loadTest loadTestV1.LoadTest) error {
+	if _, ok := loadTest.Spec.Tags["invalid"]; ok {
+		return errBackendInvalid
+	}
+	return nil
+}
+
+func validLoadTest() loadTestV1.LoadTest {
+	distributedPods := int32(1)
+	return loadTestV1.LoadTest{
+		Spec: loadTestV1.LoadTestSpec{
+			Type:            loadTestV1.LoadTestTypeGhz,
+			DistributedPods: &distributedPods,
+			TestFile:        []byte(`{"call": "helloworld.Greeter.SayHello"}`),
+		},
+	}
+}
+
+func TestValidate(t *testing.T) {
+	negativeThreshold := -time.Hour
+	zeroPods := int32(0)
+
+	for _, tt := range []struct {
+		name   string
+		modify func(*loadTestV1.LoadTestSpec)
+		err    error
+	}{
+		{
+			name:   "valid",
+			modify: func(*loadTestV1.LoadTestSpec) {},
+		},
+		{
+			name: "test file URL",
+			modify: func(spec *loadTestV1.LoadTestSpec) {
+				spec.TestFile = nil
+				spec.TestFileURL = "https://example.com/test.json"
+			},
+		},
+		{
+			name: "unregistered type",
+			modify: func(spec *loadTestV1.LoadTestSpec) {
+				spec.Type = loadTestV1.LoadTestTypeJMeter
+			},
+			err: backends.ErrNoBackendRegistered,
+		},
+		{
+			name: "missing distributed pods",
+			modify: func(spec *loadTestV1.LoadTestSpec) {
+				spec.DistributedPods = nil
+			},
+			err: ErrRequireMinOneDistributedPod,
+		},
+		{
+			name: "zero distributed pods",
+			modify: func(spec *loadTestV1.LoadTestSpec) {
+				spec.DistributedPods = &zeroPods
+			},
+			err: ErrRequireMinOneDistributedPod,
+		},
+		{
+			name: "missing test file",
+			modify: func(spec *loadTestV1.LoadTestSpec) {
+				spec.TestFile = nil
+			},
+			err: ErrRequireTestFile,
+		},
+		{
+			name: "thresholds without summary",
+			modify: func(spec *loadTestV1.LoadTestSpec) {
+				spec.Thresholds = []string{"p99 < 200ms"}
+			},
+			err: ErrThresholdsUnsupported,
+		},
+		{
+			name: "negative duration",
+			modify: func(spec *loadTestV1.LoadTestSpec) {
+				spec.Duration = -time.Minute
+			},
+			err: ErrNegativeDuration,
+		},
+		{
+			name: "negative vus",
+			modify: func(spec *loadTestV1.LoadTestSpec) {
+				spec.VUs = -1
+			},
+			err: ErrNegativeVUs,
+		},
+		{
+			name: "negative cleanup threshold",
+			modify: func(spec *loadTestV1.LoadTestSpec) {
+				spec.CleanupThreshold = &negativeThreshold
+			},
+			err: ErrNegativeCleanupThreshold,
+		},
+		{
+			name: "negative timeout",
+			modify: func(spec *loadTestV1.LoadTestSpec) {
+				spec.Timeout = -time.Minute
+			},
+			err: ErrNegativeTimeout,
+		},
+		{
+			name: "rejected by backend",
+			modify: func(spec *loadTestV1.LoadTestSpec) {
+				spec.Tags = loadTestV1.LoadTestTags{"invalid": "true"}
+			},
+			err: errBackendInvalid,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			loadTest := validLoadTest()
+			tt.modify(&loadTest.Spec)
+
+			err := Validate(testRegistry{backend: validateBackend{}}, loadTest)
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
+// scriptlessBackend runs load tests without a test file
+type scriptlessBackend struct {
+	backends.Backend
+}
+
+func (scriptlessBackend) Type() loadTestV1.LoadTestType {
+	return loadTestV1.LoadTestTypeWrk2
+}
+
+func (scriptlessBackend) Capabilities() backends.Capabilities {
+	return backends.Capabilities{
+		RequiredFields: []string{"distributedPods", "targetURL"},
+		OptionalFields: []string{"testFile", "testFileURL", "thresholds"},
+	}
+}
+
+func TestValidateOptionalTestFile(t *testing.T) {
+	loadTest := validLoadTest()
+	loadTest.Spec.Type = loadTestV1.LoadTestTypeWrk2
+	loadTest.Spec.TestFile = nil
+
+	assert.NoError(t, Validate(testRegistry{backend: scriptlessBackend{}}, loadTest))
+}
+
+func TestValidateThresholds(t *testing.T) {
+	loadTest := validLoadTest()
+	loadTest.Spec.Type = loadTestV1.LoadTestTypeWrk2
+	loadTest.Spec.Thresholds = []string{"p99 < 200ms"}
+
+	assert.NoError(t, Validate(testRegistry{backend: scriptlessBackend{}}, loadTest))
+}